@@ -0,0 +1,108 @@
+package xlogger
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// AfterClosePolicy decides what happens to entries logged after Close.
+type AfterClosePolicy string
+
+const (
+	// AfterCloseDrop discards entries logged after Close
+	AfterCloseDrop AfterClosePolicy = "drop"
+	// AfterCloseStderr writes entries logged after Close to stderr
+	AfterCloseStderr AfterClosePolicy = "stderr"
+	// AfterClosePanicInDev panics on entries logged after Close in
+	// development mode and discards them otherwise
+	AfterClosePanicInDev AfterClosePolicy = "panic_in_dev"
+)
+
+// Normalize returns the normalized lowercase policy.
+func (p AfterClosePolicy) Normalize() AfterClosePolicy {
+	return AfterClosePolicy(strings.ToLower(string(p)))
+}
+
+// isValid reports whether p is a known policy
+func (p AfterClosePolicy) isValid() bool {
+	switch p.Normalize() {
+	case AfterCloseDrop, AfterCloseStderr, AfterClosePanicInDev:
+		return true
+	}
+	return false
+}
+
+// closedCore handles entries logged once the outputs are closed according
+// to the after-close policy, instead of writing them to closed outputs
+type closedCore struct {
+	zapcore.Core
+	closed      *atomic.Bool
+	policy      AfterClosePolicy
+	development bool
+	stderr      zapcore.Core // nil unless the policy is AfterCloseStderr
+}
+
+// closedCoreWrapper returns a function wrapping cores with the after-close
+// policy of outputs. Entries written to stderr are encoded like config,
+// using JSON for binary formats.
+func closedCoreWrapper(config zap.Config, outputs *loggerOutputs) (func(zapcore.Core) zapcore.Core, error) {
+	var stderr zapcore.Core
+	if outputs.postClose == AfterCloseStderr {
+		encoding, encoderConfig := config.Encoding, config.EncoderConfig
+		if isBinaryEncoding(encoding) {
+			encoding, encoderConfig = "json", createBaseEncoderConfig()
+		}
+		encoder, err := buildEncoder(encoding, encoderConfig)
+		if err != nil {
+			return nil, err
+		}
+		stderr = zapcore.NewCore(encoder, zapcore.Lock(os.Stderr), config.Level)
+	}
+
+	return func(core zapcore.Core) zapcore.Core {
+		return &closedCore{
+			Core:        core,
+			closed:      &outputs.closed,
+			policy:      outputs.postClose,
+			development: config.Development,
+			stderr:      stderr,
+		}
+	}, nil
+}
+
+// Level reports the inner core's minimum level to zap.Logger.Level
+func (c *closedCore) Level() zapcore.Level {
+	return zapcore.LevelOf(c.Core)
+}
+
+// With implements zapcore.Core
+func (c *closedCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.Core = c.Core.With(fields)
+	if c.stderr != nil {
+		clone.stderr = c.stderr.With(fields)
+	}
+	return &clone
+}
+
+// Check implements zapcore.Core
+func (c *closedCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.closed.Load() {
+		return c.Core.Check(ent, ce)
+	}
+
+	switch c.policy {
+	case AfterCloseStderr:
+		return c.stderr.Check(ent, ce)
+	case AfterClosePanicInDev:
+		if c.development && c.Core.Enabled(ent.Level) {
+			panic(fmt.Sprintf("xlogger: %q logged after Close", ent.Message))
+		}
+	}
+	return ce
+}
@@ -0,0 +1,81 @@
+package xlogger
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAfterClosePolicy tests the handling of entries logged after Close
+func TestAfterClosePolicy(t *testing.T) {
+	t.Run("should drop entries by default", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+		logger.Info("before close")
+		require.NoError(t, logger.Close(context.Background()))
+
+		assert.NotPanics(t, func() {
+			logger.Info("after close")
+			logger.ForInfra("db").Warn("infra after close")
+		})
+		assert.Contains(t, output(), "before close")
+		assert.NotContains(t, output(), "after close")
+	})
+
+	t.Run("should write entries to stderr", func(t *testing.T) {
+		stderr, err := os.Create(filepath.Join(t.TempDir(), "stderr.log"))
+		require.NoError(t, err)
+		defer stderr.Close()
+
+		original := os.Stderr
+		os.Stderr = stderr
+		defer func() { os.Stderr = original }()
+
+		path := filepath.Join(t.TempDir(), "app.log")
+		logger, err := NewZapLogger(NewLoggerConfig(
+			WithOutputPaths(path),
+			WithAfterClosePolicy(AfterCloseStderr),
+		))
+		require.NoError(t, err)
+		child := logger.With(String("worker", "w1"))
+		require.NoError(t, logger.Close(context.Background()))
+
+		child.Info("late flush")
+		logger.ForInfra("db").Info("late infra flush")
+		logger.Debug("filtered")
+
+		output := readFile(t, stderr.Name())
+		entries := entriesWithMessage(t, output, "late flush")
+		require.Len(t, entries, 1)
+		assert.Equal(t, "w1", entries[0]["worker"])
+		assert.Len(t, entriesWithMessage(t, output, "late infra flush"), 1)
+		assert.NotContains(t, output, "filtered")
+		assert.NotContains(t, readFile(t, path), "late")
+	})
+
+	t.Run("should panic in development only", func(t *testing.T) {
+		for _, development := range []bool{true, false} {
+			logger, err := NewZapLogger(NewLoggerConfig(
+				WithOutputPaths(filepath.Join(t.TempDir(), "app.log")),
+				WithDevelopment(development),
+				WithAfterClosePolicy(AfterClosePanicInDev),
+			))
+			require.NoError(t, err)
+			require.NoError(t, logger.Close(context.Background()))
+
+			if development {
+				assert.PanicsWithValue(t, `xlogger: "late" logged after Close`, func() { logger.Info("late") })
+			} else {
+				assert.NotPanics(t, func() { logger.Info("late") })
+			}
+		}
+	})
+
+	t.Run("should ignore unknown policies", func(t *testing.T) {
+		cfg := NewLoggerConfig(WithAfterClosePolicy("STDERR"), WithAfterClosePolicy("retry"))
+		assert.Equal(t, AfterCloseStderr, cfg.AfterClose)
+	})
+}
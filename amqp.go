@@ -0,0 +1,92 @@
+package xlogger
+
+// amqpComponent is the infrastructure component of RabbitMQ events
+const amqpComponent = "amqp"
+
+// AMQPLogger logs the connection and channel events of a RabbitMQ client,
+// such as amqp091-go, under the "amqp" component. amqp091-go reports them on
+// the channels of its Notify methods; read those in a goroutine and pass
+// each event to the matching method.
+//
+// Example:
+//
+//	amqpLogger := xlogger.NewAMQPLogger(logger)
+//	go func() {
+//	    for err := range conn.NotifyClose(make(chan *amqp.Error, 1)) {
+//	        amqpLogger.ConnectionClosed(err)
+//	    }
+//	}()
+//	go func() {
+//	    for r := range ch.NotifyReturn(make(chan amqp.Return, 1)) {
+//	        amqpLogger.Returned(r.Exchange, r.RoutingKey, r.ReplyCode, r.ReplyText)
+//	    }
+//	}()
+type AMQPLogger struct {
+	logger Logger
+}
+
+// NewAMQPLogger returns a RabbitMQ event logger.
+func NewAMQPLogger(logger Logger) *AMQPLogger {
+	return &AMQPLogger{logger: logger.ForInfra(amqpComponent)}
+}
+
+// ConnectionClosed logs a connection closed by the server or the network at
+// Warn with err, and a closed connection without error at Info. Read it from
+// Connection.NotifyClose.
+func (a *AMQPLogger) ConnectionClosed(err error) {
+	a.closed("connection", err)
+}
+
+// ChannelClosed logs a channel closed by the server at Warn with err, and a
+// closed channel without error at Info. Read it from Channel.NotifyClose.
+func (a *AMQPLogger) ChannelClosed(err error) {
+	a.closed("channel", err)
+}
+
+// Blocked logs at Warn that the server blocked the publishers of the
+// connection, with its reason, and at Info that it unblocked them. Read it
+// from Connection.NotifyBlocked.
+func (a *AMQPLogger) Blocked(active bool, reason string) {
+	if !active {
+		a.logger.Info("amqp connection unblocked")
+		return
+	}
+	a.logger.Warn("amqp connection blocked", String("reason", reason))
+}
+
+// Returned logs at Warn a mandatory or immediate message the server could
+// not route. Read it from Channel.NotifyReturn.
+func (a *AMQPLogger) Returned(exchange, routingKey string, replyCode uint16, replyText string) {
+	a.logger.Warn("amqp message returned",
+		String("exchange", exchange),
+		String("routing_key", routingKey),
+		Int("reply_code", int(replyCode)),
+		String("reply_text", replyText),
+	)
+}
+
+// ConsumerCancelled logs at Warn that the server cancelled a consumer, such
+// as when its queue was deleted. Read it from Channel.NotifyCancel.
+func (a *AMQPLogger) ConsumerCancelled(consumerTag string) {
+	a.logger.Warn("amqp consumer cancelled", String("consumer_tag", consumerTag))
+}
+
+// Flow logs at Warn that the server paused the deliveries of a channel, and
+// at Info that it resumed them. Read it from Channel.NotifyFlow.
+func (a *AMQPLogger) Flow(active bool) {
+	if active {
+		a.logger.Info("amqp channel flow resumed")
+		return
+	}
+	a.logger.Warn("amqp channel flow paused")
+}
+
+// closed logs the closing of the connection or channel source
+func (a *AMQPLogger) closed(source string, err error) {
+	msg := "amqp " + source + " closed"
+	if err == nil {
+		a.logger.Info(msg)
+		return
+	}
+	a.logger.Warn(msg, Error(err))
+}
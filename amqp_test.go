@@ -0,0 +1,64 @@
+package xlogger
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAMQPLogger tests the RabbitMQ event logger
+func TestAMQPLogger(t *testing.T) {
+	t.Run("should log closed connections and channels", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+		amqpLogger := NewAMQPLogger(logger)
+
+		amqpLogger.ConnectionClosed(errors.New("Exception (320) Reason: \"CONNECTION_FORCED\""))
+		amqpLogger.ChannelClosed(nil)
+
+		log := output()
+		connection := entriesWithMessage(t, log, "amqp connection closed")
+		require.Len(t, connection, 1)
+		assert.Equal(t, "warn", connection[0]["level"])
+		assert.Equal(t, "amqp", connection[0]["component"])
+		assert.Contains(t, connection[0]["error"], "CONNECTION_FORCED")
+		channel := entriesWithMessage(t, log, "amqp channel closed")
+		require.Len(t, channel, 1)
+		assert.Equal(t, "info", channel[0]["level"])
+	})
+
+	t.Run("should log returned messages", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+
+		NewAMQPLogger(logger).Returned("orders", "orders.created", 312, "NO_ROUTE")
+
+		returned := entriesWithMessage(t, output(), "amqp message returned")
+		require.Len(t, returned, 1)
+		assert.Equal(t, "warn", returned[0]["level"])
+		assert.Equal(t, "orders", returned[0]["exchange"])
+		assert.Equal(t, "orders.created", returned[0]["routing_key"])
+		assert.Equal(t, float64(312), returned[0]["reply_code"])
+		assert.Equal(t, "NO_ROUTE", returned[0]["reply_text"])
+	})
+
+	t.Run("should log blocking, flow and cancelled consumers", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+		amqpLogger := NewAMQPLogger(logger)
+
+		amqpLogger.Blocked(true, "low on memory")
+		amqpLogger.Blocked(false, "")
+		amqpLogger.Flow(false)
+		amqpLogger.Flow(true)
+		amqpLogger.ConsumerCancelled("ctag-1")
+
+		log := output()
+		blocked := entriesWithMessage(t, log, "amqp connection blocked")
+		require.Len(t, blocked, 1)
+		assert.Equal(t, "low on memory", blocked[0]["reason"])
+		assert.Len(t, entriesWithMessage(t, log, "amqp connection unblocked"), 1)
+		assert.Equal(t, "warn", entriesWithMessage(t, log, "amqp channel flow paused")[0]["level"])
+		assert.Equal(t, "info", entriesWithMessage(t, log, "amqp channel flow resumed")[0]["level"])
+		assert.Equal(t, "ctag-1", entriesWithMessage(t, log, "amqp consumer cancelled")[0]["consumer_tag"])
+	})
+}
@@ -0,0 +1,156 @@
+package xlogger
+
+import (
+	"context"
+	"maps"
+	"strings"
+)
+
+// traceBaggageKey holds the baggage of RunWithTraceContext
+const traceBaggageKey = "logger-trace-baggage"
+
+// defaultBaggagePrefix is the prefix of baggage field keys without
+// Config.BaggagePrefix
+const defaultBaggagePrefix = "baggage."
+
+// TraceContext is the trace state of RunWithTraceContext and
+// ContextWithTraceContext: the request and correlation identifiers of
+// RunWithTrace, the W3C traceparent of RunWithTraceparent with its
+// tracestate, the trace actor and baggage, key-value pairs logged on every
+// entry of the run.
+type TraceContext struct {
+	RequestID     string
+	CorrelationID string
+	Traceparent   string // Ignored when invalid, with Tracestate
+	Tracestate    string
+	TenantID      string // Trace actor, as set by SetTraceActor
+	UserID        string
+	Baggage       map[string]string
+}
+
+// RunWithTraceContext executes fn within a goroutine-local context holding tc.
+// Every entry logged within fn carries the identifiers of tc, trace_id and
+// span_id of a valid traceparent, and one field per baggage entry, keyed with
+// Config.BaggagePrefix ("baggage." by default). The tracestate can be
+// forwarded with TraceState. Baggage of enclosing runs is kept unless tc sets
+// the same keys.
+//
+// Example:
+//
+//	err := xlogger.RunWithTraceContext(xlogger.TraceContext{
+//	    RequestID: r.Header.Get("X-Request-ID"),
+//	    Baggage:   map[string]string{"tenant": tenant, "plan": "pro"},
+//	}, func() error {
+//	    logger.Info("Processing request") // includes baggage.plan and baggage.tenant
+//	    return handle(r)
+//	})
+func RunWithTraceContext(tc TraceContext, fn func() error) error {
+	return defaultTraceScope.RunWithTraceContext(tc, fn)
+}
+
+// TraceBaggage returns the goroutine-local baggage value of key, or an empty
+// string when no run sets it.
+func TraceBaggage(key string) string {
+	return defaultTraceScope.TraceBaggage(key)
+}
+
+// TraceBaggageFromContext returns the baggage value of key stored in ctx,
+// the context.Context counterpart of TraceBaggage.
+func TraceBaggageFromContext(ctx context.Context, key string) string {
+	return TraceContextFromContext(ctx).Baggage[key]
+}
+
+// RunWithTraceContext is the scoped form of the package-level
+// RunWithTraceContext.
+func (s *TraceScope) RunWithTraceContext(tc TraceContext, fn func() error) error {
+	if fn == nil {
+		return nil
+	}
+
+	values := traceValues{
+		traceRequestIDKey:     tc.RequestID,
+		traceCorrelationIDKey: tc.CorrelationID,
+	}
+	if parent, err := ParseTraceparent(tc.Traceparent); err == nil {
+		values[traceParentKey] = parent
+		values[traceStateKey] = strings.TrimSpace(tc.Tracestate)
+	}
+	if len(tc.Baggage) > 0 {
+		baggage := maps.Clone(s.baggage())
+		if baggage == nil {
+			baggage = make(map[string]string, len(tc.Baggage))
+		}
+		maps.Copy(baggage, tc.Baggage)
+		values[traceBaggageKey] = baggage
+	}
+
+	values = s.runValues(values)
+	if tc.TenantID != "" || tc.UserID != "" {
+		values[traceActorKey].(*traceActor).ids.Store(&[2]string{tc.TenantID, tc.UserID})
+	}
+
+	var result error
+	s.store.SetValues(values, func() {
+		result = fn()
+	})
+	return result
+}
+
+// CurrentTraceContext returns the goroutine-local trace state, for example
+// to carry it in a context.Context with ContextWithTraceContext.
+func CurrentTraceContext() TraceContext {
+	return defaultTraceScope.CurrentTraceContext()
+}
+
+// CurrentTraceContext returns the trace state stored in this scope.
+func (s *TraceScope) CurrentTraceContext() TraceContext {
+	tc := TraceContext{
+		RequestID:     s.TraceRequestID(),
+		CorrelationID: s.TraceCorrelationID(),
+		Traceparent:   s.TraceParent(),
+		Tracestate:    s.TraceState(),
+		Baggage:       maps.Clone(s.baggage()),
+	}
+	tc.TenantID, tc.UserID = s.actor().get()
+	return tc
+}
+
+// TraceBaggage returns the baggage value of key stored in this scope.
+func (s *TraceScope) TraceBaggage(key string) string {
+	return s.baggage()[key]
+}
+
+// baggage returns the baggage stored in this scope, which must not be
+// modified
+func (s *TraceScope) baggage() map[string]string {
+	value, ok := s.store.GetValue(traceBaggageKey)
+	if !ok {
+		return nil
+	}
+	baggage, _ := value.(map[string]string)
+	return baggage
+}
+
+// baggagePrefix returns the prefix of baggage field keys
+func (c *Config) baggagePrefix() string {
+	if c.BaggagePrefix == "" {
+		return defaultBaggagePrefix
+	}
+	return c.BaggagePrefix
+}
+
+// withBaggageFields appends the baggage of scope as top-level fields keyed
+// with prefix, in key order, leaving out keys the entry already has
+func withBaggageFields(scope *TraceScope, prefix string, fields []Field) []Field {
+	baggage := scope.baggage()
+	if len(baggage) == 0 {
+		return fields
+	}
+	extra := make([]Field, 0, len(baggage))
+	for _, key := range sortedKeys(baggage) {
+		if fieldIndex(fields, prefix+key) < 0 {
+			extra = append(extra, String(prefix+key, baggage[key]))
+		}
+	}
+	return appendTopLevel(fields, extra...)
+}
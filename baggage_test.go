@@ -0,0 +1,85 @@
+package xlogger
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRunWithTraceContext tests trace identifiers and baggage of a run
+func TestRunWithTraceContext(t *testing.T) {
+	t.Run("should log identifiers and baggage", func(t *testing.T) {
+		skipWithoutGLS(t)
+		logger, output := newFileLogger(t)
+
+		err := RunWithTraceContext(TraceContext{
+			RequestID:     "req-1",
+			CorrelationID: "corr-1",
+			Baggage:       map[string]string{"tenant": "acme", "plan": "pro"},
+		}, func() error {
+			assert.Equal(t, "req-1", TraceRequestID())
+			assert.Equal(t, "acme", TraceBaggage("tenant"))
+			logger.Info("processing")
+			return nil
+		})
+		require.NoError(t, err)
+		require.NoError(t, logger.Sync())
+
+		entries := decodeJSONLines(t, output())
+		require.Len(t, entries, 1)
+		assert.Equal(t, "req-1", entries[0]["request_id"])
+		assert.Equal(t, "corr-1", entries[0]["correlation_id"])
+		assert.Equal(t, "acme", entries[0]["baggage.tenant"])
+		assert.Equal(t, "pro", entries[0]["baggage.plan"])
+	})
+
+	t.Run("should use the configured prefix", func(t *testing.T) {
+		skipWithoutGLS(t)
+		logger, output := newFileLogger(t, WithBaggagePrefix("ctx."))
+
+		_ = RunWithTraceContext(TraceContext{Baggage: map[string]string{"tenant": "acme"}}, func() error {
+			logger.ForInfra("db").Info("query")
+			return nil
+		})
+		require.NoError(t, logger.Sync())
+
+		entries := decodeJSONLines(t, output())
+		require.Len(t, entries, 1)
+		assert.Equal(t, "acme", entries[0]["ctx.tenant"])
+		assert.NotContains(t, entries[0], "baggage.tenant")
+	})
+
+	t.Run("should merge baggage of nested runs", func(t *testing.T) {
+		skipWithoutGLS(t)
+		_ = RunWithTraceContext(TraceContext{Baggage: map[string]string{"tenant": "acme", "plan": "pro"}}, func() error {
+			return RunWithTraceContext(TraceContext{Baggage: map[string]string{"plan": "free"}}, func() error {
+				assert.Equal(t, "acme", TraceBaggage("tenant"))
+				assert.Equal(t, "free", TraceBaggage("plan"))
+				return nil
+			})
+		})
+		assert.Empty(t, TraceBaggage("tenant"))
+	})
+
+	t.Run("should keep fields passed with the entry", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+
+		_ = RunWithTraceContext(TraceContext{Baggage: map[string]string{"tenant": "acme"}}, func() error {
+			logger.Info("override", String("baggage.tenant", "globex"))
+			return nil
+		})
+		require.NoError(t, logger.Sync())
+
+		assert.Contains(t, output(), `"baggage.tenant":"globex"`)
+		assert.NotContains(t, output(), "acme")
+	})
+
+	t.Run("should return the error of fn", func(t *testing.T) {
+		errFailed := errors.New("failed")
+
+		assert.ErrorIs(t, RunWithTraceContext(TraceContext{}, func() error { return errFailed }), errFailed)
+		assert.NoError(t, RunWithTraceContext(TraceContext{}, nil))
+	})
+}
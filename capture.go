@@ -0,0 +1,118 @@
+package xlogger
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// streamFieldKey names the captured stream of CaptureStdout entries
+const streamFieldKey = "stream"
+
+// captureMu guards the active capture, as os.Stdout and os.Stderr are global
+var (
+	captureMu     sync.Mutex
+	captureActive bool
+)
+
+// ErrCaptureActive is returned by CaptureStdout while another capture runs
+var ErrCaptureActive = errors.New("xlogger: stdout and stderr are already captured")
+
+// CaptureStdout replaces os.Stdout and os.Stderr with pipes and logs every
+// line written to them as an entry of the component's ForInfra logger
+// (logger itself for an empty component): Info for stdout and Error for
+// stderr, with a "stream" field. It eases moving services printing with
+// fmt.Println onto structured logging one code path at a time.
+//
+// The returned stop function restores os.Stdout and os.Stderr and returns
+// once the lines written before it are logged, including a last line
+// without newline. Outputs opened by logger keep writing to the original
+// streams, so logger must be created before the capture starts. Writers
+// that stored os.Stdout or os.Stderr before, such as the standard log
+// package, are not captured; see NewStdLog.
+//
+// Example:
+//
+//	stop, err := xlogger.CaptureStdout(logger, "legacy")
+//	if err != nil {
+//	    return err
+//	}
+//	defer stop()
+//
+//	fmt.Println("user created") // {"level":"info","message":"user created","component":"legacy","stream":"stdout"}
+func CaptureStdout(logger Logger, component string) (stop func() error, err error) {
+	captureMu.Lock()
+	defer captureMu.Unlock()
+	if captureActive {
+		return nil, ErrCaptureActive
+	}
+
+	if component != "" {
+		logger = logger.ForInfra(component)
+	}
+
+	stdoutReader, stdoutWriter, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	stderrReader, stderrWriter, err := os.Pipe()
+	if err != nil {
+		_ = stdoutReader.Close()
+		_ = stdoutWriter.Close()
+		return nil, err
+	}
+
+	stdout, stderr := os.Stdout, os.Stderr
+	os.Stdout, os.Stderr = stdoutWriter, stderrWriter
+	captureActive = true
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		captureLines(stdoutReader, func(line string) {
+			logger.Info(line, String(streamFieldKey, "stdout"))
+		})
+	}()
+	go func() {
+		defer wg.Done()
+		captureLines(stderrReader, func(line string) {
+			logger.Error(line, String(streamFieldKey, "stderr"))
+		})
+	}()
+
+	var once sync.Once
+	stop = func() error {
+		var err error
+		once.Do(func() {
+			captureMu.Lock()
+			os.Stdout, os.Stderr = stdout, stderr
+			captureActive = false
+			captureMu.Unlock()
+
+			// Closing the write ends lets the readers drain the pipes and stop
+			err = errors.Join(stdoutWriter.Close(), stderrWriter.Close())
+			wg.Wait()
+			err = errors.Join(err, stdoutReader.Close(), stderrReader.Close())
+		})
+		return err
+	}
+	return stop, nil
+}
+
+// captureLines calls log with every line read from r until it is closed
+func captureLines(r io.Reader, log func(line string)) {
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadString('\n')
+		if line = strings.TrimRight(line, "\r\n"); line != "" {
+			log(line)
+		}
+		if err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package xlogger
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCaptureStdout tests logging lines written to os.Stdout and os.Stderr
+func TestCaptureStdout(t *testing.T) {
+	t.Run("should log stdout and stderr lines", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+		stdout, stderr := os.Stdout, os.Stderr
+
+		stop, err := CaptureStdout(logger, "legacy")
+		require.NoError(t, err)
+		fmt.Println("user created")
+		fmt.Printf("first\r\nsecond\n\n")
+		fmt.Fprint(os.Stderr, "connection refused")
+		require.NoError(t, stop())
+
+		assert.Same(t, stdout, os.Stdout)
+		assert.Same(t, stderr, os.Stderr)
+
+		entries := entriesWithMessage(t, output(), "user created")
+		require.Len(t, entries, 1)
+		assert.Equal(t, "info", entries[0]["level"])
+		assert.Equal(t, "stdout", entries[0]["stream"])
+		assert.Equal(t, "legacy", entries[0]["component"])
+		assert.Len(t, entriesWithMessage(t, output(), "first"), 1)
+		assert.Len(t, entriesWithMessage(t, output(), "second"), 1)
+
+		entries = entriesWithMessage(t, output(), "connection refused")
+		require.Len(t, entries, 1)
+		assert.Equal(t, "error", entries[0]["level"])
+		assert.Equal(t, "stderr", entries[0]["stream"])
+	})
+
+	t.Run("should allow one capture at a time", func(t *testing.T) {
+		logger, _ := newFileLogger(t)
+
+		stop, err := CaptureStdout(logger, "")
+		require.NoError(t, err)
+		_, err = CaptureStdout(logger, "")
+		assert.ErrorIs(t, err, ErrCaptureActive)
+
+		require.NoError(t, stop())
+		assert.NoError(t, stop())
+
+		stop, err = CaptureStdout(logger, "")
+		require.NoError(t, err)
+		assert.NoError(t, stop())
+	})
+}
@@ -0,0 +1,53 @@
+package xlogger
+
+import (
+	"go.uber.org/zap/zapcore"
+)
+
+// CheckedEntry is an entry that passed the level checks of a logger, see
+// Logger.Check. A nil CheckedEntry is valid and writes nothing.
+type CheckedEntry struct {
+	entry  *zapcore.CheckedEntry
+	logger *ZapLogger
+}
+
+// Write writes the entry with fields. It must be called at most once.
+func (ce *CheckedEntry) Write(fields ...Field) {
+	if ce == nil {
+		return
+	}
+	ce.entry.Write(ce.logger.zapFields(fields)...)
+}
+
+// Enabled reports whether entries of level may be written by this logger
+// or by a sink with a lower minimum level. It can report true for entries
+// later dropped by sampling or a per-name level; Check is exact.
+//
+// Example:
+//
+//	if logger.Enabled(zapcore.DebugLevel) {
+//	    logger.Debug("Cache state", xlogger.Any("entries", cache.Snapshot()))
+//	}
+func (l *ZapLogger) Enabled(level zapcore.Level) bool {
+	return l.logger.Core().Enabled(level)
+}
+
+// Check returns the entry of level and msg if it will be written, nil
+// otherwise, so expensive fields are only built for written entries. The
+// caller is that of Check. An entry checked before Reconfigure is written
+// to the previous outputs, which stay open until it is, for 10 seconds or
+// until Close at the longest; write checked entries right away, as an
+// entry written later is lost.
+//
+// Example:
+//
+//	if ce := logger.Check(zapcore.DebugLevel, "Request payload"); ce != nil {
+//	    ce.Write(xlogger.String("body", string(dumpBody(req))))
+//	}
+func (l *ZapLogger) Check(level zapcore.Level, msg string) *CheckedEntry {
+	ce := l.logger.Check(level, msg)
+	if ce == nil {
+		return nil
+	}
+	return &CheckedEntry{entry: ce, logger: l}
+}
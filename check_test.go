@@ -0,0 +1,76 @@
+package xlogger
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+// TestZapLogger_Enabled tests the level guard
+func TestZapLogger_Enabled(t *testing.T) {
+	t.Run("should follow the logger level", func(t *testing.T) {
+		logger, _ := newFileLogger(t)
+
+		assert.False(t, logger.Enabled(zapcore.DebugLevel))
+		assert.True(t, logger.Enabled(zapcore.InfoLevel))
+
+		logger.SetLevel(zapcore.DebugLevel)
+		assert.True(t, logger.Enabled(zapcore.DebugLevel))
+	})
+
+	t.Run("should account for sinks with a lower minimum level", func(t *testing.T) {
+		logger, err := NewZapLogger(NewLoggerConfig(
+			WithOutputPaths(filepath.Join(t.TempDir(), "app.log")),
+			WithSink(SinkConfig{Output: filepath.Join(t.TempDir(), "debug.log"), MinLevel: zapcore.DebugLevel}),
+		))
+		require.NoError(t, err)
+
+		assert.True(t, logger.Enabled(zapcore.DebugLevel))
+	})
+}
+
+// TestZapLogger_Check tests writing checked entries
+func TestZapLogger_Check(t *testing.T) {
+	t.Run("should return nil for disabled entries", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+
+		built := false
+		if ce := logger.Check(zapcore.DebugLevel, "payload"); ce != nil {
+			built = true
+			ce.Write(String("body", "large"))
+		}
+		assert.False(t, built)
+		assert.NotContains(t, output(), "payload")
+
+		var ce *CheckedEntry
+		assert.NotPanics(t, func() { ce.Write(String("body", "large")) })
+	})
+
+	t.Run("should write enabled entries with logger fields and tags", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+		child := logger.With(String("worker", "w1")).WithTags("batch")
+
+		ce := child.Check(zapcore.WarnLevel, "payload")
+		require.NotNil(t, ce)
+		ce.Write(String("body", "large"), Tags("slow"))
+
+		entries := entriesWithMessage(t, output(), "payload")
+		require.Len(t, entries, 1)
+		assert.Equal(t, "warn", entries[0]["level"])
+		assert.Equal(t, "large", entries[0]["body"])
+		assert.Equal(t, "w1", entries[0]["worker"])
+		assert.Equal(t, []interface{}{"batch", "slow"}, entries[0]["tags"])
+		assert.Contains(t, entries[0]["caller"], "check_test.go")
+	})
+
+	t.Run("should apply component levels of infrastructure loggers", func(t *testing.T) {
+		logger, _ := newFileLogger(t)
+		logger.SetComponentLevel("cache", zapcore.ErrorLevel)
+
+		assert.Nil(t, logger.ForInfra("cache").Check(zapcore.WarnLevel, "miss"))
+		assert.NotNil(t, logger.ForInfra("cache").Check(zapcore.ErrorLevel, "miss"))
+	})
+}
@@ -0,0 +1,46 @@
+// Command xlog converts binary xlogger entries back to JSON lines.
+//
+// Usage:
+//
+//	xlog decode -format protobuf < app.log.pb > app.log.json
+//	xlog decode -format msgpack app.log.msgpack
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/hotfixfirst/go-xlogger"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "xlog:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, in io.Reader, out io.Writer) error {
+	if len(args) == 0 || args[0] != "decode" {
+		return fmt.Errorf("usage: xlog decode -format protobuf|msgpack|cbor [file]")
+	}
+
+	flags := flag.NewFlagSet("decode", flag.ContinueOnError)
+	format := flags.String("format", string(xlogger.FormatProtobuf), "input format: protobuf, msgpack or cbor")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	if flags.NArg() > 0 {
+		file, err := os.Open(flags.Arg(0))
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		in = file
+	}
+
+	return xlogger.ConvertToJSON(xlogger.LogFormat(*format), in, out)
+}
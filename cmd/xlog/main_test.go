@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hotfixfirst/go-xlogger"
+)
+
+// writeEntries logs two entries in format to a temporary file and returns
+// its path
+func writeEntries(t *testing.T, format xlogger.LogFormat) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	logger, err := xlogger.NewZapLogger(xlogger.NewLoggerConfig(
+		xlogger.WithFormat(format),
+		xlogger.WithOutputPaths(path),
+	))
+	require.NoError(t, err)
+
+	logger.Info("order placed", xlogger.String("id", "o-1"))
+	logger.Warn("stock low", xlogger.Int("left", 3))
+	require.NoError(t, logger.Close(t.Context()))
+	return path
+}
+
+// TestRunFlags tests argument and flag handling of xlog
+func TestRunFlags(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{name: "should require a command", args: nil, wantErr: "usage: xlog decode"},
+		{name: "should reject unknown commands", args: []string{"encode"}, wantErr: "usage: xlog decode"},
+		{name: "should reject unknown flags", args: []string{"decode", "-level", "info"}, wantErr: "flag provided but not defined: -level"},
+		{name: "should require a format value", args: []string{"decode", "-format"}, wantErr: "flag needs an argument: -format"},
+		{name: "should reject text formats", args: []string{"decode", "-format", "json"}, wantErr: `format "json" is not a binary format`},
+		{name: "should reject unknown formats", args: []string{"decode", "-format", "xml"}, wantErr: `format "xml" is not a binary format`},
+		{name: "should report a missing file", args: []string{"decode", filepath.Join(t.TempDir(), "missing.log")}, wantErr: "no such file or directory"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			err := run(tt.args, strings.NewReader(""), &out)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+			assert.Zero(t, out.Len())
+		})
+	}
+
+	t.Run("should return flag.ErrHelp for -h", func(t *testing.T) {
+		assert.ErrorIs(t, run([]string{"decode", "-h"}, strings.NewReader(""), &bytes.Buffer{}), flag.ErrHelp)
+	})
+}
+
+// TestRunDecode tests conversion of binary input to JSON lines
+func TestRunDecode(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   xlogger.LogFormat
+		args     []string
+		fromFile bool
+	}{
+		{name: "should decode protobuf by default", format: xlogger.FormatProtobuf, args: []string{"decode"}},
+		{name: "should decode protobuf from stdin", format: xlogger.FormatProtobuf, args: []string{"decode", "-format", "protobuf"}},
+		{name: "should decode msgpack from stdin", format: xlogger.FormatMsgpack, args: []string{"decode", "-format", "msgpack"}},
+		{name: "should decode cbor from stdin", format: xlogger.FormatCBOR, args: []string{"decode", "-format", "cbor"}},
+		{name: "should decode protobuf from a file", format: xlogger.FormatProtobuf, args: []string{"decode", "-format", "protobuf"}, fromFile: true},
+		{name: "should decode msgpack from a file", format: xlogger.FormatMsgpack, args: []string{"decode", "-format", "msgpack"}, fromFile: true},
+		{name: "should decode cbor from a file", format: xlogger.FormatCBOR, args: []string{"decode", "-format", "cbor"}, fromFile: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeEntries(t, tt.format)
+			args := tt.args
+			in := strings.NewReader("")
+			if tt.fromFile {
+				args = append(args, path)
+			} else {
+				data, err := os.ReadFile(path)
+				require.NoError(t, err)
+				in = strings.NewReader(string(data))
+			}
+
+			var out bytes.Buffer
+			require.NoError(t, run(args, in, &out))
+
+			lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+			require.Len(t, lines, 2)
+
+			var first, second map[string]interface{}
+			require.NoError(t, json.Unmarshal([]byte(lines[0]), &first))
+			require.NoError(t, json.Unmarshal([]byte(lines[1]), &second))
+			assert.Equal(t, "info", first["level"])
+			assert.Equal(t, "order placed", first["message"])
+			assert.Equal(t, "o-1", first["id"])
+			assert.Equal(t, "warn", second["level"])
+			assert.Equal(t, float64(3), second["left"])
+		})
+	}
+
+	t.Run("should write nothing for empty input", func(t *testing.T) {
+		var out bytes.Buffer
+		require.NoError(t, run([]string{"decode"}, strings.NewReader(""), &out))
+		assert.Zero(t, out.Len())
+	})
+
+	t.Run("should report truncated input", func(t *testing.T) {
+		data, err := os.ReadFile(writeEntries(t, xlogger.FormatMsgpack))
+		require.NoError(t, err)
+
+		err = run([]string{"decode", "-format", "msgpack"}, bytes.NewReader(data[:len(data)-2]), &bytes.Buffer{})
+		assert.Error(t, err)
+	})
+}
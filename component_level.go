@@ -0,0 +1,110 @@
+package xlogger
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// componentLevels holds per-component level overrides. Reads are lock free;
+// writes replace the whole map.
+type componentLevels struct {
+	mu        sync.Mutex
+	overrides atomic.Pointer[map[string]zapcore.Level]
+}
+
+func newComponentLevels() *componentLevels {
+	c := &componentLevels{}
+	c.overrides.Store(&map[string]zapcore.Level{})
+	return c
+}
+
+func (c *componentLevels) get(component string) (zapcore.Level, bool) {
+	level, ok := (*c.overrides.Load())[component]
+	return level, ok
+}
+
+// all returns a copy of every override
+func (c *componentLevels) all() map[string]zapcore.Level {
+	current := *c.overrides.Load()
+	levels := make(map[string]zapcore.Level, len(current))
+	for component, level := range current {
+		levels[component] = level
+	}
+	return levels
+}
+
+func (c *componentLevels) update(fn func(map[string]zapcore.Level)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	levels := c.all()
+	fn(levels)
+	c.overrides.Store(&levels)
+}
+
+// componentLevelCore filters entries with the component's override, falling
+// back to the shared level. The wrapped infrastructure core accepts every
+// level so an override can be more verbose than the shared level.
+type componentLevelCore struct {
+	zapcore.Core
+	component string
+	global    zap.AtomicLevel
+	levels    *componentLevels
+	explainer *dropExplainer
+}
+
+// effectiveLevel returns the override of the component or the shared level
+func (c *componentLevelCore) effectiveLevel() zapcore.Level {
+	if c.component != "" {
+		if level, ok := c.levels.get(c.component); ok {
+			return level
+		}
+	}
+	return c.global.Level()
+}
+
+// Enabled implements zapcore.LevelEnabler. In explain mode every level is
+// enabled so Check can report dropped entries.
+func (c *componentLevelCore) Enabled(level zapcore.Level) bool {
+	return c.explainer != nil || c.effectiveLevel().Enabled(level)
+}
+
+// Level reports the effective level to zap.Logger.Level
+func (c *componentLevelCore) Level() zapcore.Level {
+	return c.effectiveLevel()
+}
+
+// With implements zapcore.Core
+func (c *componentLevelCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.Core = c.Core.With(fields)
+	return &clone
+}
+
+// Check implements zapcore.Core
+func (c *componentLevelCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if level := c.effectiveLevel(); !level.Enabled(ent.Level) {
+		if c.explainer != nil {
+			c.explainer.explain(ent, fmt.Sprintf(dropReasonLevel, ent.Level, level))
+		}
+		return ce
+	}
+	return c.Core.Check(ent, ce)
+}
+
+// forComponent returns a copy of core filtered with the component's level
+func forComponent(core zapcore.Core, component string) zapcore.Core {
+	switch c := core.(type) {
+	case *componentLevelCore:
+		clone := *c
+		clone.component = component
+		return &clone
+	case *reloadCore:
+		return c.forComponent(component)
+	}
+	return core
+}
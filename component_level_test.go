@@ -0,0 +1,83 @@
+package xlogger
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+// TestZapLogger_ComponentLevels tests per-component level overrides
+func TestZapLogger_ComponentLevels(t *testing.T) {
+	t.Run("should make one component more verbose", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+		db := logger.ForInfra("db")
+		cache := logger.ForInfra("cache")
+
+		logger.SetComponentLevel("db", zapcore.DebugLevel)
+		db.Debug("db debug")
+		cache.Debug("cache debug")
+		logger.Debug("base debug")
+
+		out := output()
+		assert.Contains(t, out, "db debug")
+		assert.NotContains(t, out, "cache debug")
+		assert.NotContains(t, out, "base debug")
+		assert.Equal(t, zapcore.DebugLevel, db.Level())
+		assert.Equal(t, zapcore.InfoLevel, cache.Level())
+	})
+
+	t.Run("should make one component quieter", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+
+		logger.SetComponentLevel("noisy", zapcore.ErrorLevel)
+		logger.ForInfra("noisy").Warn("noisy warn")
+		logger.ForInfra("other").Warn("other warn")
+
+		out := output()
+		assert.NotContains(t, out, "noisy warn")
+		assert.Contains(t, out, "other warn")
+	})
+
+	t.Run("should follow shared level after reset", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+		db := logger.ForInfra("db")
+
+		logger.SetComponentLevel("db", zapcore.ErrorLevel)
+		logger.ResetComponentLevel("db")
+		logger.SetLevel(zapcore.DebugLevel)
+		db.Debug("after reset")
+
+		assert.Contains(t, output(), "after reset")
+		assert.Empty(t, logger.ComponentLevels())
+	})
+
+	t.Run("should target gorm", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+
+		logger.SetComponentLevel("gorm", zapcore.ErrorLevel)
+		logger.ForGORM().Trace(context.Background(), time.Now().Add(-time.Second), func() (string, int64) {
+			return "SELECT 1", 1
+		}, nil)
+
+		assert.NotContains(t, output(), "SELECT 1")
+		assert.Equal(t, map[string]zapcore.Level{"gorm": zapcore.ErrorLevel}, logger.ComponentLevels())
+	})
+
+	t.Run("should explain component drops", func(t *testing.T) {
+		errPath := filepath.Join(t.TempDir(), "errors.log")
+		logger, _ := newFileLogger(t, WithExplainDrops(true), WithErrorOutputPaths(errPath))
+
+		logger.SetComponentLevel("db", zapcore.ErrorLevel)
+		logger.ForInfra("db").Warn("component drop")
+
+		data, err := os.ReadFile(errPath)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), "level warn is below the minimum level error")
+	})
+}
@@ -0,0 +1,138 @@
+package xlogger
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+	"go.uber.org/zap/zapcore"
+)
+
+// Compression represents the compression algorithm applied to log outputs.
+type Compression string
+
+const (
+	// CompressionNone writes outputs uncompressed.
+	CompressionNone Compression = ""
+	// CompressionGzip compresses outputs with gzip.
+	CompressionGzip Compression = "gzip"
+	// CompressionZstd compresses outputs with zstd.
+	CompressionZstd Compression = "zstd"
+)
+
+// String returns the string representation of Compression.
+func (c Compression) String() string {
+	return string(c)
+}
+
+// IsValid returns true if the compression is none, gzip or zstd.
+func (c Compression) IsValid() bool {
+	switch c.Normalize() {
+	case CompressionNone, CompressionGzip, CompressionZstd:
+		return true
+	default:
+		return false
+	}
+}
+
+// Normalize returns the normalized lowercase compression.
+func (c Compression) Normalize() Compression {
+	return Compression(strings.ToLower(string(c)))
+}
+
+// isTerminalPath reports whether path is the standard output or error,
+// which are never compressed by Config.Compression
+func isTerminalPath(path string) bool {
+	return path == "stdout" || path == "stderr"
+}
+
+// compressSink returns ws compressed with compression at level, or ws
+// itself with CompressionNone
+func compressSink(ws zapcore.WriteSyncer, compression Compression, level int) (zapcore.WriteSyncer, error) {
+	if compression.Normalize() == CompressionNone {
+		return ws, nil
+	}
+	return newCompressedSink(ws, compression, level)
+}
+
+// frameWriter is a compressor that can finish a frame and start a new one
+type frameWriter interface {
+	io.WriteCloser
+	Reset(w io.Writer)
+}
+
+// compressedSink compresses everything written to it. Each Sync finishes the
+// current gzip member or zstd frame, so the output is a concatenation of
+// complete frames and is decodable up to the last Sync even if the process dies.
+type compressedSink struct {
+	mu     sync.Mutex
+	inner  zapcore.WriteSyncer
+	writer frameWriter
+	open   bool
+}
+
+// newCompressedSink wraps ws with the given compression algorithm and level.
+// A level of 0 selects the algorithm default.
+func newCompressedSink(ws zapcore.WriteSyncer, compression Compression, level int) (*compressedSink, error) {
+	var writer frameWriter
+	switch compression.Normalize() {
+	case CompressionGzip:
+		if level == 0 {
+			level = gzip.DefaultCompression
+		}
+		gz, err := gzip.NewWriterLevel(ws, level)
+		if err != nil {
+			return nil, fmt.Errorf("invalid gzip level %d: %w", level, err)
+		}
+		writer = gz
+	case CompressionZstd:
+		opts := []zstd.EOption{zstd.WithEncoderConcurrency(1)}
+		if level != 0 {
+			opts = append(opts, zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(level)))
+		}
+		enc, err := zstd.NewWriter(ws, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("create zstd encoder: %w", err)
+		}
+		writer = enc
+	default:
+		return nil, fmt.Errorf("unsupported compression %q", compression)
+	}
+
+	// The first Write starts the first frame so idle outputs stay empty
+	return &compressedSink{inner: ws, writer: writer}, nil
+}
+
+// Write implements zapcore.WriteSyncer
+func (s *compressedSink) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.open {
+		s.writer.Reset(s.inner)
+		s.open = true
+	}
+	return s.writer.Write(p)
+}
+
+// Sync finishes the current frame and syncs the underlying output
+func (s *compressedSink) Sync() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.finishFrame(); err != nil {
+		return err
+	}
+	return s.inner.Sync()
+}
+
+func (s *compressedSink) finishFrame() error {
+	if !s.open {
+		return nil
+	}
+	s.open = false
+	return s.writer.Close()
+}
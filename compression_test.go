@@ -0,0 +1,163 @@
+package xlogger
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+// decompress reads every gzip member or zstd frame in data
+func decompress(t *testing.T, compression Compression, data []byte) string {
+	t.Helper()
+
+	var r io.Reader
+	switch compression {
+	case CompressionGzip:
+		gz, err := gzip.NewReader(bytes.NewReader(data))
+		require.NoError(t, err)
+		r = gz
+	case CompressionZstd:
+		dec, err := zstd.NewReader(bytes.NewReader(data))
+		require.NoError(t, err)
+		defer dec.Close()
+		r = dec
+	}
+
+	out, err := io.ReadAll(r)
+	require.NoError(t, err)
+	return string(out)
+}
+
+// TestCompression tests the Compression type
+func TestCompression(t *testing.T) {
+	t.Run("should validate compression", func(t *testing.T) {
+		assert.True(t, CompressionNone.IsValid())
+		assert.True(t, CompressionGzip.IsValid())
+		assert.True(t, Compression("ZSTD").IsValid())
+		assert.False(t, Compression("lz4").IsValid())
+	})
+
+	t.Run("should normalize compression", func(t *testing.T) {
+		assert.Equal(t, CompressionGzip, Compression("GZip").Normalize())
+		assert.Equal(t, "zstd", CompressionZstd.String())
+	})
+}
+
+// TestCompressedSink tests the compressing write syncer
+func TestCompressedSink(t *testing.T) {
+	for _, compression := range []Compression{CompressionGzip, CompressionZstd} {
+		t.Run("should round trip "+compression.String(), func(t *testing.T) {
+			var buf bytes.Buffer
+			sink, err := newCompressedSink(zapcore.AddSync(&buf), compression, 0)
+			require.NoError(t, err)
+
+			_, err = sink.Write([]byte("first\n"))
+			require.NoError(t, err)
+			require.NoError(t, sink.Sync())
+
+			_, err = sink.Write([]byte("second\n"))
+			require.NoError(t, err)
+			require.NoError(t, sink.Sync())
+
+			assert.Equal(t, "first\nsecond\n", decompress(t, compression, buf.Bytes()))
+		})
+	}
+
+	t.Run("should be decodable up to the last sync", func(t *testing.T) {
+		var buf bytes.Buffer
+		sink, err := newCompressedSink(zapcore.AddSync(&buf), CompressionZstd, 3)
+		require.NoError(t, err)
+
+		_, err = sink.Write([]byte("synced\n"))
+		require.NoError(t, err)
+		require.NoError(t, sink.Sync())
+		synced := append([]byte(nil), buf.Bytes()...)
+
+		_, err = sink.Write([]byte("pending\n"))
+		require.NoError(t, err)
+
+		assert.Equal(t, "synced\n", decompress(t, CompressionZstd, synced))
+	})
+
+	t.Run("should write nothing when idle", func(t *testing.T) {
+		var buf bytes.Buffer
+		sink, err := newCompressedSink(zapcore.AddSync(&buf), CompressionGzip, 0)
+		require.NoError(t, err)
+
+		require.NoError(t, sink.Sync())
+		assert.Zero(t, buf.Len())
+	})
+
+	t.Run("should reject invalid gzip level", func(t *testing.T) {
+		_, err := newCompressedSink(zapcore.AddSync(io.Discard), CompressionGzip, 42)
+		assert.Error(t, err)
+	})
+
+	t.Run("should reject unsupported compression", func(t *testing.T) {
+		_, err := newCompressedSink(zapcore.AddSync(io.Discard), "lz4", 0)
+		assert.Error(t, err)
+	})
+}
+
+// TestNewZapLoggerWithCompression tests logger creation with compressed output
+func TestNewZapLoggerWithCompression(t *testing.T) {
+	t.Run("should create logger with compression", func(t *testing.T) {
+		cfg := NewLoggerConfig(WithOutputPaths(filepath.Join(t.TempDir(), "app.log.gz")), WithCompression(CompressionGzip, 0))
+		logger, err := NewZapLogger(cfg)
+		require.NoError(t, err)
+		assert.NotNil(t, logger)
+	})
+
+	t.Run("should fail with invalid compression level", func(t *testing.T) {
+		cfg := NewLoggerConfig(WithOutputPaths(filepath.Join(t.TempDir(), "app.log.gz")), WithCompression(CompressionGzip, 42))
+		_, err := NewZapLogger(cfg)
+		assert.Error(t, err)
+	})
+
+	t.Run("should leave stdout and stderr plain", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "app.log.gz")
+		outputs, err := openOutputs(NewLoggerConfig(
+			WithOutputPaths("stdout", "stderr", path),
+			WithCompression(CompressionGzip, 0),
+		))
+		require.NoError(t, err)
+		defer outputs.close()
+
+		require.Len(t, outputs.sinks, 3)
+		for i, compressed := range []bool{false, false, true} {
+			_, ok := outputs.sinks[i].WriteSyncer.(*compressedSink)
+			assert.Equal(t, compressed, ok, outputs.sinks[i].status.Path)
+		}
+	})
+
+	t.Run("should compress sinks with their own compression", func(t *testing.T) {
+		dir := t.TempDir()
+		path, sinkPath := filepath.Join(dir, "app.log"), filepath.Join(dir, "archive.log.zst")
+		logger, err := NewZapLogger(NewLoggerConfig(
+			WithOutputPaths(path),
+			WithSink(SinkConfig{Output: sinkPath, Compression: CompressionZstd, CompressionLevel: 3}),
+		))
+		require.NoError(t, err)
+
+		logger.Info("archived entry")
+		require.NoError(t, logger.Sync())
+
+		assert.Contains(t, readFile(t, path), "archived entry")
+		data, err := os.ReadFile(sinkPath)
+		require.NoError(t, err)
+		assert.Contains(t, decompress(t, CompressionZstd, data), "archived entry")
+	})
+
+	t.Run("should ignore sinks with unknown compression", func(t *testing.T) {
+		cfg := NewLoggerConfig(WithSink(SinkConfig{Output: "stderr", Compression: "lz4"}))
+		assert.Empty(t, cfg.Sinks)
+	})
+}
@@ -2,9 +2,13 @@
 package xlogger
 
 import (
+	"regexp"
 	"strings"
+	"time"
 
+	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+	gormlogger "gorm.io/gorm/logger"
 )
 
 // LogFormat represents the log output format.
@@ -15,6 +19,20 @@ const (
 	FormatJSON LogFormat = "json"
 	// FormatText outputs logs in human-readable text format.
 	FormatText LogFormat = "text"
+	// FormatProtobuf outputs length-delimited protobuf Entry messages for binary sinks.
+	FormatProtobuf LogFormat = "protobuf"
+	// FormatMsgpack outputs one MessagePack map per entry.
+	FormatMsgpack LogFormat = "msgpack"
+	// FormatCBOR outputs one CBOR map per entry.
+	FormatCBOR LogFormat = "cbor"
+	// FormatGCP outputs JSON in the Google Cloud Logging structured format.
+	FormatGCP LogFormat = "gcp"
+	// FormatDatadog outputs JSON with the reserved attributes of Datadog.
+	FormatDatadog LogFormat = "datadog"
+	// FormatPretty outputs the message on one line and the fields as an
+	// indented, colorized block underneath in development mode, and text
+	// otherwise.
+	FormatPretty LogFormat = "pretty"
 )
 
 // String returns the string representation of LogFormat.
@@ -22,10 +40,14 @@ func (f LogFormat) String() string {
 	return string(f)
 }
 
-// IsValid returns true if the format is valid (json or text).
+// IsValid returns true if the format is valid (json, text, pretty, protobuf, msgpack, cbor, gcp or datadog).
 func (f LogFormat) IsValid() bool {
-	lower := strings.ToLower(string(f))
-	return lower == "json" || lower == "text"
+	switch f.Normalize() {
+	case FormatJSON, FormatText, FormatPretty, FormatProtobuf, FormatMsgpack, FormatCBOR, FormatGCP, FormatDatadog:
+		return true
+	default:
+		return false
+	}
 }
 
 // Normalize returns the normalized lowercase format.
@@ -33,15 +55,75 @@ func (f LogFormat) Normalize() LogFormat {
 	return LogFormat(strings.ToLower(string(f)))
 }
 
+// ServiceTags identify the service writing the entries, as unified service
+// tagging of Datadog expects. Empty values are left out.
+type ServiceTags struct {
+	Service string
+	Env     string
+	Version string
+}
+
+// fields returns the non-empty tags as fields
+func (t *ServiceTags) fields() []zap.Field {
+	if t == nil {
+		return nil
+	}
+	var fields []zap.Field
+	for _, tag := range [...]struct{ key, value string }{
+		{"service", t.Service},
+		{"env", t.Env},
+		{"version", t.Version},
+	} {
+		if tag.value != "" {
+			fields = append(fields, zap.String(tag.key, tag.value))
+		}
+	}
+	return fields
+}
+
 // Config represents logger configuration options.
 type Config struct {
-	Level             zapcore.Level // Minimum log level
-	Format            LogFormat     // Log format: FormatJSON or FormatText
-	Development       bool          // Development mode (pretty printing)
-	DisableCaller     bool          // Disable caller information
-	DisableStacktrace bool          // Disable stacktrace in errors
-	TimeFormat        string        // Time format (empty for default)
-	CallerSkip        int           // Number of caller frames to skip
+	Level             zapcore.Level       // Minimum log level
+	Format            LogFormat           // Log format: FormatJSON, FormatText, a platform preset or a binary format
+	Development       bool                // Development mode (pretty printing)
+	DisableCaller     bool                // Disable caller information
+	DisableStacktrace bool                // Disable stacktrace in errors
+	StacktraceLevel   *zapcore.Level      // Minimum level with a stack trace (nil for Error, or Warn in development)
+	TimeFormat        string              // Time format (empty for default)
+	CallerSkip        int                 // Number of caller frames to skip
+	Compression       Compression         // Compression of file OutputPaths: CompressionNone, CompressionGzip or CompressionZstd (stdout and stderr stay plain)
+	CompressionLevel  int                 // Compression level (0 for the algorithm default)
+	OutputPaths       []string            // Log destinations: "stdout", "stderr", file paths or registered sink URLs
+	ErrorOutputPaths  []string            // Destinations for internal logger errors
+	Shadow            *ShadowConfig       // Candidate format receiving a copy of every entry (nil to disable)
+	ExplainDrops      bool                // Explain suppressed entries once on the error outputs
+	Partition         *PartitionConfig    // Files partitioned by date and component, next to OutputPaths (nil to disable)
+	ErrorReporters    []ErrorReporter     // Receivers of Error-and-above entries, such as Sentry
+	Hooks             []Hook              // Callbacks receiving every enabled entry
+	Sampling          *SamplingConfig     // Sampling of repeated entries (nil for the first 100 per second, then every 100th)
+	DisableSampling   bool                // Log every entry, ignoring Sampling
+	ProducerTracking  int                 // Call sites counted for TopProducers (0 to disable)
+	DedupeWindow      time.Duration       // Collapse identical entries within the window into one (0 to disable)
+	DetectSecrets     bool                // Mask string fields that look like credentials
+	Redaction         *RedactionConfig    // Field keys and value patterns masked as "***" (nil to disable)
+	Processors        []Processor         // Functions rewriting the message and fields of every entry, such as scrubbers
+	ErrorChains       bool                // Add <key>.kind and <key>.cause fields with the root cause of wrapped errors
+	MessageOutputs    []string            // Destinations receiving only the message text of each entry
+	Sinks             []SinkConfig        // Additional outputs with their own format and minimum level
+	Audit             *AuditConfig        // Dedicated outputs of ForAudit entries (nil to disable)
+	RetentionHints    RetentionHints      // Retention stamped on entries of each level (nil to disable)
+	TraceScope        *TraceScope         // Trace state read for request and trace fields (nil for the package-level scope)
+	TraceConflict     TraceConflict       // Handling of trace fields passed with another value than the trace scope's (empty for TraceConflictPreferCaller)
+	TraceBackend      TraceBackend        // Source of trace fields: GLSBackend or ContextBackend (empty for GLSBackend)
+	BaggagePrefix     string              // Prefix of the keys of RunWithTraceContext baggage fields (empty for "baggage.")
+	IDGenerator       IDGenerator         // Generator of the request IDs of this logger's middleware and groups (nil for the process-wide one)
+	AfterClose        AfterClosePolicy    // Handling of entries logged after Close (empty for AfterCloseDrop)
+	EntryShape        *EntryLimits        // Field count and size histograms per component, warning on wider entries (nil to disable)
+	ServiceTags       *ServiceTags        // Service, env and version fields added to every entry (nil to disable)
+	HostFields        bool                // Add hostname, pid, go_version, app_version and commit to every entry
+	StaticFields      []Field             // Deploy-time constants added to every entry, such as region and cluster
+	GORMLevel         gormlogger.LogLevel // Level of ForGORM and ForGORMNamed loggers, logging statements at Info (0 to map it from Level)
+	ConsoleStyle      ConsoleStyle        // Levels of FormatText: ConsoleStyleEmoji, ConsoleStyleColor or ConsoleStylePlain (empty for emoji)
 }
 
 // DefaultLoggerConfig returns default logger configuration with INFO level and JSON format.
@@ -53,6 +135,9 @@ type Config struct {
 //   - DisableCaller: false
 //   - DisableStacktrace: true
 //   - CallerSkip: 1
+//   - Sampling: first 100 entries per message each second, then every 100th
+//   - OutputPaths: ["stdout"]
+//   - ErrorOutputPaths: ["stderr"]
 //
 // Example:
 //
@@ -67,6 +152,8 @@ func DefaultLoggerConfig() *Config {
 		DisableStacktrace: true,
 		TimeFormat:        "",
 		CallerSkip:        1,
+		OutputPaths:       []string{"stdout"},
+		ErrorOutputPaths:  []string{"stderr"},
 	}
 }
 
@@ -129,6 +216,21 @@ func (c *Config) IsTextFormat() bool {
 	return c.Format.Normalize() == FormatText
 }
 
+// IsProtobufFormat returns true if the format is protobuf.
+func (c *Config) IsProtobufFormat() bool {
+	return c.Format.Normalize() == FormatProtobuf
+}
+
+// IsBinaryFormat returns true if the format is protobuf, msgpack or cbor.
+func (c *Config) IsBinaryFormat() bool {
+	switch c.Format.Normalize() {
+	case FormatProtobuf, FormatMsgpack, FormatCBOR:
+		return true
+	default:
+		return false
+	}
+}
+
 // IsDevelopment returns true if development mode is enabled.
 func (c *Config) IsDevelopment() bool {
 	return c.Development
@@ -189,7 +291,26 @@ func WithFormat(format LogFormat) Option {
 	}
 }
 
-// WithDevelopment enables or disables development mode.
+// WithConsoleStyle sets how FormatText writes levels: ConsoleStyleEmoji
+// (the default), ConsoleStyleColor for ANSI-colored uppercase levels or
+// ConsoleStylePlain for plain uppercase levels. Unknown styles are ignored.
+//
+// Example:
+//
+//	cfg := xlogger.NewLoggerConfig(
+//	    xlogger.WithFormat(xlogger.FormatText),
+//	    xlogger.WithConsoleStyle(xlogger.ConsoleStylePlain),
+//	)
+func WithConsoleStyle(style ConsoleStyle) Option {
+	return func(c *Config) {
+		if style.isValid() {
+			c.ConsoleStyle = style.Normalize()
+		}
+	}
+}
+
+// WithDevelopment enables or disables development mode. FormatPretty
+// prints its multi-line output in development mode only.
 //
 // Example:
 //
@@ -228,6 +349,24 @@ func WithDisableStacktrace(disable bool) Option {
 	}
 }
 
+// WithStacktraceLevel attaches stack traces to entries at level and above,
+// independently of the log level, and enables stack traces. Without it,
+// stacks are attached at Error and above, or Warn and above in development
+// mode. Infrastructure loggers never attach stacks.
+//
+// Example:
+//
+//	cfg := xlogger.NewLoggerConfig(
+//	    xlogger.WithLevel(zapcore.DebugLevel),
+//	    xlogger.WithStacktraceLevel(zapcore.PanicLevel),
+//	)
+func WithStacktraceLevel(level zapcore.Level) Option {
+	return func(c *Config) {
+		c.StacktraceLevel = &level
+		c.DisableStacktrace = false
+	}
+}
+
 // WithTimeFormat sets the time format.
 //
 // Example:
@@ -253,3 +392,621 @@ func WithCallerSkip(skip int) Option {
 		c.CallerSkip = skip
 	}
 }
+
+// WithCompression compresses the file OutputPaths with gzip or zstd at the
+// given level; stdout and stderr are never compressed. A level of 0 selects
+// the algorithm default. Each Sync finishes the current frame, so call Sync
+// before exit to flush buffered entries. Unsupported algorithms are ignored.
+// Sinks are compressed with SinkConfig.Compression instead.
+//
+// Example:
+//
+//	cfg := xlogger.NewLoggerConfig(
+//	    xlogger.WithCompression(xlogger.CompressionZstd, 3),
+//	)
+func WithCompression(algo Compression, level int) Option {
+	return func(c *Config) {
+		if algo.IsValid() {
+			c.Compression = algo.Normalize()
+			c.CompressionLevel = level
+		}
+	}
+}
+
+// WithOutputPaths sets where logs are written. Paths may be "stdout", "stderr",
+// file paths or URLs of sinks registered with zap.RegisterSink.
+// Calling it without paths keeps the current destinations.
+//
+// Example:
+//
+//	cfg := xlogger.NewLoggerConfig(
+//	    xlogger.WithOutputPaths("stdout", "/var/log/app.log"),
+//	)
+func WithOutputPaths(paths ...string) Option {
+	return func(c *Config) {
+		if len(paths) > 0 {
+			c.OutputPaths = paths
+		}
+	}
+}
+
+// WithErrorOutputPaths sets where internal logger errors are written.
+// Calling it without paths keeps the current destinations.
+//
+// Example:
+//
+//	cfg := xlogger.NewLoggerConfig(
+//	    xlogger.WithErrorOutputPaths("/var/log/app-errors.log"),
+//	)
+func WithErrorOutputPaths(paths ...string) Option {
+	return func(c *Config) {
+		if len(paths) > 0 {
+			c.ErrorOutputPaths = paths
+		}
+	}
+}
+
+// WithShadow sends a copy of every entry through a candidate format and
+// destinations, so a format migration can be compared with ZapLogger.ShadowReport
+// before switching. Without paths the shadow output is only measured.
+// Invalid formats are ignored.
+//
+// Example:
+//
+//	cfg := xlogger.NewLoggerConfig(
+//	    xlogger.WithShadow(xlogger.FormatMsgpack, "/var/log/app.msgpack"),
+//	)
+func WithShadow(format LogFormat, paths ...string) Option {
+	return func(c *Config) {
+		if format.IsValid() {
+			c.Shadow = &ShadowConfig{Format: format.Normalize(), OutputPaths: paths}
+		}
+	}
+}
+
+// WithExplainDrops enables a diagnostics mode that explains, once per distinct
+// entry, why an entry was suppressed (level filtered or sampled). Explanations
+// go to the error output paths. Intended for development, as every log call
+// reaches the core even when its level is disabled.
+//
+// Example:
+//
+//	cfg := xlogger.NewLoggerConfig(
+//	    xlogger.WithExplainDrops(true),
+//	)
+func WithExplainDrops(enable bool) Option {
+	return func(c *Config) {
+		c.ExplainDrops = enable
+	}
+}
+
+// WithPartitionedOutput also writes entries to files partitioned by date and
+// component, rendered from a template with {date} (YYYY-MM-DD) and {component}
+// placeholders. Directories are created as needed. Each partition file is
+// rotated once it reaches maxSize bytes, and partition files not modified for
+// maxAge are removed; 0 disables either. Entries without a component go to
+// the "app" partition. An empty template is ignored.
+//
+// Example:
+//
+//	cfg := xlogger.NewLoggerConfig(
+//	    xlogger.WithPartitionedOutput("logs/{date}/{component}.log", 100<<20, 14*24*time.Hour),
+//	)
+func WithPartitionedOutput(template string, maxSize int64, maxAge time.Duration) Option {
+	return func(c *Config) {
+		if template != "" {
+			c.Partition = &PartitionConfig{PathTemplate: template, MaxSize: maxSize, MaxAge: maxAge}
+		}
+	}
+}
+
+// WithErrorReporter forwards Error, Panic and Fatal entries of every logger,
+// including infrastructure and GORM loggers, to reporter with their fields,
+// stack and trace IDs. It can be given several times; nil is ignored.
+//
+// Example:
+//
+//	cfg := xlogger.NewLoggerConfig(
+//	    xlogger.WithErrorReporter(xloggersentry.New(nil)),
+//	)
+func WithErrorReporter(reporter ErrorReporter) Option {
+	return func(c *Config) {
+		if reporter != nil {
+			c.ErrorReporters = append(c.ErrorReporters, reporter)
+		}
+	}
+}
+
+// WithHook calls hook with the level, message, time, caller and fields of
+// every entry that passes the level filter, on every logger including
+// infrastructure and GORM loggers. It can be given several times; nil is
+// ignored.
+//
+// Example:
+//
+//	cfg := xlogger.NewLoggerConfig(
+//	    xlogger.WithHook(func(entry xlogger.Entry) error {
+//	        entriesTotal.WithLabelValues(entry.Level.String()).Inc()
+//	        return nil
+//	    }),
+//	)
+func WithHook(hook Hook) Option {
+	return func(c *Config) {
+		if hook != nil {
+			c.Hooks = append(c.Hooks, hook)
+		}
+	}
+}
+
+// WithSampling logs, per message and level, the first initial entries of each
+// second and then every thereafter-th, replacing the default of 100 and 100.
+// A thereafter of 0 drops the rest. Per-level overrides are kept, and
+// sampling is enabled again after WithSamplingDisabled. Negative values are
+// ignored.
+//
+// Example:
+//
+//	cfg := xlogger.NewLoggerConfig(
+//	    xlogger.WithSampling(1000, 10),
+//	)
+func WithSampling(initial, thereafter int) Option {
+	return func(c *Config) {
+		if initial < 0 || thereafter < 0 {
+			return
+		}
+		sampling := c.withSamplingConfig()
+		sampling.Initial = initial
+		sampling.Thereafter = thereafter
+		c.DisableSampling = false
+	}
+}
+
+// WithLevelSampling overrides the sampling of one level. An initial of 0
+// logs every entry of the level, for example to never drop debug entries
+// while other levels keep the default sampling. Negative values are ignored.
+//
+// Example:
+//
+//	cfg := xlogger.NewLoggerConfig(
+//	    xlogger.WithLevelSampling(zapcore.DebugLevel, 0, 0),
+//	    xlogger.WithLevelSampling(zapcore.InfoLevel, 500, 50),
+//	)
+func WithLevelSampling(level zapcore.Level, initial, thereafter int) Option {
+	return func(c *Config) {
+		if initial < 0 || thereafter < 0 {
+			return
+		}
+		sampling := c.withSamplingConfig()
+		if sampling.Levels == nil {
+			sampling.Levels = make(map[zapcore.Level]LevelSampling)
+		}
+		sampling.Levels[level] = LevelSampling{Initial: initial, Thereafter: thereafter}
+	}
+}
+
+// WithSamplingDisabled logs every entry, including per-level overrides.
+//
+// Example:
+//
+//	cfg := xlogger.NewLoggerConfig(
+//	    xlogger.WithSamplingDisabled(),
+//	)
+func WithSamplingDisabled() Option {
+	return func(c *Config) {
+		c.DisableSampling = true
+	}
+}
+
+// WithProducerTracking counts emitted entries per call site (caller file:line)
+// in a table bounded to size call sites, so the noisiest log statements can
+// be listed with ZapLogger.TopProducers or ProducersHandler. Entries without
+// caller information, such as those of infrastructure loggers, are not
+// counted. A non-positive size is ignored.
+//
+// Example:
+//
+//	cfg := xlogger.NewLoggerConfig(
+//	    xlogger.WithProducerTracking(1000),
+//	)
+func WithProducerTracking(size int) Option {
+	return func(c *Config) {
+		if size > 0 {
+			c.ProducerTracking = size
+		}
+	}
+}
+
+// WithDedupe protects log pipelines from storms of identical entries. The
+// first entry with a given level, message and fields is written, identical
+// entries within window are suppressed, and when the window closes a copy of
+// the last one is written with a "repeated" field counting them, without
+// waiting for a later entry. Summaries of open windows are written by
+// Logger.Sync. Panic and Fatal entries are never suppressed. A non-positive window is ignored.
+//
+// Example:
+//
+//	cfg := xlogger.NewLoggerConfig(
+//	    xlogger.WithDedupe(10 * time.Second),
+//	)
+func WithDedupe(window time.Duration) Option {
+	return func(c *Config) {
+		if window > 0 {
+			c.DedupeWindow = window
+		}
+	}
+}
+
+// WithSecretDetection masks string fields that look like credentials, as a
+// safety net against accidental logging. Tokens with well-known prefixes
+// (AWS "AKIA", GitHub "ghp_", Slack "xoxb-", Stripe "sk_live_", JWTs and
+// PEM private keys) and long high-entropy tokens are replaced with
+// "[MASKED]", keeping known prefixes. ZapLogger.MaskedSecrets counts them.
+// Messages and non-string fields are not scanned.
+//
+// Example:
+//
+//	cfg := xlogger.NewLoggerConfig(
+//	    xlogger.WithSecretDetection(true),
+//	)
+func WithSecretDetection(enable bool) Option {
+	return func(c *Config) {
+		c.DetectSecrets = enable
+	}
+}
+
+// WithRedaction masks sensitive data in every output, sink, hook and error
+// reporter: values of fields whose key is in keys (case-insensitive) are
+// replaced with "***", as are matches of patterns in messages and string,
+// byte string, stringer and error fields. Values nested in objects and maps
+// are masked as they are encoded. DefaultRedactionKeys, CreditCardPattern
+// and EmailPattern cover common cases. Repeated calls add keys and patterns.
+//
+// Example:
+//
+//	cfg := xlogger.NewLoggerConfig(
+//	    xlogger.WithRedaction(
+//	        append(xlogger.DefaultRedactionKeys, "ssn"),
+//	        []*regexp.Regexp{xlogger.CreditCardPattern, xlogger.EmailPattern},
+//	    ),
+//	)
+func WithRedaction(keys []string, patterns []*regexp.Regexp) Option {
+	return func(c *Config) {
+		if len(keys) == 0 && len(patterns) == 0 {
+			return
+		}
+		if c.Redaction == nil {
+			c.Redaction = &RedactionConfig{}
+		}
+		c.Redaction.Keys = append(c.Redaction.Keys, keys...)
+		c.Redaction.Patterns = append(c.Redaction.Patterns, patterns...)
+	}
+}
+
+// WithProcessor adds processors rewriting the message and fields of every
+// entry before it reaches the outputs, sinks, hooks and error reporters.
+// Processors run in the order added, before redaction. ScrubEmails,
+// ScrubIPs and ScrubNationalIDs scrub personal data and NewScrubber builds
+// scrubbers for other patterns. Fields of processed entries are written in
+// key order.
+//
+// Example:
+//
+//	cfg := xlogger.NewLoggerConfig(
+//	    xlogger.WithProcessor(xlogger.ScrubEmails(), xlogger.ScrubIPs()),
+//	    xlogger.WithProcessor(func(entry *xlogger.Entry) {
+//	        delete(entry.Fields, "debug_payload")
+//	    }),
+//	)
+func WithProcessor(processors ...Processor) Option {
+	return func(c *Config) {
+		for _, p := range processors {
+			if p != nil {
+				c.Processors = append(c.Processors, p)
+			}
+		}
+	}
+}
+
+// WithErrorChains follows the errors.Unwrap chain of every error field and
+// adds the type of the innermost error as "<key>.kind" and its message as
+// "<key>.cause", so errors wrapped with fmt.Errorf("...: %w", err) can be
+// grouped by their root cause. The cause is left out when it reads like the
+// error itself.
+//
+// Example:
+//
+//	cfg := xlogger.NewLoggerConfig(xlogger.WithErrorChains())
+//	logger.Error("load failed", xlogger.Error(fmt.Errorf("read config: %w", err)))
+//	// "error":"read config: open app.yaml: no such file or directory",
+//	// "error.kind":"syscall.Errno","error.cause":"no such file or directory"
+func WithErrorChains() Option {
+	return func(c *Config) {
+		c.ErrorChains = true
+	}
+}
+
+// WithMessageOutput also writes the bare message text of each entry, one per
+// line and without level, time or fields, to paths. It suits consumers that
+// want human text while the other outputs get structured entries, such as a
+// legacy line-based collector or a terminal status pane. Calling it without
+// paths is ignored.
+//
+// Example:
+//
+//	cfg := xlogger.NewLoggerConfig(
+//	    xlogger.WithMessageOutput("/var/log/app-messages.log"),
+//	)
+func WithMessageOutput(paths ...string) Option {
+	return func(c *Config) {
+		if len(paths) > 0 {
+			c.MessageOutputs = paths
+		}
+	}
+}
+
+// WithSink adds an output with its own format and minimum level next to
+// OutputPaths, for example text on the console at Info plus JSON in a file
+// at Debug. The minimum level applies instead of the logger level, while
+// infrastructure entries still follow the logger and component levels.
+// Sinks are compressed with their own Compression and appear in SinkStatus.
+// It can be given several times; sinks without an output or with an invalid
+// format or compression are ignored.
+//
+// Example:
+//
+//	cfg := xlogger.NewLoggerConfig(
+//	    xlogger.WithFormat(xlogger.FormatText),
+//	    xlogger.WithSink(xlogger.SinkConfig{Output: "/var/log/app.json", Format: xlogger.FormatJSON, MinLevel: zapcore.DebugLevel}),
+//	    xlogger.WithSink(xlogger.SinkConfig{Output: "stderr", MinLevel: zapcore.ErrorLevel}),
+//	)
+func WithSink(sink SinkConfig) Option {
+	return func(c *Config) {
+		if sink.Output == "" || (sink.Format != "" && !sink.Format.IsValid()) || !sink.Compression.IsValid() {
+			return
+		}
+		sink.Format = sink.Format.Normalize()
+		sink.Compression = sink.Compression.Normalize()
+		c.Sinks = append(c.Sinks, sink)
+	}
+}
+
+// WithAudit writes the entries of ForAudit to paths, in format or in the
+// logger format when empty. Audit outputs are synced after every entry and
+// appear in SinkStatus. Calling it again replaces the audit outputs; it is
+// ignored without paths or with an invalid format.
+//
+// Example:
+//
+//	cfg := xlogger.NewLoggerConfig(
+//	    xlogger.WithAudit(xlogger.FormatJSON, "/var/log/app/audit.log"),
+//	)
+func WithAudit(format LogFormat, paths ...string) Option {
+	return func(c *Config) {
+		if len(paths) == 0 || (format != "" && !format.IsValid()) {
+			return
+		}
+		c.Audit = &AuditConfig{OutputPaths: paths, Format: format.Normalize()}
+	}
+}
+
+// WithRetentionHints stamps entries with a "retention" field holding the
+// duration given for their level, for lifecycle policies of the storage
+// layer, so debug entries can expire earlier than errors kept for audits.
+// Levels without a hint get no field. Calling it again replaces the hints.
+//
+// Example:
+//
+//	cfg := xlogger.NewLoggerConfig(
+//	    xlogger.WithRetentionHints(map[zapcore.Level]time.Duration{
+//	        zapcore.DebugLevel: 24 * time.Hour,
+//	        zapcore.InfoLevel:  7 * 24 * time.Hour,
+//	        zapcore.ErrorLevel: 365 * 24 * time.Hour,
+//	    }),
+//	)
+func WithRetentionHints(hints map[zapcore.Level]time.Duration) Option {
+	return func(c *Config) {
+		c.RetentionHints = nil
+		for level, retention := range hints {
+			if c.RetentionHints == nil {
+				c.RetentionHints = make(RetentionHints, len(hints))
+			}
+			c.RetentionHints[level] = retention
+		}
+	}
+}
+
+// WithTraceScope makes the logger read request, correlation and W3C trace
+// identifiers from scope instead of the package-level trace functions, so a
+// plugin or test can keep its trace state isolated from the host.
+//
+// Example:
+//
+//	scope := xlogger.NewTraceScope()
+//	logger, err := xlogger.NewZapLogger(xlogger.NewLoggerConfig(
+//	    xlogger.WithTraceScope(scope),
+//	))
+//	_ = scope.RunWithTrace("req-123", "corr-456", func() error {
+//	    logger.Info("handled") // carries request_id and correlation_id
+//	    return nil
+//	})
+func WithTraceScope(scope *TraceScope) Option {
+	return func(c *Config) {
+		c.TraceScope = scope
+	}
+}
+
+// WithTraceConflict sets what is logged when an entry carries a request,
+// correlation or W3C trace field whose value differs from the trace scope's:
+// TraceConflictPreferCaller keeps the caller's value (the default),
+// TraceConflictPreferContext the scope's, TraceConflictEmitBoth adds the
+// scope's under the key with the "_context" suffix and TraceConflictWarn
+// keeps the caller's and logs a "trace field mismatch" Warn, as mismatches
+// usually point to IDs not propagated correctly. Unknown strategies are
+// ignored.
+//
+// Example:
+//
+//	cfg := xlogger.NewLoggerConfig(
+//	    xlogger.WithTraceConflict(xlogger.TraceConflictWarn),
+//	)
+func WithTraceConflict(conflict TraceConflict) Option {
+	return func(c *Config) {
+		if conflict.isValid() {
+			c.TraceConflict = conflict.Normalize()
+		}
+	}
+}
+
+// WithTraceBackend sets where loggers read trace fields from: GLSBackend
+// (the default) reads the goroutine-local state of RunWithTrace and the
+// other runs, ContextBackend only the contexts passed to Logger.WithContext.
+// Build with the xlogger_nogls tag to leave github.com/jtolds/gls out of
+// the binary; goroutine-local state is then always empty, RunWithTrace and
+// the other runs add no trace fields, and GoroutineLocalTrace is false.
+// Unknown backends are ignored.
+//
+// Example:
+//
+//	cfg := xlogger.NewLoggerConfig(
+//	    xlogger.WithTraceBackend(xlogger.ContextBackend),
+//	)
+//	logger.WithContext(ctx).Info("Charging card")
+func WithTraceBackend(backend TraceBackend) Option {
+	return func(c *Config) {
+		if backend.isValid() {
+			c.TraceBackend = backend.Normalize()
+		}
+	}
+}
+
+// WithBaggagePrefix sets the prefix of the keys of the fields logged for
+// RunWithTraceContext baggage, "baggage." by default.
+//
+// Example:
+//
+//	cfg := xlogger.NewLoggerConfig(
+//	    xlogger.WithBaggagePrefix("ctx."), // {"ctx.tenant":"acme"}
+//	)
+func WithBaggagePrefix(prefix string) Option {
+	return func(c *Config) {
+		c.BaggagePrefix = prefix
+	}
+}
+
+// WithIDGenerator sets the generator of the request IDs of the logger,
+// used by HTTPMiddleware and groups: NewHexID (the default), NewUUIDv7,
+// NewULID or a custom function. It only applies to this logger and those
+// derived from it; SetIDGenerator sets the generator of the package-level
+// NewRequestID, NewCorrelationID and RunWithNewTrace.
+//
+// Example:
+//
+//	cfg := xlogger.NewLoggerConfig(
+//	    xlogger.WithIDGenerator(xlogger.NewUUIDv7),
+//	)
+func WithIDGenerator(gen IDGenerator) Option {
+	return func(c *Config) {
+		c.IDGenerator = gen
+	}
+}
+
+// WithAfterClosePolicy sets what happens to entries logged after Close,
+// such as late calls from goroutines still shutting down: AfterCloseDrop
+// discards them (the default), AfterCloseStderr writes them to stderr and
+// AfterClosePanicInDev panics in development mode to surface the bug and
+// discards them in production. Unknown policies are ignored.
+//
+// Example:
+//
+//	cfg := xlogger.NewLoggerConfig(
+//	    xlogger.WithAfterClosePolicy(xlogger.AfterCloseStderr),
+//	)
+func WithAfterClosePolicy(policy AfterClosePolicy) Option {
+	return func(c *Config) {
+		if policy.isValid() {
+			c.AfterClose = policy.Normalize()
+		}
+	}
+}
+
+// WithEntryShape measures the field count and encoded size of every entry
+// per component, reported by EntryShapes and EntryShapeHandler, and logs one
+// "wide log entry" Warn per call site whose entries exceed maxFields fields
+// or maxBytes bytes. Zero disables a limit; metrics are collected either way.
+//
+// Example:
+//
+//	cfg := xlogger.NewLoggerConfig(
+//	    xlogger.WithEntryShape(32, 8<<10), // warn above 32 fields or 8 KiB
+//	)
+func WithEntryShape(maxFields, maxBytes int) Option {
+	return func(c *Config) {
+		c.EntryShape = &EntryLimits{MaxFields: max(maxFields, 0), MaxBytes: max(maxBytes, 0)}
+	}
+}
+
+// WithServiceTags adds service, env and version fields to every entry of
+// the logger and its infrastructure loggers, the reserved attributes of
+// Datadog unified service tagging. Empty values are left out.
+//
+// Example:
+//
+//	cfg := xlogger.NewLoggerConfig(
+//	    xlogger.WithFormat(xlogger.FormatDatadog),
+//	    xlogger.WithServiceTags("orders", "prod", "1.4.2"),
+//	)
+func WithServiceTags(service, env, version string) Option {
+	return func(c *Config) {
+		c.ServiceTags = &ServiceTags{Service: service, Env: env, Version: version}
+	}
+}
+
+// WithHostFields adds the hostname, pid, go_version and, when the binary
+// embeds them, the app_version and commit of debug.ReadBuildInfo to every
+// entry of the logger and its infrastructure loggers.
+//
+// Example:
+//
+//	cfg := xlogger.NewLoggerConfig(xlogger.WithHostFields())
+func WithHostFields() Option {
+	return func(c *Config) {
+		c.HostFields = true
+	}
+}
+
+// WithStaticFields adds fields to every entry of the logger and its
+// infrastructure loggers, for deploy-time constants. Fields of repeated
+// calls are added together.
+//
+// Example:
+//
+//	cfg := xlogger.NewLoggerConfig(
+//	    xlogger.WithStaticFields(
+//	        xlogger.String("region", os.Getenv("REGION")),
+//	        xlogger.String("cluster", os.Getenv("CLUSTER")),
+//	    ),
+//	)
+func WithStaticFields(fields ...Field) Option {
+	return func(c *Config) {
+		c.StaticFields = append(c.StaticFields, fields...)
+	}
+}
+
+// WithGORMLevel sets the GORM log level of ForGORM and ForGORMNamed loggers
+// instead of mapping it from the logger level, and logs the statements it
+// selects at Info like NewGORMLoggerWithLevel. gormlogger.Info logs all SQL
+// while the application logs at Info. Unknown levels are ignored.
+//
+// Example:
+//
+//	cfg := xlogger.NewLoggerConfig(
+//	    xlogger.WithLevel(zapcore.InfoLevel),
+//	    xlogger.WithGORMLevel(gormlogger.Info),
+//	)
+func WithGORMLevel(level gormlogger.LogLevel) Option {
+	return func(c *Config) {
+		if level >= gormlogger.Silent && level <= gormlogger.Info {
+			c.GORMLevel = level
+		}
+	}
+}
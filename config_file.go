@@ -0,0 +1,402 @@
+package xlogger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/yaml.v3"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// configFile is the representation of Config in YAML and JSON files. Keys
+// missing from a file are nil and keep the value already in the Config.
+type configFile struct {
+	Level             *string             `json:"level" yaml:"level"`
+	Format            *string             `json:"format" yaml:"format"`
+	Development       *bool               `json:"development" yaml:"development"`
+	DisableCaller     *bool               `json:"disable_caller" yaml:"disable_caller"`
+	DisableStacktrace *bool               `json:"disable_stacktrace" yaml:"disable_stacktrace"`
+	StacktraceLevel   *string             `json:"stacktrace_level" yaml:"stacktrace_level"`
+	TimeFormat        *string             `json:"time_format" yaml:"time_format"`
+	CallerSkip        *int                `json:"caller_skip" yaml:"caller_skip"`
+	Compression       *string             `json:"compression" yaml:"compression"`
+	CompressionLevel  *int                `json:"compression_level" yaml:"compression_level"`
+	OutputPaths       []string            `json:"output_paths" yaml:"output_paths"`
+	ErrorOutputPaths  []string            `json:"error_output_paths" yaml:"error_output_paths"`
+	Shadow            *shadowSection      `json:"shadow" yaml:"shadow"`
+	ExplainDrops      *bool               `json:"explain_drops" yaml:"explain_drops"`
+	Partition         *partitionSection   `json:"partition" yaml:"partition"`
+	Sampling          *samplingSection    `json:"sampling" yaml:"sampling"`
+	DisableSampling   *bool               `json:"disable_sampling" yaml:"disable_sampling"`
+	ProducerTracking  *int                `json:"producer_tracking" yaml:"producer_tracking"`
+	DedupeWindow      *string             `json:"dedupe_window" yaml:"dedupe_window"`
+	DetectSecrets     *bool               `json:"detect_secrets" yaml:"detect_secrets"`
+	Redaction         *redactionSection   `json:"redaction" yaml:"redaction"`
+	ErrorChains       *bool               `json:"error_chains" yaml:"error_chains"`
+	MessageOutputs    []string            `json:"message_outputs" yaml:"message_outputs"`
+	Sinks             []sinkSection       `json:"sinks" yaml:"sinks"`
+	Audit             *auditSection       `json:"audit" yaml:"audit"`
+	RetentionHints    map[string]string   `json:"retention_hints" yaml:"retention_hints"`
+	TraceConflict     *string             `json:"trace_conflict" yaml:"trace_conflict"`
+	TraceBackend      *string             `json:"trace_backend" yaml:"trace_backend"`
+	BaggagePrefix     *string             `json:"baggage_prefix" yaml:"baggage_prefix"`
+	IDGenerator       *string             `json:"id_generator" yaml:"id_generator"`
+	AfterClose        *string             `json:"after_close" yaml:"after_close"`
+	EntryShape        *entryShapeSection  `json:"entry_shape" yaml:"entry_shape"`
+	ServiceTags       *serviceTagsSection `json:"service_tags" yaml:"service_tags"`
+	HostFields        *bool               `json:"host_fields" yaml:"host_fields"`
+	StaticFields      map[string]string   `json:"static_fields" yaml:"static_fields"`
+	GORMLevel         *string             `json:"gorm_level" yaml:"gorm_level"`
+	ConsoleStyle      *string             `json:"console_style" yaml:"console_style"`
+}
+
+type shadowSection struct {
+	Format      string   `json:"format" yaml:"format"`
+	OutputPaths []string `json:"output_paths" yaml:"output_paths"`
+}
+
+type partitionSection struct {
+	PathTemplate string `json:"path_template" yaml:"path_template"`
+	MaxSize      int64  `json:"max_size" yaml:"max_size"`
+	MaxAge       string `json:"max_age" yaml:"max_age"`
+}
+
+type samplingSection struct {
+	Initial    int                             `json:"initial" yaml:"initial"`
+	Thereafter int                             `json:"thereafter" yaml:"thereafter"`
+	Levels     map[string]levelSamplingSection `json:"levels" yaml:"levels"`
+}
+
+type levelSamplingSection struct {
+	Initial    int `json:"initial" yaml:"initial"`
+	Thereafter int `json:"thereafter" yaml:"thereafter"`
+}
+
+type sinkSection struct {
+	Output           string `json:"output" yaml:"output"`
+	Format           string `json:"format" yaml:"format"`
+	MinLevel         string `json:"min_level" yaml:"min_level"`
+	Compression      string `json:"compression" yaml:"compression"`
+	CompressionLevel int    `json:"compression_level" yaml:"compression_level"`
+}
+
+type auditSection struct {
+	OutputPaths []string `json:"output_paths" yaml:"output_paths"`
+	Format      string   `json:"format" yaml:"format"`
+}
+
+type entryShapeSection struct {
+	MaxFields int `json:"max_fields" yaml:"max_fields"`
+	MaxBytes  int `json:"max_bytes" yaml:"max_bytes"`
+}
+
+type redactionSection struct {
+	Keys     []string `json:"keys" yaml:"keys"`
+	Patterns []string `json:"patterns" yaml:"patterns"`
+}
+
+type serviceTagsSection struct {
+	Service string `json:"service" yaml:"service"`
+	Env     string `json:"env" yaml:"env"`
+	Version string `json:"version" yaml:"version"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Keys are snake_case field
+// names, levels are strings such as "debug" and durations strings such as
+// "24h". Keys missing from data keep their current value, so decoding into
+// DefaultLoggerConfig only overrides what the file sets. Unknown keys and
+// unparsable levels or durations are errors; use Validate for the rest.
+//
+// Example:
+//
+//	var file struct {
+//	    Logger *xlogger.Config `json:"logger"`
+//	}
+//	file.Logger = xlogger.DefaultLoggerConfig()
+//	if err := json.Unmarshal(data, &file); err != nil {
+//	    return err
+//	}
+func (c *Config) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+
+	var file configFile
+	if err := dec.Decode(&file); err != nil {
+		return fmt.Errorf("xlogger: decode config: %w", err)
+	}
+	return c.apply(&file)
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler with the keys and rules of
+// UnmarshalJSON.
+//
+// Example:
+//
+//	logger:
+//	  level: debug
+//	  format: json
+//	  output_paths: [stdout, /var/log/app.log]
+//	  sinks:
+//	    - output: stderr
+//	      min_level: error
+func (c *Config) UnmarshalYAML(value *yaml.Node) error {
+	// Node.Decode ignores unknown keys; re-decoding the node rejects them
+	data, err := yaml.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("xlogger: decode config: %w", err)
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+
+	var file configFile
+	if err := dec.Decode(&file); err != nil {
+		return fmt.Errorf("xlogger: decode config: %w", err)
+	}
+	return c.apply(&file)
+}
+
+// apply sets the fields of c present in file
+func (c *Config) apply(file *configFile) error {
+	var errs []error
+	parseLevel := func(key, text string) zapcore.Level {
+		level, err := zapcore.ParseLevel(text)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", key, err))
+		}
+		return level
+	}
+	parseDuration := func(key, text string) time.Duration {
+		if text == "" {
+			return 0
+		}
+		d, err := time.ParseDuration(text)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", key, err))
+		}
+		return d
+	}
+
+	if file.Level != nil {
+		c.Level = parseLevel("level", *file.Level)
+	}
+	if file.Format != nil {
+		c.Format = LogFormat(*file.Format).Normalize()
+	}
+	setValue(&c.Development, file.Development)
+	setValue(&c.DisableCaller, file.DisableCaller)
+	setValue(&c.DisableStacktrace, file.DisableStacktrace)
+	if file.StacktraceLevel != nil {
+		level := parseLevel("stacktrace_level", *file.StacktraceLevel)
+		c.StacktraceLevel = &level
+		if file.DisableStacktrace == nil {
+			c.DisableStacktrace = false
+		}
+	}
+	setValue(&c.TimeFormat, file.TimeFormat)
+	setValue(&c.CallerSkip, file.CallerSkip)
+	if file.Compression != nil {
+		c.Compression = Compression(*file.Compression).Normalize()
+	}
+	setValue(&c.CompressionLevel, file.CompressionLevel)
+	if file.OutputPaths != nil {
+		c.OutputPaths = file.OutputPaths
+	}
+	if file.ErrorOutputPaths != nil {
+		c.ErrorOutputPaths = file.ErrorOutputPaths
+	}
+	if file.Shadow != nil {
+		c.Shadow = &ShadowConfig{Format: LogFormat(file.Shadow.Format).Normalize(), OutputPaths: file.Shadow.OutputPaths}
+	}
+	setValue(&c.ExplainDrops, file.ExplainDrops)
+	if file.Partition != nil {
+		c.Partition = &PartitionConfig{
+			PathTemplate: file.Partition.PathTemplate,
+			MaxSize:      file.Partition.MaxSize,
+			MaxAge:       parseDuration("partition.max_age", file.Partition.MaxAge),
+		}
+	}
+	if file.Sampling != nil {
+		c.Sampling = &SamplingConfig{Initial: file.Sampling.Initial, Thereafter: file.Sampling.Thereafter}
+		for name, level := range file.Sampling.Levels {
+			if c.Sampling.Levels == nil {
+				c.Sampling.Levels = make(map[zapcore.Level]LevelSampling)
+			}
+			c.Sampling.Levels[parseLevel("sampling.levels", name)] = LevelSampling(level)
+		}
+	}
+	setValue(&c.DisableSampling, file.DisableSampling)
+	setValue(&c.ProducerTracking, file.ProducerTracking)
+	if file.DedupeWindow != nil {
+		c.DedupeWindow = parseDuration("dedupe_window", *file.DedupeWindow)
+	}
+	setValue(&c.DetectSecrets, file.DetectSecrets)
+	if file.Redaction != nil {
+		c.Redaction = &RedactionConfig{Keys: file.Redaction.Keys}
+		for i, text := range file.Redaction.Patterns {
+			pattern, err := regexp.Compile(text)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("redaction.patterns[%d]: %w", i, err))
+				continue
+			}
+			c.Redaction.Patterns = append(c.Redaction.Patterns, pattern)
+		}
+	}
+	setValue(&c.ErrorChains, file.ErrorChains)
+	if file.MessageOutputs != nil {
+		c.MessageOutputs = file.MessageOutputs
+	}
+	if file.Sinks != nil {
+		c.Sinks = make([]SinkConfig, len(file.Sinks))
+		for i, sink := range file.Sinks {
+			c.Sinks[i] = SinkConfig{
+				Output:           sink.Output,
+				Format:           LogFormat(sink.Format).Normalize(),
+				Compression:      Compression(sink.Compression).Normalize(),
+				CompressionLevel: sink.CompressionLevel,
+			}
+			if sink.MinLevel != "" {
+				c.Sinks[i].MinLevel = parseLevel(fmt.Sprintf("sinks[%d].min_level", i), sink.MinLevel)
+			}
+		}
+	}
+	if file.Audit != nil {
+		c.Audit = &AuditConfig{OutputPaths: file.Audit.OutputPaths, Format: LogFormat(file.Audit.Format).Normalize()}
+	}
+	if file.RetentionHints != nil {
+		c.RetentionHints = make(RetentionHints, len(file.RetentionHints))
+		for name, text := range file.RetentionHints {
+			c.RetentionHints[parseLevel("retention_hints", name)] = parseDuration("retention_hints."+name, text)
+		}
+	}
+	if file.TraceConflict != nil {
+		c.TraceConflict = TraceConflict(*file.TraceConflict).Normalize()
+	}
+	if file.TraceBackend != nil {
+		c.TraceBackend = TraceBackend(*file.TraceBackend).Normalize()
+	}
+	setValue(&c.BaggagePrefix, file.BaggagePrefix)
+	if file.IDGenerator != nil {
+		gen, err := parseIDGenerator(*file.IDGenerator)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("id_generator: %w", err))
+		}
+		c.IDGenerator = gen
+	}
+	if file.AfterClose != nil {
+		c.AfterClose = AfterClosePolicy(*file.AfterClose).Normalize()
+	}
+	if file.EntryShape != nil {
+		c.EntryShape = &EntryLimits{MaxFields: file.EntryShape.MaxFields, MaxBytes: file.EntryShape.MaxBytes}
+	}
+	if file.ServiceTags != nil {
+		c.ServiceTags = &ServiceTags{Service: file.ServiceTags.Service, Env: file.ServiceTags.Env, Version: file.ServiceTags.Version}
+	}
+	setValue(&c.HostFields, file.HostFields)
+	if file.StaticFields != nil {
+		c.StaticFields = make([]Field, 0, len(file.StaticFields))
+		for _, key := range sortedKeys(file.StaticFields) {
+			c.StaticFields = append(c.StaticFields, String(key, file.StaticFields[key]))
+		}
+	}
+	if file.GORMLevel != nil {
+		level, err := parseGORMLevel(*file.GORMLevel)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("gorm_level: %w", err))
+		}
+		c.GORMLevel = level
+	}
+	if file.ConsoleStyle != nil {
+		c.ConsoleStyle = ConsoleStyle(*file.ConsoleStyle).Normalize()
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("xlogger: decode config: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// setValue sets *dst to *src when src is set
+func setValue[T any](dst *T, src *T) {
+	if src != nil {
+		*dst = *src
+	}
+}
+
+// Validate reports every invalid setting of c, such as unknown formats and
+// negative sizes, joined into one error. Options ignore invalid values, so
+// Validate is mostly useful for configurations decoded from files.
+//
+// Example:
+//
+//	if err := cfg.Validate(); err != nil {
+//	    log.Fatalf("invalid logger config: %v", err)
+//	}
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(valid bool, format string, args ...interface{}) {
+		if !valid {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(c.Format == "" || c.Format.IsValid(), "format: unknown format %q", c.Format)
+	check(c.Compression.IsValid(), "compression: unknown compression %q", c.Compression)
+	check(c.CompressionLevel >= 0, "compression_level: negative level %d", c.CompressionLevel)
+	check(c.CallerSkip >= 0, "caller_skip: negative skip %d", c.CallerSkip)
+	check(len(c.OutputPaths) > 0, "output_paths: no output")
+	for i, path := range c.OutputPaths {
+		check(path != "", "output_paths[%d]: empty path", i)
+	}
+	if c.Shadow != nil {
+		check(c.Shadow.Format.IsValid(), "shadow.format: unknown format %q", c.Shadow.Format)
+	}
+	if c.Partition != nil {
+		check(c.Partition.PathTemplate != "", "partition.path_template: empty template")
+		check(c.Partition.MaxSize >= 0, "partition.max_size: negative size %d", c.Partition.MaxSize)
+		check(c.Partition.MaxAge >= 0, "partition.max_age: negative age %s", c.Partition.MaxAge)
+	}
+	if c.Sampling != nil {
+		check(c.Sampling.Initial >= 0 && c.Sampling.Thereafter >= 0, "sampling: negative rate %d/%d",
+			c.Sampling.Initial, c.Sampling.Thereafter)
+		for level, rate := range c.Sampling.Levels {
+			check(rate.Initial >= 0 && rate.Thereafter >= 0, "sampling.levels.%s: negative rate %d/%d",
+				level, rate.Initial, rate.Thereafter)
+		}
+	}
+	check(c.ProducerTracking >= 0, "producer_tracking: negative size %d", c.ProducerTracking)
+	check(c.DedupeWindow >= 0, "dedupe_window: negative window %s", c.DedupeWindow)
+	for i, sink := range c.Sinks {
+		check(sink.Output != "", "sinks[%d].output: empty output", i)
+		check(sink.Format == "" || sink.Format.IsValid(), "sinks[%d].format: unknown format %q", i, sink.Format)
+		check(sink.Compression.IsValid(), "sinks[%d].compression: unknown compression %q", i, sink.Compression)
+		check(sink.CompressionLevel >= 0, "sinks[%d].compression_level: negative level %d", i, sink.CompressionLevel)
+	}
+	if c.Audit != nil {
+		check(len(c.Audit.OutputPaths) > 0, "audit.output_paths: no output")
+		for i, path := range c.Audit.OutputPaths {
+			check(path != "", "audit.output_paths[%d]: empty path", i)
+		}
+		check(c.Audit.Format == "" || c.Audit.Format.IsValid(), "audit.format: unknown format %q", c.Audit.Format)
+	}
+	for level, retention := range c.RetentionHints {
+		check(retention >= 0, "retention_hints.%s: negative retention %s", level, retention)
+	}
+	check(c.TraceConflict == "" || c.TraceConflict.isValid(), "trace_conflict: unknown strategy %q", c.TraceConflict)
+	check(c.TraceBackend == "" || c.TraceBackend.isValid(), "trace_backend: unknown backend %q", c.TraceBackend)
+	check(c.AfterClose == "" || c.AfterClose.isValid(), "after_close: unknown policy %q", c.AfterClose)
+	if c.EntryShape != nil {
+		check(c.EntryShape.MaxFields >= 0 && c.EntryShape.MaxBytes >= 0, "entry_shape: negative limit")
+	}
+	check(c.GORMLevel >= 0 && c.GORMLevel <= gormlogger.Info, "gorm_level: unknown level %d", c.GORMLevel)
+	check(c.ConsoleStyle == "" || c.ConsoleStyle.isValid(), "console_style: unknown style %q", c.ConsoleStyle)
+
+	if len(errs) > 0 {
+		return fmt.Errorf("xlogger: invalid config: %w", errors.Join(errs...))
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+package xlogger
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/yaml.v3"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// TestConfig_UnmarshalYAML tests decoding the logger section of YAML files
+func TestConfig_UnmarshalYAML(t *testing.T) {
+	t.Run("should decode an embedded logger section", func(t *testing.T) {
+		data := `
+service: orders
+logger:
+  level: debug
+  format: TEXT
+  output_paths: [stdout, /var/log/app.log]
+  dedupe_window: 2s
+  sampling:
+    initial: 50
+    thereafter: 5
+    levels:
+      debug: {initial: 0}
+  sinks:
+    - output: stderr
+      min_level: error
+  retention_hints:
+    error: 8760h
+  service_tags:
+    service: orders
+    env: prod
+  host_fields: true
+  static_fields:
+    region: ap-southeast-1
+    cluster: blue
+  after_close: stderr
+  baggage_prefix: ctx.
+  id_generator: ulid
+  gorm_level: INFO
+  trace_backend: CONTEXT
+  redaction:
+    keys: [password]
+    patterns: ['\d{16}']
+  audit:
+    output_paths: [/var/log/audit.log]
+  error_chains: true
+  stacktrace_level: warn
+`
+		var file struct {
+			Service string  `yaml:"service"`
+			Logger  *Config `yaml:"logger"`
+		}
+		file.Logger = DefaultLoggerConfig()
+		require.NoError(t, yaml.Unmarshal([]byte(data), &file))
+
+		cfg := file.Logger
+		assert.Equal(t, zapcore.DebugLevel, cfg.Level)
+		assert.Equal(t, FormatText, cfg.Format)
+		assert.Equal(t, []string{"stdout", "/var/log/app.log"}, cfg.OutputPaths)
+		assert.Equal(t, 2*time.Second, cfg.DedupeWindow)
+		assert.Equal(t, &SamplingConfig{
+			Initial:    50,
+			Thereafter: 5,
+			Levels:     map[zapcore.Level]LevelSampling{zapcore.DebugLevel: {}},
+		}, cfg.Sampling)
+		assert.Equal(t, []SinkConfig{{Output: "stderr", MinLevel: zapcore.ErrorLevel}}, cfg.Sinks)
+		assert.Equal(t, RetentionHints{zapcore.ErrorLevel: 8760 * time.Hour}, cfg.RetentionHints)
+		assert.Equal(t, &ServiceTags{Service: "orders", Env: "prod"}, cfg.ServiceTags)
+		assert.True(t, cfg.HostFields)
+		assert.Equal(t, []Field{String("cluster", "blue"), String("region", "ap-southeast-1")}, cfg.StaticFields)
+		assert.Equal(t, AfterCloseStderr, cfg.AfterClose)
+		assert.Equal(t, "ctx.", cfg.BaggagePrefix)
+		assert.Equal(t, ContextBackend, cfg.TraceBackend)
+		require.NotNil(t, cfg.IDGenerator)
+		assert.Len(t, cfg.IDGenerator(), 26)
+		assert.Equal(t, gormlogger.Info, cfg.GORMLevel)
+		require.NotNil(t, cfg.Redaction)
+		assert.Equal(t, []string{"password"}, cfg.Redaction.Keys)
+		require.Len(t, cfg.Redaction.Patterns, 1)
+		assert.Equal(t, `\d{16}`, cfg.Redaction.Patterns[0].String())
+		assert.Equal(t, &AuditConfig{OutputPaths: []string{"/var/log/audit.log"}}, cfg.Audit)
+		assert.True(t, cfg.ErrorChains)
+		require.NotNil(t, cfg.StacktraceLevel)
+		assert.Equal(t, zapcore.WarnLevel, *cfg.StacktraceLevel)
+		assert.False(t, cfg.DisableStacktrace)
+
+		// Keys missing from the file keep the defaults
+		assert.Equal(t, []string{"stderr"}, cfg.ErrorOutputPaths)
+		assert.False(t, cfg.Development)
+		assert.NoError(t, cfg.Validate())
+	})
+
+	t.Run("should reject unknown keys and invalid values", func(t *testing.T) {
+		var cfg Config
+		err := yaml.Unmarshal([]byte("levle: debug\n"), &cfg)
+		assert.ErrorContains(t, err, "field levle not found")
+
+		err = yaml.Unmarshal([]byte("level: loud\ndedupe_window: soon\nredaction: {patterns: ['[']}\nid_generator: snowflake\ngorm_level: debug\n"), &cfg)
+		assert.ErrorContains(t, err, "level: unrecognized level")
+		assert.ErrorContains(t, err, `id_generator: unknown id generator "snowflake"`)
+		assert.ErrorContains(t, err, `gorm_level: unknown GORM level "debug"`)
+		assert.ErrorContains(t, err, "dedupe_window: time: invalid duration")
+		assert.ErrorContains(t, err, "redaction.patterns[0]: error parsing regexp")
+	})
+}
+
+// TestConfig_UnmarshalJSON tests decoding the logger section of JSON files
+func TestConfig_UnmarshalJSON(t *testing.T) {
+	t.Run("should decode levels, formats and durations", func(t *testing.T) {
+		cfg := DefaultLoggerConfig()
+		data := `{
+			"level": "warn",
+			"format": "gcp",
+			"partition": {"path_template": "logs/{date}/{component}.log", "max_age": "72h"},
+			"trace_conflict": "warn_on_mismatch",
+			"console_style": "plain",
+			"entry_shape": {"max_fields": 32}
+		}`
+		require.NoError(t, json.Unmarshal([]byte(data), cfg))
+
+		assert.Equal(t, zapcore.WarnLevel, cfg.Level)
+		assert.Equal(t, FormatGCP, cfg.Format)
+		assert.Equal(t, &PartitionConfig{PathTemplate: "logs/{date}/{component}.log", MaxAge: 72 * time.Hour}, cfg.Partition)
+		assert.Equal(t, TraceConflictWarn, cfg.TraceConflict)
+		assert.Equal(t, ConsoleStylePlain, cfg.ConsoleStyle)
+		assert.Equal(t, &EntryLimits{MaxFields: 32}, cfg.EntryShape)
+		assert.Equal(t, []string{"stdout"}, cfg.OutputPaths)
+	})
+
+	t.Run("should reject unknown keys", func(t *testing.T) {
+		var cfg Config
+		err := json.Unmarshal([]byte(`{"output": "stdout"}`), &cfg)
+		assert.ErrorContains(t, err, `unknown field "output"`)
+	})
+}
+
+// TestConfig_Validate tests the aggregated validation errors
+func TestConfig_Validate(t *testing.T) {
+	t.Run("should accept the default config", func(t *testing.T) {
+		assert.NoError(t, DefaultLoggerConfig().Validate())
+	})
+
+	t.Run("should report every invalid setting", func(t *testing.T) {
+		cfg := DefaultLoggerConfig()
+		data := `
+format: xml
+compression: lz4
+output_paths: []
+sinks:
+  - format: json
+    compression: lz4
+audit:
+  format: yaml
+producer_tracking: -1
+after_close: ignore
+console_style: neon
+trace_backend: thread_local
+`
+		require.NoError(t, yaml.Unmarshal([]byte(data), cfg))
+
+		err := cfg.Validate()
+		require.Error(t, err)
+		for _, msg := range []string{
+			`format: unknown format "xml"`,
+			`compression: unknown compression "lz4"`,
+			"output_paths: no output",
+			"sinks[0].output: empty output",
+			`sinks[0].compression: unknown compression "lz4"`,
+			"audit.output_paths: no output",
+			`audit.format: unknown format "yaml"`,
+			"producer_tracking: negative size -1",
+			`after_close: unknown policy "ignore"`,
+			`console_style: unknown style "neon"`,
+			`trace_backend: unknown backend "thread_local"`,
+		} {
+			assert.ErrorContains(t, err, msg)
+		}
+	})
+}
@@ -1,10 +1,15 @@
 package xlogger
 
 import (
+	"regexp"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+	gormlogger "gorm.io/gorm/logger"
 )
 
 // TestLogFormat tests the LogFormat type
@@ -19,6 +24,12 @@ func TestLogFormat(t *testing.T) {
 		assert.True(t, FormatText.IsValid())
 		assert.True(t, LogFormat("JSON").IsValid())
 		assert.True(t, LogFormat("TEXT").IsValid())
+		assert.True(t, FormatProtobuf.IsValid())
+		assert.True(t, LogFormat("Protobuf").IsValid())
+		assert.True(t, FormatMsgpack.IsValid())
+		assert.True(t, FormatCBOR.IsValid())
+		assert.True(t, FormatGCP.IsValid())
+		assert.True(t, FormatDatadog.IsValid())
 		assert.False(t, LogFormat("invalid").IsValid())
 		assert.False(t, LogFormat("").IsValid())
 	})
@@ -60,6 +71,8 @@ func TestNewLoggerConfig(t *testing.T) {
 		assert.Equal(t, expected.DisableCaller, cfg.DisableCaller)
 		assert.Equal(t, expected.DisableStacktrace, cfg.DisableStacktrace)
 		assert.Equal(t, expected.CallerSkip, cfg.CallerSkip)
+		assert.Equal(t, []string{"stdout"}, cfg.OutputPaths)
+		assert.Equal(t, []string{"stderr"}, cfg.ErrorOutputPaths)
 	})
 
 	t.Run("should apply single option", func(t *testing.T) {
@@ -272,6 +285,209 @@ func TestWithCallerSkip(t *testing.T) {
 	})
 }
 
+// TestWithCompression tests the WithCompression option
+func TestWithCompression(t *testing.T) {
+	t.Run("should set compression and level", func(t *testing.T) {
+		cfg := NewLoggerConfig(WithCompression("ZSTD", 3))
+		assert.Equal(t, CompressionZstd, cfg.Compression)
+		assert.Equal(t, 3, cfg.CompressionLevel)
+	})
+
+	t.Run("should ignore unsupported compression", func(t *testing.T) {
+		cfg := NewLoggerConfig(WithCompression("lz4", 3))
+		assert.Equal(t, CompressionNone, cfg.Compression)
+		assert.Equal(t, 0, cfg.CompressionLevel)
+	})
+}
+
+// TestWithOutputPaths tests the WithOutputPaths and WithErrorOutputPaths options
+func TestWithOutputPaths(t *testing.T) {
+	t.Run("should set output paths", func(t *testing.T) {
+		cfg := NewLoggerConfig(
+			WithOutputPaths("stdout", "/tmp/app.log"),
+			WithErrorOutputPaths("/tmp/app-errors.log"),
+		)
+		assert.Equal(t, []string{"stdout", "/tmp/app.log"}, cfg.OutputPaths)
+		assert.Equal(t, []string{"/tmp/app-errors.log"}, cfg.ErrorOutputPaths)
+	})
+
+	t.Run("should keep defaults when no paths given", func(t *testing.T) {
+		cfg := NewLoggerConfig(WithOutputPaths(), WithErrorOutputPaths())
+		assert.Equal(t, []string{"stdout"}, cfg.OutputPaths)
+		assert.Equal(t, []string{"stderr"}, cfg.ErrorOutputPaths)
+	})
+}
+
+// TestWithShadow tests the WithShadow option
+func TestWithShadow(t *testing.T) {
+	t.Run("should set shadow format and paths", func(t *testing.T) {
+		cfg := NewLoggerConfig(WithShadow("MSGPACK", "/tmp/app.msgpack"))
+		require.NotNil(t, cfg.Shadow)
+		assert.Equal(t, FormatMsgpack, cfg.Shadow.Format)
+		assert.Equal(t, []string{"/tmp/app.msgpack"}, cfg.Shadow.OutputPaths)
+	})
+
+	t.Run("should ignore invalid format", func(t *testing.T) {
+		cfg := NewLoggerConfig(WithShadow("ecs"))
+		assert.Nil(t, cfg.Shadow)
+	})
+}
+
+// TestWithPartitionedOutput tests the WithPartitionedOutput option
+func TestWithPartitionedOutput(t *testing.T) {
+	t.Run("should set partition template, size and age", func(t *testing.T) {
+		cfg := NewLoggerConfig(WithPartitionedOutput("logs/{date}/{component}.log", 1024, time.Hour))
+		require.NotNil(t, cfg.Partition)
+		assert.Equal(t, PartitionConfig{PathTemplate: "logs/{date}/{component}.log", MaxSize: 1024, MaxAge: time.Hour}, *cfg.Partition)
+	})
+
+	t.Run("should ignore empty template", func(t *testing.T) {
+		cfg := NewLoggerConfig(WithPartitionedOutput("", 1024, time.Hour))
+		assert.Nil(t, cfg.Partition)
+	})
+}
+
+// TestWithSampling tests the sampling options
+func TestWithSampling(t *testing.T) {
+	t.Run("should replace the default rate and keep level overrides", func(t *testing.T) {
+		cfg := NewLoggerConfig(
+			WithLevelSampling(zapcore.DebugLevel, 0, 0),
+			WithSampling(1000, 10),
+		)
+		require.NotNil(t, cfg.Sampling)
+		assert.Equal(t, 1000, cfg.Sampling.Initial)
+		assert.Equal(t, 10, cfg.Sampling.Thereafter)
+		assert.Equal(t, LevelSampling{}, cfg.Sampling.Levels[zapcore.DebugLevel])
+	})
+
+	t.Run("should start level overrides from the default rate", func(t *testing.T) {
+		cfg := NewLoggerConfig(WithLevelSampling(zapcore.InfoLevel, 500, 50))
+		require.NotNil(t, cfg.Sampling)
+		assert.Equal(t, 100, cfg.Sampling.Initial)
+		assert.Equal(t, LevelSampling{Initial: 500, Thereafter: 50}, cfg.Sampling.Levels[zapcore.InfoLevel])
+	})
+
+	t.Run("should ignore negative values", func(t *testing.T) {
+		cfg := NewLoggerConfig(WithSampling(-1, 10), WithLevelSampling(zapcore.InfoLevel, 1, -1))
+		assert.Nil(t, cfg.Sampling)
+	})
+
+	t.Run("should disable and re-enable sampling", func(t *testing.T) {
+		cfg := NewLoggerConfig(WithSamplingDisabled())
+		assert.True(t, cfg.DisableSampling)
+
+		cfg = NewLoggerConfig(WithSamplingDisabled(), WithSampling(10, 10))
+		assert.False(t, cfg.DisableSampling)
+	})
+}
+
+// TestWithServiceTags tests the service tags option
+func TestWithServiceTags(t *testing.T) {
+	t.Run("should set service tags", func(t *testing.T) {
+		cfg := NewLoggerConfig(WithServiceTags("orders", "prod", "1.4.2"))
+		require.NotNil(t, cfg.ServiceTags)
+		assert.Equal(t, ServiceTags{Service: "orders", Env: "prod", Version: "1.4.2"}, *cfg.ServiceTags)
+	})
+
+	t.Run("should leave out empty tags", func(t *testing.T) {
+		cfg := NewLoggerConfig(WithServiceTags("orders", "", "1.4.2"))
+		assert.Equal(t, []zap.Field{zap.String("service", "orders"), zap.String("version", "1.4.2")}, cfg.ServiceTags.fields())
+		assert.Nil(t, DefaultLoggerConfig().ServiceTags.fields())
+	})
+}
+
+// TestWithStaticFields tests the host and static fields options
+func TestWithStaticFields(t *testing.T) {
+	t.Run("should enable host fields", func(t *testing.T) {
+		assert.True(t, NewLoggerConfig(WithHostFields()).HostFields)
+		assert.False(t, DefaultLoggerConfig().HostFields)
+	})
+
+	t.Run("should add static fields of every call", func(t *testing.T) {
+		cfg := NewLoggerConfig(WithStaticFields(String("region", "eu-west-1")), WithStaticFields(String("cluster", "blue")))
+		assert.Equal(t, []Field{String("region", "eu-west-1"), String("cluster", "blue")}, cfg.StaticFields)
+	})
+}
+
+// TestWithGORMLevel tests the GORM level option
+func TestWithGORMLevel(t *testing.T) {
+	t.Run("should set known levels", func(t *testing.T) {
+		assert.Equal(t, gormlogger.Info, NewLoggerConfig(WithGORMLevel(gormlogger.Info)).GORMLevel)
+		assert.Equal(t, gormlogger.Silent, NewLoggerConfig(WithGORMLevel(gormlogger.Silent)).GORMLevel)
+		assert.Zero(t, DefaultLoggerConfig().GORMLevel)
+	})
+
+	t.Run("should ignore unknown levels", func(t *testing.T) {
+		assert.Zero(t, NewLoggerConfig(WithGORMLevel(gormlogger.LogLevel(9))).GORMLevel)
+	})
+
+	t.Run("should reject unknown levels in Validate", func(t *testing.T) {
+		cfg := DefaultLoggerConfig()
+		cfg.GORMLevel = 9
+		assert.ErrorContains(t, cfg.Validate(), "gorm_level: unknown level 9")
+	})
+}
+
+// TestWithRedaction tests the redaction option
+func TestWithRedaction(t *testing.T) {
+	t.Run("should add keys and patterns", func(t *testing.T) {
+		cfg := NewLoggerConfig(
+			WithRedaction([]string{"password"}, nil),
+			WithRedaction([]string{"ssn"}, []*regexp.Regexp{EmailPattern}),
+		)
+		require.NotNil(t, cfg.Redaction)
+		assert.Equal(t, []string{"password", "ssn"}, cfg.Redaction.Keys)
+		assert.Equal(t, []*regexp.Regexp{EmailPattern}, cfg.Redaction.Patterns)
+	})
+
+	t.Run("should ignore empty redaction", func(t *testing.T) {
+		assert.Nil(t, NewLoggerConfig(WithRedaction(nil, nil)).Redaction)
+	})
+}
+
+// TestWithProcessor tests adding entry processors
+func TestWithProcessor(t *testing.T) {
+	t.Run("should append processors and skip nil", func(t *testing.T) {
+		cfg := NewLoggerConfig(
+			WithProcessor(ScrubEmails()),
+			WithProcessor(nil, ScrubIPs()),
+		)
+		assert.Len(t, cfg.Processors, 2)
+	})
+}
+
+// TestWithAudit tests configuring the audit outputs
+func TestWithAudit(t *testing.T) {
+	t.Run("should set the audit outputs", func(t *testing.T) {
+		cfg := NewLoggerConfig(WithAudit("JSON", "audit.log"))
+		assert.Equal(t, &AuditConfig{OutputPaths: []string{"audit.log"}, Format: FormatJSON}, cfg.Audit)
+	})
+
+	t.Run("should ignore missing paths and invalid formats", func(t *testing.T) {
+		assert.Nil(t, NewLoggerConfig(WithAudit(FormatJSON)).Audit)
+		assert.Nil(t, NewLoggerConfig(WithAudit("xml", "audit.log")).Audit)
+	})
+}
+
+// TestWithErrorChains tests enabling error chain fields
+func TestWithErrorChains(t *testing.T) {
+	t.Run("should enable error chains", func(t *testing.T) {
+		assert.False(t, NewLoggerConfig().ErrorChains)
+		assert.True(t, NewLoggerConfig(WithErrorChains()).ErrorChains)
+	})
+}
+
+// TestWithStacktraceLevel tests setting the stack trace threshold
+func TestWithStacktraceLevel(t *testing.T) {
+	t.Run("should set the threshold and enable stack traces", func(t *testing.T) {
+		cfg := NewLoggerConfig(WithStacktraceLevel(zapcore.WarnLevel))
+		require.NotNil(t, cfg.StacktraceLevel)
+		assert.Equal(t, zapcore.WarnLevel, *cfg.StacktraceLevel)
+		assert.False(t, cfg.DisableStacktrace)
+		assert.Nil(t, NewLoggerConfig().StacktraceLevel)
+	})
+}
+
 // TestConfigHelperMethods tests the helper methods on Config
 func TestConfigHelperMethods(t *testing.T) {
 	t.Run("GetLevel should return level string", func(t *testing.T) {
@@ -353,6 +569,24 @@ func TestConfigHelperMethods(t *testing.T) {
 		assert.False(t, cfg.IsTextFormat())
 	})
 
+	t.Run("IsProtobufFormat should return correct value", func(t *testing.T) {
+		cfg := &Config{Format: FormatProtobuf}
+		assert.True(t, cfg.IsProtobufFormat())
+
+		cfg.Format = FormatJSON
+		assert.False(t, cfg.IsProtobufFormat())
+	})
+
+	t.Run("IsBinaryFormat should return correct value", func(t *testing.T) {
+		for _, format := range []LogFormat{FormatProtobuf, FormatMsgpack, FormatCBOR} {
+			cfg := &Config{Format: format}
+			assert.True(t, cfg.IsBinaryFormat())
+		}
+
+		cfg := &Config{Format: FormatText}
+		assert.False(t, cfg.IsBinaryFormat())
+	})
+
 	t.Run("IsDevelopment should return correct value", func(t *testing.T) {
 		cfg := &Config{Development: true}
 		assert.True(t, cfg.IsDevelopment())
@@ -0,0 +1,46 @@
+package xlogger
+
+import (
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// ConsoleStyle decides how FormatText writes levels.
+type ConsoleStyle string
+
+const (
+	// ConsoleStyleEmoji writes levels with an emoji, such as "📢 INFO"
+	ConsoleStyleEmoji ConsoleStyle = "emoji"
+	// ConsoleStyleColor writes ANSI-colored uppercase levels
+	ConsoleStyleColor ConsoleStyle = "color"
+	// ConsoleStylePlain writes plain uppercase levels, for terminals and
+	// log scrapers that cannot handle escape sequences or multibyte glyphs
+	ConsoleStylePlain ConsoleStyle = "plain"
+)
+
+// Normalize returns the normalized lowercase style.
+func (s ConsoleStyle) Normalize() ConsoleStyle {
+	return ConsoleStyle(strings.ToLower(string(s)))
+}
+
+// isValid reports whether s is a known style
+func (s ConsoleStyle) isValid() bool {
+	switch s.Normalize() {
+	case ConsoleStyleEmoji, ConsoleStyleColor, ConsoleStylePlain:
+		return true
+	}
+	return false
+}
+
+// levelEncoder returns the level encoder of the style, emoji by default
+func (s ConsoleStyle) levelEncoder() zapcore.LevelEncoder {
+	switch s.Normalize() {
+	case ConsoleStyleColor:
+		return zapcore.CapitalColorLevelEncoder
+	case ConsoleStylePlain:
+		return zapcore.CapitalLevelEncoder
+	default:
+		return emojiLevelEncoder
+	}
+}
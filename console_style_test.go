@@ -0,0 +1,61 @@
+package xlogger
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestConsoleStyle tests the level styles of the text format
+func TestConsoleStyle(t *testing.T) {
+	newTextLogger := func(t *testing.T, style ConsoleStyle) (*ZapLogger, func() string, func() string) {
+		t.Helper()
+
+		dir := t.TempDir()
+		path, sinkPath := filepath.Join(dir, "app.log"), filepath.Join(dir, "sink.log")
+		logger, err := NewZapLogger(NewLoggerConfig(
+			WithOutputPaths(path),
+			WithFormat(FormatText),
+			WithConsoleStyle(style),
+			WithSink(SinkConfig{Output: sinkPath, Format: FormatText}),
+		))
+		require.NoError(t, err)
+		return logger, func() string { return readFile(t, path) }, func() string { return readFile(t, sinkPath) }
+	}
+
+	t.Run("should write emoji levels by default", func(t *testing.T) {
+		logger, output, _ := newTextLogger(t, "")
+		logger.Info("started")
+		require.NoError(t, logger.Sync())
+
+		assert.Contains(t, output(), "📢 INFO")
+	})
+
+	t.Run("should write plain levels", func(t *testing.T) {
+		logger, output, sink := newTextLogger(t, ConsoleStylePlain)
+		logger.Warn("disk almost full")
+		require.NoError(t, logger.Sync())
+
+		for _, text := range []string{output(), sink()} {
+			assert.Contains(t, text, "\tWARN\t")
+			assert.NotContains(t, text, "🚧")
+			assert.NotContains(t, text, "\x1b[")
+		}
+	})
+
+	t.Run("should write colored levels", func(t *testing.T) {
+		logger, output, _ := newTextLogger(t, ConsoleStyleColor)
+		logger.Error("payment failed")
+		require.NoError(t, logger.Sync())
+
+		assert.Contains(t, output(), "\x1b[31mERROR\x1b[0m")
+		assert.NotContains(t, output(), "❌")
+	})
+
+	t.Run("should ignore unknown styles", func(t *testing.T) {
+		cfg := NewLoggerConfig(WithConsoleStyle("PLAIN"), WithConsoleStyle("neon"))
+		assert.Equal(t, ConsoleStylePlain, cfg.ConsoleStyle)
+	})
+}
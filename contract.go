@@ -0,0 +1,216 @@
+package xlogger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// ContractSample is a generated entry used to verify that downstream parsers
+// accept what the configured encoder produces.
+type ContractSample struct {
+	Name    string
+	Level   zapcore.Level
+	Message string
+	Fields  []Field
+}
+
+// ContractParser validates one encoded entry, for example by running it
+// through the parser used by the log platform. It returns an error when the
+// entry does not meet the expectation.
+type ContractParser func(sample ContractSample, entry []byte) error
+
+// contractStruct is encoded through Any to cover reflected values
+type contractStruct struct {
+	ID     int               `json:"id"`
+	Name   string            `json:"name"`
+	Tags   []string          `json:"tags"`
+	Labels map[string]string `json:"labels"`
+}
+
+// ContractSamples returns entries covering every field type, unicode and
+// control characters, numeric limits and nested Any values.
+func ContractSamples() []ContractSample {
+	ts := time.Date(2024, 2, 29, 23, 59, 59, 123456789, time.UTC)
+	return []ContractSample{
+		{
+			Name:    "all field types",
+			Level:   zapcore.InfoLevel,
+			Message: "contract sample",
+			Fields: []Field{
+				String("string", "value"),
+				Int("int", 42),
+				Int64("int64", -42),
+				Float64("float64", 3.14),
+				Bool("bool", true),
+				Error(errors.New("sample error")),
+				NamedError("cause", errors.New("sample cause")),
+				Duration("duration", 1500*time.Millisecond),
+				Time("time", ts),
+				Any("any", []int{1, 2, 3}),
+			},
+		},
+		{
+			Name:    "unicode",
+			Level:   zapcore.InfoLevel,
+			Message: "héllo 世界 🚀",
+			Fields: []Field{
+				String("ключ", "значение"),
+				String("emoji", "👩‍💻"),
+				String("control", "tab\tnewline\nquote\"backslash\\nul\x00"),
+				String("invalid_utf8", "\xff\xfe"),
+			},
+		},
+		{
+			Name:    "big numbers",
+			Level:   zapcore.WarnLevel,
+			Message: "numeric limits",
+			Fields: []Field{
+				Int64("max_int64", math.MaxInt64),
+				Int64("min_int64", math.MinInt64),
+				Any("max_uint64", uint64(math.MaxUint64)),
+				Float64("max_float64", math.MaxFloat64),
+				Float64("smallest_float64", math.SmallestNonzeroFloat64),
+				Float64("positive_infinity", math.Inf(1)),
+				Float64("not_a_number", math.NaN()),
+			},
+		},
+		{
+			Name:    "nested any",
+			Level:   zapcore.ErrorLevel,
+			Message: "nested values",
+			Fields: []Field{
+				Any("map", map[string]interface{}{
+					"level1": map[string]interface{}{
+						"level2": []interface{}{1, "two", map[string]interface{}{"three": 3.0}},
+					},
+				}),
+				Any("struct", contractStruct{
+					ID:     7,
+					Name:   "nested",
+					Tags:   []string{"a", "b"},
+					Labels: map[string]string{"env": "test"},
+				}),
+				Any("nil", nil),
+			},
+		},
+		{
+			Name:    "empty values",
+			Level:   zapcore.DebugLevel,
+			Message: "",
+			Fields: []Field{
+				String("empty", ""),
+				String("", "empty key"),
+				Any("empty_slice", []string{}),
+				Any("empty_map", map[string]string{}),
+			},
+		},
+	}
+}
+
+// contractRecorder keeps every write as a separate entry
+type contractRecorder struct {
+	entries [][]byte
+}
+
+func (r *contractRecorder) Write(p []byte) (int, error) {
+	r.entries = append(r.entries, append([]byte(nil), p...))
+	return len(p), nil
+}
+
+func (r *contractRecorder) Sync() error {
+	return nil
+}
+
+// VerifyContract encodes ContractSamples with the encoder configured by cfg
+// and checks each entry with every parser. All violations are returned
+// joined, so it can run directly in an application's test suite.
+//
+// Example:
+//
+//	func TestLogContract(t *testing.T) {
+//	    if err := xlogger.VerifyContract(cfg, xlogger.JSONContract("time", "level", "message")); err != nil {
+//	        t.Fatal(err)
+//	    }
+//	}
+func VerifyContract(cfg *Config, parsers ...ContractParser) error {
+	if cfg == nil {
+		cfg = DefaultLoggerConfig()
+	}
+
+	// Every sample must reach the encoder regardless of level and sampling
+	config := newBaseZapConfig(cfg)
+	config.Level = zap.NewAtomicLevelAt(zapcore.DebugLevel)
+
+	recorder := &contractRecorder{}
+	var internalErrors bytes.Buffer
+	logger, err := buildZapLogger(config, nil, &loggerOutputs{
+		sink:    recorder,
+		errSink: zapcore.AddSync(&internalErrors),
+	}, nil)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, sample := range ContractSamples() {
+		recorder.entries = nil
+		internalErrors.Reset()
+
+		if ce := logger.Check(sample.Level, sample.Message); ce != nil {
+			ce.Write(convertFieldsToZap(sample.Fields)...)
+		}
+
+		if internalErrors.Len() > 0 {
+			errs = append(errs, fmt.Errorf("contract sample %q: encoding failed: %s",
+				sample.Name, strings.TrimSpace(internalErrors.String())))
+			continue
+		}
+		if len(recorder.entries) != 1 {
+			errs = append(errs, fmt.Errorf("contract sample %q: expected one entry, got %d",
+				sample.Name, len(recorder.entries)))
+			continue
+		}
+
+		for _, parse := range parsers {
+			if err := parse(sample, recorder.entries[0]); err != nil {
+				errs = append(errs, fmt.Errorf("contract sample %q: %w", sample.Name, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// JSONContract expects each entry to be a single JSON object containing the
+// required keys and the sample message. Use it with FormatJSON; binary
+// formats can be checked by converting entries with ConvertToJSON first.
+func JSONContract(requiredKeys ...string) ContractParser {
+	return func(sample ContractSample, entry []byte) error {
+		var obj map[string]interface{}
+		decoder := json.NewDecoder(bytes.NewReader(entry))
+		decoder.UseNumber()
+		if err := decoder.Decode(&obj); err != nil {
+			return fmt.Errorf("invalid JSON: %w", err)
+		}
+		if decoder.More() {
+			return errors.New("entry contains more than one JSON value")
+		}
+
+		for _, key := range requiredKeys {
+			if _, ok := obj[key]; !ok {
+				return fmt.Errorf("missing key %q", key)
+			}
+		}
+		if message, ok := obj["message"]; ok && message != sample.Message {
+			return fmt.Errorf("message %q does not match %q", message, sample.Message)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,91 @@
+package xlogger
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestContractSamples tests the generated contract samples
+func TestContractSamples(t *testing.T) {
+	t.Run("should have unique names and fields", func(t *testing.T) {
+		seen := make(map[string]bool)
+		for _, sample := range ContractSamples() {
+			assert.False(t, seen[sample.Name], sample.Name)
+			seen[sample.Name] = true
+			assert.NotEmpty(t, sample.Fields, sample.Name)
+		}
+	})
+}
+
+// TestVerifyContract tests contract verification
+func TestVerifyContract(t *testing.T) {
+	t.Run("should pass JSON contract with JSON format", func(t *testing.T) {
+		err := VerifyContract(NewLoggerConfig(WithFormat(FormatJSON)), JSONContract("time", "level", "message"))
+		assert.NoError(t, err)
+	})
+
+	t.Run("should pass with nil config", func(t *testing.T) {
+		assert.NoError(t, VerifyContract(nil, JSONContract("message")))
+	})
+
+	t.Run("should report every sample that violates the contract", func(t *testing.T) {
+		err := VerifyContract(NewLoggerConfig(WithFormat(FormatText)), JSONContract())
+		require.Error(t, err)
+		for _, sample := range ContractSamples() {
+			assert.Contains(t, err.Error(), sample.Name)
+		}
+	})
+
+	t.Run("should report missing keys", func(t *testing.T) {
+		err := VerifyContract(DefaultLoggerConfig(), JSONContract("@timestamp"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `missing key "@timestamp"`)
+	})
+
+	t.Run("should pass samples to custom parsers", func(t *testing.T) {
+		var names []string
+		err := VerifyContract(NewLoggerConfig(WithFormat(FormatMsgpack)), func(sample ContractSample, entry []byte) error {
+			names = append(names, sample.Name)
+			var converted bytes.Buffer
+			if err := ConvertToJSON(FormatMsgpack, bytes.NewReader(entry), &converted); err != nil {
+				return err
+			}
+			return JSONContract("time", "level", "message")(sample, converted.Bytes())
+		})
+		assert.NoError(t, err)
+		assert.Len(t, names, len(ContractSamples()))
+	})
+
+	t.Run("should wrap parser errors", func(t *testing.T) {
+		errRejected := errors.New("rejected")
+		err := VerifyContract(DefaultLoggerConfig(), func(ContractSample, []byte) error {
+			return errRejected
+		})
+		assert.ErrorIs(t, err, errRejected)
+	})
+}
+
+// TestJSONContract tests the JSON contract parser
+func TestJSONContract(t *testing.T) {
+	sample := ContractSample{Name: "sample", Message: "hello"}
+
+	t.Run("should accept matching entry", func(t *testing.T) {
+		assert.NoError(t, JSONContract("message")(sample, []byte(`{"message":"hello"}`+"\n")))
+	})
+
+	t.Run("should reject invalid JSON", func(t *testing.T) {
+		assert.Error(t, JSONContract()(sample, []byte(`message=hello`)))
+	})
+
+	t.Run("should reject multiple values", func(t *testing.T) {
+		assert.Error(t, JSONContract()(sample, []byte(`{"message":"hello"}{"message":"hello"}`)))
+	})
+
+	t.Run("should reject mismatched message", func(t *testing.T) {
+		assert.Error(t, JSONContract()(sample, []byte(`{"message":"bye"}`)))
+	})
+}
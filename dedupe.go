@@ -0,0 +1,231 @@
+package xlogger
+
+import (
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// maxDedupeKeys bounds the distinct entries tracked per window; entries
+// beyond it are written without deduplication
+const maxDedupeKeys = 4096
+
+// dedupeRepeatedKey is the field counting the entries collapsed into a summary
+const dedupeRepeatedKey = "repeated"
+
+// dedupeState tracks the open windows of a logger's identical entries.
+// Windows follow entry times rather than the clock, so replays are
+// deterministic; a timer also closes windows with suppressed entries once
+// they have lasted a window of clock time, so summaries are written without
+// waiting for a later entry.
+type dedupeState struct {
+	window  time.Duration
+	errSink zapcore.WriteSyncer
+
+	mu        sync.Mutex
+	entries   map[uint64]*dedupeEntry
+	nextSweep time.Time
+	timer     *time.Timer // Closes expired windows, nil when none has suppressed entries
+	timerAt   time.Time   // Clock time the timer fires at
+}
+
+// dedupeEntry is an open window of one distinct entry
+type dedupeEntry struct {
+	core     zapcore.Core
+	until    time.Time
+	deadline time.Time       // Clock time at which the timer closes the window
+	ent      zapcore.Entry   // Last suppressed entry
+	fields   []zapcore.Field // Fields of the last suppressed entry
+	repeated int
+}
+
+func newDedupeState(window time.Duration, errSink zapcore.WriteSyncer) *dedupeState {
+	return &dedupeState{window: window, errSink: errSink, entries: make(map[uint64]*dedupeEntry)}
+}
+
+// dedupeCore writes the first of identical entries (same level, message and
+// fields) within a window and suppresses the rest. When the window closes, a
+// copy of the last suppressed entry is written with a repeated count.
+type dedupeCore struct {
+	zapcore.Core
+	enc   zapcore.Encoder // Field-only encoder used to hash logger and entry fields
+	state *dedupeState
+}
+
+func newDedupeCore(core zapcore.Core, state *dedupeState) zapcore.Core {
+	return &dedupeCore{Core: core, enc: zapcore.NewJSONEncoder(zapcore.EncoderConfig{}), state: state}
+}
+
+// With implements zapcore.Core
+func (c *dedupeCore) With(fields []zapcore.Field) zapcore.Core {
+	enc := c.enc.Clone()
+	for _, field := range fields {
+		field.AddTo(enc)
+	}
+	return &dedupeCore{Core: c.Core.With(fields), enc: enc, state: c.state}
+}
+
+// Check implements zapcore.Core
+func (c *dedupeCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if ent.Level > zapcore.ErrorLevel {
+		// Panic and Fatal entries are never held back
+		return c.Core.Check(ent, ce)
+	}
+	if c.Core.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write implements zapcore.Core
+func (c *dedupeCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	key, ok := c.key(ent, fields)
+	if !ok {
+		writeChecked(c.Core, ent, fields, c.state.errSink)
+		return nil
+	}
+
+	s := c.state
+	s.mu.Lock()
+	closed := s.sweep(ent.Time)
+	if e, ok := s.entries[key]; ok {
+		if ent.Time.Before(e.until) {
+			e.repeated++
+			e.ent, e.fields = ent, fields
+			s.schedule(e.deadline)
+			s.mu.Unlock()
+			s.flush(closed)
+			return nil
+		}
+		delete(s.entries, key)
+		if e.repeated > 0 {
+			closed = append(closed, e)
+		}
+	}
+	if len(s.entries) < maxDedupeKeys {
+		s.entries[key] = &dedupeEntry{core: c.Core, until: ent.Time.Add(s.window), deadline: time.Now().Add(s.window)}
+	}
+	s.mu.Unlock()
+
+	s.flush(closed)
+	writeChecked(c.Core, ent, fields, s.errSink)
+	return nil
+}
+
+// Sync implements zapcore.Core, writing the summaries of open windows first
+func (c *dedupeCore) Sync() error {
+	s := c.state
+	s.mu.Lock()
+	var open []*dedupeEntry
+	for key, e := range s.entries {
+		if e.repeated > 0 {
+			open = append(open, e)
+		}
+		delete(s.entries, key)
+	}
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	s.mu.Unlock()
+
+	s.flush(open)
+	return c.Core.Sync()
+}
+
+// key hashes the level, message and fields of an entry
+func (c *dedupeCore) key(ent zapcore.Entry, fields []zapcore.Field) (uint64, bool) {
+	buf, err := c.enc.EncodeEntry(zapcore.Entry{}, fields)
+	if err != nil {
+		return 0, false
+	}
+	defer buf.Free()
+
+	h := fnv.New64a()
+	_, _ = h.Write([]byte{byte(ent.Level)})
+	_, _ = h.Write([]byte(ent.Message))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write(buf.Bytes())
+	return h.Sum64(), true
+}
+
+// sweep removes the windows closed at now, at most once per window, and
+// returns those with suppressed entries. Callers hold s.mu.
+func (s *dedupeState) sweep(now time.Time) []*dedupeEntry {
+	if now.Before(s.nextSweep) {
+		return nil
+	}
+	s.nextSweep = now.Add(s.window)
+
+	var closed []*dedupeEntry
+	for key, e := range s.entries {
+		if now.Before(e.until) {
+			continue
+		}
+		if e.repeated > 0 {
+			closed = append(closed, e)
+		}
+		delete(s.entries, key)
+	}
+	return closed
+}
+
+// schedule arms the timer to close expired windows at deadline, unless it
+// fires earlier. Callers hold s.mu.
+func (s *dedupeState) schedule(deadline time.Time) {
+	if s.timer != nil {
+		if !deadline.Before(s.timerAt) {
+			return
+		}
+		s.timer.Stop()
+	}
+	s.timer, s.timerAt = time.AfterFunc(time.Until(deadline), s.flushExpired), deadline
+}
+
+// flushExpired removes the windows whose deadline passed, writes the
+// summaries of those with suppressed entries and rearms the timer for the
+// next one
+func (s *dedupeState) flushExpired() {
+	now := time.Now()
+	s.mu.Lock()
+	var closed []*dedupeEntry
+	var next time.Time
+	for key, e := range s.entries {
+		switch {
+		case !now.Before(e.deadline):
+			if e.repeated > 0 {
+				closed = append(closed, e)
+			}
+			delete(s.entries, key)
+		case e.repeated > 0 && (next.IsZero() || e.deadline.Before(next)):
+			next = e.deadline
+		}
+	}
+	s.timer = nil
+	if !next.IsZero() {
+		s.schedule(next)
+	}
+	s.mu.Unlock()
+
+	s.flush(closed)
+}
+
+// flush writes the summary of each closed window
+func (s *dedupeState) flush(closed []*dedupeEntry) {
+	for _, e := range closed {
+		fields := append(e.fields[:len(e.fields):len(e.fields)], zap.Int(dedupeRepeatedKey, e.repeated))
+		writeChecked(e.core, e.ent, fields, s.errSink)
+	}
+}
+
+// writeChecked passes an entry to core, which may be a tee of cores with
+// their own level checks, reporting failures on errSink like zap does
+func writeChecked(core zapcore.Core, ent zapcore.Entry, fields []zapcore.Field, errSink zapcore.WriteSyncer) {
+	if ce := core.Check(ent, nil); ce != nil {
+		ce.ErrorOutput = errSink
+		ce.Write(fields...)
+	}
+}
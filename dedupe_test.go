@@ -0,0 +1,140 @@
+package xlogger
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// TestDedupe tests collapsing of identical entries
+func TestDedupe(t *testing.T) {
+	t.Run("should collapse identical entries and report the count on sync", func(t *testing.T) {
+		logger, output := newFileLogger(t, WithDedupe(time.Hour))
+
+		for i := 0; i < 5; i++ {
+			logger.Error("db unavailable", String("host", "db-1"))
+		}
+		logger.Error("db unavailable", String("host", "db-2"))
+		assert.Len(t, entriesWithMessage(t, output(), "db unavailable"), 2)
+
+		require.NoError(t, logger.Sync())
+		entries := entriesWithMessage(t, output(), "db unavailable")
+		require.Len(t, entries, 3)
+		assert.NotContains(t, entries[0], "repeated")
+		assert.Equal(t, "db-1", entries[2]["host"])
+		assert.Equal(t, float64(4), entries[2]["repeated"])
+	})
+
+	t.Run("should write the summary when the window expires without another entry", func(t *testing.T) {
+		logger, output := newFileLogger(t, WithDedupe(50*time.Millisecond))
+
+		for i := 0; i < 3; i++ {
+			logger.Error("db unavailable", String("host", "db-1"))
+		}
+
+		require.Eventually(t, func() bool {
+			return len(entriesWithMessage(t, output(), "db unavailable")) == 2
+		}, 5*time.Second, 10*time.Millisecond)
+		entries := entriesWithMessage(t, output(), "db unavailable")
+		assert.Equal(t, float64(2), entries[1]["repeated"])
+
+		logger.Error("db unavailable", String("host", "db-1"))
+		require.NoError(t, logger.Sync())
+		assert.Len(t, entriesWithMessage(t, output(), "db unavailable"), 3, "a new window starts after the summary")
+	})
+
+	t.Run("should treat different fields and levels as distinct", func(t *testing.T) {
+		logger, output := newFileLogger(t, WithDedupe(time.Hour))
+
+		logger.Warn("retrying", Int("attempt", 1))
+		logger.Warn("retrying", Int("attempt", 2))
+		logger.Error("retrying", Int("attempt", 2))
+		logger.With(String("shard", "a")).Warn("retrying", Int("attempt", 1))
+
+		assert.Len(t, entriesWithMessage(t, output(), "retrying"), 4)
+	})
+
+	t.Run("should deduplicate infrastructure entries", func(t *testing.T) {
+		logger, output := newFileLogger(t, WithDedupe(time.Hour))
+
+		for i := 0; i < 3; i++ {
+			logger.ForInfra("db").Warn("slow query")
+		}
+		require.NoError(t, logger.Sync())
+
+		entries := entriesWithMessage(t, output(), "slow query")
+		require.Len(t, entries, 2)
+		assert.Equal(t, float64(2), entries[1]["repeated"])
+	})
+
+	t.Run("should never hold back panics", func(t *testing.T) {
+		logger, output := newFileLogger(t, WithDedupe(time.Hour))
+
+		for i := 0; i < 2; i++ {
+			assert.Panics(t, func() { logger.Panic("invariant broken") })
+		}
+		assert.Len(t, entriesWithMessage(t, output(), "invariant broken"), 2)
+	})
+}
+
+// TestDedupeCore tests window handling by entry time
+func TestDedupeCore(t *testing.T) {
+	newCore := func() (zapcore.Core, *observer.ObservedLogs) {
+		inner, logs := observer.New(zapcore.DebugLevel)
+		return newDedupeCore(inner, newDedupeState(time.Second, zapcore.AddSync(os.Stderr))), logs
+	}
+	write := func(core zapcore.Core, at time.Time, msg string) {
+		ent := zapcore.Entry{Level: zapcore.InfoLevel, Time: at, Message: msg}
+		if ce := core.Check(ent, nil); ce != nil {
+			ce.Write(zap.String("key", "value"))
+		}
+	}
+
+	t.Run("should write the summary when the window closes", func(t *testing.T) {
+		core, logs := newCore()
+		start := time.Unix(0, 0)
+
+		write(core, start, "storm")
+		write(core, start.Add(100*time.Millisecond), "storm")
+		write(core, start.Add(200*time.Millisecond), "storm")
+		require.Equal(t, 1, logs.Len())
+
+		write(core, start.Add(1500*time.Millisecond), "storm")
+		entries := logs.TakeAll()
+		require.Len(t, entries, 3)
+		assert.Equal(t, int64(2), entries[1].ContextMap()["repeated"])
+		assert.Equal(t, start.Add(200*time.Millisecond), entries[1].Time)
+		assert.NotContains(t, entries[2].ContextMap(), "repeated")
+	})
+
+	t.Run("should not write summaries of windows without suppressed entries", func(t *testing.T) {
+		inner, logs := observer.New(zapcore.DebugLevel)
+		core := newDedupeCore(inner, newDedupeState(20*time.Millisecond, zapcore.AddSync(os.Stderr)))
+
+		write(core, time.Now(), "once")
+		time.Sleep(100 * time.Millisecond)
+
+		assert.Equal(t, 1, logs.Len())
+	})
+
+	t.Run("should flush closed windows of other entries", func(t *testing.T) {
+		core, logs := newCore()
+		start := time.Unix(0, 0)
+
+		write(core, start, "first")
+		write(core, start.Add(10*time.Millisecond), "first")
+		write(core, start.Add(2*time.Second), "second")
+
+		entries := logs.TakeAll()
+		require.Len(t, entries, 3)
+		assert.Equal(t, "first", entries[1].Message)
+		assert.Equal(t, int64(1), entries[1].ContextMap()["repeated"])
+		assert.Equal(t, "second", entries[2].Message)
+	})
+}
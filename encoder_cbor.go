@@ -0,0 +1,291 @@
+package xlogger
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// cborEncoding is the zap encoder name registered for FormatCBOR
+const cborEncoding = "xlogger-cbor"
+
+// CBOR major types
+const (
+	cborUnsigned byte = 0
+	cborNegative byte = 1
+	cborBytes    byte = 2
+	cborText     byte = 3
+	cborArray    byte = 4
+	cborMap      byte = 5
+	cborTag      byte = 6
+)
+
+// CBOR tags used for typed values (RFC 8949 and RFC 9581). Extended time and
+// duration are maps of {1: seconds, -9: nanoseconds}.
+const (
+	cborTagDateString   = 0
+	cborTagEpochTime    = 1
+	cborTagExtendedTime = 1001
+	cborTagDuration     = 1002
+)
+
+func init() {
+	if err := zap.RegisterEncoder(cborEncoding, func(zapcore.EncoderConfig) (zapcore.Encoder, error) {
+		return newCBOREncoder(), nil
+	}); err != nil {
+		panic(err)
+	}
+}
+
+// newCBOREncoder creates an encoder writing one CBOR map per entry
+func newCBOREncoder() zapcore.Encoder {
+	return newStructuredEncoder(func(rec *entryRecord, buf *buffer.Buffer) error {
+		buf.Write(appendCBORValue(nil, recordObject(rec)))
+		return nil
+	})
+}
+
+// appendCBORHead writes a major type with its argument in the shortest form
+func appendCBORHead(b []byte, major byte, n uint64) []byte {
+	m := major << 5
+	switch {
+	case n < 24:
+		return append(b, m|byte(n))
+	case n <= math.MaxUint8:
+		return append(b, m|24, byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(b, m|25), uint16(n))
+	case n <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(b, m|26), uint32(n))
+	default:
+		return binary.BigEndian.AppendUint64(append(b, m|27), n)
+	}
+}
+
+func appendCBORInt(b []byte, v int64) []byte {
+	if v >= 0 {
+		return appendCBORHead(b, cborUnsigned, uint64(v))
+	}
+	return appendCBORHead(b, cborNegative, uint64(-1-v))
+}
+
+// appendCBORSecondsNanos writes the {1: seconds, -9: nanoseconds} map body
+func appendCBORSecondsNanos(b []byte, seconds, nanos int64) []byte {
+	b = appendCBORHead(b, cborMap, 2)
+	b = appendCBORInt(b, 1)
+	b = appendCBORInt(b, seconds)
+	b = appendCBORInt(b, -9)
+	return appendCBORInt(b, nanos)
+}
+
+func appendCBORValue(b []byte, value interface{}) []byte {
+	switch v := value.(type) {
+	case nil:
+		return append(b, 0xf6)
+	case bool:
+		if v {
+			return append(b, 0xf5)
+		}
+		return append(b, 0xf4)
+	case int64:
+		return appendCBORInt(b, v)
+	case uint64:
+		return appendCBORHead(b, cborUnsigned, v)
+	case float64:
+		return binary.BigEndian.AppendUint64(append(b, 0xfb), math.Float64bits(v))
+	case string:
+		b = appendCBORHead(b, cborText, uint64(len(v)))
+		return append(b, v...)
+	case []byte:
+		b = appendCBORHead(b, cborBytes, uint64(len(v)))
+		return append(b, v...)
+	case time.Time:
+		b = appendCBORHead(b, cborTag, cborTagExtendedTime)
+		return appendCBORSecondsNanos(b, v.Unix(), int64(v.Nanosecond()))
+	case time.Duration:
+		b = appendCBORHead(b, cborTag, cborTagDuration)
+		return appendCBORSecondsNanos(b, int64(v/time.Second), int64(v%time.Second))
+	case *objectValue:
+		b = appendCBORHead(b, cborMap, uint64(len(v.fields)))
+		for _, kv := range v.fields {
+			b = appendCBORHead(b, cborText, uint64(len(kv.key)))
+			b = append(b, kv.key...)
+			b = appendCBORValue(b, kv.value)
+		}
+		return b
+	case []interface{}:
+		b = appendCBORHead(b, cborArray, uint64(len(v)))
+		for _, item := range v {
+			b = appendCBORValue(b, item)
+		}
+		return b
+	default:
+		s := fmt.Sprintf("%v", v)
+		b = appendCBORHead(b, cborText, uint64(len(s)))
+		return append(b, s...)
+	}
+}
+
+// readCBORHead reads an initial byte and its argument
+func readCBORHead(r *bufio.Reader) (major byte, info byte, arg uint64, err error) {
+	c, err := r.ReadByte()
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	major, info = c>>5, c&0x1f
+
+	switch {
+	case info < 24:
+		return major, info, uint64(info), nil
+	case info <= 27:
+		arg, err = readUint(r, 1<<(info-24))
+		return major, info, arg, err
+	default:
+		return major, info, 0, fmt.Errorf("cbor: unsupported additional info %d", info)
+	}
+}
+
+// readCBORValue decodes one CBOR data item into the value tree.
+// Integers decode as int64 unless they only fit in uint64.
+func readCBORValue(r *bufio.Reader) (interface{}, error) {
+	major, info, arg, err := readCBORHead(r)
+	if err != nil {
+		return nil, err
+	}
+
+	switch major {
+	case cborUnsigned:
+		if arg <= math.MaxInt64 {
+			return int64(arg), nil
+		}
+		return arg, nil
+	case cborNegative:
+		if arg > math.MaxInt64 {
+			return nil, fmt.Errorf("cbor: negative integer out of range")
+		}
+		return -1 - int64(arg), nil
+	case cborBytes:
+		return readBytes(r, int(arg))
+	case cborText:
+		data, err := readBytes(r, int(arg))
+		return string(data), err
+	case cborArray:
+		values := make([]interface{}, 0, boundedCapacity(arg))
+		for i := uint64(0); i < arg; i++ {
+			item, err := readCBORValue(r)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, item)
+		}
+		return values, nil
+	case cborMap:
+		obj := &objectValue{fields: make([]objectKV, 0, boundedCapacity(arg))}
+		for i := uint64(0); i < arg; i++ {
+			key, err := readCBORValue(r)
+			if err != nil {
+				return nil, err
+			}
+			value, err := readCBORValue(r)
+			if err != nil {
+				return nil, err
+			}
+			obj.add(fmt.Sprint(key), value)
+		}
+		return obj, nil
+	case cborTag:
+		content, err := readCBORValue(r)
+		if err != nil {
+			return nil, err
+		}
+		return decodeCBORTag(arg, content)
+	default:
+		// Major type 7: simple values and floats
+		return decodeCBORSimple(info, arg)
+	}
+}
+
+// decodeCBORTag converts tagged times and durations; other tags yield their content
+func decodeCBORTag(tag uint64, content interface{}) (interface{}, error) {
+	switch tag {
+	case cborTagDateString:
+		s, _ := content.(string)
+		return time.Parse(time.RFC3339Nano, s)
+	case cborTagEpochTime:
+		switch v := content.(type) {
+		case int64:
+			return time.Unix(v, 0), nil
+		case float64:
+			sec, frac := math.Modf(v)
+			return time.Unix(int64(sec), int64(frac*1e9)), nil
+		}
+	case cborTagExtendedTime, cborTagDuration:
+		obj, ok := content.(*objectValue)
+		if !ok {
+			return nil, fmt.Errorf("cbor: tag %d content is %T", tag, content)
+		}
+		var seconds, nanos int64
+		for _, kv := range obj.fields {
+			switch kv.key {
+			case "1":
+				seconds, _ = kv.value.(int64)
+			case "-9":
+				nanos, _ = kv.value.(int64)
+			}
+		}
+		if tag == cborTagDuration {
+			return time.Duration(seconds)*time.Second + time.Duration(nanos), nil
+		}
+		return time.Unix(seconds, nanos), nil
+	}
+	return content, nil
+}
+
+// decodeCBORSimple handles booleans, null and floats (major type 7)
+func decodeCBORSimple(info byte, arg uint64) (interface{}, error) {
+	switch info {
+	case 20:
+		return false, nil
+	case 21:
+		return true, nil
+	case 22, 23:
+		return nil, nil
+	case 25:
+		return halfToFloat64(uint16(arg)), nil
+	case 26:
+		return float64(math.Float32frombits(uint32(arg))), nil
+	case 27:
+		return math.Float64frombits(arg), nil
+	default:
+		return nil, fmt.Errorf("cbor: unsupported simple value %d", info)
+	}
+}
+
+// halfToFloat64 converts an IEEE 754 half-precision float
+func halfToFloat64(h uint16) float64 {
+	exp := int(h>>10) & 0x1f
+	mant := float64(h & 0x3ff)
+	var v float64
+	switch exp {
+	case 0:
+		v = math.Ldexp(mant, -24)
+	case 31:
+		if mant == 0 {
+			v = math.Inf(1)
+		} else {
+			v = math.NaN()
+		}
+	default:
+		v = math.Ldexp(mant+1024, exp-25)
+	}
+	if h&0x8000 != 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,95 @@
+package xlogger
+
+import (
+	"bufio"
+	"bytes"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// TestCBOREncoder tests CBOR encoding and decoding of entries
+func TestCBOREncoder(t *testing.T) {
+	t.Run("should round trip typed fields", func(t *testing.T) {
+		var buf bytes.Buffer
+		at := time.Date(2024, 5, 1, 12, 0, 0, 123456789, time.UTC)
+		newEncoderTestLogger(newCBOREncoder(), &buf).Error("typed", typedTestFields(at)...)
+
+		var records []*entryRecord
+		require.NoError(t, readStructuredRecords(&buf, readCBORValue, func(rec *entryRecord) error {
+			records = append(records, rec)
+			return nil
+		}))
+		require.Len(t, records, 1)
+
+		rec := records[0]
+		assert.Equal(t, "error", rec.Level)
+		assert.Equal(t, "typed", rec.Message)
+
+		values := recordFieldValues(rec)
+		assert.Equal(t, "o-1", values["id"])
+		assert.Equal(t, int64(-42000), values["amount"])
+		assert.Equal(t, uint64(1<<63), values["big"])
+		assert.Equal(t, 0.25, values["ratio"])
+		assert.Equal(t, true, values["paid"])
+		assert.Equal(t, -1500*time.Millisecond, values["elapsed"])
+		assert.True(t, at.Equal(values["at"].(time.Time)))
+		assert.Equal(t, []byte{0x00, 0xff}, values["raw"])
+		assert.Equal(t, []interface{}{"a", "b"}, values["tags"])
+		assert.Equal(t, []objectKV{{key: "status", value: int64(201)}}, values["http"].(*objectValue).fields)
+	})
+
+	t.Run("should decode standard tags and floats", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			data     []byte
+			expected interface{}
+		}{
+			{name: "epoch time", data: []byte{0xc1, 0x1a, 0x51, 0x4b, 0x67, 0xb0}, expected: time.Unix(1363896240, 0)},
+			{name: "half float", data: []byte{0xf9, 0x3e, 0x00}, expected: 1.5},
+			{name: "single float", data: []byte{0xfa, 0x47, 0xc3, 0x50, 0x00}, expected: 100000.0},
+			{name: "null", data: []byte{0xf6}, expected: nil},
+			{name: "unknown tag", data: []byte{0xd8, 0x20, 0x61, 0x61}, expected: "a"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				value, err := readCBORValue(bufio.NewReader(bytes.NewReader(tt.data)))
+				require.NoError(t, err)
+				assert.Equal(t, tt.expected, value)
+			})
+		}
+	})
+
+	t.Run("should decode half float specials", func(t *testing.T) {
+		assert.True(t, math.IsInf(halfToFloat64(0x7c00), 1))
+		assert.True(t, math.IsNaN(halfToFloat64(0x7e00)))
+		assert.Equal(t, -2.0, halfToFloat64(0xc000))
+	})
+
+	t.Run("should reject indefinite lengths", func(t *testing.T) {
+		_, err := readCBORValue(bufio.NewReader(bytes.NewReader([]byte{0x9f, 0xff})))
+		assert.Error(t, err)
+	})
+
+	t.Run("should convert to JSON", func(t *testing.T) {
+		var buf bytes.Buffer
+		newEncoderTestLogger(newCBOREncoder(), &buf).Info("hello", zap.Int("n", -3))
+
+		var out bytes.Buffer
+		require.NoError(t, ConvertToJSON(FormatCBOR, &buf, &out))
+		assert.Contains(t, out.String(), `"message":"hello","n":-3}`)
+	})
+
+	t.Run("should be selectable through config", func(t *testing.T) {
+		logger, err := NewZapLogger(NewLoggerConfig(WithFormat(FormatCBOR)))
+		require.NoError(t, err)
+		assert.NotPanics(t, func() {
+			logger.Info("cbor entry", String("key", "value"))
+		})
+	})
+}
@@ -0,0 +1,90 @@
+package xlogger
+
+import (
+	"strconv"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// datadogEncoding is the zap encoding name of FormatDatadog
+const datadogEncoding = "datadog"
+
+// Keys Datadog correlates with APM traces
+const (
+	datadogTraceIDKey = "dd.trace_id"
+	datadogSpanIDKey  = "dd.span_id"
+)
+
+// datadogEncoderConfig returns the encoder configuration using the reserved
+// and standard attributes of Datadog log pipelines
+func datadogEncoderConfig() zapcore.EncoderConfig {
+	config := createBaseEncoderConfig()
+	config.TimeKey = "timestamp"
+	config.LevelKey = "status"
+	config.NameKey = "logger.name"
+	config.StacktraceKey = "error.stack"
+	config.EncodeLevel = datadogStatusEncoder
+	config.EncodeTime = zapcore.RFC3339NanoTimeEncoder
+	return config
+}
+
+// datadogStatusEncoder encodes levels as Datadog statuses
+func datadogStatusEncoder(level zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+	enc.AppendString(datadogStatus(level))
+}
+
+// datadogStatus returns the Datadog status of level
+func datadogStatus(level zapcore.Level) string {
+	switch level {
+	case zapcore.DPanicLevel:
+		return "critical"
+	case zapcore.PanicLevel:
+		return "alert"
+	case zapcore.FatalLevel:
+		return "emergency"
+	default:
+		return level.String()
+	}
+}
+
+// newDatadogEncoder returns an encoder of Datadog's JSON log format,
+// replacing the W3C trace_id and span_id with the decimal dd.trace_id and
+// dd.span_id Datadog correlates with traces
+func newDatadogEncoder() zapcore.Encoder {
+	return newPresetEncoder(datadogEncoderConfig(), datadogTraceFields)
+}
+
+// datadogTraceFields maps W3C trace fields to Datadog trace fields. IDs that
+// are not hexadecimal keep their key.
+func datadogTraceFields(field zapcore.Field) (zapcore.Field, bool) {
+	if field.Type != zapcore.StringType {
+		return field, false
+	}
+	key := datadogTraceIDKey
+	switch field.Key {
+	case traceIDFieldKey:
+	case spanIDFieldKey:
+		key = datadogSpanIDKey
+	default:
+		return field, false
+	}
+	id, ok := datadogID(field.String)
+	if !ok {
+		return field, false
+	}
+	return zap.String(key, id), true
+}
+
+// datadogID converts a hexadecimal W3C ID to the decimal ID of Datadog
+// tracers, the lower 64 bits of 128-bit trace IDs
+func datadogID(hex string) (string, bool) {
+	if len(hex) > 16 {
+		hex = hex[len(hex)-16:]
+	}
+	id, err := strconv.ParseUint(hex, 16, 64)
+	if err != nil {
+		return "", false
+	}
+	return strconv.FormatUint(id, 10), true
+}
@@ -0,0 +1,98 @@
+package xlogger
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// TestDatadogEncoder tests the Datadog JSON log format
+func TestDatadogEncoder(t *testing.T) {
+	t.Run("should write Datadog attributes and statuses", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := newEncoderTestLogger(newDatadogEncoder(), &buf).Named("orders")
+
+		logger.Info("order created", zap.String("order_id", "o-1"))
+		logger.Warn("slow query")
+
+		entries := decodeJSONLines(t, buf.String())
+		require.Len(t, entries, 2)
+		assert.Equal(t, "info", entries[0]["status"])
+		assert.Equal(t, "order created", entries[0]["message"])
+		assert.Equal(t, "orders", entries[0]["logger.name"])
+		assert.Equal(t, "o-1", entries[0]["order_id"])
+		assert.Contains(t, entries[0], "timestamp")
+		assert.Equal(t, "warn", entries[1]["status"])
+	})
+
+	t.Run("should map every level to a status", func(t *testing.T) {
+		expected := map[zapcore.Level]string{
+			zapcore.DebugLevel:  "debug",
+			zapcore.InfoLevel:   "info",
+			zapcore.WarnLevel:   "warn",
+			zapcore.ErrorLevel:  "error",
+			zapcore.DPanicLevel: "critical",
+			zapcore.PanicLevel:  "alert",
+			zapcore.FatalLevel:  "emergency",
+		}
+		for level, status := range expected {
+			assert.Equal(t, status, datadogStatus(level), level.String())
+		}
+	})
+
+	t.Run("should convert trace fields to decimal Datadog IDs", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := newEncoderTestLogger(newDatadogEncoder(), &buf)
+		traceFields := []zap.Field{
+			zap.String(traceIDFieldKey, "4bf92f3577b34da6a3ce929d0e0e4736"),
+			zap.String(spanIDFieldKey, "00f067aa0ba902b7"),
+		}
+
+		logger.Info("entry fields", traceFields...)
+		logger.With(traceFields...).Info("logger fields")
+		logger.Info("invalid", zap.String(traceIDFieldKey, "not-hex"))
+
+		entries := decodeJSONLines(t, buf.String())
+		require.Len(t, entries, 3)
+		for _, entry := range entries[:2] {
+			assert.Equal(t, "11803532876627986230", entry[datadogTraceIDKey])
+			assert.Equal(t, "67667974448284343", entry[datadogSpanIDKey])
+			assert.NotContains(t, entry, traceIDFieldKey)
+		}
+		assert.Equal(t, "not-hex", entries[2][traceIDFieldKey])
+		assert.Equal(t, traceIDFieldKey, traceFields[0].Key, "caller's fields should be left untouched")
+	})
+
+	t.Run("should add service tags to every entry", func(t *testing.T) {
+		skipWithoutGLS(t)
+		path := filepath.Join(t.TempDir(), "app.log")
+		logger, err := NewZapLogger(NewLoggerConfig(
+			WithOutputPaths(path),
+			WithFormat(FormatDatadog),
+			WithServiceTags("orders", "prod", "1.4.2"),
+		))
+		require.NoError(t, err)
+
+		_ = RunWithTraceparent(testTraceparent, func() error {
+			logger.Error("payment failed")
+			return nil
+		})
+		logger.ForInfra("db").Info("connected")
+		require.NoError(t, logger.Sync())
+
+		entries := decodeJSONLines(t, readFile(t, path))
+		require.Len(t, entries, 2)
+		for _, entry := range entries {
+			assert.Equal(t, "orders", entry["service"])
+			assert.Equal(t, "prod", entry["env"])
+			assert.Equal(t, "1.4.2", entry["version"])
+		}
+		assert.Equal(t, "error", entries[0]["status"])
+		assert.Equal(t, "11803532876627986230", entries[0][datadogTraceIDKey])
+	})
+}
@@ -0,0 +1,90 @@
+package xlogger
+
+import (
+	"os"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// gcpEncoding is the zap encoding name of FormatGCP
+const gcpEncoding = "gcp"
+
+// gcpProjectEnv names the project of the trace resource names
+const gcpProjectEnv = "GOOGLE_CLOUD_PROJECT"
+
+// Keys Cloud Logging reads from structured log entries
+const (
+	gcpTraceKey        = "logging.googleapis.com/trace"
+	gcpSpanIDKey       = "logging.googleapis.com/spanId"
+	gcpTraceSampledKey = "logging.googleapis.com/trace_sampled"
+)
+
+// traceSampledFieldKey is the sampling flag added by xloggerotel
+const traceSampledFieldKey = "trace_sampled"
+
+// gcpEncoderConfig returns the encoder configuration of the Cloud Logging
+// structured log format
+func gcpEncoderConfig() zapcore.EncoderConfig {
+	config := createBaseEncoderConfig()
+	config.TimeKey = "timestamp"
+	config.LevelKey = "severity"
+	config.StacktraceKey = "stack_trace" // Read by Error Reporting
+	config.EncodeLevel = gcpSeverityEncoder
+	config.EncodeTime = zapcore.RFC3339NanoTimeEncoder
+	return config
+}
+
+// gcpSeverityEncoder encodes levels as Cloud Logging severities
+func gcpSeverityEncoder(level zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+	enc.AppendString(gcpSeverity(level))
+}
+
+// gcpSeverity returns the Cloud Logging severity of level
+func gcpSeverity(level zapcore.Level) string {
+	switch level {
+	case zapcore.DebugLevel:
+		return "DEBUG"
+	case zapcore.InfoLevel:
+		return "INFO"
+	case zapcore.WarnLevel:
+		return "WARNING"
+	case zapcore.ErrorLevel:
+		return "ERROR"
+	case zapcore.DPanicLevel:
+		return "CRITICAL"
+	case zapcore.PanicLevel:
+		return "ALERT"
+	case zapcore.FatalLevel:
+		return "EMERGENCY"
+	default:
+		return "DEFAULT"
+	}
+}
+
+// newGCPEncoder returns an encoder of the Cloud Logging structured log
+// format, moving trace_id, span_id and trace_sampled to the keys Cloud
+// Logging correlates with Cloud Trace
+func newGCPEncoder() zapcore.Encoder {
+	return newPresetEncoder(gcpEncoderConfig(), gcpTraceFields(os.Getenv(gcpProjectEnv)))
+}
+
+// gcpTraceFields returns the mapper of trace fields to Cloud Logging keys.
+// Trace IDs become resource names of project, or stay bare without one.
+func gcpTraceFields(project string) fieldMapper {
+	return func(field zapcore.Field) (zapcore.Field, bool) {
+		switch {
+		case field.Key == traceIDFieldKey && field.Type == zapcore.StringType:
+			trace := field.String
+			if project != "" {
+				trace = "projects/" + project + "/traces/" + trace
+			}
+			return zap.String(gcpTraceKey, trace), true
+		case field.Key == spanIDFieldKey && field.Type == zapcore.StringType:
+			return zap.String(gcpSpanIDKey, field.String), true
+		case field.Key == traceSampledFieldKey && field.Type == zapcore.BoolType:
+			return zap.Bool(gcpTraceSampledKey, field.Integer == 1), true
+		}
+		return field, false
+	}
+}
@@ -0,0 +1,127 @@
+package xlogger
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// decodeJSONLines decodes the JSON lines written by a preset encoder
+func decodeJSONLines(t *testing.T, output string) []map[string]interface{} {
+	t.Helper()
+
+	var entries []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		var entry map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// TestGCPEncoder tests the Cloud Logging structured log format
+func TestGCPEncoder(t *testing.T) {
+	t.Run("should write Cloud Logging keys and severities", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := newEncoderTestLogger(newGCPEncoder(), &buf)
+
+		logger.Info("order created", zap.String("order_id", "o-1"))
+		logger.Warn("slow query")
+
+		entries := decodeJSONLines(t, buf.String())
+		require.Len(t, entries, 2)
+		assert.Equal(t, "INFO", entries[0]["severity"])
+		assert.Equal(t, "order created", entries[0]["message"])
+		assert.Equal(t, "o-1", entries[0]["order_id"])
+		assert.Equal(t, "WARNING", entries[1]["severity"])
+
+		timestamp, err := time.Parse(time.RFC3339Nano, entries[0]["timestamp"].(string))
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now(), timestamp, time.Minute)
+		assert.NotContains(t, entries[0], "level")
+		assert.NotContains(t, entries[0], "time")
+	})
+
+	t.Run("should map every level to a severity", func(t *testing.T) {
+		expected := map[zapcore.Level]string{
+			zapcore.DebugLevel:  "DEBUG",
+			zapcore.InfoLevel:   "INFO",
+			zapcore.WarnLevel:   "WARNING",
+			zapcore.ErrorLevel:  "ERROR",
+			zapcore.DPanicLevel: "CRITICAL",
+			zapcore.PanicLevel:  "ALERT",
+			zapcore.FatalLevel:  "EMERGENCY",
+			zapcore.Level(42):   "DEFAULT",
+		}
+		for level, severity := range expected {
+			assert.Equal(t, severity, gcpSeverity(level), level.String())
+		}
+	})
+
+	t.Run("should move trace fields to the Cloud Logging keys", func(t *testing.T) {
+		t.Setenv(gcpProjectEnv, "shop-prod")
+
+		var buf bytes.Buffer
+		logger := newEncoderTestLogger(newGCPEncoder(), &buf)
+		traceFields := []zap.Field{
+			zap.String(traceIDFieldKey, "4bf92f3577b34da6a3ce929d0e0e4736"),
+			zap.String(spanIDFieldKey, "00f067aa0ba902b7"),
+			zap.Bool(traceSampledFieldKey, true),
+		}
+
+		logger.Info("entry fields", traceFields...)
+		logger.With(traceFields...).Info("logger fields")
+
+		for _, entry := range decodeJSONLines(t, buf.String()) {
+			assert.Equal(t, "projects/shop-prod/traces/4bf92f3577b34da6a3ce929d0e0e4736", entry[gcpTraceKey])
+			assert.Equal(t, "00f067aa0ba902b7", entry[gcpSpanIDKey])
+			assert.Equal(t, true, entry[gcpTraceSampledKey])
+			assert.NotContains(t, entry, traceIDFieldKey)
+			assert.NotContains(t, entry, traceSampledFieldKey)
+		}
+		assert.Equal(t, traceIDFieldKey, traceFields[0].Key, "caller's fields should be left untouched")
+	})
+
+	t.Run("should write bare trace IDs without a project", func(t *testing.T) {
+		t.Setenv(gcpProjectEnv, "")
+
+		var buf bytes.Buffer
+		newEncoderTestLogger(newGCPEncoder(), &buf).Info("request",
+			zap.String(traceIDFieldKey, "4bf92f3577b34da6a3ce929d0e0e4736"),
+			zap.Int(spanIDFieldKey, 7))
+
+		entries := decodeJSONLines(t, buf.String())
+		require.Len(t, entries, 1)
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", entries[0][gcpTraceKey])
+		assert.Equal(t, float64(7), entries[0][spanIDFieldKey], "non-string span IDs keep their key")
+	})
+
+	t.Run("should emit the traceparent of the trace scope", func(t *testing.T) {
+		skipWithoutGLS(t)
+		t.Setenv(gcpProjectEnv, "shop-prod")
+
+		path := filepath.Join(t.TempDir(), "app.log")
+		logger, err := NewZapLogger(NewLoggerConfig(WithOutputPaths(path), WithFormat(FormatGCP)))
+		require.NoError(t, err)
+
+		_ = RunWithTraceparent(testTraceparent, func() error {
+			logger.Error("payment failed")
+			return nil
+		})
+		require.NoError(t, logger.Sync())
+
+		entries := decodeJSONLines(t, readFile(t, path))
+		require.Len(t, entries, 1)
+		assert.Equal(t, "ERROR", entries[0]["severity"])
+		assert.Equal(t, "projects/shop-prod/traces/4bf92f3577b34da6a3ce929d0e0e4736", entries[0][gcpTraceKey])
+		assert.Equal(t, "00f067aa0ba902b7", entries[0][gcpSpanIDKey])
+	})
+}
@@ -0,0 +1,337 @@
+package xlogger
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// msgpackEncoding is the zap encoder name registered for FormatMsgpack
+const msgpackEncoding = "xlogger-msgpack"
+
+// MessagePack extension types. Times use the standard timestamp extension;
+// durations use an application extension holding int64 nanoseconds.
+const (
+	msgpackTimestampExt byte = 0xff // -1 as int8
+	msgpackDurationExt  byte = 1
+)
+
+func init() {
+	if err := zap.RegisterEncoder(msgpackEncoding, func(zapcore.EncoderConfig) (zapcore.Encoder, error) {
+		return newMsgpackEncoder(), nil
+	}); err != nil {
+		panic(err)
+	}
+}
+
+// newMsgpackEncoder creates an encoder writing one MessagePack map per entry
+func newMsgpackEncoder() zapcore.Encoder {
+	return newStructuredEncoder(func(rec *entryRecord, buf *buffer.Buffer) error {
+		buf.Write(appendMsgpackValue(nil, recordObject(rec)))
+		return nil
+	})
+}
+
+func appendMsgpackValue(b []byte, value interface{}) []byte {
+	switch v := value.(type) {
+	case nil:
+		return append(b, 0xc0)
+	case bool:
+		if v {
+			return append(b, 0xc3)
+		}
+		return append(b, 0xc2)
+	case int64:
+		return appendMsgpackInt(b, v)
+	case uint64:
+		return appendMsgpackUint(b, v)
+	case float64:
+		b = append(b, 0xcb)
+		return binary.BigEndian.AppendUint64(b, math.Float64bits(v))
+	case string:
+		return appendMsgpackString(b, v)
+	case []byte:
+		return appendMsgpackBinary(b, v)
+	case time.Time:
+		// timestamp 96: uint32 nanoseconds followed by int64 seconds
+		b = append(b, 0xc7, 12, msgpackTimestampExt)
+		b = binary.BigEndian.AppendUint32(b, uint32(v.Nanosecond()))
+		return binary.BigEndian.AppendUint64(b, uint64(v.Unix()))
+	case time.Duration:
+		b = append(b, 0xd7, msgpackDurationExt)
+		return binary.BigEndian.AppendUint64(b, uint64(v))
+	case *objectValue:
+		b = appendMsgpackLength(b, len(v.fields), 0x80, 0xde, 0xdf)
+		for _, kv := range v.fields {
+			b = appendMsgpackString(b, kv.key)
+			b = appendMsgpackValue(b, kv.value)
+		}
+		return b
+	case []interface{}:
+		b = appendMsgpackLength(b, len(v), 0x90, 0xdc, 0xdd)
+		for _, item := range v {
+			b = appendMsgpackValue(b, item)
+		}
+		return b
+	default:
+		return appendMsgpackString(b, fmt.Sprintf("%v", v))
+	}
+}
+
+// appendMsgpackInt always uses the signed family (or a fixint) so decoding
+// can tell signed and unsigned values apart
+func appendMsgpackInt(b []byte, v int64) []byte {
+	switch {
+	case v >= 0 && v <= 0x7f:
+		return append(b, byte(v))
+	case v < 0 && v >= -32:
+		return append(b, byte(int8(v)))
+	case v >= math.MinInt8 && v <= math.MaxInt8:
+		return append(b, 0xd0, byte(int8(v)))
+	case v >= math.MinInt16 && v <= math.MaxInt16:
+		return binary.BigEndian.AppendUint16(append(b, 0xd1), uint16(int16(v)))
+	case v >= math.MinInt32 && v <= math.MaxInt32:
+		return binary.BigEndian.AppendUint32(append(b, 0xd2), uint32(int32(v)))
+	default:
+		return binary.BigEndian.AppendUint64(append(b, 0xd3), uint64(v))
+	}
+}
+
+// appendMsgpackUint always uses the unsigned family, never a fixint
+func appendMsgpackUint(b []byte, v uint64) []byte {
+	switch {
+	case v <= math.MaxUint8:
+		return append(b, 0xcc, byte(v))
+	case v <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(b, 0xcd), uint16(v))
+	case v <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(b, 0xce), uint32(v))
+	default:
+		return binary.BigEndian.AppendUint64(append(b, 0xcf), v)
+	}
+}
+
+func appendMsgpackString(b []byte, s string) []byte {
+	n := len(s)
+	switch {
+	case n < 32:
+		b = append(b, 0xa0|byte(n))
+	case n <= math.MaxUint8:
+		b = append(b, 0xd9, byte(n))
+	case n <= math.MaxUint16:
+		b = binary.BigEndian.AppendUint16(append(b, 0xda), uint16(n))
+	default:
+		b = binary.BigEndian.AppendUint32(append(b, 0xdb), uint32(n))
+	}
+	return append(b, s...)
+}
+
+func appendMsgpackBinary(b []byte, data []byte) []byte {
+	n := len(data)
+	switch {
+	case n <= math.MaxUint8:
+		b = append(b, 0xc4, byte(n))
+	case n <= math.MaxUint16:
+		b = binary.BigEndian.AppendUint16(append(b, 0xc5), uint16(n))
+	default:
+		b = binary.BigEndian.AppendUint32(append(b, 0xc6), uint32(n))
+	}
+	return append(b, data...)
+}
+
+// appendMsgpackLength writes a map or array header using the fix, 16 and 32 bit forms
+func appendMsgpackLength(b []byte, n int, fix, len16, len32 byte) []byte {
+	switch {
+	case n < 16:
+		return append(b, fix|byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(b, len16), uint16(n))
+	default:
+		return binary.BigEndian.AppendUint32(append(b, len32), uint32(n))
+	}
+}
+
+// readMsgpackValue decodes one MessagePack value into the value tree
+func readMsgpackValue(r *bufio.Reader) (interface{}, error) {
+	c, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case c <= 0x7f:
+		return int64(c), nil
+	case c >= 0xe0:
+		return int64(int8(c)), nil
+	case c&0xf0 == 0x80:
+		return readMsgpackMap(r, int(c&0x0f))
+	case c&0xf0 == 0x90:
+		return readMsgpackArray(r, int(c&0x0f))
+	case c&0xe0 == 0xa0:
+		return readMsgpackString(r, int(c&0x1f))
+	}
+
+	switch c {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xc4, 0xc5, 0xc6:
+		n, err := readMsgpackSize(r, c-0xc4)
+		if err != nil {
+			return nil, err
+		}
+		return readBytes(r, n)
+	case 0xc7, 0xc8, 0xc9:
+		n, err := readMsgpackSize(r, c-0xc7)
+		if err != nil {
+			return nil, err
+		}
+		return readMsgpackExt(r, n)
+	case 0xca:
+		bits, err := readUint(r, 4)
+		return float64(math.Float32frombits(uint32(bits))), err
+	case 0xcb:
+		bits, err := readUint(r, 8)
+		return math.Float64frombits(bits), err
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		return readUint(r, 1<<(c-0xcc))
+	case 0xd0, 0xd1, 0xd2, 0xd3:
+		size := 1 << (c - 0xd0)
+		v, err := readUint(r, size)
+		if err != nil {
+			return nil, err
+		}
+		shift := 64 - 8*size
+		return int64(v<<shift) >> shift, nil
+	case 0xd4, 0xd5, 0xd6, 0xd7, 0xd8:
+		return readMsgpackExt(r, 1<<(c-0xd4))
+	case 0xd9, 0xda, 0xdb:
+		n, err := readMsgpackSize(r, c-0xd9)
+		if err != nil {
+			return nil, err
+		}
+		return readMsgpackString(r, n)
+	case 0xdc, 0xdd:
+		n, err := readMsgpackSize(r, c-0xdc+1)
+		if err != nil {
+			return nil, err
+		}
+		return readMsgpackArray(r, n)
+	case 0xde, 0xdf:
+		n, err := readMsgpackSize(r, c-0xde+1)
+		if err != nil {
+			return nil, err
+		}
+		return readMsgpackMap(r, n)
+	default:
+		return nil, fmt.Errorf("msgpack: unsupported type byte 0x%02x", c)
+	}
+}
+
+// readMsgpackSize reads an 8, 16 or 32 bit length selected by width (0, 1, 2)
+func readMsgpackSize(r *bufio.Reader, width byte) (int, error) {
+	v, err := readUint(r, 1<<width)
+	return int(v), err
+}
+
+func readMsgpackString(r *bufio.Reader, n int) (interface{}, error) {
+	data, err := readBytes(r, n)
+	return string(data), err
+}
+
+func readMsgpackArray(r *bufio.Reader, n int) (interface{}, error) {
+	values := make([]interface{}, 0, boundedCapacity(uint64(n)))
+	for i := 0; i < n; i++ {
+		item, err := readMsgpackValue(r)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, item)
+	}
+	return values, nil
+}
+
+func readMsgpackMap(r *bufio.Reader, n int) (interface{}, error) {
+	obj := &objectValue{fields: make([]objectKV, 0, boundedCapacity(uint64(n)))}
+	for i := 0; i < n; i++ {
+		key, err := readMsgpackValue(r)
+		if err != nil {
+			return nil, err
+		}
+		value, err := readMsgpackValue(r)
+		if err != nil {
+			return nil, err
+		}
+		obj.add(fmt.Sprint(key), value)
+	}
+	return obj, nil
+}
+
+// readMsgpackExt decodes the timestamp and duration extensions; other
+// extensions are returned as their raw payload
+func readMsgpackExt(r *bufio.Reader, n int) (interface{}, error) {
+	typ, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	data, err := readBytes(r, n)
+	if err != nil {
+		return nil, err
+	}
+
+	switch typ {
+	case msgpackTimestampExt:
+		switch n {
+		case 4:
+			return time.Unix(int64(binary.BigEndian.Uint32(data)), 0), nil
+		case 8:
+			v := binary.BigEndian.Uint64(data)
+			return time.Unix(int64(v&0x3ffffffff), int64(v>>34)), nil
+		case 12:
+			nsec := binary.BigEndian.Uint32(data[:4])
+			sec := int64(binary.BigEndian.Uint64(data[4:]))
+			return time.Unix(sec, int64(nsec)), nil
+		}
+		return nil, fmt.Errorf("msgpack: invalid timestamp length %d", n)
+	case msgpackDurationExt:
+		if n != 8 {
+			return nil, fmt.Errorf("msgpack: invalid duration length %d", n)
+		}
+		return time.Duration(int64(binary.BigEndian.Uint64(data))), nil
+	default:
+		return data, nil
+	}
+}
+
+// readUint reads a big-endian unsigned integer of size bytes
+func readUint(r *bufio.Reader, size int) (uint64, error) {
+	data, err := readBytes(r, size)
+	if err != nil {
+		return 0, err
+	}
+	var v uint64
+	for _, c := range data {
+		v = v<<8 | uint64(c)
+	}
+	return v, nil
+}
+
+// readBytes reads exactly n bytes
+func readBytes(r *bufio.Reader, n int) ([]byte, error) {
+	if n < 0 || n > maxEncodedEntrySize {
+		return nil, fmt.Errorf("length %d exceeds limit", n)
+	}
+	data := make([]byte, n)
+	_, err := io.ReadFull(r, data)
+	return data, err
+}
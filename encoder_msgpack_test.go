@@ -0,0 +1,128 @@
+package xlogger
+
+import (
+	"bufio"
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// typedTestFields covers every value kind of the structured encoders
+func typedTestFields(at time.Time) []zap.Field {
+	return []zap.Field{
+		zap.String("id", "o-1"),
+		zap.Int64("amount", -42000),
+		zap.Int("small", 7),
+		zap.Uint64("big", 1<<63),
+		zap.Float64("ratio", 0.25),
+		zap.Bool("paid", true),
+		zap.Duration("elapsed", -1500*time.Millisecond),
+		zap.Time("at", at),
+		zap.Binary("raw", []byte{0x00, 0xff}),
+		zap.Strings("tags", []string{"a", "b"}),
+		zap.Namespace("http"),
+		zap.Int("status", 201),
+	}
+}
+
+// recordFieldValues flattens top-level record fields by key
+func recordFieldValues(rec *entryRecord) map[string]interface{} {
+	values := map[string]interface{}{}
+	for _, kv := range rec.Fields.fields {
+		values[kv.key] = kv.value
+	}
+	return values
+}
+
+// TestMsgpackEncoder tests MessagePack encoding and decoding of entries
+func TestMsgpackEncoder(t *testing.T) {
+	t.Run("should round trip typed fields", func(t *testing.T) {
+		var buf bytes.Buffer
+		at := time.Date(2024, 5, 1, 12, 0, 0, 123456789, time.UTC)
+		newEncoderTestLogger(newMsgpackEncoder(), &buf).Named("svc").Warn("typed", typedTestFields(at)...)
+
+		var records []*entryRecord
+		require.NoError(t, readStructuredRecords(&buf, readMsgpackValue, func(rec *entryRecord) error {
+			records = append(records, rec)
+			return nil
+		}))
+		require.Len(t, records, 1)
+
+		rec := records[0]
+		assert.Equal(t, "warn", rec.Level)
+		assert.Equal(t, "svc", rec.Logger)
+		assert.Equal(t, "typed", rec.Message)
+
+		values := recordFieldValues(rec)
+		assert.Equal(t, "o-1", values["id"])
+		assert.Equal(t, int64(-42000), values["amount"])
+		assert.Equal(t, int64(7), values["small"])
+		assert.Equal(t, uint64(1<<63), values["big"])
+		assert.Equal(t, 0.25, values["ratio"])
+		assert.Equal(t, true, values["paid"])
+		assert.Equal(t, -1500*time.Millisecond, values["elapsed"])
+		assert.True(t, at.Equal(values["at"].(time.Time)))
+		assert.Equal(t, []byte{0x00, 0xff}, values["raw"])
+		assert.Equal(t, []interface{}{"a", "b"}, values["tags"])
+		assert.Equal(t, []objectKV{{key: "status", value: int64(201)}}, values["http"].(*objectValue).fields)
+	})
+
+	t.Run("should keep unsigned values distinct from small ints", func(t *testing.T) {
+		b := appendMsgpackValue(nil, uint64(5))
+		value, err := readMsgpackValue(bufio.NewReader(bytes.NewReader(b)))
+		require.NoError(t, err)
+		assert.Equal(t, uint64(5), value)
+	})
+
+	t.Run("should round trip large strings and collections", func(t *testing.T) {
+		long := string(bytes.Repeat([]byte("x"), 70000))
+		obj := &objectValue{}
+		items := make([]interface{}, 20)
+		for i := range items {
+			items[i] = int64(-i * 1000)
+			obj.add(string(rune('a'+i)), int64(i))
+		}
+		for _, value := range []interface{}{long, items, obj, int64(-1 << 40), nil} {
+			decoded, err := readMsgpackValue(bufio.NewReader(bytes.NewReader(appendMsgpackValue(nil, value))))
+			require.NoError(t, err)
+			assert.Equal(t, value, decoded)
+		}
+	})
+
+	t.Run("should convert to JSON", func(t *testing.T) {
+		var buf bytes.Buffer
+		newEncoderTestLogger(newMsgpackEncoder(), &buf).Info("hello", zap.String("k", "v"))
+
+		var out bytes.Buffer
+		require.NoError(t, ConvertToJSON(FormatMsgpack, &buf, &out))
+		assert.Contains(t, out.String(), `"message":"hello","k":"v"}`)
+	})
+
+	t.Run("should be selectable through config", func(t *testing.T) {
+		logger, err := NewZapLogger(NewLoggerConfig(WithFormat(FormatMsgpack)))
+		require.NoError(t, err)
+		assert.NotPanics(t, func() {
+			logger.Info("msgpack entry", String("key", "value"))
+		})
+	})
+}
+
+// TestConvertToJSON tests format dispatch of the JSON converter
+func TestConvertToJSON(t *testing.T) {
+	t.Run("should reject text formats", func(t *testing.T) {
+		err := ConvertToJSON(FormatJSON, bytes.NewReader(nil), &bytes.Buffer{})
+		assert.Error(t, err)
+	})
+
+	t.Run("should report truncated input", func(t *testing.T) {
+		var buf bytes.Buffer
+		newEncoderTestLogger(newMsgpackEncoder(), &buf).Info("truncated")
+
+		err := ConvertToJSON(FormatMsgpack, bytes.NewReader(buf.Bytes()[:buf.Len()-2]), &bytes.Buffer{})
+		assert.Error(t, err)
+	})
+}
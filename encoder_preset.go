@@ -0,0 +1,64 @@
+package xlogger
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// fieldMapper returns field under the key a log platform reads, and whether
+// it changed it
+type fieldMapper func(field zapcore.Field) (zapcore.Field, bool)
+
+// presetEncoder writes JSON entries in the format of a log platform, moving
+// fields such as trace IDs to the keys the platform reads
+type presetEncoder struct {
+	zapcore.Encoder
+	mapField fieldMapper
+}
+
+func newPresetEncoder(config zapcore.EncoderConfig, mapField fieldMapper) zapcore.Encoder {
+	return &presetEncoder{Encoder: zapcore.NewJSONEncoder(config), mapField: mapField}
+}
+
+// Clone implements zapcore.Encoder
+func (e *presetEncoder) Clone() zapcore.Encoder {
+	return &presetEncoder{Encoder: e.Encoder.Clone(), mapField: e.mapField}
+}
+
+// AddString implements zapcore.ObjectEncoder for fields added with With
+func (e *presetEncoder) AddString(key, value string) {
+	e.addField(zap.String(key, value))
+}
+
+// AddBool implements zapcore.ObjectEncoder for fields added with With
+func (e *presetEncoder) AddBool(key string, value bool) {
+	e.addField(zap.Bool(key, value))
+}
+
+func (e *presetEncoder) addField(field zapcore.Field) {
+	if mapped, ok := e.mapField(field); ok {
+		field = mapped
+	}
+	field.AddTo(e.Encoder)
+}
+
+// EncodeEntry implements zapcore.Encoder
+func (e *presetEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	var mapped []zapcore.Field
+	for i, field := range fields {
+		mappedField, ok := e.mapField(field)
+		if !ok {
+			continue
+		}
+		if mapped == nil {
+			// Copy so the caller's fields are left untouched
+			mapped = append([]zapcore.Field(nil), fields...)
+		}
+		mapped[i] = mappedField
+	}
+	if mapped != nil {
+		fields = mapped
+	}
+	return e.Encoder.EncodeEntry(ent, fields)
+}
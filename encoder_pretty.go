@@ -0,0 +1,147 @@
+package xlogger
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// prettyEncoding is the zap encoding name of FormatPretty
+const prettyEncoding = "pretty"
+
+// prettyIndent indents the field block and stack trace under the message
+const prettyIndent = "    "
+
+// ANSI escape sequences of the pretty encoder
+const (
+	ansiReset   = "\x1b[0m"
+	ansiBold    = "\x1b[1m"
+	ansiFaint   = "\x1b[2m"
+	ansiRed     = "\x1b[31m"
+	ansiYellow  = "\x1b[33m"
+	ansiBlue    = "\x1b[34m"
+	ansiMagenta = "\x1b[35m"
+	ansiCyan    = "\x1b[36m"
+)
+
+var prettyPool = buffer.NewPool()
+
+// prettyEncoder writes the message of an entry on one line and its fields
+// as an indented, colorized block underneath, for reading logs in a
+// terminal during development
+type prettyEncoder struct {
+	*zapcore.MapObjectEncoder // Logger fields added with With
+	lineEnding                string
+}
+
+func newPrettyEncoder(config zapcore.EncoderConfig) zapcore.Encoder {
+	lineEnding := config.LineEnding
+	if lineEnding == "" {
+		lineEnding = zapcore.DefaultLineEnding
+	}
+	return &prettyEncoder{MapObjectEncoder: zapcore.NewMapObjectEncoder(), lineEnding: lineEnding}
+}
+
+// Clone implements zapcore.Encoder
+func (e *prettyEncoder) Clone() zapcore.Encoder {
+	clone := zapcore.NewMapObjectEncoder()
+	for key, value := range e.Fields {
+		clone.Fields[key] = value
+	}
+	return &prettyEncoder{MapObjectEncoder: clone, lineEnding: e.lineEnding}
+}
+
+// EncodeEntry implements zapcore.Encoder
+func (e *prettyEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	enc := e.Clone().(*prettyEncoder)
+	for _, field := range fields {
+		field.AddTo(enc.MapObjectEncoder)
+	}
+
+	buf := prettyPool.Get()
+	buf.AppendString(ansiFaint)
+	buf.AppendString(ent.Time.Format(ConsoleTimeLayout))
+	buf.AppendString(ansiReset)
+	buf.AppendByte(' ')
+	buf.AppendString(prettyLevelColor(ent.Level))
+	buf.AppendString(fmt.Sprintf("%-5s", ent.Level.CapitalString()))
+	buf.AppendString(ansiReset)
+	if ent.LoggerName != "" {
+		buf.AppendByte(' ')
+		buf.AppendString(ent.LoggerName)
+	}
+	buf.AppendByte(' ')
+	buf.AppendString(ansiBold)
+	buf.AppendString(ent.Message)
+	buf.AppendString(ansiReset)
+	if ent.Caller.Defined {
+		buf.AppendString(ansiFaint)
+		buf.AppendString(" (")
+		buf.AppendString(ent.Caller.TrimmedPath())
+		buf.AppendByte(')')
+		buf.AppendString(ansiReset)
+	}
+	buf.AppendString(e.lineEnding)
+
+	keys := make([]string, 0, len(enc.Fields))
+	for key := range enc.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		buf.AppendString(prettyIndent)
+		buf.AppendString(ansiCyan)
+		buf.AppendString(key)
+		buf.AppendString(ansiReset)
+		buf.AppendString(": ")
+		buf.AppendString(prettyValue(enc.Fields[key]))
+		buf.AppendString(e.lineEnding)
+	}
+
+	if ent.Stack != "" {
+		for _, line := range strings.Split(ent.Stack, "\n") {
+			buf.AppendString(prettyIndent)
+			buf.AppendString(ansiFaint)
+			buf.AppendString(line)
+			buf.AppendString(ansiReset)
+			buf.AppendString(e.lineEnding)
+		}
+	}
+	return buf, nil
+}
+
+// prettyLevelColor returns the color of level
+func prettyLevelColor(level zapcore.Level) string {
+	switch level {
+	case zapcore.DebugLevel:
+		return ansiMagenta
+	case zapcore.InfoLevel:
+		return ansiBlue
+	case zapcore.WarnLevel:
+		return ansiYellow
+	default:
+		return ansiRed
+	}
+}
+
+// prettyValue formats a field value, with objects, arrays and reflected
+// values as JSON
+func prettyValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case time.Duration, fmt.Stringer:
+		return fmt.Sprint(v)
+	}
+	if data, err := json.Marshal(value); err == nil {
+		return string(data)
+	}
+	return fmt.Sprint(value)
+}
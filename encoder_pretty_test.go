@@ -0,0 +1,108 @@
+package xlogger
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// stripANSI removes the color escape sequences of the pretty encoder
+func stripANSI(s string) string {
+	for _, seq := range []string{ansiReset, ansiBold, ansiFaint, ansiRed, ansiYellow, ansiBlue, ansiMagenta, ansiCyan} {
+		s = strings.ReplaceAll(s, seq, "")
+	}
+	return s
+}
+
+// TestPrettyEncoder tests the multi-line development encoder
+func TestPrettyEncoder(t *testing.T) {
+	t.Run("should print the message and an indented field block", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := newEncoderTestLogger(newPrettyEncoder(createBaseEncoderConfig()), &buf).
+			Named("orders").
+			With(zap.String("service", "api"))
+
+		logger.Warn("order delayed",
+			zap.Int("items", 3),
+			zap.Duration("delay", 1500*time.Millisecond),
+			zap.Any("address", map[string]string{"city": "Oslo"}),
+			zap.Strings("tags", []string{"retry"}))
+
+		lines := strings.Split(strings.TrimSuffix(stripANSI(buf.String()), "\n"), "\n")
+		require.Len(t, lines, 6)
+		assert.Regexp(t, `^\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2} [+-]\d{2}:\d{2} WARN  orders order delayed$`, lines[0])
+		assert.Equal(t, []string{
+			`    address: {"city":"Oslo"}`,
+			`    delay: 1.5s`,
+			`    items: 3`,
+			`    service: api`,
+			`    tags: ["retry"]`,
+		}, lines[1:])
+	})
+
+	t.Run("should color levels and keys", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := newEncoderTestLogger(newPrettyEncoder(createBaseEncoderConfig()), &buf)
+
+		logger.Error("payment failed", zap.String("order_id", "o-1"))
+
+		assert.Contains(t, buf.String(), ansiRed+"ERROR"+ansiReset)
+		assert.Contains(t, buf.String(), ansiBold+"payment failed"+ansiReset)
+		assert.Contains(t, buf.String(), prettyIndent+ansiCyan+"order_id"+ansiReset+": o-1\n")
+	})
+
+	t.Run("should not share logger fields between clones", func(t *testing.T) {
+		var buf bytes.Buffer
+		base := newEncoderTestLogger(newPrettyEncoder(createBaseEncoderConfig()), &buf)
+
+		base.With(zap.String("a", "1")).Info("first")
+		base.Info("second")
+
+		assert.NotContains(t, stripANSI(buf.String()[strings.Index(buf.String(), "second"):]), "a: 1")
+	})
+
+	t.Run("should print multi-line entries in development mode only", func(t *testing.T) {
+		for _, development := range []bool{true, false} {
+			path := filepath.Join(t.TempDir(), "app.log")
+			logger, err := NewZapLogger(NewLoggerConfig(
+				WithOutputPaths(path),
+				WithFormat(FormatPretty),
+				WithDevelopment(development),
+				WithDisableStacktrace(true),
+			))
+			require.NoError(t, err)
+
+			logger.Info("started", String("port", "8080"))
+			require.NoError(t, logger.Sync())
+
+			output := stripANSI(readFile(t, path))
+			if development {
+				assert.Contains(t, output, "INFO  started (")
+				assert.Contains(t, output, "\n    port: 8080\n")
+			} else {
+				assert.Contains(t, output, `{"port": "8080"}`)
+				assert.Equal(t, 1, strings.Count(output, "\n"))
+			}
+		}
+	})
+
+	t.Run("should indent stack traces", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := zap.New(zapcore.NewCore(newPrettyEncoder(createBaseEncoderConfig()), zapcore.AddSync(&buf), zapcore.DebugLevel),
+			zap.AddStacktrace(zapcore.ErrorLevel))
+
+		logger.Error("crashed")
+
+		lines := strings.Split(stripANSI(buf.String()), "\n")
+		require.Greater(t, len(lines), 2)
+		assert.True(t, strings.HasPrefix(lines[1], prettyIndent))
+		assert.Contains(t, lines[1], "TestPrettyEncoder")
+	})
+}
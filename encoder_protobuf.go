@@ -0,0 +1,358 @@
+package xlogger
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// protobufEncoding is the zap encoder name registered for FormatProtobuf
+const protobufEncoding = "xlogger-protobuf"
+
+// Field numbers from proto/xlogger/v1/entry.proto
+const (
+	pbEntryTime       protowire.Number = 1
+	pbEntryLevel      protowire.Number = 2
+	pbEntryLogger     protowire.Number = 3
+	pbEntryCaller     protowire.Number = 4
+	pbEntryMessage    protowire.Number = 5
+	pbEntryStacktrace protowire.Number = 6
+	pbEntryFields     protowire.Number = 7
+
+	pbFieldKey   protowire.Number = 1
+	pbFieldValue protowire.Number = 2
+
+	pbValueBool     protowire.Number = 1
+	pbValueInt      protowire.Number = 2
+	pbValueUint     protowire.Number = 3
+	pbValueDouble   protowire.Number = 4
+	pbValueString   protowire.Number = 5
+	pbValueBytes    protowire.Number = 6
+	pbValueTime     protowire.Number = 7
+	pbValueDuration protowire.Number = 8
+	pbValueObject   protowire.Number = 9
+	pbValueArray    protowire.Number = 10
+	pbValueNull     protowire.Number = 11
+
+	pbObjectFields protowire.Number = 1
+	pbArrayValues  protowire.Number = 1
+)
+
+func init() {
+	if err := zap.RegisterEncoder(protobufEncoding, func(zapcore.EncoderConfig) (zapcore.Encoder, error) {
+		return newProtobufEncoder(), nil
+	}); err != nil {
+		panic(err)
+	}
+}
+
+// newProtobufEncoder creates an encoder writing length-delimited Entry messages
+func newProtobufEncoder() zapcore.Encoder {
+	return newStructuredEncoder(marshalProtobufRecord)
+}
+
+// marshalProtobufRecord appends a varint length prefix and the Entry message
+func marshalProtobufRecord(rec *entryRecord, buf *buffer.Buffer) error {
+	msg := appendProtobufEntry(nil, rec)
+	buf.Write(protowire.AppendVarint(nil, uint64(len(msg))))
+	buf.Write(msg)
+	return nil
+}
+
+func appendProtobufEntry(b []byte, rec *entryRecord) []byte {
+	if !rec.Time.IsZero() {
+		b = protowire.AppendTag(b, pbEntryTime, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(rec.Time.UnixNano()))
+	}
+	b = appendProtobufString(b, pbEntryLevel, rec.Level)
+	b = appendProtobufString(b, pbEntryLogger, rec.Logger)
+	b = appendProtobufString(b, pbEntryCaller, rec.Caller)
+	b = appendProtobufString(b, pbEntryMessage, rec.Message)
+	b = appendProtobufString(b, pbEntryStacktrace, rec.Stacktrace)
+	if rec.Fields != nil {
+		for _, kv := range rec.Fields.fields {
+			b = protowire.AppendTag(b, pbEntryFields, protowire.BytesType)
+			b = protowire.AppendBytes(b, appendProtobufField(nil, kv))
+		}
+	}
+	return b
+}
+
+// appendProtobufString writes a string field, skipping empty values as proto3 does
+func appendProtobufString(b []byte, num protowire.Number, s string) []byte {
+	if s == "" {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, s)
+}
+
+func appendProtobufField(b []byte, kv objectKV) []byte {
+	b = protowire.AppendTag(b, pbFieldKey, protowire.BytesType)
+	b = protowire.AppendString(b, kv.key)
+	b = protowire.AppendTag(b, pbFieldValue, protowire.BytesType)
+	return protowire.AppendBytes(b, appendProtobufValue(nil, kv.value))
+}
+
+func appendProtobufValue(b []byte, value interface{}) []byte {
+	switch v := value.(type) {
+	case nil:
+		b = protowire.AppendTag(b, pbValueNull, protowire.VarintType)
+		b = protowire.AppendVarint(b, 1)
+	case bool:
+		b = protowire.AppendTag(b, pbValueBool, protowire.VarintType)
+		b = protowire.AppendVarint(b, protowire.EncodeBool(v))
+	case int64:
+		b = protowire.AppendTag(b, pbValueInt, protowire.VarintType)
+		b = protowire.AppendVarint(b, protowire.EncodeZigZag(v))
+	case uint64:
+		b = protowire.AppendTag(b, pbValueUint, protowire.VarintType)
+		b = protowire.AppendVarint(b, v)
+	case float64:
+		b = protowire.AppendTag(b, pbValueDouble, protowire.Fixed64Type)
+		b = protowire.AppendFixed64(b, math.Float64bits(v))
+	case string:
+		b = protowire.AppendTag(b, pbValueString, protowire.BytesType)
+		b = protowire.AppendString(b, v)
+	case []byte:
+		b = protowire.AppendTag(b, pbValueBytes, protowire.BytesType)
+		b = protowire.AppendBytes(b, v)
+	case time.Time:
+		b = protowire.AppendTag(b, pbValueTime, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(v.UnixNano()))
+	case time.Duration:
+		b = protowire.AppendTag(b, pbValueDuration, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(v))
+	case *objectValue:
+		var obj []byte
+		for _, kv := range v.fields {
+			obj = protowire.AppendTag(obj, pbObjectFields, protowire.BytesType)
+			obj = protowire.AppendBytes(obj, appendProtobufField(nil, kv))
+		}
+		b = protowire.AppendTag(b, pbValueObject, protowire.BytesType)
+		b = protowire.AppendBytes(b, obj)
+	case []interface{}:
+		var arr []byte
+		for _, item := range v {
+			arr = protowire.AppendTag(arr, pbArrayValues, protowire.BytesType)
+			arr = protowire.AppendBytes(arr, appendProtobufValue(nil, item))
+		}
+		b = protowire.AppendTag(b, pbValueArray, protowire.BytesType)
+		b = protowire.AppendBytes(b, arr)
+	default:
+		b = protowire.AppendTag(b, pbValueString, protowire.BytesType)
+		b = protowire.AppendString(b, fmt.Sprintf("%v", v))
+	}
+	return b
+}
+
+// readProtobufRecords decodes delimited Entry messages from r until EOF
+func readProtobufRecords(r io.Reader, fn func(*entryRecord) error) error {
+	reader := bufio.NewReader(r)
+	for {
+		size, err := readUvarint(reader)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("read entry length: %w", err)
+		}
+		if size > maxEncodedEntrySize {
+			return fmt.Errorf("entry length %d exceeds limit", size)
+		}
+
+		msg := make([]byte, size)
+		if _, err := io.ReadFull(reader, msg); err != nil {
+			return fmt.Errorf("read entry: %w", err)
+		}
+
+		rec, err := decodeProtobufEntry(msg)
+		if err != nil {
+			return err
+		}
+		if err := fn(rec); err != nil {
+			return err
+		}
+	}
+}
+
+// readUvarint reads a varint, returning io.EOF only when no byte was read
+func readUvarint(r io.ByteReader) (uint64, error) {
+	var value uint64
+	for shift := uint(0); shift < 64; shift += 7 {
+		c, err := r.ReadByte()
+		if err != nil {
+			if shift > 0 && errors.Is(err, io.EOF) {
+				return 0, io.ErrUnexpectedEOF
+			}
+			return 0, err
+		}
+		value |= uint64(c&0x7f) << shift
+		if c < 0x80 {
+			return value, nil
+		}
+	}
+	return 0, errors.New("varint overflow")
+}
+
+func decodeProtobufEntry(b []byte) (*entryRecord, error) {
+	rec := &entryRecord{Fields: &objectValue{}}
+	err := walkProtobuf(b, func(num protowire.Number, typ protowire.Type, data []byte, scalar uint64) error {
+		switch {
+		case num == pbEntryTime && typ == protowire.VarintType:
+			rec.Time = time.Unix(0, int64(scalar))
+		case num == pbEntryLevel && typ == protowire.BytesType:
+			rec.Level = string(data)
+		case num == pbEntryLogger && typ == protowire.BytesType:
+			rec.Logger = string(data)
+		case num == pbEntryCaller && typ == protowire.BytesType:
+			rec.Caller = string(data)
+		case num == pbEntryMessage && typ == protowire.BytesType:
+			rec.Message = string(data)
+		case num == pbEntryStacktrace && typ == protowire.BytesType:
+			rec.Stacktrace = string(data)
+		case num == pbEntryFields && typ == protowire.BytesType:
+			kv, err := decodeProtobufField(data)
+			if err != nil {
+				return err
+			}
+			rec.Fields.fields = append(rec.Fields.fields, kv)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("decode entry: %w", err)
+	}
+	return rec, nil
+}
+
+func decodeProtobufField(b []byte) (objectKV, error) {
+	var kv objectKV
+	err := walkProtobuf(b, func(num protowire.Number, typ protowire.Type, data []byte, _ uint64) error {
+		switch {
+		case num == pbFieldKey && typ == protowire.BytesType:
+			kv.key = string(data)
+		case num == pbFieldValue && typ == protowire.BytesType:
+			value, err := decodeProtobufValue(data)
+			if err != nil {
+				return err
+			}
+			kv.value = value
+		}
+		return nil
+	})
+	return kv, err
+}
+
+func decodeProtobufValue(b []byte) (interface{}, error) {
+	var value interface{}
+	err := walkProtobuf(b, func(num protowire.Number, typ protowire.Type, data []byte, scalar uint64) error {
+		switch num {
+		case pbValueNull:
+			value = nil
+		case pbValueBool:
+			value = protowire.DecodeBool(scalar)
+		case pbValueInt:
+			value = protowire.DecodeZigZag(scalar)
+		case pbValueUint:
+			value = scalar
+		case pbValueDouble:
+			value = math.Float64frombits(scalar)
+		case pbValueString:
+			value = string(data)
+		case pbValueBytes:
+			value = append([]byte(nil), data...)
+		case pbValueTime:
+			value = time.Unix(0, int64(scalar))
+		case pbValueDuration:
+			value = time.Duration(int64(scalar))
+		case pbValueObject:
+			obj := &objectValue{}
+			err := walkProtobuf(data, func(num protowire.Number, typ protowire.Type, data []byte, _ uint64) error {
+				if num != pbObjectFields || typ != protowire.BytesType {
+					return nil
+				}
+				kv, err := decodeProtobufField(data)
+				obj.fields = append(obj.fields, kv)
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			value = obj
+		case pbValueArray:
+			values := []interface{}{}
+			err := walkProtobuf(data, func(num protowire.Number, typ protowire.Type, data []byte, _ uint64) error {
+				if num != pbArrayValues || typ != protowire.BytesType {
+					return nil
+				}
+				item, err := decodeProtobufValue(data)
+				values = append(values, item)
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			value = values
+		}
+		return nil
+	})
+	return value, err
+}
+
+// walkProtobuf iterates over the fields of a message. Varint and fixed
+// values are passed as scalar, length-delimited values as data.
+func walkProtobuf(b []byte, fn func(num protowire.Number, typ protowire.Type, data []byte, scalar uint64) error) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		var data []byte
+		var scalar uint64
+		switch typ {
+		case protowire.VarintType:
+			scalar, n = protowire.ConsumeVarint(b)
+		case protowire.Fixed64Type:
+			scalar, n = protowire.ConsumeFixed64(b)
+		case protowire.Fixed32Type:
+			var v uint32
+			v, n = protowire.ConsumeFixed32(b)
+			scalar = uint64(v)
+		case protowire.BytesType:
+			data, n = protowire.ConsumeBytes(b)
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, b)
+		}
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		if err := fn(num, typ, data, scalar); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ConvertProtobufToJSON reads entries written with FormatProtobuf from r and
+// writes them to w as JSON lines using the keys of the JSON format.
+//
+// Example:
+//
+//	in, _ := os.Open("app.log.pb")
+//	err := xlogger.ConvertProtobufToJSON(in, os.Stdout)
+func ConvertProtobufToJSON(r io.Reader, w io.Writer) error {
+	return ConvertToJSON(FormatProtobuf, r, w)
+}
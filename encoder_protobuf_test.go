@@ -0,0 +1,132 @@
+package xlogger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// newEncoderTestLogger returns a zap logger writing entries from enc into buf
+func newEncoderTestLogger(enc zapcore.Encoder, buf *bytes.Buffer) *zap.Logger {
+	return zap.New(zapcore.NewCore(enc, zapcore.AddSync(buf), zapcore.DebugLevel))
+}
+
+// TestProtobufEncoder tests protobuf encoding and decoding of entries
+func TestProtobufEncoder(t *testing.T) {
+	t.Run("should round trip typed fields", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := newEncoderTestLogger(newProtobufEncoder(), &buf).Named("orders")
+
+		at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+		logger.Info("order placed",
+			zap.String("id", "o-1"),
+			zap.Int64("amount", -42),
+			zap.Uint64("big", 1<<63),
+			zap.Float64("ratio", 0.5),
+			zap.Bool("paid", true),
+			zap.Duration("elapsed", 1500*time.Millisecond),
+			zap.Time("at", at),
+			zap.Binary("raw", []byte{0x01, 0x02}),
+			zap.Strings("tags", []string{"a", "b"}),
+		)
+
+		var records []*entryRecord
+		require.NoError(t, readProtobufRecords(&buf, func(rec *entryRecord) error {
+			records = append(records, rec)
+			return nil
+		}))
+		require.Len(t, records, 1)
+
+		rec := records[0]
+		assert.Equal(t, "info", rec.Level)
+		assert.Equal(t, "orders", rec.Logger)
+		assert.Equal(t, "order placed", rec.Message)
+
+		values := map[string]interface{}{}
+		for _, kv := range rec.Fields.fields {
+			values[kv.key] = kv.value
+		}
+		assert.Equal(t, "o-1", values["id"])
+		assert.Equal(t, int64(-42), values["amount"])
+		assert.Equal(t, uint64(1<<63), values["big"])
+		assert.Equal(t, 0.5, values["ratio"])
+		assert.Equal(t, true, values["paid"])
+		assert.Equal(t, 1500*time.Millisecond, values["elapsed"])
+		assert.True(t, at.Equal(values["at"].(time.Time)))
+		assert.Equal(t, []byte{0x01, 0x02}, values["raw"])
+		assert.Equal(t, []interface{}{"a", "b"}, values["tags"])
+	})
+
+	t.Run("should write several delimited entries", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := newEncoderTestLogger(newProtobufEncoder(), &buf)
+		logger.Info("first")
+		logger.Warn("second")
+
+		var messages []string
+		require.NoError(t, readProtobufRecords(&buf, func(rec *entryRecord) error {
+			messages = append(messages, rec.Message)
+			return nil
+		}))
+		assert.Equal(t, []string{"first", "second"}, messages)
+	})
+
+	t.Run("should fail on truncated input", func(t *testing.T) {
+		var buf bytes.Buffer
+		newEncoderTestLogger(newProtobufEncoder(), &buf).Info("truncated")
+
+		data := buf.Bytes()[:buf.Len()-3]
+		err := readProtobufRecords(bytes.NewReader(data), func(*entryRecord) error { return nil })
+		assert.Error(t, err)
+	})
+
+	t.Run("should be selectable through config", func(t *testing.T) {
+		logger, err := NewZapLogger(NewLoggerConfig(WithFormat(FormatProtobuf)))
+		require.NoError(t, err)
+		assert.NotPanics(t, func() {
+			logger.Info("protobuf entry", String("key", "value"))
+		})
+	})
+}
+
+// TestConvertProtobufToJSON tests conversion of protobuf entries to JSON lines
+func TestConvertProtobufToJSON(t *testing.T) {
+	t.Run("should convert nested values", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := newEncoderTestLogger(newProtobufEncoder(), &buf)
+		logger.Error("payment failed",
+			zap.Namespace("http"),
+			zap.String("method", "POST"),
+			zap.Int("status", 502),
+		)
+		logger.Info("with error", zap.Error(errors.New("boom")), zap.Any("meta", map[string]int{"b": 2, "a": 1}))
+
+		var out bytes.Buffer
+		require.NoError(t, ConvertProtobufToJSON(&buf, &out))
+
+		lines := bytes.Split(bytes.TrimSpace(out.Bytes()), []byte("\n"))
+		require.Len(t, lines, 2)
+
+		var first map[string]interface{}
+		require.NoError(t, json.Unmarshal(lines[0], &first))
+		assert.Equal(t, "error", first["level"])
+		assert.Equal(t, "payment failed", first["message"])
+		assert.Equal(t, map[string]interface{}{"method": "POST", "status": float64(502)}, first["http"])
+
+		assert.Contains(t, string(lines[1]), `"error":"boom"`)
+		assert.Contains(t, string(lines[1]), `"meta":{"a":1,"b":2}`)
+	})
+
+	t.Run("should return nil for empty input", func(t *testing.T) {
+		var out bytes.Buffer
+		assert.NoError(t, ConvertProtobufToJSON(bytes.NewReader(nil), &out))
+		assert.Zero(t, out.Len())
+	})
+}
@@ -0,0 +1,610 @@
+package xlogger
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// Binary encoders collect each entry into an ordered tree of typed values
+// before serializing it, so nested objects, arrays and namespaces keep their
+// shape and field typing survives a round trip. Values in the tree are one of:
+// nil, bool, int64, uint64, float64, string, []byte, time.Time, time.Duration,
+// *objectValue or []interface{}.
+
+// maxEncodedEntrySize bounds lengths read while decoding to guard against corrupt input
+const maxEncodedEntrySize = 64 << 20
+
+// boundedCapacity caps preallocation for lengths read from untrusted input
+func boundedCapacity(n uint64) int {
+	if n > 1024 {
+		return 1024
+	}
+	return int(n)
+}
+
+// objectKV is a single key/value pair in an object
+type objectKV struct {
+	key   string
+	value interface{}
+}
+
+// objectValue is an ordered set of key/value pairs
+type objectValue struct {
+	fields []objectKV
+}
+
+func (o *objectValue) add(key string, value interface{}) {
+	o.fields = append(o.fields, objectKV{key: key, value: value})
+}
+
+// entryRecord is the decoded, encoder-independent form of a log entry
+type entryRecord struct {
+	Time       time.Time
+	Level      string
+	Logger     string
+	Caller     string
+	Message    string
+	Stacktrace string
+	Fields     *objectValue
+}
+
+// newEntryRecord captures the entry metadata with the given fields
+func newEntryRecord(ent zapcore.Entry, fields *objectValue) *entryRecord {
+	rec := &entryRecord{
+		Time:       ent.Time,
+		Level:      ent.Level.String(),
+		Logger:     ent.LoggerName,
+		Message:    ent.Message,
+		Stacktrace: ent.Stack,
+		Fields:     fields,
+	}
+	if ent.Caller.Defined {
+		rec.Caller = ent.Caller.TrimmedPath()
+	}
+	return rec
+}
+
+// fieldCollector implements zapcore.ObjectEncoder by building an objectValue tree
+type fieldCollector struct {
+	// stack holds the root object followed by every open namespace
+	stack []*objectValue
+}
+
+func newFieldCollector() *fieldCollector {
+	return &fieldCollector{stack: []*objectValue{{}}}
+}
+
+func (c *fieldCollector) root() *objectValue {
+	return c.stack[0]
+}
+
+func (c *fieldCollector) current() *objectValue {
+	return c.stack[len(c.stack)-1]
+}
+
+// clone copies the root and every open namespace so the copy can keep
+// appending without touching the original
+func (c *fieldCollector) clone() *fieldCollector {
+	stack := make([]*objectValue, len(c.stack))
+	for i, obj := range c.stack {
+		stack[i] = &objectValue{fields: append([]objectKV(nil), obj.fields...)}
+		if i > 0 {
+			// An open namespace is always the last field of its parent
+			parent := stack[i-1]
+			parent.fields[len(parent.fields)-1].value = stack[i]
+		}
+	}
+	return &fieldCollector{stack: stack}
+}
+
+func (c *fieldCollector) add(key string, value interface{}) {
+	c.current().add(key, value)
+}
+
+// AddArray implements zapcore.ObjectEncoder
+func (c *fieldCollector) AddArray(key string, marshaler zapcore.ArrayMarshaler) error {
+	arr := &arrayCollector{}
+	err := marshaler.MarshalLogArray(arr)
+	c.add(key, arr.values)
+	return err
+}
+
+// AddObject implements zapcore.ObjectEncoder
+func (c *fieldCollector) AddObject(key string, marshaler zapcore.ObjectMarshaler) error {
+	obj := newFieldCollector()
+	err := marshaler.MarshalLogObject(obj)
+	c.add(key, obj.root())
+	return err
+}
+
+// AddBinary implements zapcore.ObjectEncoder
+func (c *fieldCollector) AddBinary(key string, value []byte) {
+	c.add(key, append([]byte(nil), value...))
+}
+
+// AddByteString implements zapcore.ObjectEncoder
+func (c *fieldCollector) AddByteString(key string, value []byte) {
+	c.add(key, string(value))
+}
+
+// AddBool implements zapcore.ObjectEncoder
+func (c *fieldCollector) AddBool(key string, value bool) { c.add(key, value) }
+
+// AddComplex128 implements zapcore.ObjectEncoder
+func (c *fieldCollector) AddComplex128(key string, value complex128) {
+	c.add(key, strconv.FormatComplex(value, 'g', -1, 128))
+}
+
+// AddComplex64 implements zapcore.ObjectEncoder
+func (c *fieldCollector) AddComplex64(key string, value complex64) {
+	c.add(key, strconv.FormatComplex(complex128(value), 'g', -1, 64))
+}
+
+// AddDuration implements zapcore.ObjectEncoder
+func (c *fieldCollector) AddDuration(key string, value time.Duration) { c.add(key, value) }
+
+// AddFloat64 implements zapcore.ObjectEncoder
+func (c *fieldCollector) AddFloat64(key string, value float64) { c.add(key, value) }
+
+// AddFloat32 implements zapcore.ObjectEncoder
+func (c *fieldCollector) AddFloat32(key string, value float32) { c.add(key, float64(value)) }
+
+// AddInt implements zapcore.ObjectEncoder
+func (c *fieldCollector) AddInt(key string, value int) { c.add(key, int64(value)) }
+
+// AddInt64 implements zapcore.ObjectEncoder
+func (c *fieldCollector) AddInt64(key string, value int64) { c.add(key, value) }
+
+// AddInt32 implements zapcore.ObjectEncoder
+func (c *fieldCollector) AddInt32(key string, value int32) { c.add(key, int64(value)) }
+
+// AddInt16 implements zapcore.ObjectEncoder
+func (c *fieldCollector) AddInt16(key string, value int16) { c.add(key, int64(value)) }
+
+// AddInt8 implements zapcore.ObjectEncoder
+func (c *fieldCollector) AddInt8(key string, value int8) { c.add(key, int64(value)) }
+
+// AddString implements zapcore.ObjectEncoder
+func (c *fieldCollector) AddString(key, value string) { c.add(key, value) }
+
+// AddTime implements zapcore.ObjectEncoder
+func (c *fieldCollector) AddTime(key string, value time.Time) { c.add(key, value) }
+
+// AddUint implements zapcore.ObjectEncoder
+func (c *fieldCollector) AddUint(key string, value uint) { c.add(key, uint64(value)) }
+
+// AddUint64 implements zapcore.ObjectEncoder
+func (c *fieldCollector) AddUint64(key string, value uint64) { c.add(key, value) }
+
+// AddUint32 implements zapcore.ObjectEncoder
+func (c *fieldCollector) AddUint32(key string, value uint32) { c.add(key, uint64(value)) }
+
+// AddUint16 implements zapcore.ObjectEncoder
+func (c *fieldCollector) AddUint16(key string, value uint16) { c.add(key, uint64(value)) }
+
+// AddUint8 implements zapcore.ObjectEncoder
+func (c *fieldCollector) AddUint8(key string, value uint8) { c.add(key, uint64(value)) }
+
+// AddUintptr implements zapcore.ObjectEncoder
+func (c *fieldCollector) AddUintptr(key string, value uintptr) { c.add(key, uint64(value)) }
+
+// AddReflected implements zapcore.ObjectEncoder
+func (c *fieldCollector) AddReflected(key string, value interface{}) error {
+	converted, err := reflectedValue(value)
+	c.add(key, converted)
+	return err
+}
+
+// OpenNamespace implements zapcore.ObjectEncoder
+func (c *fieldCollector) OpenNamespace(key string) {
+	ns := &objectValue{}
+	c.add(key, ns)
+	c.stack = append(c.stack, ns)
+}
+
+// arrayCollector implements zapcore.ArrayEncoder for the value tree
+type arrayCollector struct {
+	values []interface{}
+}
+
+func (a *arrayCollector) append(value interface{}) {
+	a.values = append(a.values, value)
+}
+
+// AppendArray implements zapcore.ArrayEncoder
+func (a *arrayCollector) AppendArray(marshaler zapcore.ArrayMarshaler) error {
+	arr := &arrayCollector{}
+	err := marshaler.MarshalLogArray(arr)
+	a.append(arr.values)
+	return err
+}
+
+// AppendObject implements zapcore.ArrayEncoder
+func (a *arrayCollector) AppendObject(marshaler zapcore.ObjectMarshaler) error {
+	obj := newFieldCollector()
+	err := marshaler.MarshalLogObject(obj)
+	a.append(obj.root())
+	return err
+}
+
+// AppendReflected implements zapcore.ArrayEncoder
+func (a *arrayCollector) AppendReflected(value interface{}) error {
+	converted, err := reflectedValue(value)
+	a.append(converted)
+	return err
+}
+
+// AppendBool implements zapcore.ArrayEncoder
+func (a *arrayCollector) AppendBool(value bool) { a.append(value) }
+
+// AppendByteString implements zapcore.ArrayEncoder
+func (a *arrayCollector) AppendByteString(value []byte) { a.append(string(value)) }
+
+// AppendComplex128 implements zapcore.ArrayEncoder
+func (a *arrayCollector) AppendComplex128(value complex128) {
+	a.append(strconv.FormatComplex(value, 'g', -1, 128))
+}
+
+// AppendComplex64 implements zapcore.ArrayEncoder
+func (a *arrayCollector) AppendComplex64(value complex64) {
+	a.append(strconv.FormatComplex(complex128(value), 'g', -1, 64))
+}
+
+// AppendFloat64 implements zapcore.ArrayEncoder
+func (a *arrayCollector) AppendFloat64(value float64) { a.append(value) }
+
+// AppendFloat32 implements zapcore.ArrayEncoder
+func (a *arrayCollector) AppendFloat32(value float32) { a.append(float64(value)) }
+
+// AppendInt implements zapcore.ArrayEncoder
+func (a *arrayCollector) AppendInt(value int) { a.append(int64(value)) }
+
+// AppendInt64 implements zapcore.ArrayEncoder
+func (a *arrayCollector) AppendInt64(value int64) { a.append(value) }
+
+// AppendInt32 implements zapcore.ArrayEncoder
+func (a *arrayCollector) AppendInt32(value int32) { a.append(int64(value)) }
+
+// AppendInt16 implements zapcore.ArrayEncoder
+func (a *arrayCollector) AppendInt16(value int16) { a.append(int64(value)) }
+
+// AppendInt8 implements zapcore.ArrayEncoder
+func (a *arrayCollector) AppendInt8(value int8) { a.append(int64(value)) }
+
+// AppendString implements zapcore.ArrayEncoder
+func (a *arrayCollector) AppendString(value string) { a.append(value) }
+
+// AppendUint implements zapcore.ArrayEncoder
+func (a *arrayCollector) AppendUint(value uint) { a.append(uint64(value)) }
+
+// AppendUint64 implements zapcore.ArrayEncoder
+func (a *arrayCollector) AppendUint64(value uint64) { a.append(value) }
+
+// AppendUint32 implements zapcore.ArrayEncoder
+func (a *arrayCollector) AppendUint32(value uint32) { a.append(uint64(value)) }
+
+// AppendUint16 implements zapcore.ArrayEncoder
+func (a *arrayCollector) AppendUint16(value uint16) { a.append(uint64(value)) }
+
+// AppendUint8 implements zapcore.ArrayEncoder
+func (a *arrayCollector) AppendUint8(value uint8) { a.append(uint64(value)) }
+
+// AppendUintptr implements zapcore.ArrayEncoder
+func (a *arrayCollector) AppendUintptr(value uintptr) { a.append(uint64(value)) }
+
+// AppendDuration implements zapcore.ArrayEncoder
+func (a *arrayCollector) AppendDuration(value time.Duration) { a.append(value) }
+
+// AppendTime implements zapcore.ArrayEncoder
+func (a *arrayCollector) AppendTime(value time.Time) { a.append(value) }
+
+// reflectedValue converts an arbitrary value into the tree representation by
+// way of its JSON form. Map keys are sorted so the output is deterministic.
+func reflectedValue(value interface{}) (interface{}, error) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%+v", value), err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var decoded interface{}
+	if err := decoder.Decode(&decoded); err != nil {
+		return string(raw), err
+	}
+	return fromJSONValue(decoded), nil
+}
+
+// fromJSONValue maps decoded JSON values onto tree values
+func fromJSONValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		if u, err := strconv.ParseUint(v.String(), 10, 64); err == nil {
+			return u
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		obj := &objectValue{fields: make([]objectKV, 0, len(keys))}
+		for _, key := range keys {
+			obj.add(key, fromJSONValue(v[key]))
+		}
+		return obj
+	case []interface{}:
+		values := make([]interface{}, len(v))
+		for i, item := range v {
+			values[i] = fromJSONValue(item)
+		}
+		return values
+	default:
+		return v
+	}
+}
+
+// recordMarshaler serializes one entry record into buf
+type recordMarshaler func(rec *entryRecord, buf *buffer.Buffer) error
+
+// structuredEncoder is a zapcore.Encoder that hands each entry to a
+// recordMarshaler as a typed value tree
+type structuredEncoder struct {
+	*fieldCollector
+	marshal recordMarshaler
+}
+
+var structuredBufferPool = buffer.NewPool()
+
+func newStructuredEncoder(marshal recordMarshaler) *structuredEncoder {
+	return &structuredEncoder{
+		fieldCollector: newFieldCollector(),
+		marshal:        marshal,
+	}
+}
+
+// Clone implements zapcore.Encoder
+func (e *structuredEncoder) Clone() zapcore.Encoder {
+	return &structuredEncoder{
+		fieldCollector: e.fieldCollector.clone(),
+		marshal:        e.marshal,
+	}
+}
+
+// EncodeEntry implements zapcore.Encoder
+func (e *structuredEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	collector := e.fieldCollector.clone()
+	for _, field := range fields {
+		field.AddTo(collector)
+	}
+
+	buf := structuredBufferPool.Get()
+	if err := e.marshal(newEntryRecord(ent, collector.root()), buf); err != nil {
+		buf.Free()
+		return nil, err
+	}
+	return buf, nil
+}
+
+// writeJSONRecord renders a record as a single JSON line using the same keys
+// as the JSON encoder
+func writeJSONRecord(buf *bytes.Buffer, rec *entryRecord) {
+	buf.WriteByte('{')
+	writeJSONKey(buf, "time", true)
+	writeJSONString(buf, rec.Time.Format(time.RFC3339Nano))
+	writeJSONKey(buf, "level", false)
+	writeJSONString(buf, rec.Level)
+	if rec.Logger != "" {
+		writeJSONKey(buf, "logger", false)
+		writeJSONString(buf, rec.Logger)
+	}
+	if rec.Caller != "" {
+		writeJSONKey(buf, "caller", false)
+		writeJSONString(buf, rec.Caller)
+	}
+	writeJSONKey(buf, "message", false)
+	writeJSONString(buf, rec.Message)
+	if rec.Fields != nil {
+		for _, kv := range rec.Fields.fields {
+			writeJSONKey(buf, kv.key, false)
+			writeJSONValue(buf, kv.value)
+		}
+	}
+	if rec.Stacktrace != "" {
+		writeJSONKey(buf, "stacktrace", false)
+		writeJSONString(buf, rec.Stacktrace)
+	}
+	buf.WriteString("}\n")
+}
+
+func writeJSONKey(buf *bytes.Buffer, key string, first bool) {
+	if !first {
+		buf.WriteByte(',')
+	}
+	writeJSONString(buf, key)
+	buf.WriteByte(':')
+}
+
+func writeJSONString(buf *bytes.Buffer, s string) {
+	encoded, _ := json.Marshal(s)
+	buf.Write(encoded)
+}
+
+func writeJSONValue(buf *bytes.Buffer, value interface{}) {
+	switch v := value.(type) {
+	case nil:
+		buf.WriteString("null")
+	case string:
+		writeJSONString(buf, v)
+	case time.Time:
+		writeJSONString(buf, v.Format(time.RFC3339Nano))
+	case time.Duration:
+		writeJSONString(buf, v.String())
+	case *objectValue:
+		buf.WriteByte('{')
+		for i, kv := range v.fields {
+			writeJSONKey(buf, kv.key, i == 0)
+			writeJSONValue(buf, kv.value)
+		}
+		buf.WriteByte('}')
+	case []interface{}:
+		buf.WriteByte('[')
+		for i, item := range v {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writeJSONValue(buf, item)
+		}
+		buf.WriteByte(']')
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			writeJSONString(buf, fmt.Sprintf("%v", v))
+			return
+		}
+		buf.Write(encoded)
+	}
+}
+
+// Keys of the top-level map used by self-describing binary formats
+const (
+	recordTimeKey       = "time"
+	recordLevelKey      = "level"
+	recordLoggerKey     = "logger"
+	recordCallerKey     = "caller"
+	recordMessageKey    = "message"
+	recordStacktraceKey = "stacktrace"
+	recordFieldsKey     = "fields"
+)
+
+// recordObject lays out a record as a map with entry metadata first and the
+// fields nested under "fields", so field keys never collide with metadata
+func recordObject(rec *entryRecord) *objectValue {
+	obj := &objectValue{fields: make([]objectKV, 0, 7)}
+	obj.add(recordTimeKey, rec.Time)
+	obj.add(recordLevelKey, rec.Level)
+	if rec.Logger != "" {
+		obj.add(recordLoggerKey, rec.Logger)
+	}
+	if rec.Caller != "" {
+		obj.add(recordCallerKey, rec.Caller)
+	}
+	obj.add(recordMessageKey, rec.Message)
+	if rec.Stacktrace != "" {
+		obj.add(recordStacktraceKey, rec.Stacktrace)
+	}
+	fields := rec.Fields
+	if fields == nil {
+		fields = &objectValue{}
+	}
+	obj.add(recordFieldsKey, fields)
+	return obj
+}
+
+// recordFromObject is the inverse of recordObject
+func recordFromObject(value interface{}) (*entryRecord, error) {
+	obj, ok := value.(*objectValue)
+	if !ok {
+		return nil, fmt.Errorf("entry is %T, want a map", value)
+	}
+
+	rec := &entryRecord{Fields: &objectValue{}}
+	for _, kv := range obj.fields {
+		switch kv.key {
+		case recordTimeKey:
+			rec.Time, _ = kv.value.(time.Time)
+		case recordLevelKey:
+			rec.Level, _ = kv.value.(string)
+		case recordLoggerKey:
+			rec.Logger, _ = kv.value.(string)
+		case recordCallerKey:
+			rec.Caller, _ = kv.value.(string)
+		case recordMessageKey:
+			rec.Message, _ = kv.value.(string)
+		case recordStacktraceKey:
+			rec.Stacktrace, _ = kv.value.(string)
+		case recordFieldsKey:
+			if fields, ok := kv.value.(*objectValue); ok {
+				rec.Fields = fields
+			}
+		}
+	}
+	return rec, nil
+}
+
+// valueReader decodes the next value of a self-delimiting binary format
+type valueReader func(r *bufio.Reader) (interface{}, error)
+
+// readStructuredRecords decodes consecutive top-level values from r until EOF
+func readStructuredRecords(r io.Reader, read valueReader, fn func(*entryRecord) error) error {
+	reader := bufio.NewReader(r)
+	for {
+		if _, err := reader.Peek(1); errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		value, err := read(reader)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				err = io.ErrUnexpectedEOF
+			}
+			return fmt.Errorf("decode entry: %w", err)
+		}
+
+		rec, err := recordFromObject(value)
+		if err != nil {
+			return err
+		}
+		if err := fn(rec); err != nil {
+			return err
+		}
+	}
+}
+
+// ConvertToJSON reads binary entries in the given format (FormatProtobuf,
+// FormatMsgpack or FormatCBOR) from r and writes them to w as JSON lines.
+//
+// Example:
+//
+//	in, _ := os.Open("app.log.msgpack")
+//	err := xlogger.ConvertToJSON(xlogger.FormatMsgpack, in, os.Stdout)
+func ConvertToJSON(format LogFormat, r io.Reader, w io.Writer) error {
+	var line bytes.Buffer
+	write := func(rec *entryRecord) error {
+		line.Reset()
+		writeJSONRecord(&line, rec)
+		_, err := w.Write(line.Bytes())
+		return err
+	}
+
+	return readBinaryRecords(format, r, write)
+}
+
+// readBinaryRecords decodes entries of a binary format and calls fn for each
+func readBinaryRecords(format LogFormat, r io.Reader, fn func(*entryRecord) error) error {
+	switch format.Normalize() {
+	case FormatProtobuf:
+		return readProtobufRecords(r, fn)
+	case FormatMsgpack:
+		return readStructuredRecords(r, readMsgpackValue, fn)
+	case FormatCBOR:
+		return readStructuredRecords(r, readCBORValue, fn)
+	default:
+		return fmt.Errorf("format %q is not a binary format", format)
+	}
+}
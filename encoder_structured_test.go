@@ -0,0 +1,91 @@
+package xlogger
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// TestFieldCollector tests building the value tree from zap fields
+func TestFieldCollector(t *testing.T) {
+	t.Run("should nest fields under open namespaces", func(t *testing.T) {
+		c := newFieldCollector()
+		c.AddString("a", "1")
+		c.OpenNamespace("http")
+		c.AddInt("status", 200)
+
+		root := c.root()
+		assert.Len(t, root.fields, 2)
+		ns := root.fields[1].value.(*objectValue)
+		assert.Equal(t, []objectKV{{key: "status", value: int64(200)}}, ns.fields)
+	})
+
+	t.Run("should clone without sharing open namespaces", func(t *testing.T) {
+		c := newFieldCollector()
+		c.OpenNamespace("ctx")
+		c.AddString("shared", "x")
+
+		clone := c.clone()
+		clone.AddString("only_clone", "y")
+		c.AddString("only_original", "z")
+
+		original := c.root().fields[0].value.(*objectValue)
+		cloned := clone.root().fields[0].value.(*objectValue)
+		assert.Equal(t, "only_original", original.fields[1].key)
+		assert.Equal(t, "only_clone", cloned.fields[1].key)
+	})
+
+	t.Run("should convert reflected values with sorted keys", func(t *testing.T) {
+		c := newFieldCollector()
+		err := c.AddReflected("data", map[string]interface{}{"z": 1, "a": []int{1, 2}, "f": 1.5})
+		assert.NoError(t, err)
+
+		obj := c.root().fields[0].value.(*objectValue)
+		assert.Equal(t, "a", obj.fields[0].key)
+		assert.Equal(t, []interface{}{int64(1), int64(2)}, obj.fields[0].value)
+		assert.Equal(t, 1.5, obj.fields[1].value)
+		assert.Equal(t, int64(1), obj.fields[2].value)
+	})
+
+	t.Run("should keep context fields on cloned encoders", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := zap.New(zapcore.NewCore(newProtobufEncoder(), zapcore.AddSync(&buf), zapcore.DebugLevel))
+		child := logger.With(zap.String("service", "api"))
+		child.Info("one", zap.Int("n", 1))
+		logger.Info("two")
+
+		var fieldCounts []int
+		_ = readProtobufRecords(&buf, func(rec *entryRecord) error {
+			fieldCounts = append(fieldCounts, len(rec.Fields.fields))
+			return nil
+		})
+		assert.Equal(t, []int{2, 0}, fieldCounts)
+	})
+}
+
+// TestWriteJSONRecord tests JSON rendering of decoded records
+func TestWriteJSONRecord(t *testing.T) {
+	t.Run("should render metadata and fields in order", func(t *testing.T) {
+		rec := &entryRecord{
+			Time:    time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+			Level:   "warn",
+			Caller:  "app/main.go:10",
+			Message: "slow",
+			Fields: &objectValue{fields: []objectKV{
+				{key: "elapsed", value: 2 * time.Second},
+				{key: "nil", value: nil},
+				{key: "raw", value: []byte("hi")},
+			}},
+		}
+
+		var buf bytes.Buffer
+		writeJSONRecord(&buf, rec)
+		assert.Equal(t,
+			`{"time":"2024-01-02T03:04:05Z","level":"warn","caller":"app/main.go:10","message":"slow","elapsed":"2s","nil":null,"raw":"aGk="}`+"\n",
+			buf.String())
+	})
+}
@@ -0,0 +1,316 @@
+package xlogger
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// appComponent labels entries of loggers without a component field
+const appComponent = "app"
+
+// maxWideWarnings bounds the call sites warned about wide entries
+const maxWideWarnings = 1024
+
+// Upper bounds of the histogram buckets of field counts and encoded sizes
+var (
+	fieldCountBounds = []int{4, 8, 16, 32, 64, 128}
+	entrySizeBounds  = []int{256, 512, 1024, 2048, 4096, 8192, 16384, 65536}
+)
+
+// EntryLimits are the soft limits of entry width. Entries above them are
+// still written, followed by one Warn per call site.
+type EntryLimits struct {
+	MaxFields int // Fields of an entry, including logger fields (0 for no limit)
+	MaxBytes  int // Encoded size of an entry (0 for no limit)
+}
+
+// Histogram is a distribution of observed values. Counts has one more
+// element than Bounds, counting the values above the last bound.
+type Histogram struct {
+	Bounds []int    `json:"bounds"` // Inclusive upper bounds of the buckets
+	Counts []uint64 `json:"counts"` // Values per bucket, not cumulative
+	Sum    uint64   `json:"sum"`
+}
+
+func newHistogram(bounds []int) Histogram {
+	return Histogram{Bounds: bounds, Counts: make([]uint64, len(bounds)+1)}
+}
+
+// observe adds one value
+func (h *Histogram) observe(value int) {
+	i := sort.SearchInts(h.Bounds, value)
+	h.Counts[i]++
+	h.Sum += uint64(value)
+}
+
+// clone returns a copy sharing no counts with h
+func (h Histogram) clone() Histogram {
+	h.Counts = append([]uint64(nil), h.Counts...)
+	return h
+}
+
+// EntryShape is the distribution of field counts and encoded sizes of the
+// entries of one component.
+type EntryShape struct {
+	Component string    `json:"component"` // Value of the component field, "app" without one
+	Entries   uint64    `json:"entries"`
+	Fields    Histogram `json:"fields"`
+	Bytes     Histogram `json:"bytes"`
+	Wide      uint64    `json:"wide"` // Entries above the limits
+}
+
+// entryShapes holds the entry shapes of every component and the call sites
+// already warned about
+type entryShapes struct {
+	limits EntryLimits
+
+	mu         sync.Mutex
+	components map[string]*EntryShape
+	warned     map[string]struct{}
+}
+
+func newEntryShapes(limits EntryLimits) *entryShapes {
+	return &entryShapes{
+		limits:     limits,
+		components: make(map[string]*EntryShape),
+		warned:     make(map[string]struct{}),
+	}
+}
+
+// record adds an entry of component and reports whether it is wide and its
+// call site not warned about yet
+func (s *entryShapes) record(component, site string, fields, size int) (wide, warn bool) {
+	wide = s.limits.MaxFields > 0 && fields > s.limits.MaxFields ||
+		s.limits.MaxBytes > 0 && size > s.limits.MaxBytes
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	shape, ok := s.components[component]
+	if !ok {
+		shape = &EntryShape{
+			Component: component,
+			Fields:    newHistogram(fieldCountBounds),
+			Bytes:     newHistogram(entrySizeBounds),
+		}
+		s.components[component] = shape
+	}
+	shape.Entries++
+	shape.Fields.observe(fields)
+	shape.Bytes.observe(size)
+	if !wide {
+		return false, false
+	}
+
+	shape.Wide++
+	if _, ok := s.warned[site]; ok || len(s.warned) >= maxWideWarnings {
+		return true, false
+	}
+	s.warned[site] = struct{}{}
+	return true, true
+}
+
+// snapshot returns the shapes of every component, sorted by component
+func (s *entryShapes) snapshot() []EntryShape {
+	s.mu.Lock()
+	shapes := make([]EntryShape, 0, len(s.components))
+	for _, shape := range s.components {
+		snapshot := *shape
+		snapshot.Fields = shape.Fields.clone()
+		snapshot.Bytes = shape.Bytes.clone()
+		shapes = append(shapes, snapshot)
+	}
+	s.mu.Unlock()
+
+	sort.Slice(shapes, func(i, j int) bool {
+		return shapes[i].Component < shapes[j].Component
+	})
+	return shapes
+}
+
+// shapeCore measures the entries written to the core it wraps, encoding
+// them like the outputs to know their size
+type shapeCore struct {
+	zapcore.Core
+	enc       zapcore.Encoder // Carries the logger fields
+	fields    int             // Logger fields added with With
+	component string
+	shapes    *entryShapes
+	errSink   zapcore.WriteSyncer
+}
+
+func newShapeCore(core zapcore.Core, enc zapcore.Encoder, shapes *entryShapes, errSink zapcore.WriteSyncer) zapcore.Core {
+	return &shapeCore{Core: core, enc: enc, component: appComponent, shapes: shapes, errSink: errSink}
+}
+
+// With implements zapcore.Core
+func (c *shapeCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.Core = c.Core.With(fields)
+	clone.enc = c.enc.Clone()
+	for _, field := range fields {
+		field.AddTo(clone.enc)
+	}
+	clone.fields += len(fields)
+	clone.component = componentOf(fields, c.component)
+	return &clone
+}
+
+// Check implements zapcore.Core
+func (c *shapeCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Core.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write implements zapcore.Core
+func (c *shapeCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	writeChecked(c.Core, ent, fields, c.errSink)
+
+	buf, err := c.enc.EncodeEntry(ent, fields)
+	if err != nil {
+		return err
+	}
+	size := buf.Len()
+	buf.Free()
+
+	count := c.fields + len(fields)
+	site := ent.Message
+	if ent.Caller.Defined {
+		site = ent.Caller.TrimmedPath()
+	}
+	if _, warn := c.shapes.record(componentOf(fields, c.component), site, count, size); warn {
+		writeChecked(c.Core, zapcore.Entry{
+			Level:      zapcore.WarnLevel,
+			Time:       ent.Time,
+			LoggerName: ent.LoggerName,
+			Caller:     ent.Caller,
+			Message:    "wide log entry",
+		}, []zapcore.Field{
+			zap.String("entry_message", ent.Message),
+			zap.Int("entry_fields", count),
+			zap.Int("entry_bytes", size),
+			zap.Int("max_fields", c.shapes.limits.MaxFields),
+			zap.Int("max_bytes", c.shapes.limits.MaxBytes),
+		}, c.errSink)
+	}
+	return nil
+}
+
+// componentOf returns the value of the last component field, or component
+// when fields have none
+func componentOf(fields []zapcore.Field, component string) string {
+	for _, field := range fields {
+		if field.Key == "component" && field.Type == zapcore.StringType {
+			component = field.String
+		}
+	}
+	return component
+}
+
+// EntryShapes returns the distribution of field counts and encoded sizes
+// per component. It returns nil unless enabled with WithEntryShape.
+func (l *ZapLogger) EntryShapes() []EntryShape {
+	outputs := l.outputs()
+	if outputs == nil || outputs.shapes == nil {
+		return nil
+	}
+	return outputs.shapes.snapshot()
+}
+
+// entryShaper is implemented by loggers measuring entry shapes
+type entryShaper interface {
+	EntryShapes() []EntryShape
+}
+
+// entryShapeHandler serves the entry shapes of a logger
+type entryShapeHandler struct {
+	logger Logger
+}
+
+// EntryShapeHandler returns an opt-in http.Handler reporting the field
+// counts and encoded sizes of entries per component, to find components
+// logging pathologically wide entries. GET returns a JSON array of
+// EntryShape. With format=prometheus the histograms are written in the
+// Prometheus text format as xlogger_entry_fields and xlogger_entry_bytes.
+// Requires WithEntryShape.
+//
+// Example:
+//
+//	mux.Handle("/debug/log-shape", xlogger.EntryShapeHandler(logger))
+//
+//	curl 'localhost:8080/debug/log-shape?format=prometheus'
+func EntryShapeHandler(logger Logger) http.Handler {
+	return &entryShapeHandler{logger: logger}
+}
+
+// ServeHTTP implements http.Handler
+func (h *entryShapeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", "GET")
+		writeHandlerError(w, http.StatusMethodNotAllowed, "only GET is supported")
+		return
+	}
+
+	shaper, ok := h.logger.(entryShaper)
+	if !ok {
+		writeHandlerError(w, http.StatusNotFound, "logger does not measure entries")
+		return
+	}
+	shapes := shaper.EntryShapes()
+	if shapes == nil {
+		writeHandlerError(w, http.StatusNotFound, "entry shape metrics are disabled")
+		return
+	}
+
+	if r.URL.Query().Get("format") == "prometheus" {
+		writePrometheusEntryShapes(w, shapes)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(shapes)
+}
+
+func writePrometheusEntryShapes(w http.ResponseWriter, shapes []EntryShape) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	writePrometheusHistogram(w, "xlogger_entry_fields", "Fields per log entry.", shapes,
+		func(s EntryShape) Histogram { return s.Fields })
+	writePrometheusHistogram(w, "xlogger_entry_bytes", "Encoded size of log entries in bytes.", shapes,
+		func(s EntryShape) Histogram { return s.Bytes })
+
+	fmt.Fprintln(w, "# HELP xlogger_wide_entries_total Log entries above the entry limits.")
+	fmt.Fprintln(w, "# TYPE xlogger_wide_entries_total counter")
+	for _, s := range shapes {
+		fmt.Fprintf(w, "xlogger_wide_entries_total{component=\"%s\"} %d\n",
+			prometheusLabelEscaper.Replace(s.Component), s.Wide)
+	}
+}
+
+func writePrometheusHistogram(w http.ResponseWriter, name, help string, shapes []EntryShape, histogram func(EntryShape) Histogram) {
+	fmt.Fprintf(w, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(w, "# TYPE %s histogram\n", name)
+	for _, s := range shapes {
+		h := histogram(s)
+		component := prometheusLabelEscaper.Replace(s.Component)
+
+		var cumulative uint64
+		for i, count := range h.Counts {
+			cumulative += count
+			le := "+Inf"
+			if i < len(h.Bounds) {
+				le = strconv.Itoa(h.Bounds[i])
+			}
+			fmt.Fprintf(w, "%s_bucket{component=\"%s\",le=\"%s\"} %d\n", name, component, le, cumulative)
+		}
+		fmt.Fprintf(w, "%s_sum{component=\"%s\"} %d\n", name, component, h.Sum)
+		fmt.Fprintf(w, "%s_count{component=\"%s\"} %d\n", name, component, s.Entries)
+	}
+}
@@ -0,0 +1,159 @@
+package xlogger
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestHistogram tests the bucketing of observed values
+func TestHistogram(t *testing.T) {
+	t.Run("should count values in inclusive buckets", func(t *testing.T) {
+		h := newHistogram([]int{4, 8})
+		for _, value := range []int{0, 4, 5, 8, 9, 100} {
+			h.observe(value)
+		}
+
+		assert.Equal(t, []uint64{2, 2, 2}, h.Counts)
+		assert.Equal(t, uint64(126), h.Sum)
+	})
+}
+
+// TestEntryShape tests field count and size metrics
+func TestEntryShape(t *testing.T) {
+	t.Run("should measure entries per component", func(t *testing.T) {
+		logger, _ := newFileLogger(t, WithEntryShape(0, 0))
+
+		logger.Info("request", String("path", "/orders"))
+		logger.With(String("user", "alice")).Info("request", String("path", "/orders"))
+		logger.ForInfra("db").Info("query")
+
+		shapes := logger.EntryShapes()
+		require.Len(t, shapes, 2)
+
+		app := shapes[0]
+		assert.Equal(t, "app", app.Component)
+		assert.Equal(t, uint64(2), app.Entries)
+		assert.Equal(t, uint64(3), app.Fields.Sum)
+		assert.Equal(t, uint64(2), app.Fields.Counts[0])
+		assert.Equal(t, uint64(2), sum(app.Bytes.Counts))
+		assert.Greater(t, app.Bytes.Sum, uint64(100))
+		assert.Zero(t, app.Wide)
+
+		db := shapes[1]
+		assert.Equal(t, "db", db.Component)
+		assert.Equal(t, uint64(1), db.Entries)
+		assert.Equal(t, uint64(1), db.Fields.Sum) // component
+	})
+
+	t.Run("should warn once per call site about wide entries", func(t *testing.T) {
+		logger, output := newFileLogger(t, WithEntryShape(3, 0))
+
+		for i := 0; i < 2; i++ {
+			logger.With(String("a", "1"), String("b", "2")).Info("wide", String("c", "3"), String("d", "4"))
+		}
+		logger.Info("narrow", String("a", "1"))
+
+		entries := entriesWithMessage(t, output(), "wide log entry")
+		require.Len(t, entries, 1)
+		assert.Equal(t, "warn", entries[0]["level"])
+		assert.Equal(t, "wide", entries[0]["entry_message"])
+		assert.Equal(t, float64(4), entries[0]["entry_fields"])
+		assert.Equal(t, float64(3), entries[0]["max_fields"])
+		assert.Equal(t, "1", entries[0]["a"])
+		assert.Len(t, entriesWithMessage(t, output(), "wide"), 2)
+
+		shapes := logger.EntryShapes()
+		require.Len(t, shapes, 1)
+		assert.Equal(t, uint64(2), shapes[0].Wide)
+	})
+
+	t.Run("should warn about large entries", func(t *testing.T) {
+		logger, output := newFileLogger(t, WithEntryShape(0, 256))
+
+		logger.Info("dump", String("body", strings.Repeat("x", 512)))
+
+		entries := entriesWithMessage(t, output(), "wide log entry")
+		require.Len(t, entries, 1)
+		assert.Greater(t, entries[0]["entry_bytes"], float64(512))
+	})
+
+	t.Run("should be disabled by default", func(t *testing.T) {
+		logger, _ := newFileLogger(t)
+		logger.Info("request")
+
+		assert.Nil(t, logger.EntryShapes())
+	})
+}
+
+// TestEntryShapeHandler tests the entry shape admin handler
+func TestEntryShapeHandler(t *testing.T) {
+	serve := func(handler http.Handler, method, target string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(method, target, nil))
+		return rec
+	}
+
+	t.Run("should list entry shapes as JSON", func(t *testing.T) {
+		logger, _ := newFileLogger(t, WithEntryShape(0, 0))
+		logger.Info("request", String("path", "/orders"))
+
+		rec := serve(EntryShapeHandler(logger), http.MethodGet, "/")
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+		var shapes []EntryShape
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &shapes))
+		require.Len(t, shapes, 1)
+		assert.Equal(t, uint64(1), shapes[0].Entries)
+		assert.Equal(t, fieldCountBounds, shapes[0].Fields.Bounds)
+	})
+
+	t.Run("should write the Prometheus text format", func(t *testing.T) {
+		logger, _ := newFileLogger(t, WithEntryShape(1, 0))
+		logger.ForInfra("db").Info("query", String("table", "orders"))
+
+		rec := serve(EntryShapeHandler(logger), http.MethodGet, "/?format=prometheus")
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.True(t, strings.HasPrefix(rec.Header().Get("Content-Type"), "text/plain"))
+		body := rec.Body.String()
+		assert.Contains(t, body, "# TYPE xlogger_entry_fields histogram")
+		assert.Contains(t, body, `xlogger_entry_fields_bucket{component="db",le="4"} 1`)
+		assert.Contains(t, body, `xlogger_entry_fields_bucket{component="db",le="+Inf"} 1`)
+		assert.Contains(t, body, `xlogger_entry_fields_sum{component="db"} 2`)
+		assert.Contains(t, body, `xlogger_entry_bytes_count{component="db"} 1`)
+		assert.Contains(t, body, `xlogger_wide_entries_total{component="db"} 1`)
+	})
+
+	t.Run("should reject invalid requests", func(t *testing.T) {
+		logger, _ := newFileLogger(t, WithEntryShape(0, 0))
+
+		rec := serve(EntryShapeHandler(logger), http.MethodPost, "/")
+		assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+		assert.Equal(t, "GET", rec.Header().Get("Allow"))
+	})
+
+	t.Run("should report disabled metrics", func(t *testing.T) {
+		logger, _ := newFileLogger(t)
+
+		rec := serve(EntryShapeHandler(logger), http.MethodGet, "/")
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Contains(t, rec.Body.String(), "entry shape metrics are disabled")
+
+		rec = serve(EntryShapeHandler(&MockLogger{}), http.MethodGet, "/")
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+func sum(counts []uint64) uint64 {
+	var total uint64
+	for _, count := range counts {
+		total += count
+	}
+	return total
+}
@@ -0,0 +1,190 @@
+package xlogger
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Suffixes of the keys added next to error fields
+const (
+	errorKindSuffix  = ".kind"
+	errorCauseSuffix = ".cause"
+	errorStackSuffix = ".stack"
+)
+
+// stackError is an error carrying the stack of the ErrorWithStack call
+type stackError struct {
+	err   error
+	stack []runtime.Frame
+}
+
+// Error implements error
+func (e *stackError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the error given to ErrorWithStack
+func (e *stackError) Unwrap() error {
+	return e.err
+}
+
+// ErrorWithStack creates an "error" field that also logs the stack of its
+// call site under "error.stack", for errors handled far from where they are
+// logged or logged below Error level, where zap adds no stack trace. Error
+// reporters receive this stack instead of the stack of the log call.
+//
+// Example:
+//
+//	if err := repo.Save(ctx, order); err != nil {
+//	    logger.Warn("save failed, retrying", xlogger.ErrorWithStack(err))
+//	}
+func ErrorWithStack(err error) Field {
+	if err == nil {
+		return Error(nil)
+	}
+	return Error(&stackError{err: err, stack: callSiteStack()})
+}
+
+// callSiteStack returns the stack of the caller of ErrorWithStack
+func callSiteStack() []runtime.Frame {
+	pcs := make([]uintptr, maxReportFrames)
+	// Skips runtime.Callers, callSiteStack and ErrorWithStack
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	var stack []runtime.Frame
+	for {
+		frame, more := frames.Next()
+		stack = append(stack, frame)
+		if !more {
+			return stack
+		}
+	}
+}
+
+// errorStack returns the stack carried by err, nil without ErrorWithStack
+func errorStack(err error) []runtime.Frame {
+	var stacked *stackError
+	if errors.As(err, &stacked) {
+		return stacked.stack
+	}
+	return nil
+}
+
+// formatStack formats frames like zap stack traces, one function per line
+// followed by its indented file and line
+func formatStack(frames []runtime.Frame) string {
+	var b strings.Builder
+	for i, frame := range frames {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString(frame.Function)
+		b.WriteString("\n\t")
+		b.WriteString(frame.File)
+		b.WriteByte(':')
+		b.WriteString(strconv.Itoa(frame.Line))
+	}
+	return b.String()
+}
+
+// rootCause returns the innermost error of the errors.Unwrap chain of err,
+// stopping before a typed nil error
+func rootCause(err error) error {
+	for {
+		next := errors.Unwrap(err)
+		if isNilValue(next) {
+			return err
+		}
+		err = next
+	}
+}
+
+// isNilValue reports whether v is nil or a typed nil pointer, map, slice,
+// func or chan, whose Error or String method may panic. Zap logs such
+// values as "<nil>".
+func isNilValue(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	switch rv := reflect.ValueOf(v); rv.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+		return rv.IsNil()
+	}
+	return false
+}
+
+// errorDetails returns the fields added next to the error field key: the
+// stack of ErrorWithStack and, with chains, the type and message of the
+// root cause. The cause is left out when it reads like err.
+func errorDetails(key string, err error, chains bool) []zapcore.Field {
+	var details []zapcore.Field
+	if chains {
+		root := rootCause(err)
+		details = append(details, zap.String(key+errorKindSuffix, fmt.Sprintf("%T", root)))
+		if cause := root.Error(); cause != err.Error() {
+			details = append(details, zap.String(key+errorCauseSuffix, cause))
+		}
+	}
+	if stack := errorStack(err); stack != nil {
+		details = append(details, zap.String(key+errorStackSuffix, formatStack(stack)))
+	}
+	return details
+}
+
+// expandErrorFields adds the details of every error field after it,
+// copying fields only when something is added
+func expandErrorFields(fields []zapcore.Field, chains bool) []zapcore.Field {
+	var expanded []zapcore.Field
+	for i, field := range fields {
+		var details []zapcore.Field
+		if err, ok := field.Interface.(error); ok && field.Type == zapcore.ErrorType && !isNilValue(err) {
+			details = errorDetails(field.Key, err, chains)
+		}
+		if expanded == nil {
+			if len(details) == 0 {
+				continue
+			}
+			expanded = append(make([]zapcore.Field, 0, len(fields)+len(details)), fields[:i]...)
+		}
+		expanded = append(append(expanded, field), details...)
+	}
+	if expanded == nil {
+		return fields
+	}
+	return expanded
+}
+
+// errorFieldsCore expands error fields before entries reach the outputs,
+// sinks, hooks and reporters
+type errorFieldsCore struct {
+	zapcore.Core
+	errSink zapcore.WriteSyncer
+	chains  bool
+}
+
+// With implements zapcore.Core
+func (c *errorFieldsCore) With(fields []zapcore.Field) zapcore.Core {
+	return &errorFieldsCore{Core: c.Core.With(expandErrorFields(fields, c.chains)), errSink: c.errSink, chains: c.chains}
+}
+
+// Check implements zapcore.Core
+func (c *errorFieldsCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Core.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write implements zapcore.Core
+func (c *errorFieldsCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	writeChecked(c.Core, ent, expandErrorFields(fields, c.chains), c.errSink)
+	return nil
+}
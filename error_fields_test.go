@@ -0,0 +1,151 @@
+package xlogger
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestErrorWithStack tests error fields carrying their call site stack
+func TestErrorWithStack(t *testing.T) {
+	t.Run("should log the stack of the call site", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+
+		field := ErrorWithStack(errors.New("connection reset"))
+		logger.Warn("save failed", field)
+
+		entries := entriesWithMessage(t, output(), "save failed")
+		require.Len(t, entries, 1)
+		assert.Equal(t, "connection reset", entries[0]["error"])
+		stack, ok := entries[0]["error.stack"].(string)
+		require.True(t, ok)
+		assert.True(t, strings.HasPrefix(stack, xloggerPackage+".TestErrorWithStack"), stack)
+		assert.Contains(t, stack, "error_fields_test.go:")
+	})
+
+	t.Run("should keep the error chain", func(t *testing.T) {
+		field := ErrorWithStack(fs.ErrNotExist)
+		err, ok := field.Value().(error)
+		require.True(t, ok)
+		assert.ErrorIs(t, err, fs.ErrNotExist)
+		assert.Nil(t, ErrorWithStack(nil).Value())
+	})
+
+	t.Run("should hand the stack to error reporters", func(t *testing.T) {
+		var report ErrorReport
+		logger, err := NewZapLogger(NewLoggerConfig(
+			WithOutputPaths(filepath.Join(t.TempDir(), "app.log")),
+			WithErrorReporter(ErrorReporterFunc(func(r ErrorReport) error {
+				report = r
+				return nil
+			})),
+		))
+		require.NoError(t, err)
+
+		field := ErrorWithStack(errors.New("boom"))
+		logger.Error("failed", field)
+		require.NotEmpty(t, report.Stack)
+		assert.Equal(t, xloggerPackage+".TestErrorWithStack.func3", report.Stack[0].Function)
+	})
+}
+
+// TestWithErrorChains_Logger tests expanding wrapped errors into their root cause
+func TestWithErrorChains_Logger(t *testing.T) {
+	_, openErr := os.Open(filepath.Join(t.TempDir(), "missing.yaml"))
+	require.Error(t, openErr)
+	wrapped := fmt.Errorf("load config: %w", openErr)
+	cause := errors.Unwrap(openErr)
+
+	t.Run("should add the kind and cause of wrapped errors", func(t *testing.T) {
+		logger, output := newFileLogger(t, WithErrorChains())
+
+		logger.With(NamedError("last_error", errors.New("timeout"))).Error("load failed", Error(wrapped))
+
+		entries := entriesWithMessage(t, output(), "load failed")
+		require.Len(t, entries, 1)
+		assert.Equal(t, wrapped.Error(), entries[0]["error"])
+		assert.Equal(t, "syscall.Errno", entries[0]["error.kind"])
+		assert.Equal(t, cause.Error(), entries[0]["error.cause"])
+		assert.Equal(t, "*errors.errorString", entries[0]["last_error.kind"])
+		assert.NotContains(t, entries[0], "last_error.cause")
+	})
+
+	t.Run("should combine chains with stacks", func(t *testing.T) {
+		logger, output := newFileLogger(t, WithErrorChains())
+
+		logger.Info("retrying", ErrorWithStack(wrapped))
+
+		entries := entriesWithMessage(t, output(), "retrying")
+		require.Len(t, entries, 1)
+		assert.Equal(t, "syscall.Errno", entries[0]["error.kind"])
+		assert.Equal(t, cause.Error(), entries[0]["error.cause"])
+		assert.Contains(t, entries[0], "error.stack")
+	})
+
+	t.Run("should leave error fields alone by default", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+
+		logger.Error("load failed", Error(wrapped))
+
+		entries := entriesWithMessage(t, output(), "load failed")
+		require.Len(t, entries, 1)
+		assert.NotContains(t, entries[0], "error.kind")
+		assert.NotContains(t, entries[0], "error.cause")
+	})
+
+	t.Run("should redact added causes", func(t *testing.T) {
+		logger, output := newFileLogger(t, WithErrorChains(), WithRedaction(nil, []*regexp.Regexp{EmailPattern}))
+
+		logger.Error("signup failed", Error(fmt.Errorf("signup: %w", errors.New("duplicate jane@example.com"))))
+		assert.NotContains(t, output(), "jane@example.com")
+	})
+
+	t.Run("should log typed nil errors like zap", func(t *testing.T) {
+		logger, output := newFileLogger(t, WithErrorChains())
+		var typedNil *pointerError
+
+		require.NotPanics(t, func() {
+			logger.Error("load failed", Error(typedNil))
+			logger.Error("load failed", NamedError("wrapped", &wrapperError{inner: typedNil}))
+		})
+
+		entries := entriesWithMessage(t, output(), "load failed")
+		require.Len(t, entries, 2)
+		assert.Equal(t, "<nil>", entries[0]["error"])
+		assert.NotContains(t, entries[0], "error.kind")
+		assert.Equal(t, "*xlogger.wrapperError", entries[1]["wrapped.kind"])
+	})
+}
+
+// pointerError is an error whose Error method panics on a nil receiver
+type pointerError struct {
+	msg string
+}
+
+// Error implements error
+func (e *pointerError) Error() string {
+	return e.msg
+}
+
+// wrapperError wraps an error, possibly a typed nil one
+type wrapperError struct {
+	inner error
+}
+
+// Error implements error
+func (e *wrapperError) Error() string {
+	return "wrapped"
+}
+
+// Unwrap returns the wrapped error
+func (e *wrapperError) Unwrap() error {
+	return e.inner
+}
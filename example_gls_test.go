@@ -0,0 +1,84 @@
+//go:build !xlogger_nogls
+
+package xlogger_test
+
+// Examples of goroutine-local trace state, which stays empty in builds with
+// the xlogger_nogls tag
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/hotfixfirst/go-xlogger"
+)
+
+func ExampleRunWithTrace() {
+	logger := newExampleLogger()
+	defer logger.Close(context.Background())
+
+	err := xlogger.RunWithTrace("req-abc123", "corr-xyz789", func() error {
+		fmt.Println("request:", xlogger.TraceRequestID())
+		fmt.Println("correlation:", xlogger.TraceCorrelationID())
+
+		// Trace IDs are added to every entry logged in fn
+		logger.Info("Processing request", xlogger.String("action", "user_login"))
+		return nil
+	})
+	if err != nil {
+		fmt.Println("error:", err)
+	}
+
+	fmt.Printf("outside: %q\n", xlogger.TraceRequestID())
+	// Output:
+	// request: req-abc123
+	// correlation: corr-xyz789
+	// info Processing request action=user_login correlation_id=corr-xyz789 request_id=req-abc123
+	// outside: ""
+}
+
+func ExampleRunWithTraceVoid() {
+	logger := newExampleLogger()
+	defer logger.Close(context.Background())
+
+	xlogger.RunWithTraceVoid("req-def456", "corr-uvw321", func() {
+		logger.Info("Background task started", xlogger.String("task", "cleanup"))
+	})
+	// Output:
+	// info Background task started correlation_id=corr-uvw321 request_id=req-def456 task=cleanup
+}
+
+func ExampleNewTraceScope() {
+	scope := xlogger.NewTraceScope()
+	logger := newExampleLogger(xlogger.WithTraceScope(scope))
+	defer logger.Close(context.Background())
+
+	_ = scope.RunWithTrace("req-plugin", "corr-plugin", func() error {
+		logger.Info("Plugin request")
+		fmt.Printf("package-level: %q\n", xlogger.TraceRequestID())
+		return nil
+	})
+	// Output:
+	// info Plugin request correlation_id=corr-plugin request_id=req-plugin
+	// package-level: ""
+}
+
+func ExampleHTTPMiddleware() {
+	logger := newExampleLogger()
+	defer logger.Close(context.Background())
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/orders", func(w http.ResponseWriter, r *http.Request) {
+		logger.Info("Listing orders")
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := xlogger.HTTPMiddleware(logger)(mux)
+
+	req := httptest.NewRequest(http.MethodGet, "/orders", nil)
+	req.Header.Set(xlogger.RequestIDHeader, "req-789")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	// Output:
+	// info Listing orders correlation_id=req-789 request_id=req-789
+	// info http request completed bytes=0 correlation_id=req-789 method=GET path=/orders request_id=req-789 status=200
+}
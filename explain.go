@@ -0,0 +1,93 @@
+package xlogger
+
+import (
+	"fmt"
+	"sync"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// maxExplainedDrops bounds how many distinct dropped entries are explained
+const maxExplainedDrops = 1024
+
+// Reasons reported for dropped entries
+const (
+	dropReasonLevel   = "level %s is below the minimum level %s"
+	dropReasonSampled = "sampled out, too many entries with this message in one second"
+)
+
+// dropExplainer writes a one-time explanation for each distinct dropped entry
+type dropExplainer struct {
+	mu   sync.Mutex
+	out  zapcore.WriteSyncer
+	seen map[string]struct{}
+}
+
+func newDropExplainer(out zapcore.WriteSyncer) *dropExplainer {
+	return &dropExplainer{out: out, seen: make(map[string]struct{})}
+}
+
+// explain reports a dropped entry unless the same level, logger and message
+// was already explained
+func (e *dropExplainer) explain(ent zapcore.Entry, reason string) {
+	key := ent.Level.String() + "\x00" + ent.LoggerName + "\x00" + ent.Message
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if _, ok := e.seen[key]; ok || len(e.seen) > maxExplainedDrops {
+		return
+	}
+	e.seen[key] = struct{}{}
+
+	if len(e.seen) > maxExplainedDrops {
+		fmt.Fprintf(e.out, "xlogger: more than %d distinct entries dropped, no longer explaining drops\n", maxExplainedDrops)
+	} else {
+		fmt.Fprintf(e.out, "xlogger: dropped %s entry %q%s: %s (explained once)\n",
+			ent.Level, ent.Message, loggerNameSuffix(ent.LoggerName), reason)
+	}
+	_ = e.out.Sync()
+}
+
+func loggerNameSuffix(name string) string {
+	if name == "" {
+		return ""
+	}
+	return fmt.Sprintf(" from logger %q", name)
+}
+
+// explainCore reports entries its inner core drops. It claims every level
+// is enabled so zap hands it entries that would otherwise be discarded early.
+type explainCore struct {
+	zapcore.Core
+	explainer *dropExplainer
+}
+
+// Enabled implements zapcore.LevelEnabler
+func (c *explainCore) Enabled(zapcore.Level) bool {
+	return true
+}
+
+// Level reports the inner core's minimum level to zap.Logger.Level
+func (c *explainCore) Level() zapcore.Level {
+	return zapcore.LevelOf(c.Core)
+}
+
+// With implements zapcore.Core
+func (c *explainCore) With(fields []zapcore.Field) zapcore.Core {
+	return &explainCore{Core: c.Core.With(fields), explainer: c.explainer}
+}
+
+// Check implements zapcore.Core
+func (c *explainCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.Core.Enabled(ent.Level) {
+		c.explainer.explain(ent, fmt.Sprintf(dropReasonLevel, ent.Level, zapcore.LevelOf(c.Core)))
+		return ce
+	}
+
+	checked := c.Core.Check(ent, ce)
+	if checked == ce {
+		c.explainer.explain(ent, dropReasonSampled)
+	}
+	return checked
+}
@@ -0,0 +1,94 @@
+package xlogger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(data)
+}
+
+// TestExplainDrops tests the drop explanation diagnostics mode
+func TestExplainDrops(t *testing.T) {
+	t.Run("should explain level filtered entries once", func(t *testing.T) {
+		errPath := filepath.Join(t.TempDir(), "errors.log")
+		logger, output := newFileLogger(t, WithErrorOutputPaths(errPath), WithExplainDrops(true), WithLevel(zapcore.WarnLevel))
+
+		logger.Info("hidden")
+		logger.Info("hidden")
+		logger.Warn("visible")
+
+		explanations := readFile(t, errPath)
+		assert.Equal(t, 1, strings.Count(explanations, `"hidden"`))
+		assert.Contains(t, explanations, "level info is below the minimum level warn")
+		assert.NotContains(t, explanations, "visible")
+		assert.Contains(t, output(), "visible")
+	})
+
+	t.Run("should explain sampled entries", func(t *testing.T) {
+		errPath := filepath.Join(t.TempDir(), "errors.log")
+		logger, output := newFileLogger(t, WithErrorOutputPaths(errPath), WithExplainDrops(true))
+
+		for i := 0; i < 150; i++ {
+			logger.Info("repeated")
+		}
+
+		assert.Equal(t, 100, strings.Count(output(), "repeated"))
+		explanations := readFile(t, errPath)
+		assert.Equal(t, 1, strings.Count(explanations, "repeated"))
+		assert.Contains(t, explanations, "sampled out")
+	})
+
+	t.Run("should explain infrastructure and derived loggers", func(t *testing.T) {
+		errPath := filepath.Join(t.TempDir(), "errors.log")
+		logger, _ := newFileLogger(t, WithErrorOutputPaths(errPath), WithExplainDrops(true), WithLevel(zapcore.ErrorLevel))
+
+		logger.ForInfra("db").Warn("slow query")
+		logger.With(String("k", "v")).Debug("derived")
+
+		explanations := readFile(t, errPath)
+		assert.Contains(t, explanations, "slow query")
+		assert.Contains(t, explanations, "derived")
+	})
+
+	t.Run("should keep reporting the configured level", func(t *testing.T) {
+		logger, _ := newFileLogger(t, WithErrorOutputPaths(filepath.Join(t.TempDir(), "errors.log")), WithExplainDrops(true), WithLevel(zapcore.WarnLevel))
+
+		assert.Equal(t, zapcore.WarnLevel, logger.logger.Level())
+		assert.Equal(t, zapcore.WarnLevel, logger.Level())
+	})
+
+	t.Run("should not explain when disabled", func(t *testing.T) {
+		errPath := filepath.Join(t.TempDir(), "errors.log")
+		logger, _ := newFileLogger(t, WithErrorOutputPaths(errPath), WithExplainDrops(true), WithLevel(zapcore.WarnLevel), WithExplainDrops(false))
+
+		logger.Info("hidden")
+
+		assert.Empty(t, readFile(t, errPath))
+	})
+
+	t.Run("should stop explaining after the limit", func(t *testing.T) {
+		errPath := filepath.Join(t.TempDir(), "errors.log")
+		logger, _ := newFileLogger(t, WithErrorOutputPaths(errPath), WithExplainDrops(true), WithLevel(zapcore.WarnLevel))
+
+		for i := 0; i < maxExplainedDrops+10; i++ {
+			logger.Debug(fmt.Sprintf("message %d", i))
+		}
+
+		explanations := readFile(t, errPath)
+		assert.Equal(t, maxExplainedDrops+1, strings.Count(explanations, "\n"))
+		assert.Contains(t, explanations, "no longer explaining drops")
+	})
+}
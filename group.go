@@ -0,0 +1,123 @@
+package xlogger
+
+import (
+	"sync"
+	"time"
+)
+
+// Group ties the steps of a multi-step workflow together: every entry logged
+// through it carries the group name, a shared group_id and its step number,
+// and End logs a summary with the total duration and the step that failed.
+type Group struct {
+	logger Logger
+	name   string
+	id     string
+	start  time.Time
+
+	mu                sync.Mutex
+	steps             int
+	failedStep        int
+	failedStepMessage string
+	lastStepMessage   string
+	ended             bool
+}
+
+// NewGroup begins a group named name logging through logger.
+//
+// Example:
+//
+//	group := xlogger.NewGroup(logger, "checkout")
+//	group.Info("cart validated")
+//	if err := charge(order); err != nil {
+//	    group.Error("payment failed", xlogger.Error(err))
+//	    group.End(err)
+//	    return err
+//	}
+//	group.Info("order placed")
+//	group.End(nil)
+func NewGroup(logger Logger, name string) *Group {
+	id := newRequestID(logger)
+	return &Group{
+		logger: skipCaller(logger.With(String("group", name), String("group_id", id)), 1),
+		name:   name,
+		id:     id,
+		start:  time.Now(),
+	}
+}
+
+// BeginGroup begins a group logging through l.
+func (l *ZapLogger) BeginGroup(name string) *Group {
+	return NewGroup(l, name)
+}
+
+// ID returns the group_id shared by the entries of the group.
+func (g *Group) ID() string {
+	return g.id
+}
+
+// Debug logs a debug step of the group.
+func (g *Group) Debug(msg string, fields ...Field) {
+	g.logger.Debug(msg, g.step(msg, false, fields)...)
+}
+
+// Info logs an info step of the group.
+func (g *Group) Info(msg string, fields ...Field) {
+	g.logger.Info(msg, g.step(msg, false, fields)...)
+}
+
+// Warn logs a warning step of the group.
+func (g *Group) Warn(msg string, fields ...Field) {
+	g.logger.Warn(msg, g.step(msg, false, fields)...)
+}
+
+// Error logs a failed step of the group. The first failed step is reported
+// by End.
+func (g *Group) Error(msg string, fields ...Field) {
+	g.logger.Error(msg, g.step(msg, true, fields)...)
+}
+
+// End logs the group summary with the total duration and step count, at
+// Info as "group completed" when err is nil and at Error as "group failed"
+// otherwise. The failed step is the first step logged with Error, or the
+// last step when err is set without one. Only the first call logs.
+func (g *Group) End(err error) {
+	g.mu.Lock()
+	if g.ended {
+		g.mu.Unlock()
+		return
+	}
+	g.ended = true
+	steps := g.steps
+	failedStep, failedStepMessage := g.failedStep, g.failedStepMessage
+	if err != nil && failedStep == 0 && steps > 0 {
+		failedStep, failedStepMessage = steps, g.lastStepMessage
+	}
+	g.mu.Unlock()
+
+	fields := []Field{
+		Duration("duration", time.Since(g.start)),
+		Int("steps", steps),
+	}
+	if failedStep > 0 {
+		fields = append(fields, Int("failed_step", failedStep), String("failed_step_message", failedStepMessage))
+	}
+	if err != nil {
+		g.logger.Error("group failed", append(fields, Error(err))...)
+		return
+	}
+	g.logger.Info("group completed", fields...)
+}
+
+// step numbers the next step and records it for the summary
+func (g *Group) step(msg string, failed bool, fields []Field) []Field {
+	g.mu.Lock()
+	g.steps++
+	n := g.steps
+	g.lastStepMessage = msg
+	if failed && g.failedStep == 0 {
+		g.failedStep, g.failedStepMessage = n, msg
+	}
+	g.mu.Unlock()
+
+	return append([]Field{Int("step", n)}, fields...)
+}
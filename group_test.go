@@ -0,0 +1,80 @@
+package xlogger
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGroup tests the transactional log grouping API
+func TestGroup(t *testing.T) {
+	t.Run("should share group_id and number steps", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+
+		group := logger.BeginGroup("checkout")
+		group.Info("cart validated")
+		group.Warn("coupon expired")
+		group.Info("order placed")
+		group.End(nil)
+
+		log := output()
+		for i, msg := range []string{"cart validated", "coupon expired", "order placed"} {
+			entries := entriesWithMessage(t, log, msg)
+			require.Len(t, entries, 1)
+			assert.Equal(t, "checkout", entries[0]["group"])
+			assert.Equal(t, group.ID(), entries[0]["group_id"])
+			assert.Equal(t, float64(i+1), entries[0]["step"])
+			assert.Contains(t, entries[0]["caller"], "group_test.go")
+		}
+
+		summary := entriesWithMessage(t, log, "group completed")
+		require.Len(t, summary, 1)
+		assert.Equal(t, "info", summary[0]["level"])
+		assert.Equal(t, group.ID(), summary[0]["group_id"])
+		assert.Equal(t, float64(3), summary[0]["steps"])
+		assert.Contains(t, summary[0], "duration")
+		assert.NotContains(t, summary[0], "failed_step")
+	})
+
+	t.Run("should report the first failed step", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+
+		group := logger.BeginGroup("checkout")
+		group.Info("cart validated")
+		group.Error("payment declined")
+		group.Error("refund failed")
+		group.End(errors.New("declined"))
+		group.End(nil)
+
+		log := output()
+		assert.Empty(t, entriesWithMessage(t, log, "group completed"))
+		summary := entriesWithMessage(t, log, "group failed")
+		require.Len(t, summary, 1)
+		assert.Equal(t, "error", summary[0]["level"])
+		assert.Equal(t, float64(2), summary[0]["failed_step"])
+		assert.Equal(t, "payment declined", summary[0]["failed_step_message"])
+		assert.Equal(t, "declined", summary[0]["error"])
+	})
+
+	t.Run("should blame the last step when ended with an error only", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+
+		group := NewGroup(logger, "import")
+		group.Info("file downloaded")
+		group.Info("rows parsed")
+		group.End(errors.New("commit failed"))
+
+		summary := entriesWithMessage(t, output(), "group failed")
+		require.Len(t, summary, 1)
+		assert.Equal(t, float64(2), summary[0]["failed_step"])
+		assert.Equal(t, "rows parsed", summary[0]["failed_step_message"])
+	})
+
+	t.Run("should give each group its own id", func(t *testing.T) {
+		logger, _ := newFileLogger(t)
+
+		assert.NotEqual(t, logger.BeginGroup("a").ID(), logger.BeginGroup("a").ID())
+	})
+}
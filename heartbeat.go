@@ -0,0 +1,140 @@
+package xlogger
+
+import (
+	"sync"
+	"time"
+)
+
+// Heartbeat periodically logs progress of a long-running operation until
+// Stop, and warns once when the operation exceeds its expected duration.
+type Heartbeat struct {
+	logger    Logger
+	operation string
+	start     time.Time
+
+	mu       sync.Mutex
+	progress float64
+	expected time.Duration
+	warned   bool
+
+	expectCh chan time.Duration
+	stopCh   chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewHeartbeat starts a heartbeat for operation that logs every interval.
+// A non-positive interval defaults to one minute.
+//
+// Example:
+//
+//	hb := xlogger.NewHeartbeat(logger, "migration", 30*time.Second).ExpectWithin(10 * time.Minute)
+//	defer hb.Stop()
+//	for i, row := range rows {
+//	    migrate(row)
+//	    hb.SetProgress(float64(i+1) / float64(len(rows)))
+//	}
+func NewHeartbeat(logger Logger, operation string, interval time.Duration) *Heartbeat {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	hb := &Heartbeat{
+		logger:    logger,
+		operation: operation,
+		start:     time.Now(),
+		expectCh:  make(chan time.Duration, 1),
+		stopCh:    make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	go hb.run(interval)
+	return hb
+}
+
+// Heartbeat starts a heartbeat logging through l.
+func (l *ZapLogger) Heartbeat(operation string, interval time.Duration) *Heartbeat {
+	return NewHeartbeat(l, operation, interval)
+}
+
+// SetProgress updates the progress value reported by the next heartbeat,
+// for example a completion ratio or a processed item count.
+func (hb *Heartbeat) SetProgress(progress float64) {
+	hb.mu.Lock()
+	defer hb.mu.Unlock()
+	hb.progress = progress
+}
+
+// ExpectWithin sets how long the operation is expected to take, measured from
+// the start of the heartbeat. A Warn entry is logged once when it is exceeded.
+func (hb *Heartbeat) ExpectWithin(d time.Duration) *Heartbeat {
+	hb.mu.Lock()
+	hb.expected = d
+	hb.mu.Unlock()
+
+	// Replace any pending deadline so the loop rearms its timer
+	select {
+	case <-hb.expectCh:
+	default:
+	}
+	hb.expectCh <- d
+	return hb
+}
+
+// Stop ends the heartbeat and logs the final progress and elapsed time.
+// It is safe to call more than once.
+func (hb *Heartbeat) Stop() {
+	hb.stopOnce.Do(func() {
+		close(hb.stopCh)
+		<-hb.done
+		hb.logger.Info("operation finished", hb.fields()...)
+	})
+}
+
+func (hb *Heartbeat) run(interval time.Duration) {
+	defer close(hb.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var deadline <-chan time.Time
+	for {
+		select {
+		case <-hb.stopCh:
+			return
+		case <-ticker.C:
+			hb.logger.Info("operation in progress", hb.fields()...)
+		case d := <-hb.expectCh:
+			deadline = time.After(time.Until(hb.start.Add(d)))
+		case <-deadline:
+			deadline = nil
+			hb.warnOverdue()
+		}
+	}
+}
+
+// warnOverdue logs the overdue warning once
+func (hb *Heartbeat) warnOverdue() {
+	hb.mu.Lock()
+	if hb.warned {
+		hb.mu.Unlock()
+		return
+	}
+	hb.warned = true
+	expected := hb.expected
+	hb.mu.Unlock()
+
+	hb.logger.Warn("operation exceeded expected duration",
+		append(hb.fields(), Duration("expected", expected))...)
+}
+
+func (hb *Heartbeat) fields() []Field {
+	hb.mu.Lock()
+	progress := hb.progress
+	hb.mu.Unlock()
+
+	return []Field{
+		String("operation", hb.operation),
+		Duration("elapsed", time.Since(hb.start)),
+		Float64("progress", progress),
+	}
+}
@@ -0,0 +1,84 @@
+package xlogger
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFileLogger creates a logger with opts writing to a temporary file,
+// returned with a function reading the file. The logger is closed when
+// the test ends.
+func newFileLogger(t *testing.T, opts ...Option) (*ZapLogger, func() string) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	logger, err := NewZapLogger(NewLoggerConfig(append([]Option{WithOutputPaths(path)}, opts...)...))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = logger.Close(t.Context()) })
+
+	return logger, func() string { return readFile(t, path) }
+}
+
+// TestHeartbeat tests the long-running operation heartbeat
+func TestHeartbeat(t *testing.T) {
+	t.Run("should log progress periodically until stopped", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+
+		hb := logger.Heartbeat("migration", 10*time.Millisecond)
+		hb.SetProgress(0.5)
+
+		require.Eventually(t, func() bool {
+			return strings.Count(output(), "operation in progress") >= 2
+		}, time.Second, 5*time.Millisecond)
+
+		hb.Stop()
+		hb.Stop()
+
+		log := output()
+		assert.Contains(t, log, `"operation":"migration"`)
+		assert.Contains(t, log, `"progress":0.5`)
+		assert.Equal(t, 1, strings.Count(log, "operation finished"))
+
+		// No heartbeats after Stop
+		count := strings.Count(log, "operation in progress")
+		time.Sleep(30 * time.Millisecond)
+		assert.Equal(t, count, strings.Count(output(), "operation in progress"))
+	})
+
+	t.Run("should warn once when exceeding expected duration", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+
+		hb := logger.Heartbeat("backfill", time.Hour).ExpectWithin(10 * time.Millisecond)
+		defer hb.Stop()
+
+		require.Eventually(t, func() bool {
+			return strings.Contains(output(), "operation exceeded expected duration")
+		}, time.Second, 5*time.Millisecond)
+
+		time.Sleep(30 * time.Millisecond)
+		log := output()
+		assert.Equal(t, 1, strings.Count(log, "operation exceeded expected duration"))
+		assert.Contains(t, log, `"level":"warn"`)
+		assert.Contains(t, log, `"expected":"10ms"`)
+	})
+
+	t.Run("should not warn when finished in time", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+
+		hb := logger.Heartbeat("quick", time.Hour).ExpectWithin(time.Hour)
+		hb.Stop()
+
+		assert.NotContains(t, output(), "exceeded")
+		assert.Contains(t, output(), "operation finished")
+	})
+
+	t.Run("should default non-positive interval", func(t *testing.T) {
+		hb := NewHeartbeat(NewNop(), "default", 0)
+		hb.Stop()
+	})
+}
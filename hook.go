@@ -0,0 +1,79 @@
+package xlogger
+
+import (
+	"errors"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// Entry is a log entry handed to a Hook.
+type Entry struct {
+	Level   zapcore.Level
+	Time    time.Time
+	Message string
+	Caller  string                 // file:line of the log call, when caller is enabled
+	Fields  map[string]interface{} // Logger and entry fields, including trace IDs
+}
+
+// Hook is called for every entry that passes the level filter, for example
+// to count entries, raise alerts or forward them elsewhere. Hooks run on the
+// logging goroutine, so slow hooks should queue. Returned errors are written
+// to the error outputs.
+type Hook func(entry Entry) error
+
+// hookCore hands enabled entries to the hooks
+type hookCore struct {
+	zapcore.LevelEnabler
+	hooks  []Hook
+	fields []zapcore.Field
+}
+
+// With implements zapcore.Core
+func (c *hookCore) With(fields []zapcore.Field) zapcore.Core {
+	return &hookCore{
+		LevelEnabler: c.LevelEnabler,
+		hooks:        c.hooks,
+		fields:       append(c.fields[:len(c.fields):len(c.fields)], fields...),
+	}
+}
+
+// Check implements zapcore.Core
+func (c *hookCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write implements zapcore.Core
+func (c *hookCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	entry := Entry{
+		Level:   ent.Level,
+		Time:    ent.Time,
+		Message: ent.Message,
+	}
+	if ent.Caller.Defined {
+		entry.Caller = ent.Caller.TrimmedPath()
+	}
+
+	enc := zapcore.NewMapObjectEncoder()
+	for _, field := range c.fields {
+		field.AddTo(enc)
+	}
+	for _, field := range fields {
+		field.AddTo(enc)
+	}
+	entry.Fields = enc.Fields
+
+	var errs []error
+	for _, hook := range c.hooks {
+		errs = append(errs, hook(entry))
+	}
+	return errors.Join(errs...)
+}
+
+// Sync implements zapcore.Core
+func (c *hookCore) Sync() error {
+	return nil
+}
@@ -0,0 +1,99 @@
+package xlogger
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+// TestHook tests the entry hooks
+func TestHook(t *testing.T) {
+	newHookedLogger := func(t *testing.T, opts ...Option) (*ZapLogger, func() []Entry) {
+		var (
+			mu      sync.Mutex
+			entries []Entry
+		)
+		hook := func(entry Entry) error {
+			mu.Lock()
+			defer mu.Unlock()
+			entries = append(entries, entry)
+			return nil
+		}
+		opts = append([]Option{WithOutputPaths(filepath.Join(t.TempDir(), "app.log")), WithHook(hook)}, opts...)
+		logger, err := NewZapLogger(NewLoggerConfig(opts...))
+		require.NoError(t, err)
+		return logger, func() []Entry {
+			mu.Lock()
+			defer mu.Unlock()
+			return append([]Entry(nil), entries...)
+		}
+	}
+
+	t.Run("should expose level, message, time and fields", func(t *testing.T) {
+		skipWithoutGLS(t)
+		logger, entries := newHookedLogger(t)
+
+		RunWithTraceVoid("req-1", "corr-1", func() {
+			logger.With(String("service", "billing")).Info("charged", Int("amount", 42))
+		})
+
+		got := entries()
+		require.Len(t, got, 1)
+		assert.Equal(t, zapcore.InfoLevel, got[0].Level)
+		assert.Equal(t, "charged", got[0].Message)
+		assert.False(t, got[0].Time.IsZero())
+		assert.Contains(t, got[0].Caller, "hook_test.go")
+		assert.Equal(t, "billing", got[0].Fields["service"])
+		assert.EqualValues(t, 42, got[0].Fields["amount"])
+		assert.Equal(t, "req-1", got[0].Fields["request_id"])
+	})
+
+	t.Run("should skip entries below the level", func(t *testing.T) {
+		logger, entries := newHookedLogger(t, WithLevel(zapcore.WarnLevel))
+
+		logger.Info("ignored")
+		logger.Warn("kept")
+
+		got := entries()
+		require.Len(t, got, 1)
+		assert.Equal(t, "kept", got[0].Message)
+	})
+
+	t.Run("should receive infrastructure entries", func(t *testing.T) {
+		logger, entries := newHookedLogger(t)
+
+		logger.ForInfra("db").Info("connected")
+
+		got := entries()
+		require.Len(t, got, 1)
+		assert.Equal(t, "db", got[0].Fields["component"])
+	})
+
+	t.Run("should write hook errors to the error outputs", func(t *testing.T) {
+		dir := t.TempDir()
+		errPath := filepath.Join(dir, "errors.log")
+		logger, err := NewZapLogger(NewLoggerConfig(
+			WithOutputPaths(filepath.Join(dir, "app.log")),
+			WithErrorOutputPaths(errPath),
+			WithHook(func(Entry) error { return errors.New("alert endpoint down") }),
+		))
+		require.NoError(t, err)
+
+		logger.Info("hello")
+
+		data, err := os.ReadFile(errPath)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), "alert endpoint down")
+	})
+
+	t.Run("should ignore nil hooks", func(t *testing.T) {
+		cfg := NewLoggerConfig(WithHook(nil), WithHook(func(Entry) error { return nil }))
+		assert.Len(t, cfg.Hooks, 1)
+	})
+}
@@ -0,0 +1,53 @@
+package xlogger
+
+import (
+	"os"
+	"runtime"
+	"runtime/debug"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// Keys of the fields added by WithHostFields
+const (
+	HostnameKey   = "hostname"
+	PIDKey        = "pid"
+	GoVersionKey  = "go_version"
+	AppVersionKey = "app_version"
+	CommitKey     = "commit"
+)
+
+// hostFields returns the host and build fields of the process, read once.
+// Values that cannot be read, such as the commit of a binary built outside
+// version control, are left out.
+var hostFields = sync.OnceValue(func() []zap.Field {
+	var fields []zap.Field
+	if hostname, err := os.Hostname(); err == nil && hostname != "" {
+		fields = append(fields, zap.String(HostnameKey, hostname))
+	}
+	fields = append(fields,
+		zap.Int(PIDKey, os.Getpid()),
+		zap.String(GoVersionKey, runtime.Version()),
+	)
+	return append(fields, buildFields()...)
+})
+
+// buildFields returns the main module version and VCS revision embedded by
+// the go command
+func buildFields() []zap.Field {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return nil
+	}
+	var fields []zap.Field
+	if version := info.Main.Version; version != "" && version != "(devel)" {
+		fields = append(fields, zap.String(AppVersionKey, version))
+	}
+	for _, setting := range info.Settings {
+		if setting.Key == "vcs.revision" && setting.Value != "" {
+			fields = append(fields, zap.String(CommitKey, setting.Value))
+		}
+	}
+	return fields
+}
@@ -0,0 +1,47 @@
+package xlogger
+
+import (
+	"os"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestHostFields tests the host and static fields of every entry
+func TestHostFields(t *testing.T) {
+	t.Run("should add host fields to every entry", func(t *testing.T) {
+		logger, output := newFileLogger(t, WithHostFields())
+
+		logger.Info("started")
+		logger.ForInfra("db").Info("connected")
+		require.NoError(t, logger.Sync())
+
+		entries := decodeJSONLines(t, output())
+		require.Len(t, entries, 2)
+		hostname, err := os.Hostname()
+		require.NoError(t, err)
+		for _, entry := range entries {
+			assert.Equal(t, hostname, entry[HostnameKey])
+			assert.Equal(t, float64(os.Getpid()), entry[PIDKey])
+			assert.Equal(t, runtime.Version(), entry[GoVersionKey])
+		}
+	})
+
+	t.Run("should add static fields to every entry", func(t *testing.T) {
+		logger, output := newFileLogger(t, WithStaticFields(String("region", "ap-southeast-1")), WithStaticFields(String("cluster", "blue")))
+
+		logger.Info("started")
+		logger.ForInfra("db").Info("connected")
+		require.NoError(t, logger.Sync())
+
+		entries := decodeJSONLines(t, output())
+		require.Len(t, entries, 2)
+		for _, entry := range entries {
+			assert.Equal(t, "ap-southeast-1", entry["region"])
+			assert.Equal(t, "blue", entry["cluster"])
+			assert.NotContains(t, entry, HostnameKey)
+		}
+	})
+}
@@ -0,0 +1,83 @@
+package xlogger
+
+import (
+	"net/http"
+	"net/http/httptrace"
+	"sync/atomic"
+	"time"
+)
+
+// httpClientComponent is the infrastructure component used by outbound request logging
+const httpClientComponent = "httpclient"
+
+// NewRoundTripper wraps next (http.DefaultTransport when nil) so every
+// outbound request is logged under the "httpclient" component with method,
+// url, status, latency and retries, at Error for transport failures and 5xx
+// responses and at Info otherwise. Entries carry the trace fields of the
+// request context, as with Logger.WithContext.
+//
+// The request and correlation IDs, traceparent and tracestate of the
+// request context (see ContextWithTraceContext), or of the goroutine-local
+// trace of the TraceScope of logger when the context carries none, are
+// forwarded as X-Request-ID, X-Correlation-ID and W3C headers, or under the
+// names set with WithTraceHeaderNames. Headers already set on the request
+// are kept.
+//
+// Example:
+//
+//	client := &http.Client{Transport: xlogger.NewRoundTripper(logger, nil)}
+func NewRoundTripper(logger Logger, next http.RoundTripper, opts ...HTTPOption) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &loggingRoundTripper{
+		next:    next,
+		logger:  logger.ForInfra(httpClientComponent),
+		scope:   traceScopeOf(logger),
+		headers: newHTTPConfig(opts).headers,
+	}
+}
+
+// loggingRoundTripper logs outbound requests and forwards trace headers
+type loggingRoundTripper struct {
+	next    http.RoundTripper
+	logger  Logger
+	scope   *TraceScope
+	headers TraceHeaderNames
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *loggingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+
+	// Connection attempts beyond the first are retries by http.Transport
+	var attempts atomic.Int64
+	ctx := httptrace.WithClientTrace(req.Context(), &httptrace.ClientTrace{
+		GetConn: func(string) { attempts.Add(1) },
+	})
+	out := req.Clone(ctx)
+	t.headers.Inject(out.Header, t.scope.outgoingTrace(req.Context()))
+
+	resp, err := t.next.RoundTrip(out)
+
+	retries := attempts.Load() - 1
+	if retries < 0 {
+		retries = 0
+	}
+	fields := []Field{
+		String("method", req.Method),
+		String("url", req.URL.Redacted()),
+		Duration("latency", time.Since(start)),
+		Int64("retries", retries),
+	}
+	logger := t.logger.WithContext(req.Context())
+	switch {
+	case err != nil:
+		logger.Error("http client request failed", append(fields, Error(err))...)
+	case resp.StatusCode >= http.StatusInternalServerError:
+		logger.Error("http client request completed", append(fields, Int("status", resp.StatusCode))...)
+	default:
+		logger.Info("http client request completed", append(fields, Int("status", resp.StatusCode))...)
+	}
+	return resp, err
+}
@@ -0,0 +1,168 @@
+package xlogger
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// IDGenerator returns a new unique identifier for NewRequestID and
+// NewCorrelationID.
+type IDGenerator func() string
+
+// Names of the built-in generators in config files
+const (
+	idGeneratorHex    = "hex"
+	idGeneratorUUIDv7 = "uuidv7"
+	idGeneratorULID   = "ulid"
+)
+
+// idGenerator is the generator installed by SetIDGenerator, nil for NewHexID
+var idGenerator atomic.Pointer[IDGenerator]
+
+// crockford is the Crockford base32 alphabet of ULIDs
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// SetIDGenerator installs the process-wide generator of NewRequestID and
+// NewCorrelationID; nil restores NewHexID. Call it at startup. Loggers
+// created with WithIDGenerator use their own generator instead.
+//
+// Example:
+//
+//	xlogger.SetIDGenerator(xlogger.NewULID)
+func SetIDGenerator(gen IDGenerator) {
+	if gen == nil {
+		idGenerator.Store(nil)
+		return
+	}
+	idGenerator.Store(&gen)
+}
+
+// NewRequestID returns a new request identifier of the process-wide
+// generator: random 128-bit hex unless SetIDGenerator installed another.
+func NewRequestID() string {
+	return newID()
+}
+
+// NewCorrelationID returns a new correlation identifier, generated like
+// NewRequestID.
+func NewCorrelationID() string {
+	return newID()
+}
+
+// newID returns an identifier of the installed generator
+func newID() string {
+	if gen := idGenerator.Load(); gen != nil {
+		return (*gen)()
+	}
+	return NewHexID()
+}
+
+// NewRequestID returns a new request identifier of the generator of the
+// logger, as generated by HTTPMiddleware for requests without an
+// X-Request-ID header, or of the process-wide generator without
+// WithIDGenerator.
+func (l *ZapLogger) NewRequestID() string {
+	if l.idGenerator != nil {
+		return l.idGenerator()
+	}
+	return newID()
+}
+
+// newRequestID returns a new request identifier of the generator of logger
+func newRequestID(logger Logger) string {
+	if gen, ok := logger.(interface{ NewRequestID() string }); ok {
+		return gen.NewRequestID()
+	}
+	return newID()
+}
+
+// NewHexID returns a random 128-bit identifier in hex, the default
+// IDGenerator.
+func NewHexID() string {
+	var id [16]byte
+	_, _ = rand.Read(id[:])
+	return hex.EncodeToString(id[:])
+}
+
+// NewUUIDv7 returns a version 7 UUID: a millisecond timestamp followed by
+// random bits, so identifiers sort by creation time.
+func NewUUIDv7() string {
+	var id [16]byte
+	_, _ = rand.Read(id[6:])
+	putMillis(id[:6], time.Now())
+	id[6] = id[6]&0x0f | 0x70 // version 7
+	id[8] = id[8]&0x3f | 0x80 // RFC 9562 variant
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", id[0:4], id[4:6], id[6:8], id[8:10], id[10:16])
+}
+
+// NewULID returns a ULID: a millisecond timestamp followed by 80 random
+// bits in 26 characters of Crockford base32, sorting by creation time.
+func NewULID() string {
+	var id [16]byte
+	_, _ = rand.Read(id[6:])
+	putMillis(id[:6], time.Now())
+
+	hi, lo := binary.BigEndian.Uint64(id[:8]), binary.BigEndian.Uint64(id[8:])
+	var text [26]byte
+	for i := len(text) - 1; i >= 0; i-- {
+		text[i] = crockford[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(text[:])
+}
+
+// putMillis writes the Unix milliseconds of t as 48 big-endian bits
+func putMillis(b []byte, t time.Time) {
+	ms := uint64(t.UnixMilli())
+	for i := 5; i >= 0; i-- {
+		b[i] = byte(ms)
+		ms >>= 8
+	}
+}
+
+// parseIDGenerator returns the built-in generator named name
+func parseIDGenerator(name string) (IDGenerator, error) {
+	switch strings.ToLower(name) {
+	case idGeneratorHex:
+		return NewHexID, nil
+	case idGeneratorUUIDv7:
+		return NewUUIDv7, nil
+	case idGeneratorULID:
+		return NewULID, nil
+	default:
+		return nil, fmt.Errorf("unknown id generator %q", name)
+	}
+}
+
+// RunWithNewTrace executes fn like RunWithTrace with a new request ID, for
+// work no upstream request ID reaches, such as scheduled jobs and queue
+// consumers. The correlation ID of an enclosing run is kept; outside one a
+// new correlation ID is generated.
+//
+// Example:
+//
+//	for range ticker.C {
+//	    _ = xlogger.RunWithNewTrace(func() error {
+//	        logger.Info("Expiring carts") // includes request_id and correlation_id
+//	        return expireCarts(ctx)
+//	    })
+//	}
+func RunWithNewTrace(fn func() error) error {
+	return defaultTraceScope.RunWithNewTrace(fn)
+}
+
+// RunWithNewTrace is the scoped form of the package-level RunWithNewTrace.
+func (s *TraceScope) RunWithNewTrace(fn func() error) error {
+	correlationID := s.TraceCorrelationID()
+	if correlationID == "" {
+		correlationID = NewCorrelationID()
+	}
+	return s.RunWithTrace(NewRequestID(), correlationID, fn)
+}
@@ -0,0 +1,98 @@
+package xlogger
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestIDGenerators tests the built-in identifier generators
+func TestIDGenerators(t *testing.T) {
+	t.Run("should generate random hex identifiers", func(t *testing.T) {
+		assert.Regexp(t, `^[0-9a-f]{32}$`, NewHexID())
+		assert.NotEqual(t, NewHexID(), NewHexID())
+	})
+
+	t.Run("should generate version 7 UUIDs", func(t *testing.T) {
+		before := time.Now().UnixMilli()
+		id := NewUUIDv7()
+
+		assert.Regexp(t, `^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, id)
+		ms, err := strconv.ParseInt(id[:8]+id[9:13], 16, 64)
+		require.NoError(t, err)
+		assert.InDelta(t, before, ms, 1000)
+	})
+
+	t.Run("should generate ULIDs sorting by time", func(t *testing.T) {
+		var ids []string
+		for range 3 {
+			ids = append(ids, NewULID())
+			time.Sleep(2 * time.Millisecond)
+		}
+
+		for _, id := range ids {
+			assert.Regexp(t, regexp.MustCompile(`^[0-7][0-9A-HJKMNP-TV-Z]{25}$`), id)
+		}
+		assert.True(t, sort.StringsAreSorted(ids))
+	})
+}
+
+// TestWithIDGenerator tests the generators of loggers and of the process
+func TestWithIDGenerator(t *testing.T) {
+	t.Run("should keep the generator on the logger", func(t *testing.T) {
+		uuidLogger, err := NewZapLogger(NewLoggerConfig(WithOutputPaths(t.TempDir()+"/uuid.log"), WithIDGenerator(NewUUIDv7)))
+		require.NoError(t, err)
+		defer func() { _ = uuidLogger.Close(t.Context()) }()
+		ulidLogger, err := NewZapLogger(NewLoggerConfig(WithOutputPaths(t.TempDir()+"/ulid.log"), WithIDGenerator(NewULID)))
+		require.NoError(t, err)
+		defer func() { _ = ulidLogger.Close(t.Context()) }()
+
+		assert.Regexp(t, `^[0-9a-f-]{36}$`, uuidLogger.NewRequestID())
+		assert.Regexp(t, `^[0-9a-f-]{36}$`, newRequestID(uuidLogger.ForInfra("http")))
+		assert.Len(t, ulidLogger.NewRequestID(), 26)
+		assert.Len(t, NewRequestID(), 32)
+	})
+
+	t.Run("should install the process-wide generator explicitly", func(t *testing.T) {
+		t.Cleanup(func() { SetIDGenerator(nil) })
+		logger, _ := newFileLogger(t)
+
+		SetIDGenerator(NewUUIDv7)
+		assert.Regexp(t, `^[0-9a-f-]{36}$`, NewRequestID())
+		assert.Regexp(t, `^[0-9a-f-]{36}$`, NewCorrelationID())
+		assert.Regexp(t, `^[0-9a-f-]{36}$`, logger.NewRequestID())
+
+		SetIDGenerator(nil)
+		assert.Len(t, NewRequestID(), 32)
+	})
+}
+
+// TestRunWithNewTrace tests runs with generated identifiers
+func TestRunWithNewTrace(t *testing.T) {
+	t.Run("should generate request and correlation IDs", func(t *testing.T) {
+		skipWithoutGLS(t)
+		err := RunWithNewTrace(func() error {
+			assert.Len(t, TraceRequestID(), 32)
+			assert.Len(t, TraceCorrelationID(), 32)
+			assert.NotEqual(t, TraceRequestID(), TraceCorrelationID())
+			return nil
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("should keep the correlation ID of the enclosing run", func(t *testing.T) {
+		skipWithoutGLS(t)
+		RunWithTraceVoid("req-1", "corr-1", func() {
+			_ = RunWithNewTrace(func() error {
+				assert.NotEqual(t, "req-1", TraceRequestID())
+				assert.Equal(t, "corr-1", TraceCorrelationID())
+				return nil
+			})
+		})
+	})
+}
@@ -0,0 +1,181 @@
+package xlogger
+
+import (
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// kafkaClientKey is the key of the Kafka client library of KafkaLogger
+// entries
+const kafkaClientKey = "kafka_client"
+
+// Levels of franz-go's kgo.LogLevel, taken and returned by KafkaLogger.Log
+// and KafkaLogger.Level
+const (
+	KafkaLogLevelNone int8 = iota
+	KafkaLogLevelError
+	KafkaLogLevelWarn
+	KafkaLogLevelInfo
+	KafkaLogLevelDebug
+)
+
+// kafkaErrorWords mark Print messages of clients without levels, such as
+// sarama, that report failures
+var kafkaErrorWords = []string{"error", "failed", "failure", "unable to"}
+
+// KafkaLogger logs the diagnostics of Kafka clients. It implements
+// sarama.StdLogger (Print, Printf and Println) and the Logger of
+// segmentio/kafka-go (Printf), and provides the Log and Level methods of
+// franz-go's kgo.Logger with int8 levels, which xloggerkgo adapts to
+// kgo.Logger. Entries carry a "kafka_client"
+// field naming the client library.
+type KafkaLogger struct {
+	logger Logger
+	level  zapcore.Level
+}
+
+// NewKafkaLogger creates a Kafka client logger whose entries carry client
+// as kafka_client. Print messages are logged at Info, and at Warn when
+// they report a failure. Pass logger.ForInfra("kafka") to control the
+// level of every client with SetComponentLevel("kafka", level).
+//
+// Example:
+//
+//	kafkaLogger := xlogger.NewKafkaLogger(logger.ForInfra("kafka"), "sarama")
+//	sarama.Logger = kafkaLogger
+//	sarama.DebugLogger = kafkaLogger.AtLevel(zapcore.DebugLevel)
+func NewKafkaLogger(logger Logger, client string) *KafkaLogger {
+	return &KafkaLogger{
+		// Skips write and the Print or Log method
+		logger: skipCaller(logger.With(String(kafkaClientKey, client)), 2),
+		level:  zapcore.InfoLevel,
+	}
+}
+
+// AtLevel returns a KafkaLogger logging Print messages at level, such as
+// the sarama.DebugLogger at Debug or the ErrorLogger of a kafka-go Writer
+// at Error. Messages reporting a failure are still raised to Warn.
+//
+// Example:
+//
+//	writer := &kafka.Writer{
+//	    Addr:        kafka.TCP("localhost:9092"),
+//	    Logger:      kafkaLogger.AtLevel(zapcore.DebugLevel),
+//	    ErrorLogger: kafkaLogger.AtLevel(zapcore.ErrorLevel),
+//	}
+func (k *KafkaLogger) AtLevel(level zapcore.Level) *KafkaLogger {
+	return &KafkaLogger{logger: k.logger, level: level}
+}
+
+// AddCallerSkip returns a KafkaLogger skipping skip more caller frames, for
+// adapters such as xloggerkgo that wrap its methods, so entries keep the
+// caller in the client library.
+func (k *KafkaLogger) AddCallerSkip(skip int) *KafkaLogger {
+	return &KafkaLogger{logger: skipCaller(k.logger, skip), level: k.level}
+}
+
+// Print implements sarama.StdLogger
+func (k *KafkaLogger) Print(v ...interface{}) {
+	if k.enabled() {
+		k.write(k.printLevel(fmt.Sprint(v...)))
+	}
+}
+
+// Printf implements sarama.StdLogger and kafka-go's Logger
+func (k *KafkaLogger) Printf(format string, v ...interface{}) {
+	if k.enabled() {
+		k.write(k.printLevel(fmt.Sprintf(format, v...)))
+	}
+}
+
+// Println implements sarama.StdLogger
+func (k *KafkaLogger) Println(v ...interface{}) {
+	if k.enabled() {
+		k.write(k.printLevel(fmt.Sprintln(v...)))
+	}
+}
+
+// Log logs msg with the key/value pairs of franz-go at a KafkaLogLevel.
+// KafkaLogLevelNone and unknown levels are dropped. xloggerkgo implements
+// kgo.Logger with it.
+func (k *KafkaLogger) Log(level int8, msg string, keyvals ...interface{}) {
+	zapLevel, ok := kafkaToZapLevel(level)
+	if !ok || !k.logger.Enabled(zapLevel) {
+		return
+	}
+	k.write(zapLevel, msg, convertKeysAndValues(keyvals)...)
+}
+
+// Level returns the KafkaLogLevel of the most verbose entries the logger
+// writes, so franz-go skips building the others.
+func (k *KafkaLogger) Level() int8 {
+	for _, level := range []int8{KafkaLogLevelDebug, KafkaLogLevelInfo, KafkaLogLevelWarn, KafkaLogLevelError} {
+		if zapLevel, _ := kafkaToZapLevel(level); k.logger.Enabled(zapLevel) {
+			return level
+		}
+	}
+	return KafkaLogLevelNone
+}
+
+// enabled reports whether Print messages may be written, at the level of
+// the KafkaLogger or raised to Warn, so disabled calls skip formatting
+func (k *KafkaLogger) enabled() bool {
+	return k.logger.Enabled(k.level) || k.logger.Enabled(zapcore.WarnLevel)
+}
+
+// printLevel returns the level and trimmed text of a Print message,
+// raising failures to Warn
+func (k *KafkaLogger) printLevel(msg string) (zapcore.Level, string) {
+	msg = strings.TrimSpace(msg)
+	if k.level < zapcore.WarnLevel && isKafkaFailure(msg) {
+		return zapcore.WarnLevel, msg
+	}
+	return k.level, msg
+}
+
+// write logs msg at level, two frames below the client. Empty messages are
+// dropped.
+func (k *KafkaLogger) write(level zapcore.Level, msg string, fields ...Field) {
+	if msg == "" {
+		return
+	}
+	switch level {
+	case zapcore.DebugLevel:
+		k.logger.Debug(msg, fields...)
+	case zapcore.InfoLevel:
+		k.logger.Info(msg, fields...)
+	case zapcore.WarnLevel:
+		k.logger.Warn(msg, fields...)
+	default:
+		k.logger.Error(msg, fields...)
+	}
+}
+
+// kafkaToZapLevel maps a KafkaLogLevel to a zap level, false for
+// KafkaLogLevelNone and unknown levels
+func kafkaToZapLevel(level int8) (zapcore.Level, bool) {
+	switch level {
+	case KafkaLogLevelError:
+		return zapcore.ErrorLevel, true
+	case KafkaLogLevelWarn:
+		return zapcore.WarnLevel, true
+	case KafkaLogLevelInfo:
+		return zapcore.InfoLevel, true
+	case KafkaLogLevelDebug:
+		return zapcore.DebugLevel, true
+	}
+	return zapcore.InvalidLevel, false
+}
+
+// isKafkaFailure reports whether a message without level reports a failure
+func isKafkaFailure(msg string) bool {
+	msg = strings.ToLower(msg)
+	for _, word := range kafkaErrorWords {
+		if strings.Contains(msg, word) {
+			return true
+		}
+	}
+	return false
+}
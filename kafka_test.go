@@ -0,0 +1,98 @@
+package xlogger
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+// TestKafkaLogger tests the Kafka client adapters
+func TestKafkaLogger(t *testing.T) {
+	t.Run("should log print messages with the client and component", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+		kafkaLogger := NewKafkaLogger(logger.ForInfra("kafka"), "sarama")
+
+		kafkaLogger.Printf("client/metadata fetching metadata for %s from broker %s\n", "orders", "b1:9092")
+		kafkaLogger.Println("consumer/broker/1 disconnecting")
+		kafkaLogger.Print("client/metadata got error from broker 1 while fetching metadata: ", errors.New("EOF"))
+
+		log := output()
+		fetching := entriesWithMessage(t, log, "client/metadata fetching metadata for orders from broker b1:9092")
+		require.Len(t, fetching, 1)
+		assert.Equal(t, "info", fetching[0]["level"])
+		assert.Equal(t, "sarama", fetching[0]["kafka_client"])
+		assert.Equal(t, "kafka", fetching[0]["component"])
+		assert.Len(t, entriesWithMessage(t, log, "consumer/broker/1 disconnecting"), 1)
+		failure := entriesWithMessage(t, log, "client/metadata got error from broker 1 while fetching metadata: EOF")
+		require.Len(t, failure, 1)
+		assert.Equal(t, "warn", failure[0]["level"])
+	})
+
+	t.Run("should log at the level of AtLevel", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+		kafkaLogger := NewKafkaLogger(logger, "kafka-go")
+
+		kafkaLogger.AtLevel(zapcore.DebugLevel).Printf("writing %d messages", 3)
+		kafkaLogger.AtLevel(zapcore.DebugLevel).Printf("failed to write messages")
+		kafkaLogger.AtLevel(zapcore.ErrorLevel).Printf("broker unreachable")
+
+		log := output()
+		assert.NotContains(t, log, "writing 3 messages")
+		assert.Equal(t, "warn", entriesWithMessage(t, log, "failed to write messages")[0]["level"])
+		assert.Equal(t, "error", entriesWithMessage(t, log, "broker unreachable")[0]["level"])
+	})
+
+	t.Run("should map franz-go levels and key/value pairs", func(t *testing.T) {
+		logger, output := newFileLogger(t, WithLevel(zapcore.DebugLevel))
+		logger.SetLevel(zapcore.InfoLevel)
+		kafkaLogger := NewKafkaLogger(logger, "franz-go")
+
+		kafkaLogger.Log(KafkaLogLevelWarn, "unable to open connection to broker", "addr", "b1:9092", "err", errors.New("refused"))
+		kafkaLogger.Log(KafkaLogLevelDebug, "wrote Produce v9")
+		kafkaLogger.Log(KafkaLogLevelNone, "dropped")
+		kafkaLogger.Printf("printed by %s", "sarama")
+
+		log := output()
+		entries := entriesWithMessage(t, log, "unable to open connection to broker")
+		require.Len(t, entries, 1)
+		assert.Equal(t, "warn", entries[0]["level"])
+		assert.Equal(t, "b1:9092", entries[0]["addr"])
+		assert.Equal(t, "refused", entries[0]["err"])
+		assert.Contains(t, entries[0]["caller"], "kafka_test.go")
+		assert.Contains(t, entriesWithMessage(t, log, "printed by sarama")[0]["caller"], "kafka_test.go")
+		assert.NotContains(t, log, "wrote Produce v9")
+		assert.NotContains(t, log, "dropped")
+	})
+
+	t.Run("should skip the frames of wrapping adapters", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+		kafkaLogger := NewKafkaLogger(logger, "franz-go").AddCallerSkip(1)
+		wrapped := func(msg string) { kafkaLogger.Log(KafkaLogLevelInfo, msg) }
+
+		wrapped("metadata refreshed")
+		_, _, line, _ := runtime.Caller(0)
+
+		entries := entriesWithMessage(t, output(), "metadata refreshed")
+		require.Len(t, entries, 1)
+		assert.True(t, strings.HasSuffix(entries[0]["caller"].(string), fmt.Sprintf("kafka_test.go:%d", line-1)), entries[0]["caller"])
+	})
+
+	t.Run("should report the most verbose enabled level", func(t *testing.T) {
+		logger, _ := newFileLogger(t)
+		kafkaLogger := NewKafkaLogger(logger, "franz-go")
+
+		assert.Equal(t, KafkaLogLevelInfo, kafkaLogger.Level())
+		logger.SetLevel(zapcore.DebugLevel)
+		assert.Equal(t, KafkaLogLevelDebug, kafkaLogger.Level())
+		logger.SetLevel(zapcore.ErrorLevel)
+		assert.Equal(t, KafkaLogLevelError, kafkaLogger.Level())
+		logger.SetLevel(zapcore.FatalLevel)
+		assert.Equal(t, KafkaLogLevelNone, kafkaLogger.Level())
+	})
+}
@@ -0,0 +1,147 @@
+package xlogger
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// componentLeveler is implemented by loggers supporting per-component levels
+type componentLeveler interface {
+	ComponentLevel(component string) zapcore.Level
+	SetComponentLevel(component string, level zapcore.Level)
+	ResetComponentLevel(component string)
+}
+
+// levelPayload is the JSON body of LevelHandler requests and responses
+type levelPayload struct {
+	Level     *zapcore.Level `json:"level,omitempty"`
+	Component string         `json:"component,omitempty"`
+}
+
+// handlerErrorResponse is the JSON body of admin handler errors
+type handlerErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// levelHandler serves runtime level changes for a logger
+type levelHandler struct {
+	logger Logger
+}
+
+// LevelHandler returns an opt-in http.Handler following zap's /loglevel
+// convention: GET returns {"level":"info"} and PUT with {"level":"debug"}
+// (or a level form value) changes it. A component (query parameter or JSON
+// field) targets the level of ForInfra(component) only; DELETE with a
+// component removes its override.
+//
+// Example:
+//
+//	mux.Handle("/loglevel", xlogger.LevelHandler(logger))
+//
+//	curl -X PUT localhost:8080/loglevel -d '{"level":"debug","component":"gorm"}'
+func LevelHandler(logger Logger) http.Handler {
+	return &levelHandler{logger: logger}
+}
+
+// ServeHTTP implements http.Handler
+func (h *levelHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.writeLevel(w, r.URL.Query().Get("component"))
+	case http.MethodPut:
+		h.putLevel(w, r)
+	case http.MethodDelete:
+		h.deleteLevel(w, r)
+	default:
+		w.Header().Set("Allow", "GET, PUT, DELETE")
+		writeHandlerError(w, http.StatusMethodNotAllowed, "only GET, PUT and DELETE are supported")
+	}
+}
+
+func (h *levelHandler) putLevel(w http.ResponseWriter, r *http.Request) {
+	payload, err := decodeLevelPayload(r)
+	if err != nil {
+		writeHandlerError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if payload.Level == nil {
+		writeHandlerError(w, http.StatusBadRequest, "must specify a logging level")
+		return
+	}
+
+	if payload.Component == "" {
+		h.logger.SetLevel(*payload.Level)
+	} else {
+		leveler, ok := h.logger.(componentLeveler)
+		if !ok {
+			writeHandlerError(w, http.StatusBadRequest, "logger does not support component levels")
+			return
+		}
+		leveler.SetComponentLevel(payload.Component, *payload.Level)
+	}
+	h.writeLevel(w, payload.Component)
+}
+
+func (h *levelHandler) deleteLevel(w http.ResponseWriter, r *http.Request) {
+	component := r.URL.Query().Get("component")
+	if component == "" {
+		writeHandlerError(w, http.StatusBadRequest, "must specify a component")
+		return
+	}
+	leveler, ok := h.logger.(componentLeveler)
+	if !ok {
+		writeHandlerError(w, http.StatusBadRequest, "logger does not support component levels")
+		return
+	}
+	leveler.ResetComponentLevel(component)
+	h.writeLevel(w, component)
+}
+
+// writeLevel responds with the effective level of the logger or component
+func (h *levelHandler) writeLevel(w http.ResponseWriter, component string) {
+	level := h.logger.Level()
+	if leveler, ok := h.logger.(componentLeveler); ok && component != "" {
+		level = leveler.ComponentLevel(component)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(levelPayload{Level: &level, Component: component})
+}
+
+// decodeLevelPayload reads a JSON body or form values; the component query
+// parameter applies when the body does not name one
+func decodeLevelPayload(r *http.Request) (levelPayload, error) {
+	var payload levelPayload
+
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/x-www-form-urlencoded") {
+		if err := r.ParseForm(); err != nil {
+			return payload, fmt.Errorf("invalid form: %w", err)
+		}
+		if value := r.Form.Get("level"); value != "" {
+			var level zapcore.Level
+			if err := level.UnmarshalText([]byte(value)); err != nil {
+				return payload, err
+			}
+			payload.Level = &level
+		}
+		payload.Component = r.Form.Get("component")
+	} else if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		return payload, fmt.Errorf("request body must be well-formed JSON: %w", err)
+	}
+
+	if payload.Component == "" {
+		payload.Component = r.URL.Query().Get("component")
+	}
+	return payload, nil
+}
+
+// writeHandlerError responds with a JSON error
+func writeHandlerError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(handlerErrorResponse{Error: message})
+}
@@ -0,0 +1,120 @@
+package xlogger
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+// TestLevelHandler tests the runtime level HTTP handler
+func TestLevelHandler(t *testing.T) {
+	newHandler := func(t *testing.T) (*ZapLogger, http.Handler) {
+		logger, err := NewZapLogger(NewLoggerConfig(WithOutputPaths(t.TempDir() + "/app.log")))
+		require.NoError(t, err)
+		return logger, LevelHandler(logger)
+	}
+
+	serve := func(handler http.Handler, req *http.Request) (*httptest.ResponseRecorder, map[string]string) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		body := map[string]string{}
+		_ = json.Unmarshal(rec.Body.Bytes(), &body)
+		return rec, body
+	}
+
+	t.Run("should return current level", func(t *testing.T) {
+		_, handler := newHandler(t)
+
+		rec, body := serve(handler, httptest.NewRequest(http.MethodGet, "/loglevel", nil))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+		assert.Equal(t, map[string]string{"level": "info"}, body)
+	})
+
+	t.Run("should change level with JSON body", func(t *testing.T) {
+		logger, handler := newHandler(t)
+
+		rec, body := serve(handler, httptest.NewRequest(http.MethodPut, "/loglevel", strings.NewReader(`{"level":"debug"}`)))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "debug", body["level"])
+		assert.Equal(t, zapcore.DebugLevel, logger.Level())
+	})
+
+	t.Run("should change level with form value", func(t *testing.T) {
+		logger, handler := newHandler(t)
+		req := httptest.NewRequest(http.MethodPut, "/loglevel", strings.NewReader(url.Values{"level": {"warn"}}.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		rec, body := serve(handler, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "warn", body["level"])
+		assert.Equal(t, zapcore.WarnLevel, logger.Level())
+	})
+
+	t.Run("should target a component", func(t *testing.T) {
+		logger, handler := newHandler(t)
+
+		rec, body := serve(handler, httptest.NewRequest(http.MethodPut, "/loglevel?component=gorm", strings.NewReader(`{"level":"error"}`)))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, map[string]string{"level": "error", "component": "gorm"}, body)
+		assert.Equal(t, zapcore.InfoLevel, logger.Level())
+		assert.Equal(t, zapcore.ErrorLevel, logger.ComponentLevel("gorm"))
+
+		_, body = serve(handler, httptest.NewRequest(http.MethodGet, "/loglevel?component=gorm", nil))
+		assert.Equal(t, "error", body["level"])
+	})
+
+	t.Run("should reset a component", func(t *testing.T) {
+		logger, handler := newHandler(t)
+		logger.SetComponentLevel("db", zapcore.DebugLevel)
+
+		rec, body := serve(handler, httptest.NewRequest(http.MethodDelete, "/loglevel?component=db", nil))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "info", body["level"])
+		assert.Empty(t, logger.ComponentLevels())
+	})
+
+	t.Run("should reject bad requests", func(t *testing.T) {
+		_, handler := newHandler(t)
+
+		tests := []struct {
+			name   string
+			method string
+			target string
+			body   string
+		}{
+			{"malformed JSON", http.MethodPut, "/loglevel", `{"level":`},
+			{"unknown level", http.MethodPut, "/loglevel", `{"level":"verbose"}`},
+			{"missing level", http.MethodPut, "/loglevel", `{}`},
+			{"delete without component", http.MethodDelete, "/loglevel", ""},
+		}
+
+		for _, tt := range tests {
+			rec, body := serve(handler, httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body)))
+			assert.Equal(t, http.StatusBadRequest, rec.Code, tt.name)
+			assert.NotEmpty(t, body["error"], tt.name)
+		}
+	})
+
+	t.Run("should reject other methods", func(t *testing.T) {
+		_, handler := newHandler(t)
+
+		rec, body := serve(handler, httptest.NewRequest(http.MethodPost, "/loglevel", nil))
+
+		assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+		assert.Equal(t, "GET, PUT, DELETE", rec.Header().Get("Allow"))
+		assert.NotEmpty(t, body["error"])
+	})
+}
@@ -1,9 +1,19 @@
 package xlogger
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
 	"time"
 
+	"github.com/go-logr/logr"
 	"go.uber.org/fx/fxevent"
+	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
@@ -16,23 +26,43 @@ type Logger interface {
 	Warn(msg string, fields ...Field)
 	Error(msg string, fields ...Field)
 
+	// Lazy variants that call fieldsFn only when the entry will be written
+	DebugFn(msg string, fieldsFn func() []Field)
+	InfoFn(msg string, fieldsFn func() []Field)
+	WarnFn(msg string, fieldsFn func() []Field)
+	ErrorFn(msg string, fieldsFn func() []Field)
+
+	// Level guards that skip building fields of entries that are not written
+	Enabled(level zapcore.Level) bool
+	Check(level zapcore.Level, msg string) *CheckedEntry
+
+	// DPanic logs at DPanic level and panics in development mode only
+	DPanic(msg string, fields ...Field)
+
 	// These methods will terminate the application after logging
 	Panic(msg string, fields ...Field)
 	Fatal(msg string, fields ...Field)
 
 	// Logger enhancement methods
 	With(fields ...Field) Logger
+	WithContext(ctx context.Context) Logger
+	WithTags(tags ...string) Logger
+	Named(name string) Logger
 
 	// Infrastructure optimization methods
 	ForInfra(component string) Logger
 	ForFxEvent() fxevent.Logger
 	ForGORM() *GORMLogger
+	ForGORMNamed(name string) *GORMLogger
+	ForLogr() logr.LogSink
 
 	// Logger configuration methods
 	Level() zapcore.Level
+	SetLevel(level zapcore.Level)
 
 	// Utility methods
 	Sync() error
+	Close(ctx context.Context) error
 }
 
 // Field represents a structured log field with key-value pairs
@@ -55,13 +85,30 @@ const (
 	DurationType
 	TimeType
 	AnyType
+	TagsType
+	ZapType
+	NamespaceType
 )
 
+// tagsFieldKey is the key of the tags array
+const tagsFieldKey = "tags"
+
 // String creates a string field
 func String(key, value string) Field {
 	return Field{key: key, value: value, typ: StringType}
 }
 
+// Stringp creates a string field from a pointer, logged as null when nil
+func Stringp(key string, value *string) Field {
+	return Field{key: key, value: value, typ: StringType}
+}
+
+// ByteString creates a string field from UTF-8 bytes, such as a payload,
+// without converting them to a string first
+func ByteString(key string, value []byte) Field {
+	return Field{key: key, value: value, typ: StringType}
+}
+
 // Int creates an integer field
 func Int(key string, value int) Field {
 	return Field{key: key, value: value, typ: IntType}
@@ -72,11 +119,36 @@ func Int64(key string, value int64) Field {
 	return Field{key: key, value: value, typ: IntType}
 }
 
+// Int32 creates an int32 field
+func Int32(key string, value int32) Field {
+	return Field{key: key, value: value, typ: IntType}
+}
+
+// Uint creates a uint field
+func Uint(key string, value uint) Field {
+	return Field{key: key, value: value, typ: IntType}
+}
+
+// Uint64 creates a uint64 field
+func Uint64(key string, value uint64) Field {
+	return Field{key: key, value: value, typ: IntType}
+}
+
+// Intp creates an int field from a pointer, logged as null when nil
+func Intp(key string, value *int) Field {
+	return Field{key: key, value: value, typ: IntType}
+}
+
 // Float64 creates a float64 field
 func Float64(key string, value float64) Field {
 	return Field{key: key, value: value, typ: Float64Type}
 }
 
+// Float32 creates a float32 field
+func Float32(key string, value float32) Field {
+	return Field{key: key, value: value, typ: Float64Type}
+}
+
 // Bool creates a boolean field
 func Bool(key string, value bool) Field {
 	return Field{key: key, value: value, typ: BoolType}
@@ -102,11 +174,394 @@ func Time(key string, value time.Time) Field {
 	return Field{key: key, value: value, typ: TimeType}
 }
 
+// Strings creates a field of a string slice
+func Strings(key string, values []string) Field {
+	return Field{key: key, value: values, typ: AnyType}
+}
+
+// Ints creates a field of an int slice
+func Ints(key string, values []int) Field {
+	return Field{key: key, value: values, typ: AnyType}
+}
+
+// Durations creates a field of a time.Duration slice
+func Durations(key string, values []time.Duration) Field {
+	return Field{key: key, value: values, typ: AnyType}
+}
+
 // Any creates a field for any type (use sparingly for performance)
 func Any(key string, value interface{}) Field {
 	return Field{key: key, value: value, typ: AnyType}
 }
 
+// Object creates a field encoding value as a nested object with its
+// MarshalLogObject method, without the reflection of Any.
+//
+// Example:
+//
+//	func (u User) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+//	    enc.AddString("id", u.ID)
+//	    enc.AddInt("age", u.Age)
+//	    return nil
+//	}
+//	logger.Info("user loaded", xlogger.Object("user", user))
+func Object(key string, value zapcore.ObjectMarshaler) Field {
+	return ZapField(zap.Object(key, value))
+}
+
+// Array creates a field encoding value as an array with its
+// MarshalLogArray method, without the reflection of Any.
+//
+// Example:
+//
+//	logger.Info("batch loaded", xlogger.Array("users", users))
+func Array(key string, value zapcore.ArrayMarshaler) Field {
+	return ZapField(zap.Array(key, value))
+}
+
+// FieldMarshaler is implemented by types that log themselves as nested
+// fields built with this package's constructors, without importing zap.
+// Any logs FieldMarshaler values like Nested.
+type FieldMarshaler interface {
+	MarshalLogFields() []Field
+}
+
+// Nested creates a field encoding the fields of value as a nested object.
+//
+// Example:
+//
+//	func (o Order) MarshalLogFields() []xlogger.Field {
+//	    return []xlogger.Field{xlogger.String("id", o.ID), xlogger.Int("items", len(o.Items))}
+//	}
+//	logger.Info("order placed", xlogger.Nested("order", order))
+func Nested(key string, value FieldMarshaler) Field {
+	if value == nil {
+		return Any(key, nil)
+	}
+	return ZapField(zap.Object(key, fieldObject{value}))
+}
+
+// Dict creates a field encoding fields as a nested object, for one-off
+// groups where a FieldMarshaler type is not worth it.
+//
+// Example:
+//
+//	logger.Info("request served", xlogger.Dict("http",
+//	    xlogger.String("method", r.Method),
+//	    xlogger.Int("status", status),
+//	))
+//	// "http":{"method":"GET","status":200}
+func Dict(key string, fields ...Field) Field {
+	return Nested(key, fieldList(fields))
+}
+
+// StringMap creates a field encoding m as a nested object with its keys
+// in sorted order, so entries are stable across runs.
+//
+// Example:
+//
+//	logger.Info("request received", xlogger.StringMap("headers", headers))
+func StringMap(key string, m map[string]string) Field {
+	return ZapField(zap.Object(key, stringMap(m)))
+}
+
+// AnyMap creates a field encoding m as a nested object with its keys in
+// sorted order. Values are converted like the fields of Any, so nested
+// maps are not sorted.
+//
+// Example:
+//
+//	logger.Info("job queued", xlogger.AnyMap("params", map[string]interface{}{"retries": 3, "queue": "mail"}))
+func AnyMap(key string, m map[string]interface{}) Field {
+	return ZapField(zap.Object(key, anyMap(m)))
+}
+
+// stringMap is the zapcore.ObjectMarshaler of StringMap
+type stringMap map[string]string
+
+// MarshalLogObject implements zapcore.ObjectMarshaler
+func (m stringMap) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, key := range sortedKeys(m) {
+		enc.AddString(key, m[key])
+	}
+	return nil
+}
+
+// anyMap is the zapcore.ObjectMarshaler of AnyMap
+type anyMap map[string]interface{}
+
+// MarshalLogObject implements zapcore.ObjectMarshaler
+func (m anyMap) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, key := range sortedKeys(m) {
+		toZapField(Any(key, m[key])).AddTo(enc)
+	}
+	return nil
+}
+
+// sortedKeys returns the keys of m in sorted order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// fieldList is the FieldMarshaler of Dict
+type fieldList []Field
+
+// MarshalLogFields implements FieldMarshaler
+func (l fieldList) MarshalLogFields() []Field {
+	return l
+}
+
+// fieldObject adapts a FieldMarshaler to zapcore.ObjectMarshaler
+type fieldObject struct {
+	FieldMarshaler
+}
+
+// MarshalLogObject implements zapcore.ObjectMarshaler
+func (o fieldObject) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, field := range toZapFields(o.MarshalLogFields()) {
+		field.AddTo(enc)
+	}
+	return nil
+}
+
+// Lazy creates a field whose value is computed by fn only when the entry
+// is encoded, so disabled and sampled-out entries cost no more than the
+// closure. fn runs at most once per field, even with several outputs, and
+// a panic in fn is logged as an encoding error of the field. Values added
+// with With are computed when the logger is created.
+//
+// Example:
+//
+//	logger.Debug("cache state", xlogger.Lazy("entries", func() interface{} {
+//	    return cache.Snapshot()
+//	}))
+func Lazy(key string, fn func() interface{}) Field {
+	return ZapField(zap.Reflect(key, &lazyValue{fn: fn}))
+}
+
+// Stringer creates a field whose String method is only called when the
+// entry is encoded.
+//
+// Example:
+//
+//	logger.Debug("routing", xlogger.Stringer("table", routes))
+func Stringer(key string, value fmt.Stringer) Field {
+	return ZapField(zap.Stringer(key, value))
+}
+
+// RawJSON creates a field embedding raw, already encoded JSON, as is in
+// JSON outputs rather than as an escaped string. raw is logged as a string
+// when it is not valid JSON, so malformed payloads are not lost.
+//
+// Example:
+//
+//	body, _ := io.ReadAll(resp.Body)
+//	logger.Debug("upstream response", xlogger.RawJSON("body", body))
+func RawJSON(key string, raw []byte) Field {
+	if !json.Valid(raw) {
+		return ByteString(key, raw)
+	}
+	return ZapField(zap.Reflect(key, json.RawMessage(raw)))
+}
+
+// DefaultBinaryLimit is the number of bytes Binary and Hex fields encode
+// before truncating.
+const DefaultBinaryLimit = 1024
+
+// Binary creates a field logging value base64 encoded. Values longer than
+// DefaultBinaryLimit are truncated and end with the full length, as in
+// "AAEC...(1048576 bytes)". Encoding happens only when the entry is written.
+//
+// Example:
+//
+//	logger.Debug("frame received", xlogger.Binary("payload", frame))
+func Binary(key string, value []byte) Field {
+	return BinaryN(key, value, DefaultBinaryLimit)
+}
+
+// BinaryN creates a field like Binary truncated after limit bytes, 0 for no
+// limit.
+//
+// Example:
+//
+//	logger.Debug("frame received", xlogger.BinaryN("payload", frame, 64))
+func BinaryN(key string, value []byte, limit int) Field {
+	return ZapField(zap.Stringer(key, binaryValue{data: value, limit: limit, encode: base64.StdEncoding.EncodeToString}))
+}
+
+// Hex creates a field logging value hex encoded, truncated like Binary.
+//
+// Example:
+//
+//	logger.Debug("handshake", xlogger.Hex("client_hello", hello))
+func Hex(key string, value []byte) Field {
+	return HexN(key, value, DefaultBinaryLimit)
+}
+
+// HexN creates a field like Hex truncated after limit bytes, 0 for no limit.
+//
+// Example:
+//
+//	logger.Debug("handshake", xlogger.HexN("client_hello", hello, 0))
+func HexN(key string, value []byte, limit int) Field {
+	return ZapField(zap.Stringer(key, binaryValue{data: value, limit: limit, encode: hex.EncodeToString}))
+}
+
+// binaryValue is the value of a Binary or Hex field
+type binaryValue struct {
+	data   []byte
+	limit  int
+	encode func([]byte) string
+}
+
+// String implements fmt.Stringer
+func (v binaryValue) String() string {
+	if v.limit <= 0 || len(v.data) <= v.limit {
+		return v.encode(v.data)
+	}
+	return fmt.Sprintf("%s...(%d bytes)", v.encode(v.data[:v.limit]), len(v.data))
+}
+
+// lazyValue is the value of a Lazy field, computed on first encoding
+type lazyValue struct {
+	once  sync.Once
+	fn    func() interface{}
+	value interface{}
+	err   error
+}
+
+// MarshalJSON implements json.Marshaler, which encoders use for reflected
+// values
+func (v *lazyValue) MarshalJSON() ([]byte, error) {
+	v.once.Do(v.compute)
+	if v.err != nil {
+		return nil, v.err
+	}
+	return json.Marshal(v.value)
+}
+
+// compute calls fn, recovering from panics
+func (v *lazyValue) compute() {
+	defer func() {
+		if r := recover(); r != nil {
+			v.err = fmt.Errorf("lazy value panicked: %v", r)
+		}
+	}()
+	if v.fn != nil {
+		v.value = v.fn()
+	}
+}
+
+// Tags creates a field of categorical labels (retryable, user-facing, ...).
+// Tags from the entry and from WithTags are merged into a single tags array.
+func Tags(tags ...string) Field {
+	return Field{key: tagsFieldKey, value: tags, typ: TagsType}
+}
+
+// Secret creates a field whose value is always logged as "***", whatever
+// the redaction settings. The value is not kept in the field.
+//
+// Example:
+//
+//	logger.Info("password reset", xlogger.Secret("new_password", password))
+//	// {"message":"password reset","new_password":"***"}
+func Secret(key, value string) Field {
+	return String(key, secretMask)
+}
+
+// SecretFingerprint creates a field logged as "***" followed by the first
+// 16 hex digits of the SHA-256 of value, to tell secrets apart (such as an
+// API key rotated between two requests) without revealing them.
+//
+// Example:
+//
+//	logger.Info("request authenticated", xlogger.SecretFingerprint("api_key", key))
+//	// {"message":"request authenticated","api_key":"***sha256:9f86d081884c7d65"}
+func SecretFingerprint(key, value string) Field {
+	return String(key, SecretString(value).fingerprint())
+}
+
+// SecretString is a string that formats as "***" with fmt, encoding/json
+// and encoding.TextMarshaler, so storing credentials in it keeps them out
+// of entries logged with Any or String(key, fmt.Sprint(v)). Convert it back
+// to string to use the value.
+//
+// Example:
+//
+//	type DBConfig struct {
+//	    User     string
+//	    Password xlogger.SecretString
+//	}
+//	logger.Info("connecting", xlogger.Any("db", cfg)) // "db":{"User":"app","Password":"***"}
+type SecretString string
+
+// secretMask replaces secret values
+const secretMask = "***"
+
+// String implements fmt.Stringer
+func (s SecretString) String() string {
+	return secretMask
+}
+
+// GoString implements fmt.GoStringer
+func (s SecretString) GoString() string {
+	return secretMask
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (s SecretString) MarshalText() ([]byte, error) {
+	return []byte(secretMask), nil
+}
+
+// MarshalJSON implements json.Marshaler
+func (s SecretString) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + secretMask + `"`), nil
+}
+
+// fingerprint returns the mask followed by a SHA-256 prefix of s
+func (s SecretString) fingerprint() string {
+	sum := sha256.Sum256([]byte(s))
+	return secretMask + "sha256:" + hex.EncodeToString(sum[:8])
+}
+
+// ZapField wraps a zap field, which is logged unchanged. It lets code
+// migrating from zap switch to Logger first and convert its field
+// constructors incrementally.
+//
+// Example:
+//
+//	logger.Info("order created",
+//	    xlogger.ZapField(zap.Stringer("order_id", id)),
+//	    xlogger.Int("items", 3),
+//	)
+func ZapField(field zapcore.Field) Field {
+	return Field{key: field.Key, value: field, typ: ZapType}
+}
+
+// ZapFields wraps zap fields with ZapField.
+//
+// Example:
+//
+//	logger.Info("order created", xlogger.ZapFields(
+//	    zap.String("order_id", "o-1"),
+//	    zap.Int("items", 3),
+//	)...)
+func ZapFields(fields ...zapcore.Field) []Field {
+	if len(fields) == 0 {
+		return nil
+	}
+	wrapped := make([]Field, len(fields))
+	for i, field := range fields {
+		wrapped[i] = ZapField(field)
+	}
+	return wrapped
+}
+
 // Getter methods for Field (for internal use)
 
 // Key returns the field key
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
+	"slices"
 	"strings"
 	"time"
 
@@ -16,6 +17,9 @@ import (
 // Pre-compiled regex for better performance
 var whitespaceRegex = regexp.MustCompile(`\s+`)
 
+// sqlQueryMessage is the message of SQL entries of structured GORM loggers
+const sqlQueryMessage = "sql query"
+
 // GORMLogger implements gorm.logger.Interface using our Logger
 type GORMLogger struct {
 	logger                    Logger
@@ -23,13 +27,48 @@ type GORMLogger struct {
 	slowThreshold             time.Duration
 	ignoreRecordNotFoundError bool
 	maxFilePathLevels         int
+	paramMode                 SQLParamMode
+	fingerprint               bool
+	maxSQLLength              int
+	queryObserver             QueryObserver
+	structured                bool
+	infoQueries               bool
+	ignoredErrors             []error
 }
 
 // NewGORMLogger creates a new GORM logger adapter with sensible defaults
 func NewGORMLogger(logger Logger) *GORMLogger {
+	return newGORMLogger(logger, "gorm")
+}
+
+// NewGORMLoggerWithLevel creates a GORM logger adapter at level instead of
+// the level mapped from the level of logger. Every statement level selects
+// is logged at Info rather than Debug, so gormlogger.Info logs all SQL
+// without lowering the level of logger to Debug.
+//
+// Example:
+//
+//	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+//	    Logger: xlogger.NewGORMLoggerWithLevel(logger, gormlogger.Info),
+//	})
+func NewGORMLoggerWithLevel(logger Logger, level gormlogger.LogLevel) *GORMLogger {
+	return newGORMLoggerWithLevel(logger, "gorm", level)
+}
+
+// newGORMLoggerWithLevel creates a GORM logger adapter at level whose
+// entries carry component
+func newGORMLoggerWithLevel(logger Logger, component string, level gormlogger.LogLevel) *GORMLogger {
+	gormLogger := newGORMLogger(logger, component)
+	gormLogger.level = level
+	gormLogger.infoQueries = true
+	return gormLogger
+}
+
+// newGORMLogger creates a GORM logger adapter whose entries carry component
+func newGORMLogger(logger Logger, component string) *GORMLogger {
 	gormLevel := mapLoggerLevelToGORM(logger)
 	return &GORMLogger{
-		logger:                    logger.With(String("component", "gorm")),
+		logger:                    logger.With(String("component", component)),
 		level:                     gormLevel,
 		slowThreshold:             500 * time.Millisecond,
 		ignoreRecordNotFoundError: false,
@@ -42,34 +81,46 @@ func (l *GORMLogger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
 	if l.level == level {
 		return l
 	}
-	return &GORMLogger{
-		logger:                    l.logger,
-		level:                     level,
-		slowThreshold:             l.slowThreshold,
-		ignoreRecordNotFoundError: l.ignoreRecordNotFoundError,
-		maxFilePathLevels:         l.maxFilePathLevels,
-	}
+	clone := *l
+	clone.level = level
+	return &clone
 }
 
 // Info implements gorm.logger.Interface
-func (l *GORMLogger) Info(_ context.Context, msg string, data ...interface{}) {
+func (l *GORMLogger) Info(ctx context.Context, msg string, data ...interface{}) {
 	if l.level >= gormlogger.Info {
-		l.logger.Info(fmt.Sprintf(msg, data...), String("file", l.shortFileLocation(utils.FileWithLineNum())))
+		l.withContext(ctx).Info(fmt.Sprintf(msg, data...), String("file", l.shortFileLocation(utils.FileWithLineNum())))
 	}
 }
 
 // Warn implements gorm.logger.Interface
-func (l *GORMLogger) Warn(_ context.Context, msg string, data ...interface{}) {
+func (l *GORMLogger) Warn(ctx context.Context, msg string, data ...interface{}) {
 	if l.level >= gormlogger.Warn {
-		l.logger.Warn(fmt.Sprintf(msg, data...), String("file", l.shortFileLocation(utils.FileWithLineNum())))
+		l.withContext(ctx).Warn(fmt.Sprintf(msg, data...), String("file", l.shortFileLocation(utils.FileWithLineNum())))
 	}
 }
 
 // Error implements gorm.logger.Interface
-func (l *GORMLogger) Error(_ context.Context, msg string, data ...interface{}) {
+func (l *GORMLogger) Error(ctx context.Context, msg string, data ...interface{}) {
 	if l.level >= gormlogger.Error {
-		l.logger.Error(fmt.Sprintf(msg, data...), String("file", l.shortFileLocation(utils.FileWithLineNum())))
+		l.withContext(ctx).Error(fmt.Sprintf(msg, data...), String("file", l.shortFileLocation(utils.FileWithLineNum())))
+	}
+}
+
+// withContext returns the logger adding the trace IDs and registered context
+// fields of ctx, so SQL entries carry them even outside the trace scope.
+// GORM passes context.Background to statements run without WithContext.
+func (l *GORMLogger) withContext(ctx context.Context) Logger {
+	return statementLogger(l.logger, ctx)
+}
+
+// statementLogger returns logger with the fields of the statement context
+// ctx, skipping the empty contexts of statements run without one
+func statementLogger(logger Logger, ctx context.Context) Logger {
+	if ctx == nil || ctx == context.Background() || ctx == context.TODO() {
+		return logger
 	}
+	return logger.WithContext(ctx)
 }
 
 // shortFileLocation limits file path based on maxPathLevels configuration
@@ -98,6 +149,11 @@ func (l *GORMLogger) shortFileLocation(fileWithLine string) string {
 
 // cleanSQLForLogging cleans SQL query for single-line logging by removing newlines and extra whitespace.
 func (l *GORMLogger) cleanSQLForLogging(sql string) string {
+	return cleanSQL(sql)
+}
+
+// cleanSQL cleans SQL query for single-line logging by removing newlines and extra whitespace.
+func cleanSQL(sql string) string {
 	// Early return for empty strings to avoid unnecessary processing
 	if sql == "" {
 		return sql
@@ -131,76 +187,211 @@ func (l *GORMLogger) cleanSQLForLogging(sql string) string {
 }
 
 // Trace implements gorm.logger.Interface for SQL query logging
-func (l *GORMLogger) Trace(_ context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+func (l *GORMLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	duration := time.Since(begin)
+	if l.queryObserver != nil {
+		// The SQL is rendered once for the observer and the log entry
+		sql, rows := fc()
+		fc = func() (string, int64) { return sql, rows }
+		l.queryObserver(newQueryStats(sql, rows, duration, l.slowThreshold, err))
+	}
 	if l.level <= gormlogger.Silent {
 		return
 	}
 
-	duration := time.Since(begin)
 	fileLocation := l.shortFileLocation(utils.FileWithLineNum())
 
 	switch {
-	case err != nil && l.level >= gormlogger.Error && (!errors.Is(err, gormlogger.ErrRecordNotFound) || !l.ignoreRecordNotFoundError):
+	case err != nil && l.level >= gormlogger.Error && !l.isIgnoredError(err):
 		// Error case: get SQL only when needed
 		sql, rows := fc()
-		cleanSQL := l.cleanSQLForLogging(sql)
-		rowsDisplay, rowsField := l.formatRowsInfo(rows)
-		baseFields := l.createBaseFields(fileLocation, duration, rowsField)
-		logMsg := fmt.Sprintf("[%s] [rows:%v] %s", duration.String(), rowsDisplay, cleanSQL)
-		l.logger.Error(logMsg, append(baseFields, Error(err))...)
+		logMsg, baseFields := l.sqlEntry("", fileLocation, duration, sql, rows)
+		baseFields = append(baseFields, Error(err))
+		if class := ClassifySQLError(err); class != "" {
+			baseFields = append(baseFields, String(sqlErrorClassKey, string(class)))
+		}
+		l.withContext(ctx).Error(logMsg, baseFields...)
 
 	case duration > l.slowThreshold && l.slowThreshold != 0 && l.level >= gormlogger.Warn:
 		// Slow query case: get SQL only when needed
 		sql, rows := fc()
-		cleanSQL := l.cleanSQLForLogging(sql)
-		rowsDisplay, rowsField := l.formatRowsInfo(rows)
-		baseFields := l.createBaseFields(fileLocation, duration, rowsField)
-		slowMsg := fmt.Sprintf("SLOW SQL >= %v", l.slowThreshold)
-		logMsg := fmt.Sprintf("%s [%s] [rows:%v] %s", slowMsg, duration.String(), rowsDisplay, cleanSQL)
-		l.logger.Warn(logMsg, append(baseFields, Duration("slow_threshold", l.slowThreshold), Bool("is_slow", true))...)
+		slowMsg := fmt.Sprintf("SLOW SQL >= %v ", l.slowThreshold)
+		logMsg, baseFields := l.sqlEntry(slowMsg, fileLocation, duration, sql, rows)
+		l.withContext(ctx).Warn(logMsg, append(baseFields, Duration("slow_threshold", l.slowThreshold), Bool("is_slow", true))...)
 
 	case l.level == gormlogger.Info:
 		// Normal case: get SQL only when needed
 		sql, rows := fc()
-		cleanSQL := l.cleanSQLForLogging(sql)
-		rowsDisplay, rowsField := l.formatRowsInfo(rows)
-		baseFields := l.createBaseFields(fileLocation, duration, rowsField)
-		logMsg := fmt.Sprintf("[%s] [rows:%v] %s", duration.String(), rowsDisplay, cleanSQL)
-		l.logger.Debug(logMsg, baseFields...)
+		logMsg, baseFields := l.sqlEntry("", fileLocation, duration, sql, rows)
+		if l.infoQueries {
+			l.withContext(ctx).Info(logMsg, baseFields...)
+		} else {
+			l.withContext(ctx).Debug(logMsg, baseFields...)
+		}
+	}
+}
+
+// ParamsFilter implements gorm.ParamsFilter. With SQLParamsMask or
+// SQLParamsStrip it masks the values GORM binds to a statement before the
+// dialector renders them into the logged SQL, so they are redacted whatever
+// string quote the dialect uses.
+func (l *GORMLogger) ParamsFilter(_ context.Context, sql string, params ...interface{}) (string, []interface{}) {
+	if l.paramMode == SQLParamsKeep {
+		return sql, params
+	}
+	return sql, maskSQLParams(params)
+}
+
+// isIgnoredError reports whether err is not logged as an error: a record
+// not found with SetIgnoreRecordNotFoundError, or one of SetIgnoredErrors
+func (l *GORMLogger) isIgnoredError(err error) bool {
+	if l.ignoreRecordNotFoundError && errors.Is(err, gormlogger.ErrRecordNotFound) {
+		return true
+	}
+	for _, ignored := range l.ignoredErrors {
+		if errors.Is(err, ignored) {
+			return true
+		}
+	}
+	return false
+}
+
+// sqlEntry returns the message and fields of a traced statement. The
+// message starts with prefix and carries the duration, rows and SQL, unless
+// the logger is structured.
+func (l *GORMLogger) sqlEntry(prefix, fileLocation string, duration time.Duration, sql string, rows int64) (string, []Field) {
+	cleanSQL, sqlFields := l.formatSQL(sql)
+	if l.structured {
+		fields := []Field{
+			String("file", fileLocation),
+			String("sql", cleanSQL),
+			Float64("duration_ms", float64(duration)/float64(time.Millisecond)),
+		}
+		if rows != -1 {
+			fields = append(fields, Int64("rows", rows))
+		}
+		return sqlQueryMessage, append(fields, sqlFields...)
 	}
+
+	rowsDisplay, rowsField := l.formatRowsInfo(rows)
+	logMsg := fmt.Sprintf("%s[%s] [rows:%v] %s", prefix, duration.String(), rowsDisplay, cleanSQL)
+	return logMsg, append(l.createBaseFields(fileLocation, duration, rowsField), sqlFields...)
 }
 
 // SetSlowThreshold configures slow query threshold
 func (l *GORMLogger) SetSlowThreshold(threshold time.Duration) *GORMLogger {
-	return &GORMLogger{
-		logger:                    l.logger,
-		level:                     l.level,
-		slowThreshold:             threshold,
-		ignoreRecordNotFoundError: l.ignoreRecordNotFoundError,
-		maxFilePathLevels:         l.maxFilePathLevels,
-	}
+	clone := *l
+	clone.slowThreshold = threshold
+	return &clone
 }
 
 // SetIgnoreRecordNotFoundError configures whether to ignore ErrRecordNotFound
 func (l *GORMLogger) SetIgnoreRecordNotFoundError(ignore bool) *GORMLogger {
-	return &GORMLogger{
-		logger:                    l.logger,
-		level:                     l.level,
-		slowThreshold:             l.slowThreshold,
-		ignoreRecordNotFoundError: ignore,
-		maxFilePathLevels:         l.maxFilePathLevels,
-	}
+	clone := *l
+	clone.ignoreRecordNotFoundError = ignore
+	return &clone
 }
 
 // SetMaxPathLevels configures maximum path levels to display (-1 = show "_", 0 = show full path)
 func (l *GORMLogger) SetMaxPathLevels(levels int) *GORMLogger {
-	return &GORMLogger{
-		logger:                    l.logger,
-		level:                     l.level,
-		slowThreshold:             l.slowThreshold,
-		ignoreRecordNotFoundError: l.ignoreRecordNotFoundError,
-		maxFilePathLevels:         levels,
-	}
+	clone := *l
+	clone.maxFilePathLevels = levels
+	return &clone
+}
+
+// SetSQLParamMode configures how the values bound to statements are logged.
+// SQLParamsMask and SQLParamsStrip keep values read from users tables, such
+// as emails and names, out of the logs while keeping the statement shape.
+//
+// Example:
+//
+//	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+//	    Logger: logger.ForGORM().SetSQLParamMode(xlogger.SQLParamsMask),
+//	})
+func (l *GORMLogger) SetSQLParamMode(mode SQLParamMode) *GORMLogger {
+	clone := *l
+	clone.paramMode = mode
+	return &clone
+}
+
+// SetQueryFingerprint configures whether SQL entries carry the
+// "sql_fingerprint" field: the statement with its whitespace collapsed, its
+// values replaced with "?" and lists of values collapsed to one, so slow
+// queries can be grouped by statement shape.
+//
+// Example:
+//
+//	// SELECT * FROM users WHERE id IN (1, 2, 3) AND name = 'jane'
+//	// is logged with "sql_fingerprint": "SELECT * FROM users WHERE id IN (?) AND name = ?"
+//	gormLogger := logger.ForGORM().SetQueryFingerprint(true)
+func (l *GORMLogger) SetQueryFingerprint(enabled bool) *GORMLogger {
+	clone := *l
+	clone.fingerprint = enabled
+	return &clone
+}
+
+// SetMaxSQLLength configures the maximum length in bytes of the logged SQL
+// and fingerprint. Longer statements, such as bulk inserts or giant IN
+// lists, are cut with "..." and their entries carry "sql_truncated": true.
+// Zero or less logs statements whole.
+//
+// Example:
+//
+//	gormLogger := logger.ForGORM().SetMaxSQLLength(4096)
+func (l *GORMLogger) SetMaxSQLLength(n int) *GORMLogger {
+	clone := *l
+	clone.maxSQLLength = n
+	return &clone
+}
+
+// SetQueryObserver configures a function called with the statistics of
+// every statement GORM traces, whatever the log level, to feed latency
+// metrics from the logging adapter. It runs on the goroutine of the query.
+//
+// Example:
+//
+//	gormLogger := logger.ForGORM().SetQueryObserver(func(stats xlogger.QueryStats) {
+//	    queryDuration.WithLabelValues(stats.Operation, stats.Table, strconv.FormatBool(stats.Err != nil)).
+//	        Observe(stats.Duration.Seconds())
+//	})
+func (l *GORMLogger) SetQueryObserver(observer QueryObserver) *GORMLogger {
+	clone := *l
+	clone.queryObserver = observer
+	return &clone
+}
+
+// SetStructuredMode configures whether SQL entries carry the statement as
+// fields rather than in the message: they are logged as "sql query" with
+// the "sql", "duration_ms" and "rows" fields, "rows" being left out when
+// GORM does not know it. Slow and failed statements keep their is_slow,
+// slow_threshold and error fields. Such entries are easier to query in
+// Loki or Elasticsearch than the formatted messages.
+//
+// Example:
+//
+//	gormLogger := logger.ForGORM().SetStructuredMode(true)
+func (l *GORMLogger) SetStructuredMode(enabled bool) *GORMLogger {
+	clone := *l
+	clone.structured = enabled
+	return &clone
+}
+
+// SetIgnoredErrors configures errors, matched with errors.Is, that are not
+// logged at Error level, such as the duplicate key error of an upsert that
+// is expected to conflict. Their statements are logged like successful ones
+// when slow or at the Info GORM level. It replaces the errors of earlier
+// calls.
+//
+// Example:
+//
+//	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+//	    TranslateError: true,
+//	    Logger:         logger.ForGORM().SetIgnoredErrors(gorm.ErrDuplicatedKey),
+//	})
+func (l *GORMLogger) SetIgnoredErrors(errs ...error) *GORMLogger {
+	clone := *l
+	clone.ignoredErrors = slices.Clone(errs)
+	return &clone
 }
 
 // mapLoggerLevelToGORM maps logger level to GORM level
@@ -245,3 +436,34 @@ func (l *GORMLogger) createBaseFields(fileLocation string, duration time.Duratio
 		rowsField,
 	}
 }
+
+// formatSQL returns sql as logged, cleaned, redacted and truncated, and the
+// fingerprint and truncation fields of the entry
+func (l *GORMLogger) formatSQL(sql string) (string, []Field) {
+	var fields []Field
+	if l.fingerprint {
+		fingerprint, _ := truncateSQL(fingerprintSQL(sql), l.maxSQLLength)
+		fields = append(fields, String(sqlFingerprintKey, fingerprint))
+	}
+	cleanSQL, truncated := truncateSQL(l.cleanSQLForLogging(l.paramMode.redactSQL(sql)), l.maxSQLLength)
+	if truncated {
+		fields = append(fields, Bool(sqlTruncatedKey, true))
+	}
+	return cleanSQL, fields
+}
+
+// parseGORMLevel returns the GORM log level named name
+func parseGORMLevel(name string) (gormlogger.LogLevel, error) {
+	switch strings.ToLower(name) {
+	case "silent":
+		return gormlogger.Silent, nil
+	case "error":
+		return gormlogger.Error, nil
+	case "warn":
+		return gormlogger.Warn, nil
+	case "info":
+		return gormlogger.Info, nil
+	default:
+		return 0, fmt.Errorf("unknown GORM level %q", name)
+	}
+}
@@ -0,0 +1,323 @@
+package xlogger
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// Keys of the fields GORMLogger adds to SQL entries
+const (
+	sqlFingerprintKey = "sql_fingerprint"
+	sqlTruncatedKey   = "sql_truncated"
+	sqlErrorClassKey  = "error_class"
+)
+
+// SQLErrorClass is the kind of failure of a statement, logged as the
+// "error_class" field of failed GORM statements.
+type SQLErrorClass string
+
+const (
+	// SQLErrorConstraintViolation is a unique, foreign key, check or not
+	// null constraint violation, such as a duplicate key
+	SQLErrorConstraintViolation SQLErrorClass = "constraint_violation"
+	// SQLErrorDeadlock is a deadlock or serialization failure; the
+	// transaction may be retried
+	SQLErrorDeadlock SQLErrorClass = "deadlock"
+	// SQLErrorTimeout is a statement, lock or network timeout
+	SQLErrorTimeout SQLErrorClass = "timeout"
+	// SQLErrorNotFound is a query without result, such as
+	// gorm.ErrRecordNotFound
+	SQLErrorNotFound SQLErrorClass = "not_found"
+)
+
+// sqlErrorMessages classifies driver errors without a SQLSTATE by their
+// message, in lower case
+var sqlErrorMessages = []struct {
+	class     SQLErrorClass
+	fragments []string
+}{
+	{SQLErrorDeadlock, []string{"deadlock", "could not serialize access"}},
+	{SQLErrorConstraintViolation, []string{"duplicate", "unique constraint", "foreign key", "check constraint", "not null constraint", "violates"}},
+	{SQLErrorTimeout, []string{"timeout", "timed out", "canceling statement"}},
+}
+
+// sqlEllipsis ends SQL cut by GORMLogger.SetMaxSQLLength
+const sqlEllipsis = "..."
+
+// sqlTableRegex matches the table named after FROM, INTO, UPDATE or JOIN,
+// optionally quoted (\x60 is a backquote) and qualified by its schema
+var sqlTableRegex = regexp.MustCompile(`(?i)\b(?:FROM|INTO|UPDATE|JOIN)\s+((?:[\x60"]?\w+[\x60"]?\.)*[\x60"]?\w+[\x60"]?)`)
+
+// sqlValueListRegex matches lists of stripped values such as "?, ?, ?"
+var sqlValueListRegex = regexp.MustCompile(`\?(?:\s*,\s*\?)+`)
+
+// QueryStats describes a statement traced by GORMLogger, for
+// GORMLogger.SetQueryObserver.
+type QueryStats struct {
+	Operation string        // First keyword of the statement, such as SELECT, in upper case
+	Table     string        // First table named by the statement, empty when none is found
+	Duration  time.Duration // Time the statement took
+	Rows      int64         // Rows affected, -1 when unknown
+	Slow      bool          // Whether Duration exceeds the slow threshold of the logger
+	Err       error         // Error of the statement, including gorm.ErrRecordNotFound
+}
+
+// QueryObserver receives the statistics of every statement GORM traces.
+type QueryObserver func(stats QueryStats)
+
+// newQueryStats returns the statistics of the statement sql
+func newQueryStats(sql string, rows int64, duration, slowThreshold time.Duration, err error) QueryStats {
+	return QueryStats{
+		Operation: sqlOperation(sql),
+		Table:     sqlTable(SQLParamsStrip.redactSQL(sql)),
+		Duration:  duration,
+		Rows:      rows,
+		Slow:      slowThreshold != 0 && duration > slowThreshold,
+		Err:       err,
+	}
+}
+
+// sqlOperation returns the first keyword of sql in upper case
+func sqlOperation(sql string) string {
+	sql = strings.TrimLeft(sql, " \t\r\n(")
+	end := strings.IndexFunc(sql, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z')
+	})
+	if end < 0 {
+		end = len(sql)
+	}
+	return strings.ToUpper(sql[:end])
+}
+
+// sqlTable returns the first table named by sql without its quotes, empty
+// when none is found
+func sqlTable(sql string) string {
+	match := sqlTableRegex.FindStringSubmatch(sql)
+	if match == nil {
+		return ""
+	}
+	return strings.NewReplacer("`", "", `"`, "").Replace(match[1])
+}
+
+// ClassifySQLError returns the class of the error of a statement, read from
+// its SQLSTATE when the driver error has a SQLState method, as the pgx and
+// lib/pq errors do, and otherwise from sentinel errors and the message. It
+// returns "" for nil and unclassified errors.
+func ClassifySQLError(err error) SQLErrorClass {
+	if err == nil {
+		return ""
+	}
+	if errors.Is(err, gormlogger.ErrRecordNotFound) || errors.Is(err, sql.ErrNoRows) {
+		return SQLErrorNotFound
+	}
+	var stateErr interface{ SQLState() string }
+	if errors.As(err, &stateErr) {
+		if class := classifySQLState(stateErr.SQLState()); class != "" {
+			return class
+		}
+	}
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || errors.As(err, &netErr) && netErr.Timeout() {
+		return SQLErrorTimeout
+	}
+
+	msg := strings.ToLower(err.Error())
+	for _, m := range sqlErrorMessages {
+		for _, fragment := range m.fragments {
+			if strings.Contains(msg, fragment) {
+				return m.class
+			}
+		}
+	}
+	return ""
+}
+
+// classifySQLState returns the class of a SQLSTATE code, "" when unknown
+func classifySQLState(state string) SQLErrorClass {
+	switch {
+	case strings.HasPrefix(state, "23"):
+		return SQLErrorConstraintViolation
+	case state == "40001" || state == "40P01":
+		return SQLErrorDeadlock
+	case state == "57014" || state == "55P03":
+		return SQLErrorTimeout
+	case state == "02000":
+		return SQLErrorNotFound
+	}
+	return ""
+}
+
+// SQLParamMode decides how GORMLogger logs the values GORM interpolates into
+// the SQL of a statement.
+type SQLParamMode int
+
+const (
+	// SQLParamsKeep logs the SQL with its values, as GORM renders it
+	SQLParamsKeep SQLParamMode = iota
+	// SQLParamsMask replaces every value with "***", quoted strings with
+	// '***', so the log still tells strings from numbers. Values GORM binds
+	// are masked before GORM renders them, which quotes them all
+	SQLParamsMask
+	// SQLParamsStrip replaces every value with "?", leaving the statement
+	// as it was prepared
+	SQLParamsStrip
+)
+
+// redactSQL returns sql with its values replaced as mode requires
+func (m SQLParamMode) redactSQL(sql string) string {
+	switch m {
+	case SQLParamsMask:
+		return replaceSQLLiterals(sql, func(quoted bool) string {
+			if quoted {
+				return "'" + redactedValue + "'"
+			}
+			return redactedValue
+		})
+	case SQLParamsStrip:
+		return replaceSQLLiterals(sql, func(bool) string { return "?" })
+	}
+	return sql
+}
+
+// fingerprintSQL returns the shape of sql: whitespace collapsed, values
+// replaced with "?" and lists of values, as in IN clauses or multi-column
+// VALUES, collapsed to a single "?"
+func fingerprintSQL(sql string) string {
+	shape := SQLParamsStrip.redactSQL(sql)
+	shape = strings.TrimSpace(whitespaceRegex.ReplaceAllString(shape, " "))
+	return sqlValueListRegex.ReplaceAllString(shape, "?")
+}
+
+// truncateSQL cuts sql to limit bytes, ellipsis included, without splitting
+// a UTF-8 character. It reports whether sql was cut; limit below one keeps it.
+func truncateSQL(sql string, limit int) (string, bool) {
+	if limit < 1 || len(sql) <= limit {
+		return sql, false
+	}
+	cut := max(0, limit-len(sqlEllipsis))
+	for cut > 0 && !utf8.RuneStart(sql[cut]) {
+		cut--
+	}
+	return sql[:cut] + sqlEllipsis, true
+}
+
+// maskSQLParams replaces the values GORM binds to a statement with
+// redactedValue, keeping NULLs. GORM renders them with the string quote of
+// the dialect, such as the double quotes of SQLite, which replaceSQLLiterals
+// could not tell from quoted identifiers otherwise.
+func maskSQLParams(params []interface{}) []interface{} {
+	masked := make([]interface{}, len(params))
+	for i, param := range params {
+		if param != nil {
+			masked[i] = redactedValue
+		}
+	}
+	return masked
+}
+
+// replaceSQLLiterals replaces the string, number and boolean literals of
+// sql, and the values masked by maskSQLParams whatever their quotes, with
+// the result of replace, which is told whether the literal was quoted.
+// Quoted identifiers, placeholders such as $1 and digits inside identifiers
+// such as t1 are kept.
+func replaceSQLLiterals(sql string, replace func(quoted bool) string) string {
+	var b strings.Builder
+	b.Grow(len(sql))
+	for i := 0; i < len(sql); {
+		c := sql[i]
+		wordStart := i == 0 || !isSQLIdentByte(sql[i-1])
+		switch {
+		case c == '\'':
+			i = skipSQLString(sql, i)
+			b.WriteString(replace(true))
+		case c == '"' || c == '`':
+			next := len(sql)
+			if end := strings.IndexByte(sql[i+1:], c); end >= 0 {
+				next = i + end + 2
+			}
+			if next-i == len(redactedValue)+2 && sql[i+1:next-1] == redactedValue {
+				b.WriteString(replace(true))
+			} else {
+				b.WriteString(sql[i:next])
+			}
+			i = next
+		case isSQLDigit(c) && wordStart:
+			i = skipSQLNumber(sql, i)
+			b.WriteString(replace(false))
+		case wordStart && sqlBoolLength(sql[i:]) > 0:
+			i += sqlBoolLength(sql[i:])
+			b.WriteString(replace(false))
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+	return b.String()
+}
+
+// skipSQLString returns the index after the string literal starting at
+// start, which may escape quotes by doubling them or with a backslash
+func skipSQLString(sql string, start int) int {
+	for i := start + 1; i < len(sql); i++ {
+		switch sql[i] {
+		case '\\':
+			i++
+		case '\'':
+			if i+1 < len(sql) && sql[i+1] == '\'' {
+				i++
+				continue
+			}
+			return i + 1
+		}
+	}
+	return len(sql)
+}
+
+// sqlBoolLength returns the length of the TRUE or FALSE keyword, in any
+// case, starting sql, 0 when sql starts with another word
+func sqlBoolLength(sql string) int {
+	for _, keyword := range []string{"true", "false"} {
+		n := len(keyword)
+		if len(sql) >= n && strings.EqualFold(sql[:n], keyword) && (len(sql) == n || !isSQLIdentByte(sql[n])) {
+			return n
+		}
+	}
+	return 0
+}
+
+// skipSQLNumber returns the index after the number literal starting at
+// start, including decimals, exponents and hexadecimal digits
+func skipSQLNumber(sql string, start int) int {
+	i := start
+	for i < len(sql) {
+		c := sql[i]
+		switch {
+		case isSQLIdentByte(c) || c == '.':
+			i++
+		case (c == '+' || c == '-') && (sql[i-1] == 'e' || sql[i-1] == 'E'):
+			i++
+		default:
+			return i
+		}
+	}
+	return i
+}
+
+// isSQLDigit reports whether c is an ASCII digit
+func isSQLDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// isSQLIdentByte reports whether c may be part of an identifier or
+// placeholder
+func isSQLIdentByte(c byte) bool {
+	return isSQLDigit(c) || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_' || c == '$' || c >= 0x80
+}
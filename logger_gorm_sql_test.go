@@ -0,0 +1,188 @@
+package xlogger
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// TestSQLParamMode tests the redaction of values in logged SQL
+func TestSQLParamMode(t *testing.T) {
+	tests := []struct {
+		name  string
+		sql   string
+		mask  string
+		strip string
+	}{
+		{
+			name:  "should replace strings and numbers",
+			sql:   "SELECT * FROM `users` WHERE email = 'jane@example.com' AND age > 30 LIMIT 1",
+			mask:  "SELECT * FROM `users` WHERE email = '***' AND age > *** LIMIT ***",
+			strip: "SELECT * FROM `users` WHERE email = ? AND age > ? LIMIT ?",
+		},
+		{
+			name:  "should handle escaped quotes",
+			sql:   `INSERT INTO notes (body, score) VALUES ('it''s', 1.5e-3), ('a\'b', 0x1F)`,
+			mask:  `INSERT INTO notes (body, score) VALUES ('***', ***), ('***', ***)`,
+			strip: `INSERT INTO notes (body, score) VALUES (?, ?), (?, ?)`,
+		},
+		{
+			name:  "should keep identifiers and placeholders",
+			sql:   `SELECT t1.col2 FROM "table3" t1 WHERE t1.id = $1 AND "x'y" = ?`,
+			mask:  `SELECT t1.col2 FROM "table3" t1 WHERE t1.id = $1 AND "x'y" = ?`,
+			strip: `SELECT t1.col2 FROM "table3" t1 WHERE t1.id = $1 AND "x'y" = ?`,
+		},
+		{
+			name:  "should replace booleans and masked values in any quotes",
+			sql:   `UPDATE "users" SET active = TRUE, admin = false, email = "***" WHERE is_true = 1`,
+			mask:  `UPDATE "users" SET active = ***, admin = ***, email = '***' WHERE is_true = ***`,
+			strip: `UPDATE "users" SET active = ?, admin = ?, email = ? WHERE is_true = ?`,
+		},
+		{
+			name:  "should replace unterminated strings",
+			sql:   "SELECT 'secret",
+			mask:  "SELECT '***'",
+			strip: "SELECT ?",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.sql, SQLParamsKeep.redactSQL(tt.sql))
+			assert.Equal(t, tt.mask, SQLParamsMask.redactSQL(tt.sql))
+			assert.Equal(t, tt.strip, SQLParamsStrip.redactSQL(tt.sql))
+		})
+	}
+}
+
+// TestFingerprintSQL tests the statement shape of fingerprints
+func TestFingerprintSQL(t *testing.T) {
+	tests := []struct {
+		name string
+		sql  string
+		want string
+	}{
+		{
+			name: "should collapse whitespace and replace values",
+			sql:  "SELECT *\n\tFROM users\n WHERE name = 'jane'  AND age > 30",
+			want: "SELECT * FROM users WHERE name = ? AND age > ?",
+		},
+		{
+			name: "should collapse lists of values",
+			sql:  "SELECT * FROM users WHERE id IN (1, 2,3) AND role IN ('a')",
+			want: "SELECT * FROM users WHERE id IN (?) AND role IN (?)",
+		},
+		{
+			name: "should give rows of different values one shape",
+			sql:  "INSERT INTO users (name,age) VALUES ('jane',30),('joe',41)",
+			want: "INSERT INTO users (name,age) VALUES (?),(?)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, fingerprintSQL(tt.sql))
+		})
+	}
+}
+
+// TestTruncateSQL tests the length limit of logged SQL
+func TestTruncateSQL(t *testing.T) {
+	t.Run("should cut long statements with an ellipsis", func(t *testing.T) {
+		sql, truncated := truncateSQL("SELECT * FROM users WHERE id IN (1, 2, 3)", 20)
+
+		assert.True(t, truncated)
+		assert.Equal(t, "SELECT * FROM use...", sql)
+	})
+
+	t.Run("should not split UTF-8 characters", func(t *testing.T) {
+		sql, truncated := truncateSQL("SELECT 'ผู้ใช้'", 12)
+
+		assert.True(t, truncated)
+		assert.Equal(t, "SELECT '...", sql)
+	})
+
+	t.Run("should keep short statements and disabled limits", func(t *testing.T) {
+		for _, limit := range []int{0, -1, 8} {
+			sql, truncated := truncateSQL("SELECT 1", limit)
+
+			assert.False(t, truncated)
+			assert.Equal(t, "SELECT 1", sql)
+		}
+	})
+}
+
+// TestNewQueryStats tests the statistics given to query observers
+func TestNewQueryStats(t *testing.T) {
+	tests := []struct {
+		sql       string
+		operation string
+		table     string
+	}{
+		{"SELECT * FROM `users` WHERE id = 1", "SELECT", "users"},
+		{"  insert INTO \"public\".\"orders\" (id) VALUES (1)", "INSERT", "public.orders"},
+		{"UPDATE accounts SET note = 'moved from savings' WHERE id = 2", "UPDATE", "accounts"},
+		{"DELETE FROM sessions WHERE expires_at < NOW()", "DELETE", "sessions"},
+		{"(SELECT 1) UNION (SELECT 2)", "SELECT", ""},
+		{"SELECT 'from nowhere'", "SELECT", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run("should parse "+tt.sql, func(t *testing.T) {
+			stats := newQueryStats(tt.sql, 1, time.Second, 0, nil)
+
+			assert.Equal(t, tt.operation, stats.Operation)
+			assert.Equal(t, tt.table, stats.Table)
+			assert.False(t, stats.Slow)
+		})
+	}
+
+	t.Run("should flag statements over the slow threshold", func(t *testing.T) {
+		assert.True(t, newQueryStats("SELECT 1", 1, time.Second, time.Millisecond, nil).Slow)
+		assert.False(t, newQueryStats("SELECT 1", 1, time.Millisecond, time.Second, nil).Slow)
+	})
+}
+
+// sqlStateError is a driver error with a SQLSTATE, like pgconn.PgError
+type sqlStateError struct {
+	state, msg string
+}
+
+func (e *sqlStateError) Error() string    { return e.msg }
+func (e *sqlStateError) SQLState() string { return e.state }
+
+// TestClassifySQLError tests the classes of statement errors
+func TestClassifySQLError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want SQLErrorClass
+	}{
+		{"nil", nil, ""},
+		{"record not found", fmt.Errorf("find: %w", gormlogger.ErrRecordNotFound), SQLErrorNotFound},
+		{"no rows", sql.ErrNoRows, SQLErrorNotFound},
+		{"unique violation state", &sqlStateError{"23505", "duplicate key value violates unique constraint"}, SQLErrorConstraintViolation},
+		{"foreign key state", fmt.Errorf("insert: %w", &sqlStateError{"23503", "insert failed"}), SQLErrorConstraintViolation},
+		{"deadlock state", &sqlStateError{"40P01", "deadlock detected"}, SQLErrorDeadlock},
+		{"query canceled state", &sqlStateError{"57014", "canceling statement due to statement timeout"}, SQLErrorTimeout},
+		{"unknown state", &sqlStateError{"42P01", "relation \"x\" does not exist"}, ""},
+		{"context deadline", fmt.Errorf("query: %w", context.DeadlineExceeded), SQLErrorTimeout},
+		{"mysql duplicate", errors.New("Error 1062 (23000): Duplicate entry 'a' for key 'users.email'"), SQLErrorConstraintViolation},
+		{"mysql deadlock", errors.New("Error 1213 (40001): Deadlock found when trying to get lock"), SQLErrorDeadlock},
+		{"mysql lock wait", errors.New("Error 1205 (HY000): Lock wait timeout exceeded"), SQLErrorTimeout},
+		{"sqlite unique", errors.New("UNIQUE constraint failed: users.email"), SQLErrorConstraintViolation},
+		{"unclassified", errors.New("connection refused"), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run("should classify "+tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ClassifySQLError(tt.err))
+		})
+	}
+}
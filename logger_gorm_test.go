@@ -4,11 +4,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/go-logr/logr"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/fx/fxevent"
 	"go.uber.org/zap/zapcore"
 	gormlogger "gorm.io/gorm/logger"
@@ -52,6 +56,38 @@ func (m *MockLogger) Error(msg string, fields ...Field) {
 	m.Called(args...)
 }
 
+func (m *MockLogger) DebugFn(msg string, fieldsFn func() []Field) {
+	m.Debug(msg, fieldsFn()...)
+}
+
+func (m *MockLogger) InfoFn(msg string, fieldsFn func() []Field) {
+	m.Info(msg, fieldsFn()...)
+}
+
+func (m *MockLogger) WarnFn(msg string, fieldsFn func() []Field) {
+	m.Warn(msg, fieldsFn()...)
+}
+
+func (m *MockLogger) ErrorFn(msg string, fieldsFn func() []Field) {
+	m.Error(msg, fieldsFn()...)
+}
+
+func (m *MockLogger) Enabled(level zapcore.Level) bool {
+	return true
+}
+
+func (m *MockLogger) Check(level zapcore.Level, msg string) *CheckedEntry {
+	return nil
+}
+
+func (m *MockLogger) DPanic(msg string, fields ...Field) {
+	args := []interface{}{msg}
+	for _, field := range fields {
+		args = append(args, field)
+	}
+	m.Called(args...)
+}
+
 func (m *MockLogger) Panic(msg string, fields ...Field) {
 	args := []interface{}{msg}
 	for _, field := range fields {
@@ -82,6 +118,20 @@ func (m *MockLogger) WithContext(ctx context.Context) Logger {
 	return result.Get(0).(Logger)
 }
 
+func (m *MockLogger) WithTags(tags ...string) Logger {
+	args := []interface{}{}
+	for _, tag := range tags {
+		args = append(args, tag)
+	}
+	result := m.Called(args...)
+	return result.Get(0).(Logger)
+}
+
+func (m *MockLogger) Named(name string) Logger {
+	result := m.Called(name)
+	return result.Get(0).(Logger)
+}
+
 func (m *MockLogger) ForInfra(component string) Logger {
 	result := m.Called(component)
 	return result.Get(0).(Logger)
@@ -97,6 +147,16 @@ func (m *MockLogger) ForGORM() *GORMLogger {
 	return result.Get(0).(*GORMLogger)
 }
 
+func (m *MockLogger) ForGORMNamed(name string) *GORMLogger {
+	result := m.Called(name)
+	return result.Get(0).(*GORMLogger)
+}
+
+func (m *MockLogger) ForLogr() logr.LogSink {
+	result := m.Called()
+	return result.Get(0).(logr.LogSink)
+}
+
 func (m *MockLogger) Level() zapcore.Level {
 	return m.level
 }
@@ -106,6 +166,11 @@ func (m *MockLogger) Sync() error {
 	return result.Error(0)
 }
 
+func (m *MockLogger) Close(ctx context.Context) error {
+	result := m.Called(ctx)
+	return result.Error(0)
+}
+
 func (m *MockLogger) SetLevel(level zapcore.Level) {
 	m.level = level
 }
@@ -431,7 +496,11 @@ func TestGORMLogger_Trace(t *testing.T) {
 			// Set up expectations based on expected log level
 			switch tt.expectedLogLevel {
 			case "error":
-				mockLogger.On("Error", mock.AnythingOfType("string"), mock.Anything, mock.Anything, mock.Anything, mock.Anything).Once()
+				args := []interface{}{mock.AnythingOfType("string"), mock.Anything, mock.Anything, mock.Anything, mock.Anything}
+				if ClassifySQLError(tt.err) != "" {
+					args = append(args, mock.MatchedBy(func(field Field) bool { return field.Key() == "error_class" }))
+				}
+				mockLogger.On("Error", args...).Once()
 			case "warn":
 				mockLogger.On("Warn", mock.AnythingOfType("string"), mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Once()
 			case "info":
@@ -669,3 +738,359 @@ func TestGORMLogger_Trace_SlowThresholdZero(t *testing.T) {
 
 	mockLogger.AssertExpectations(t)
 }
+
+// TestGORMLogger_Context tests trace fields read from the statement context
+func TestGORMLogger_Context(t *testing.T) {
+	t.Run("should add the trace IDs of ctx outside the trace scope", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+		gormLogger := logger.ForGORM()
+		ctx := ContextWithTrace(context.Background(), "req-gorm", "corr-gorm")
+
+		gormLogger.Error(ctx, "connection lost")
+		gormLogger.Trace(ctx, time.Now(), func() (string, int64) {
+			return "SELECT * FROM users", 0
+		}, errors.New("timeout"))
+
+		log := output()
+		for _, msg := range []string{"connection lost", "[rows:0] SELECT * FROM users"} {
+			var entry map[string]interface{}
+			for _, e := range decodeJSONLines(t, log) {
+				if strings.HasSuffix(e["message"].(string), msg) {
+					entry = e
+				}
+			}
+			require.NotNil(t, entry, msg)
+			assert.Equal(t, "req-gorm", entry["request_id"])
+			assert.Equal(t, "corr-gorm", entry["correlation_id"])
+			assert.Equal(t, "gorm", entry["component"])
+		}
+	})
+
+	t.Run("should add registered context fields", func(t *testing.T) {
+		type tenantKey struct{}
+		RegisterContextFields(func(ctx context.Context) []Field {
+			if tenant, ok := ctx.Value(tenantKey{}).(string); ok {
+				return []Field{String("gorm_tenant", tenant)}
+			}
+			return nil
+		})
+		logger, output := newFileLogger(t)
+
+		logger.ForGORM().Warn(context.WithValue(context.Background(), tenantKey{}, "acme"), "pool exhausted")
+
+		entries := entriesWithMessage(t, output(), "pool exhausted")
+		require.Len(t, entries, 1)
+		assert.Equal(t, "acme", entries[0]["gorm_tenant"])
+	})
+
+	t.Run("should use the trace scope without trace IDs in ctx", func(t *testing.T) {
+		skipWithoutGLS(t)
+		logger, output := newFileLogger(t)
+
+		RunWithTraceVoid("req-scope", "", func() {
+			logger.ForGORM().Error(context.Background(), "scoped failure")
+		})
+
+		entries := entriesWithMessage(t, output(), "scoped failure")
+		require.Len(t, entries, 1)
+		assert.Equal(t, "req-scope", entries[0]["request_id"])
+	})
+}
+
+func TestGORMLogger_SetSQLParamMode(t *testing.T) {
+	t.Run("should keep the other settings", func(t *testing.T) {
+		gormLogger := &GORMLogger{
+			logger:            &MockLogger{},
+			level:             gormlogger.Info,
+			slowThreshold:     100 * time.Millisecond,
+			maxFilePathLevels: 2,
+		}
+
+		result := gormLogger.SetSQLParamMode(SQLParamsStrip)
+
+		assert.Equal(t, SQLParamsStrip, result.paramMode)
+		assert.Equal(t, SQLParamsKeep, gormLogger.paramMode)
+		assert.Equal(t, gormLogger.slowThreshold, result.slowThreshold)
+		assert.Equal(t, gormLogger.maxFilePathLevels, result.maxFilePathLevels)
+		assert.Equal(t, SQLParamsStrip, result.SetSlowThreshold(time.Second).paramMode)
+		assert.Equal(t, SQLParamsStrip, result.LogMode(gormlogger.Warn).(*GORMLogger).paramMode)
+	})
+
+	t.Run("should mask values in logged SQL", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+		gormLogger := logger.ForGORM().SetSQLParamMode(SQLParamsMask)
+
+		gormLogger.Trace(context.Background(), time.Now(), func() (string, int64) {
+			return "SELECT * FROM users WHERE email = 'jane@example.com'", 0
+		}, errors.New("timeout"))
+
+		log := output()
+		assert.Contains(t, log, "SELECT * FROM users WHERE email = '***'")
+		assert.NotContains(t, log, "jane@example.com")
+	})
+
+	t.Run("should mask values bound with the SQLite string quote", func(t *testing.T) {
+		const query = "SELECT * FROM `users` WHERE email = ? AND active = ? AND age > ? AND deleted_at IS ?"
+		vars := []interface{}{"jane@example.com", true, 30, nil}
+
+		for mode, want := range map[SQLParamMode]string{
+			SQLParamsMask:  "SELECT * FROM `users` WHERE email = '***' AND active = '***' AND age > '***' AND deleted_at IS NULL",
+			SQLParamsStrip: "SELECT * FROM `users` WHERE email = ? AND active = ? AND age > ? AND deleted_at IS NULL",
+		} {
+			logger, output := newFileLogger(t)
+			gormLogger := logger.ForGORM().SetSQLParamMode(mode).SetStructuredMode(true)
+
+			// As GORM renders statements, with the escaper of the SQLite dialector
+			gormLogger.Trace(context.Background(), time.Now(), func() (string, int64) {
+				sql, params := gormLogger.ParamsFilter(context.Background(), query, vars...)
+				return gormlogger.ExplainSQL(sql, nil, `"`, params...), 1
+			}, errors.New("timeout"))
+
+			entries := entriesWithMessage(t, output(), sqlQueryMessage)
+			require.Len(t, entries, 1)
+			assert.Equal(t, want, entries[0]["sql"])
+		}
+	})
+
+	t.Run("should keep bound values without a mode", func(t *testing.T) {
+		gormLogger := &GORMLogger{logger: &MockLogger{}}
+
+		sql, params := gormLogger.ParamsFilter(context.Background(), "SELECT ?", "jane@example.com")
+
+		assert.Equal(t, "SELECT ?", sql)
+		assert.Equal(t, []interface{}{"jane@example.com"}, params)
+	})
+}
+
+func TestGORMLogger_SetQueryFingerprint(t *testing.T) {
+	t.Run("should add the fingerprint next to the raw SQL", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+		gormLogger := logger.ForGORM().SetQueryFingerprint(true)
+
+		for _, id := range []string{"1", "2, 3"} {
+			gormLogger.Trace(context.Background(), time.Now().Add(-time.Second), func() (string, int64) {
+				return "SELECT * FROM orders WHERE id IN (" + id + ")", 1
+			}, nil)
+		}
+
+		entries := decodeJSONLines(t, output())
+		require.Len(t, entries, 2)
+		for _, entry := range entries {
+			assert.Equal(t, "SELECT * FROM orders WHERE id IN (?)", entry["sql_fingerprint"])
+			assert.Contains(t, entry["message"], "SELECT * FROM orders WHERE id IN (")
+		}
+	})
+
+	t.Run("should not add the fingerprint by default", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+
+		logger.ForGORM().Trace(context.Background(), time.Now(), func() (string, int64) {
+			return "SELECT 1", 1
+		}, errors.New("timeout"))
+
+		assert.NotContains(t, output(), "sql_fingerprint")
+	})
+}
+
+func TestGORMLogger_SetMaxSQLLength(t *testing.T) {
+	t.Run("should truncate the SQL and flag the entry", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+		gormLogger := logger.ForGORM().SetQueryFingerprint(true).SetMaxSQLLength(32)
+
+		gormLogger.Trace(context.Background(), time.Now(), func() (string, int64) {
+			return "INSERT INTO events (id, payload) VALUES (1, 'a'), (2, 'b'), (3, 'c')", 3
+		}, errors.New("timeout"))
+		gormLogger.Trace(context.Background(), time.Now(), func() (string, int64) {
+			return "SELECT 1", 1
+		}, errors.New("timeout"))
+
+		entries := decodeJSONLines(t, output())
+		require.Len(t, entries, 2)
+		assert.True(t, strings.HasSuffix(entries[0]["message"].(string), "] INSERT INTO events (id, paylo..."))
+		assert.Equal(t, true, entries[0]["sql_truncated"])
+		assert.Equal(t, "INSERT INTO events (id, paylo...", entries[0]["sql_fingerprint"])
+		assert.NotContains(t, entries[1], "sql_truncated")
+		assert.Equal(t, true, gormLogger.SetSlowThreshold(time.Second).fingerprint)
+		assert.Equal(t, 32, gormLogger.SetSQLParamMode(SQLParamsMask).maxSQLLength)
+	})
+}
+
+func TestGORMLogger_SetQueryObserver(t *testing.T) {
+	t.Run("should observe every statement once whatever the level", func(t *testing.T) {
+		var observed []QueryStats
+		calls := 0
+		gormLogger := (&GORMLogger{logger: &MockLogger{}, level: gormlogger.Silent}).
+			SetSlowThreshold(time.Millisecond).
+			SetQueryObserver(func(stats QueryStats) { observed = append(observed, stats) })
+		errTimeout := errors.New("timeout")
+
+		gormLogger.Trace(context.Background(), time.Now().Add(-time.Second), func() (string, int64) {
+			calls++
+			return "UPDATE orders SET paid = true", 2
+		}, errTimeout)
+
+		require.Len(t, observed, 1)
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, "UPDATE", observed[0].Operation)
+		assert.Equal(t, "orders", observed[0].Table)
+		assert.Equal(t, int64(2), observed[0].Rows)
+		assert.True(t, observed[0].Slow)
+		assert.GreaterOrEqual(t, observed[0].Duration, time.Second)
+		assert.ErrorIs(t, observed[0].Err, errTimeout)
+	})
+
+	t.Run("should render the SQL once for the observer and the entry", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+		calls := 0
+		var table string
+		gormLogger := logger.ForGORM().SetQueryObserver(func(stats QueryStats) { table = stats.Table })
+
+		gormLogger.Trace(context.Background(), time.Now(), func() (string, int64) {
+			calls++
+			return "SELECT * FROM invoices", 0
+		}, errors.New("timeout"))
+
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, "invoices", table)
+		assert.Contains(t, output(), "SELECT * FROM invoices")
+		assert.NotNil(t, gormLogger.SetMaxSQLLength(10).queryObserver)
+	})
+}
+
+func TestGORMLogger_SetStructuredMode(t *testing.T) {
+	t.Run("should log the statement as fields with a stable message", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+		gormLogger := logger.ForGORM().SetStructuredMode(true).SetSlowThreshold(time.Millisecond)
+
+		gormLogger.Trace(context.Background(), time.Now().Add(-1500*time.Millisecond), func() (string, int64) {
+			return "SELECT * FROM orders", 4
+		}, nil)
+		gormLogger.Trace(context.Background(), time.Now(), func() (string, int64) {
+			return "SELECT * FROM users", -1
+		}, errors.New("timeout"))
+
+		entries := entriesWithMessage(t, output(), "sql query")
+		require.Len(t, entries, 2)
+		assert.Equal(t, "SELECT * FROM orders", entries[0]["sql"])
+		assert.GreaterOrEqual(t, entries[0]["duration_ms"], 1500.0)
+		assert.Equal(t, 4.0, entries[0]["rows"])
+		assert.Equal(t, true, entries[0]["is_slow"])
+		assert.NotContains(t, entries[0], "rows_affected")
+		assert.Equal(t, "SELECT * FROM users", entries[1]["sql"])
+		assert.NotContains(t, entries[1], "rows")
+		assert.Equal(t, "timeout", entries[1]["error"])
+		assert.True(t, gormLogger.SetQueryObserver(nil).structured)
+	})
+}
+
+func TestNewGORMLoggerWithLevel(t *testing.T) {
+	t.Run("should log every statement at info level", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+		logger.SetLevel(zapcore.InfoLevel)
+		gormLogger := NewGORMLoggerWithLevel(logger, gormlogger.Info)
+
+		gormLogger.Trace(context.Background(), time.Now(), func() (string, int64) {
+			return "SELECT * FROM products", 3
+		}, nil)
+		logger.ForGORM().LogMode(gormlogger.Info).Trace(context.Background(), time.Now(), func() (string, int64) {
+			return "SELECT * FROM carts", 3
+		}, nil)
+
+		log := output()
+		entries := decodeJSONLines(t, log)
+		require.Len(t, entries, 1)
+		assert.Equal(t, "info", entries[0]["level"])
+		assert.Contains(t, entries[0]["message"], "SELECT * FROM products")
+		assert.Equal(t, "gorm", entries[0]["component"])
+		assert.Equal(t, gormlogger.Info, gormLogger.level)
+		assert.True(t, gormLogger.SetSlowThreshold(time.Second).infoQueries)
+	})
+
+	t.Run("should keep other levels of the logger", func(t *testing.T) {
+		logger, _ := newFileLogger(t)
+		logger.SetLevel(zapcore.DebugLevel)
+
+		assert.Equal(t, gormlogger.Error, NewGORMLoggerWithLevel(logger, gormlogger.Error).level)
+	})
+}
+
+func TestZapLogger_GORMLevel(t *testing.T) {
+	t.Run("should apply the configured GORM level to every GORM logger", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "app.log")
+		logger, err := NewZapLogger(NewLoggerConfig(
+			WithOutputPaths(path),
+			WithLevel(zapcore.InfoLevel),
+			WithGORMLevel(gormlogger.Info),
+		))
+		require.NoError(t, err)
+
+		for _, gormLogger := range []*GORMLogger{logger.ForGORM(), logger.ForGORMNamed("orders-db")} {
+			assert.Equal(t, gormlogger.Info, gormLogger.level)
+			gormLogger.Trace(context.Background(), time.Now(), func() (string, int64) {
+				return "SELECT * FROM orders", 1
+			}, nil)
+		}
+		require.NoError(t, logger.Close(t.Context()))
+
+		entries := decodeJSONLines(t, readFile(t, path))
+		require.Len(t, entries, 2)
+		assert.Equal(t, "gorm", entries[0]["component"])
+		assert.Equal(t, "orders-db", entries[1]["component"])
+	})
+
+	t.Run("should map the GORM level from the logger level by default", func(t *testing.T) {
+		logger, _ := newFileLogger(t)
+
+		assert.Equal(t, gormlogger.Warn, logger.ForGORM().level)
+		assert.False(t, logger.ForGORM().infoQueries)
+	})
+}
+
+func TestGORMLogger_SetIgnoredErrors(t *testing.T) {
+	errDuplicated := errors.New("duplicated key not allowed")
+
+	t.Run("should not log ignored errors as errors", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+		gormLogger := logger.ForGORM().SetIgnoredErrors(errDuplicated)
+
+		gormLogger.Trace(context.Background(), time.Now(), func() (string, int64) {
+			return "INSERT INTO users (email) VALUES ('a@example.com')", 0
+		}, fmt.Errorf("upsert: %w", errDuplicated))
+		gormLogger.Trace(context.Background(), time.Now(), func() (string, int64) {
+			return "UPDATE users SET name = 'b'", 0
+		}, &sqlStateError{"40P01", "deadlock detected"})
+
+		entries := decodeJSONLines(t, output())
+		require.Len(t, entries, 1)
+		assert.Equal(t, "error", entries[0]["level"])
+		assert.Equal(t, "deadlock detected", entries[0]["error"])
+		assert.Equal(t, "deadlock", entries[0]["error_class"])
+	})
+
+	t.Run("should log ignored errors of slow statements as slow", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+		gormLogger := logger.ForGORM().SetSlowThreshold(time.Millisecond).SetIgnoredErrors(errDuplicated)
+
+		gormLogger.Trace(context.Background(), time.Now().Add(-time.Second), func() (string, int64) {
+			return "INSERT INTO users (email) VALUES ('a@example.com')", 0
+		}, errDuplicated)
+
+		entries := decodeJSONLines(t, output())
+		require.Len(t, entries, 1)
+		assert.Equal(t, "warn", entries[0]["level"])
+		assert.NotContains(t, entries[0], "error")
+		assert.Equal(t, []error{errDuplicated}, gormLogger.SetMaxSQLLength(10).ignoredErrors)
+	})
+
+	t.Run("should omit the class of unclassified errors", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+
+		logger.ForGORM().Trace(context.Background(), time.Now(), func() (string, int64) {
+			return "SELECT 1", 0
+		}, errors.New("connection refused"))
+
+		entries := decodeJSONLines(t, output())
+		require.Len(t, entries, 1)
+		assert.NotContains(t, entries[0], "error_class")
+	})
+}
@@ -0,0 +1,123 @@
+package xlogger
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	logrNameFieldKey      = "name"
+	logrVerbosityFieldKey = "v"
+	logrExtraValueKey     = "extra_value"
+)
+
+// logrSink wraps our logger.Logger to implement logr.LogSink
+type logrSink struct {
+	logger Logger
+	name   string
+}
+
+// NewLogrSink creates a logr.LogSink from the provided logger.
+// V(0) maps to Info and V(1) and above map to Debug.
+func NewLogrSink(logger Logger) logr.LogSink {
+	return &logrSink{logger: logger}
+}
+
+// ForLogr returns a logr.LogSink for client-go, controller-runtime and other
+// logr-based libraries
+func (l *ZapLogger) ForLogr() logr.LogSink {
+	return NewLogrSink(l.ForInfra("logr"))
+}
+
+// Init implements logr.LogSink
+func (s *logrSink) Init(logr.RuntimeInfo) {}
+
+// Enabled implements logr.LogSink
+func (s *logrSink) Enabled(level int) bool {
+	return s.logger.Level().Enabled(logrLevel(level))
+}
+
+// Info implements logr.LogSink
+func (s *logrSink) Info(level int, msg string, keysAndValues ...interface{}) {
+	fields := s.fields(keysAndValues)
+	if level > 0 {
+		fields = append(fields, Int(logrVerbosityFieldKey, level))
+	}
+
+	if logrLevel(level) == zapcore.DebugLevel {
+		s.logger.Debug(msg, fields...)
+		return
+	}
+	s.logger.Info(msg, fields...)
+}
+
+// Error implements logr.LogSink
+func (s *logrSink) Error(err error, msg string, keysAndValues ...interface{}) {
+	fields := s.fields(keysAndValues)
+	if err != nil {
+		fields = append(fields, Error(err))
+	}
+	s.logger.Error(msg, fields...)
+}
+
+// WithValues implements logr.LogSink
+func (s *logrSink) WithValues(keysAndValues ...interface{}) logr.LogSink {
+	return &logrSink{
+		logger: s.logger.With(convertKeysAndValues(keysAndValues)...),
+		name:   s.name,
+	}
+}
+
+// WithName implements logr.LogSink; names are joined with "/"
+func (s *logrSink) WithName(name string) logr.LogSink {
+	if s.name != "" {
+		name = s.name + "/" + name
+	}
+	return &logrSink{logger: s.logger, name: name}
+}
+
+func (s *logrSink) fields(keysAndValues []interface{}) []Field {
+	fields := convertKeysAndValues(keysAndValues)
+	if s.name != "" {
+		fields = append(fields, String(logrNameFieldKey, s.name))
+	}
+	return fields
+}
+
+// logrLevel maps logr verbosity to a zap level
+func logrLevel(level int) zapcore.Level {
+	if level > 0 {
+		return zapcore.DebugLevel
+	}
+	return zapcore.InfoLevel
+}
+
+// convertKeysAndValues converts logr key/value pairs into fields.
+// Non-string keys are formatted and a trailing value without key is kept.
+func convertKeysAndValues(keysAndValues []interface{}) []Field {
+	if len(keysAndValues) == 0 {
+		return nil
+	}
+
+	fields := make([]Field, 0, (len(keysAndValues)+1)/2)
+	for i := 0; i < len(keysAndValues); i += 2 {
+		if i+1 == len(keysAndValues) {
+			fields = append(fields, Any(logrExtraValueKey, keysAndValues[i]))
+			break
+		}
+
+		key, ok := keysAndValues[i].(string)
+		if !ok {
+			key = strings.TrimSpace(fmt.Sprint(keysAndValues[i]))
+		}
+		if err, ok := keysAndValues[i+1].(error); ok {
+			fields = append(fields, NamedError(key, err))
+			continue
+		}
+		fields = append(fields, Any(key, keysAndValues[i+1]))
+	}
+	return fields
+}
@@ -0,0 +1,85 @@
+package xlogger
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+)
+
+// TestLogrSink tests the logr.LogSink adapter
+func TestLogrSink(t *testing.T) {
+	t.Run("should log info with key values", func(t *testing.T) {
+		logger, output := newFileLogger(t, WithLevel(zapcore.InfoLevel))
+		log := logr.New(logger.ForLogr())
+
+		log.Info("reconciled", "namespace", "default", "replicas", 3)
+
+		out := output()
+		assert.Contains(t, out, `"level":"info"`)
+		assert.Contains(t, out, `"message":"reconciled"`)
+		assert.Contains(t, out, `"namespace":"default"`)
+		assert.Contains(t, out, `"replicas":3`)
+		assert.Contains(t, out, `"component":"logr"`)
+	})
+
+	t.Run("should map verbosity to debug", func(t *testing.T) {
+		logger, output := newFileLogger(t, WithLevel(zapcore.DebugLevel))
+		log := logr.New(logger.ForLogr())
+
+		log.V(2).Info("verbose")
+
+		out := output()
+		assert.Contains(t, out, `"level":"debug"`)
+		assert.Contains(t, out, `"v":2`)
+	})
+
+	t.Run("should honor logger level", func(t *testing.T) {
+		logger, output := newFileLogger(t, WithLevel(zapcore.InfoLevel))
+		log := logr.New(logger.ForLogr())
+
+		assert.True(t, log.Enabled())
+		assert.False(t, log.V(1).Enabled())
+
+		log.V(1).Info("hidden")
+		assert.NotContains(t, output(), "hidden")
+	})
+
+	t.Run("should log errors", func(t *testing.T) {
+		logger, output := newFileLogger(t, WithLevel(zapcore.InfoLevel))
+		log := logr.New(logger.ForLogr())
+
+		log.Error(errors.New("conflict"), "update failed", "cause", errors.New("stale"))
+
+		out := output()
+		assert.Contains(t, out, `"level":"error"`)
+		assert.Contains(t, out, `"error":"conflict"`)
+		assert.Contains(t, out, `"cause":"stale"`)
+	})
+
+	t.Run("should keep values and names", func(t *testing.T) {
+		logger, output := newFileLogger(t, WithLevel(zapcore.InfoLevel))
+		log := logr.New(logger.ForLogr()).WithName("controller").WithName("pods").WithValues("pod", "web-0")
+
+		log.Info("synced")
+
+		out := output()
+		assert.Contains(t, out, `"name":"controller/pods"`)
+		assert.Contains(t, out, `"pod":"web-0"`)
+	})
+
+	t.Run("should handle malformed key values", func(t *testing.T) {
+		logger, output := newFileLogger(t, WithLevel(zapcore.InfoLevel))
+		log := logr.New(logger.ForLogr())
+
+		log.Info("odd", 42, "answer", "dangling")
+
+		out := output()
+		assert.Contains(t, out, `"42":"answer"`)
+		assert.Contains(t, out, `"extra_value":"dangling"`)
+		assert.Equal(t, 1, strings.Count(out, "\n"))
+	})
+}
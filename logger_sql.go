@@ -0,0 +1,208 @@
+package xlogger
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// entQueryPrefix starts the statement in messages of ent's debug driver,
+// such as "driver.Query: query=SELECT ... args=[1]"
+const entQueryPrefix = "query="
+
+// entArgsRegex matches the bound arguments ent's debug driver appends to
+// its messages
+var entArgsRegex = regexp.MustCompile(` args=\[.*\]$`)
+
+// DBTX is the database handle of code generated by sqlc, implemented by
+// *sql.DB, *sql.Conn and *sql.Tx.
+type DBTX interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// QueryLogger logs the statements of database/sql based libraries such as
+// sqlc and ent like a structured GORMLogger: "sql query" entries with the
+// sql, duration_ms, rows and file fields, at Error with the error_class of failed
+// statements, at Warn for slow ones and at Debug otherwise, carrying the
+// trace fields of the statement context. With SQLParamsMask or
+// SQLParamsStrip the bound arguments are left out.
+type QueryLogger struct {
+	logger        Logger
+	slowThreshold time.Duration
+	paramMode     SQLParamMode
+	maxSQLLength  int
+}
+
+// NewQueryLogger creates a statement logger on logger.ForInfra("sql"), so
+// SetComponentLevel("sql", level) sets its level like ForGORM.
+//
+// Example:
+//
+//	queries := db.New(xlogger.NewQueryLogger(logger).WrapDBTX(sqlDB)) // sqlc
+func NewQueryLogger(logger Logger) *QueryLogger {
+	return &QueryLogger{
+		logger:        logger.ForInfra("sql"),
+		slowThreshold: 500 * time.Millisecond,
+	}
+}
+
+// SetSlowThreshold configures slow query threshold (0 to disable)
+func (q *QueryLogger) SetSlowThreshold(threshold time.Duration) *QueryLogger {
+	clone := *q
+	clone.slowThreshold = threshold
+	return &clone
+}
+
+// SetSQLParamMode configures how values in the SQL are logged, like
+// GORMLogger.SetSQLParamMode. Bound arguments are only logged with
+// SQLParamsKeep.
+func (q *QueryLogger) SetSQLParamMode(mode SQLParamMode) *QueryLogger {
+	clone := *q
+	clone.paramMode = mode
+	return &clone
+}
+
+// SetMaxSQLLength configures the maximum length in bytes of the logged SQL,
+// like GORMLogger.SetMaxSQLLength.
+func (q *QueryLogger) SetMaxSQLLength(n int) *QueryLogger {
+	clone := *q
+	clone.maxSQLLength = n
+	return &clone
+}
+
+// LogQuery logs a statement that started at begin, for drivers and
+// libraries without a dedicated adapter. rows is the number of rows
+// affected, -1 when unknown.
+func (q *QueryLogger) LogQuery(ctx context.Context, begin time.Time, query string, args []interface{}, rows int64, err error) {
+	q.logQuery(ctx, begin, query, args, rows, err)
+}
+
+// logQuery logs a statement, two frames below the caller of the adapter
+func (q *QueryLogger) logQuery(ctx context.Context, begin time.Time, query string, args []interface{}, rows int64, err error) {
+	duration := time.Since(begin)
+	slow := q.slowThreshold != 0 && duration > q.slowThreshold
+	if err == nil && !slow && !q.logger.Enabled(zapcore.DebugLevel) {
+		return
+	}
+
+	cleanQuery, truncated := truncateSQL(cleanSQL(q.paramMode.redactSQL(query)), q.maxSQLLength)
+	fields := []Field{
+		String("sql", cleanQuery),
+		Float64("duration_ms", float64(duration)/float64(time.Millisecond)),
+	}
+	if rows != -1 {
+		fields = append(fields, Int64("rows", rows))
+	}
+	if len(args) > 0 && q.paramMode == SQLParamsKeep {
+		fields = append(fields, Any("args", args))
+	}
+	if truncated {
+		fields = append(fields, Bool(sqlTruncatedKey, true))
+	}
+	// Skips logQuery and the DBTX method or LogQuery
+	if _, file, line, ok := runtime.Caller(2); ok {
+		fields = append(fields, String("file", zapcore.EntryCaller{Defined: true, File: file, Line: line}.TrimmedPath()))
+	}
+
+	logger := statementLogger(q.logger, ctx)
+	switch {
+	case err != nil:
+		fields = append(fields, Error(err))
+		if class := ClassifySQLError(err); class != "" {
+			fields = append(fields, String(sqlErrorClassKey, string(class)))
+		}
+		logger.Error(sqlQueryMessage, fields...)
+	case slow:
+		logger.Warn(sqlQueryMessage, append(fields, Duration("slow_threshold", q.slowThreshold), Bool("is_slow", true))...)
+	default:
+		logger.Debug(sqlQueryMessage, fields...)
+	}
+}
+
+// WrapDBTX returns db logging every statement, for the New function of
+// sqlc generated code. Statements prepared with PrepareContext run without
+// logging.
+//
+// Example:
+//
+//	queryLogger := xlogger.NewQueryLogger(logger).SetSQLParamMode(xlogger.SQLParamsStrip)
+//	queries := db.New(queryLogger.WrapDBTX(sqlDB))
+//	user, err := queries.GetUser(ctx, id)
+func (q *QueryLogger) WrapDBTX(db DBTX) DBTX {
+	return &loggedDBTX{db: db, logger: q}
+}
+
+// EntLogFunc returns the logger of ent's debug driver, logging each
+// statement message at Debug with the trace fields of its context. ent
+// logs statements before running them, so entries carry no duration and
+// slow statements are not detected; the arguments are left out unless the
+// mode is SQLParamsKeep.
+//
+// Example:
+//
+//	drv := dialect.DebugWithContext(entsql.OpenDB(dialect.Postgres, sqlDB), queryLogger.EntLogFunc())
+//	client := ent.NewClient(ent.Driver(drv))
+func (q *QueryLogger) EntLogFunc() func(ctx context.Context, v ...interface{}) {
+	logger := q.logger
+	return func(ctx context.Context, v ...interface{}) {
+		if !logger.Enabled(zapcore.DebugLevel) {
+			return
+		}
+		msg := fmt.Sprint(v...)
+		if i := strings.Index(msg, entQueryPrefix); i >= 0 && q.paramMode != SQLParamsKeep {
+			msg = msg[:i] + q.paramMode.redactSQL(entArgsRegex.ReplaceAllString(msg[i:], ""))
+		}
+		msg, _ = truncateSQL(msg, q.maxSQLLength)
+		statementLogger(logger, ctx).Debug(msg)
+	}
+}
+
+// loggedDBTX is a DBTX logging its statements
+type loggedDBTX struct {
+	db     DBTX
+	logger *QueryLogger
+}
+
+// ExecContext implements DBTX
+func (d *loggedDBTX) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	begin := time.Now()
+	result, err := d.db.ExecContext(ctx, query, args...)
+	rows := int64(-1)
+	if err == nil {
+		if affected, rowsErr := result.RowsAffected(); rowsErr == nil {
+			rows = affected
+		}
+	}
+	d.logger.logQuery(ctx, begin, query, args, rows, err)
+	return result, err
+}
+
+// PrepareContext implements DBTX
+func (d *loggedDBTX) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return d.db.PrepareContext(ctx, query)
+}
+
+// QueryContext implements DBTX
+func (d *loggedDBTX) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	begin := time.Now()
+	rows, err := d.db.QueryContext(ctx, query, args...)
+	d.logger.logQuery(ctx, begin, query, args, -1, err)
+	return rows, err
+}
+
+// QueryRowContext implements DBTX
+func (d *loggedDBTX) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	begin := time.Now()
+	row := d.db.QueryRowContext(ctx, query, args...)
+	d.logger.logQuery(ctx, begin, query, args, -1, row.Err())
+	return row
+}
@@ -0,0 +1,176 @@
+package xlogger
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+// fakeDBTX is a DBTX recording statements and returning err
+type fakeDBTX struct {
+	queries []string
+	delay   time.Duration
+	err     error
+}
+
+// fakeResult is the sql.Result of fakeDBTX
+type fakeResult int64
+
+func (r fakeResult) LastInsertId() (int64, error) { return 0, nil }
+func (r fakeResult) RowsAffected() (int64, error) { return int64(r), nil }
+
+func (f *fakeDBTX) ExecContext(_ context.Context, query string, _ ...interface{}) (sql.Result, error) {
+	f.queries = append(f.queries, query)
+	time.Sleep(f.delay)
+	return fakeResult(3), f.err
+}
+
+func (f *fakeDBTX) PrepareContext(_ context.Context, query string) (*sql.Stmt, error) {
+	f.queries = append(f.queries, query)
+	return nil, f.err
+}
+
+func (f *fakeDBTX) QueryContext(_ context.Context, query string, _ ...interface{}) (*sql.Rows, error) {
+	f.queries = append(f.queries, query)
+	return nil, f.err
+}
+
+func (f *fakeDBTX) QueryRowContext(_ context.Context, query string, _ ...interface{}) *sql.Row {
+	f.queries = append(f.queries, query)
+	return &sql.Row{}
+}
+
+// TestQueryLogger_WrapDBTX tests logging the statements of sqlc handles
+func TestQueryLogger_WrapDBTX(t *testing.T) {
+	t.Run("should log statements with their context and caller", func(t *testing.T) {
+		logger, output := newFileLogger(t, WithLevel(zapcore.DebugLevel))
+		db := &fakeDBTX{}
+		dbtx := NewQueryLogger(logger).WrapDBTX(db)
+		ctx := ContextWithTrace(context.Background(), "req-sql", "")
+
+		_, err := dbtx.ExecContext(ctx, "UPDATE users\n  SET name = $1", "jane")
+		require.NoError(t, err)
+		_, _ = dbtx.QueryContext(ctx, "SELECT * FROM users WHERE id = $1", 1)
+		_ = dbtx.QueryRowContext(ctx, "SELECT name FROM users WHERE id = $1", 1)
+		_, _ = dbtx.PrepareContext(ctx, "SELECT 1")
+
+		assert.Len(t, db.queries, 4)
+		entries := entriesWithMessage(t, output(), "sql query")
+		require.Len(t, entries, 3)
+		assert.Equal(t, "debug", entries[0]["level"])
+		assert.Equal(t, "UPDATE users SET name = $1", entries[0]["sql"])
+		assert.Equal(t, 3.0, entries[0]["rows"])
+		assert.Equal(t, []interface{}{"jane"}, entries[0]["args"])
+		assert.Equal(t, "sql", entries[0]["component"])
+		assert.Equal(t, "req-sql", entries[0]["request_id"])
+		assert.Contains(t, entries[0]["file"], "logger_sql_test.go")
+		assert.NotContains(t, entries[1], "rows")
+		assert.Equal(t, "SELECT name FROM users WHERE id = $1", entries[2]["sql"])
+	})
+
+	t.Run("should log failed and slow statements", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+		queryLogger := NewQueryLogger(logger).SetSlowThreshold(time.Millisecond)
+
+		_, _ = queryLogger.WrapDBTX(&fakeDBTX{err: &sqlStateError{"23505", "duplicate key"}}).
+			ExecContext(context.Background(), "INSERT INTO users (email) VALUES ($1)", "a@example.com")
+		_, _ = queryLogger.WrapDBTX(&fakeDBTX{delay: 5 * time.Millisecond}).
+			ExecContext(context.Background(), "DELETE FROM sessions")
+		_, _ = queryLogger.WrapDBTX(&fakeDBTX{}).QueryContext(context.Background(), "SELECT 1")
+
+		entries := entriesWithMessage(t, output(), "sql query")
+		require.Len(t, entries, 2)
+		assert.Equal(t, "error", entries[0]["level"])
+		assert.Equal(t, "duplicate key", entries[0]["error"])
+		assert.Equal(t, "constraint_violation", entries[0]["error_class"])
+		assert.Equal(t, "warn", entries[1]["level"])
+		assert.Equal(t, true, entries[1]["is_slow"])
+		assert.GreaterOrEqual(t, entries[1]["duration_ms"], 5.0)
+	})
+
+	t.Run("should leave out values unless kept", func(t *testing.T) {
+		logger, output := newFileLogger(t, WithLevel(zapcore.DebugLevel))
+		dbtx := NewQueryLogger(logger).SetSQLParamMode(SQLParamsStrip).SetMaxSQLLength(40).WrapDBTX(&fakeDBTX{})
+
+		_, _ = dbtx.QueryContext(context.Background(), "SELECT * FROM users WHERE email = 'jane@example.com' AND id = $1", 7)
+
+		entries := entriesWithMessage(t, output(), "sql query")
+		require.Len(t, entries, 1)
+		assert.Equal(t, "SELECT * FROM users WHERE email = ? A...", entries[0]["sql"])
+		assert.Equal(t, true, entries[0]["sql_truncated"])
+		assert.NotContains(t, entries[0], "args")
+	})
+}
+
+// TestQueryLogger_LogQuery tests logging statements of other libraries
+func TestQueryLogger_LogQuery(t *testing.T) {
+	t.Run("should log the file of the caller", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+
+		NewQueryLogger(logger).LogQuery(context.Background(), time.Now(), "SELECT 1", nil, -1, errors.New("timeout"))
+
+		entries := entriesWithMessage(t, output(), "sql query")
+		require.Len(t, entries, 1)
+		assert.Contains(t, entries[0]["file"], "logger_sql_test.go")
+		assert.Equal(t, "timeout", entries[0]["error_class"])
+	})
+
+	t.Run("should follow the sql component level", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+		logger.SetComponentLevel("sql", zapcore.DebugLevel)
+
+		NewQueryLogger(logger).LogQuery(context.Background(), time.Now(), "SELECT 1", nil, -1, nil)
+		logger.SetComponentLevel("sql", zapcore.ErrorLevel)
+		NewQueryLogger(logger).LogQuery(context.Background(), time.Now(), "SELECT 2", nil, -1, nil)
+
+		entries := entriesWithMessage(t, output(), "sql query")
+		require.Len(t, entries, 1)
+		assert.Equal(t, "SELECT 1", entries[0]["sql"])
+		assert.Equal(t, "sql", entries[0]["component"])
+	})
+}
+
+// TestQueryLogger_EntLogFunc tests the logger of ent's debug driver
+func TestQueryLogger_EntLogFunc(t *testing.T) {
+	t.Run("should log messages with the trace fields of their context", func(t *testing.T) {
+		logger, output := newFileLogger(t, WithLevel(zapcore.DebugLevel))
+		logFunc := NewQueryLogger(logger).EntLogFunc()
+		ctx := ContextWithTrace(context.Background(), "req-ent", "")
+
+		logFunc(ctx, "driver.Query: query=SELECT * FROM users WHERE id = $1 args=[42]")
+		logFunc(context.Background(), "driver.Exec: query=DELETE FROM sessions args=[]")
+
+		log := output()
+		entries := entriesWithMessage(t, log, "driver.Query: query=SELECT * FROM users WHERE id = $1 args=[42]")
+		require.Len(t, entries, 1)
+		assert.Equal(t, "req-ent", entries[0]["request_id"])
+		assert.Equal(t, "sql", entries[0]["component"])
+		assert.Len(t, entriesWithMessage(t, log, "driver.Exec: query=DELETE FROM sessions args=[]"), 1)
+	})
+
+	t.Run("should leave out arguments and values unless kept", func(t *testing.T) {
+		logger, output := newFileLogger(t, WithLevel(zapcore.DebugLevel))
+		logFunc := NewQueryLogger(logger).SetSQLParamMode(SQLParamsMask).EntLogFunc()
+
+		logFunc(context.Background(), "Tx(1f0c).Exec: query=UPDATE users SET email = 'a@example.com' WHERE id = $1 args=[42]")
+
+		log := output()
+		assert.Len(t, entriesWithMessage(t, log, "Tx(1f0c).Exec: query=UPDATE users SET email = '***' WHERE id = $1"), 1)
+		assert.NotContains(t, log, "a@example.com")
+		assert.NotContains(t, log, "args=")
+	})
+
+	t.Run("should skip messages below the logger level", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+
+		NewQueryLogger(logger).EntLogFunc()(context.Background(), "driver.Query: query=SELECT 1 args=[]")
+
+		assert.Empty(t, output())
+	})
+}
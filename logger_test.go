@@ -1,11 +1,18 @@
 package xlogger
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 // TestField_Constructors tests all field constructor functions
@@ -18,6 +25,14 @@ func TestField_Constructors(t *testing.T) {
 		assert.Equal(t, StringType, field.Type())
 	})
 
+	t.Run("should create tags field", func(t *testing.T) {
+		field := Tags("retryable", "user-facing")
+
+		assert.Equal(t, "tags", field.Key())
+		assert.Equal(t, []string{"retryable", "user-facing"}, field.Value())
+		assert.Equal(t, TagsType, field.Type())
+	})
+
 	t.Run("should create int field", func(t *testing.T) {
 		field := Int("count", 42)
 
@@ -95,6 +110,47 @@ func TestField_Constructors(t *testing.T) {
 	})
 }
 
+// TestField_TypedConstructors tests constructors converted without reflection
+func TestField_TypedConstructors(t *testing.T) {
+	name, count := "jane", 3
+	tests := []struct {
+		field    Field
+		zapType  zapcore.FieldType
+		typ      FieldType
+		expected interface{}
+	}{
+		{Uint("u", 7), zapcore.Uint64Type, IntType, float64(7)},
+		{Uint64("u64", 1<<40), zapcore.Uint64Type, IntType, float64(1 << 40)},
+		{Int32("i32", -5), zapcore.Int32Type, IntType, float64(-5)},
+		{Float32("f32", 1.5), zapcore.Float32Type, Float64Type, 1.5},
+		{ByteString("body", []byte("payload")), zapcore.ByteStringType, StringType, "payload"},
+		{Strings("names", []string{"a", "b"}), zapcore.ArrayMarshalerType, AnyType, []interface{}{"a", "b"}},
+		{Ints("ids", []int{1, 2}), zapcore.ArrayMarshalerType, AnyType, []interface{}{float64(1), float64(2)}},
+		{Durations("waits", []time.Duration{time.Second}), zapcore.ArrayMarshalerType, AnyType, []interface{}{"1s"}},
+		{Stringp("name", &name), zapcore.StringType, StringType, "jane"},
+		{Stringp("nil_name", nil), zapcore.ReflectType, StringType, nil},
+		{Intp("count", &count), zapcore.Int64Type, IntType, float64(3)},
+		{Intp("nil_count", nil), zapcore.ReflectType, IntType, nil},
+	}
+
+	logger, output := newFileLogger(t)
+	fields := make([]Field, len(tests))
+	for i, tt := range tests {
+		fields[i] = tt.field
+	}
+	logger.Info("typed fields", fields...)
+	entries := entriesWithMessage(t, output(), "typed fields")
+	require.Len(t, entries, 1)
+
+	for _, tt := range tests {
+		t.Run("should convert "+tt.field.Key(), func(t *testing.T) {
+			assert.Equal(t, tt.typ, tt.field.Type())
+			assert.Equal(t, tt.zapType, toZapField(tt.field).Type)
+			assert.Equal(t, tt.expected, entries[0][tt.field.Key()])
+		})
+	}
+}
+
 // TestField_EdgeCases tests edge cases for field constructors
 func TestField_EdgeCases(t *testing.T) {
 	t.Run("should handle empty string key", func(t *testing.T) {
@@ -170,6 +226,8 @@ func TestFieldType_Constants(t *testing.T) {
 			DurationType,
 			TimeType,
 			AnyType,
+			TagsType,
+			ZapType,
 		}
 
 		// Check that all types are unique
@@ -188,6 +246,307 @@ func TestFieldType_Constants(t *testing.T) {
 		assert.Equal(t, FieldType(5), DurationType)
 		assert.Equal(t, FieldType(6), TimeType)
 		assert.Equal(t, FieldType(7), AnyType)
+		assert.Equal(t, FieldType(8), TagsType)
+		assert.Equal(t, FieldType(9), ZapType)
+	})
+}
+
+// TestZapFields tests wrapping zap fields
+func TestZapFields(t *testing.T) {
+	t.Run("should wrap a zap field", func(t *testing.T) {
+		field := ZapField(zap.Uint32("port", 8080))
+
+		assert.Equal(t, "port", field.Key())
+		assert.Equal(t, zap.Uint32("port", 8080), field.Value())
+		assert.Equal(t, ZapType, field.Type())
+	})
+
+	t.Run("should wrap zap fields in order", func(t *testing.T) {
+		fields := ZapFields(zap.String("a", "1"), zap.Int("b", 2))
+
+		require.Len(t, fields, 2)
+		assert.Equal(t, "a", fields[0].Key())
+		assert.Equal(t, "b", fields[1].Key())
+		assert.Nil(t, ZapFields())
+	})
+}
+
+// TestSecret tests fields and values that always log masked
+func TestSecret(t *testing.T) {
+	t.Run("should mask secret fields", func(t *testing.T) {
+		field := Secret("password", "hunter2")
+
+		assert.Equal(t, "password", field.Key())
+		assert.Equal(t, "***", field.Value())
+		assert.Equal(t, StringType, field.Type())
+	})
+
+	t.Run("should add a stable fingerprint", func(t *testing.T) {
+		field := SecretFingerprint("api_key", "test")
+
+		assert.Equal(t, "***sha256:9f86d081884c7d65", field.Value())
+		assert.NotEqual(t, field.Value(), SecretFingerprint("api_key", "other").Value())
+	})
+
+	t.Run("should format secret strings masked", func(t *testing.T) {
+		type dbConfig struct {
+			User     string
+			Password SecretString
+		}
+		cfg := dbConfig{User: "app", Password: "hunter2"}
+
+		data, err := json.Marshal(cfg)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"User":"app","Password":"***"}`, string(data))
+		assert.Equal(t, "{app ***}", fmt.Sprint(cfg))
+		assert.NotContains(t, fmt.Sprintf("%#v", cfg), "hunter2")
+		assert.Equal(t, "hunter2", string(cfg.Password))
+	})
+
+	t.Run("should log secret strings masked with Any", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+
+		logger.Info("connecting", Any("password", SecretString("hunter2")))
+
+		assert.NotContains(t, output(), "hunter2")
+		entries := entriesWithMessage(t, output(), "connecting")
+		require.Len(t, entries, 1)
+		assert.Equal(t, "***", entries[0]["password"])
+	})
+}
+
+// testUser logs itself with zapcore marshalers
+type testUser struct {
+	ID  string
+	Age int
+}
+
+func (u testUser) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("id", u.ID)
+	enc.AddInt("age", u.Age)
+	return nil
+}
+
+// testUsers logs as an array of objects
+type testUsers []testUser
+
+func (us testUsers) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, u := range us {
+		if err := enc.AppendObject(u); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// testOrder logs itself with xlogger fields
+type testOrder struct {
+	ID    string
+	Items int
+	User  testUser
+}
+
+func (o testOrder) MarshalLogFields() []Field {
+	return []Field{String("id", o.ID), Int("items", o.Items), Object("user", o.User)}
+}
+
+// TestObjectFields tests nested fields encoded by marshalers
+func TestObjectFields(t *testing.T) {
+	t.Run("should encode objects and arrays", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+
+		logger.Info("users loaded",
+			Object("user", testUser{ID: "u1", Age: 30}),
+			Array("users", testUsers{{ID: "u1", Age: 30}, {ID: "u2", Age: 41}}),
+		)
+
+		entries := entriesWithMessage(t, output(), "users loaded")
+		require.Len(t, entries, 1)
+		assert.Equal(t, map[string]interface{}{"id": "u1", "age": float64(30)}, entries[0]["user"])
+		assert.Equal(t, []interface{}{
+			map[string]interface{}{"id": "u1", "age": float64(30)},
+			map[string]interface{}{"id": "u2", "age": float64(41)},
+		}, entries[0]["users"])
+	})
+
+	t.Run("should encode field marshalers with Nested and Any", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+		order := testOrder{ID: "o1", Items: 2, User: testUser{ID: "u1", Age: 30}}
+
+		logger.Info("order placed", Nested("order", order), Any("copy", order), Nested("none", nil))
+
+		entries := entriesWithMessage(t, output(), "order placed")
+		require.Len(t, entries, 1)
+		want := map[string]interface{}{
+			"id":    "o1",
+			"items": float64(2),
+			"user":  map[string]interface{}{"id": "u1", "age": float64(30)},
+		}
+		assert.Equal(t, want, entries[0]["order"])
+		assert.Equal(t, want, entries[0]["copy"])
+		assert.Nil(t, entries[0]["none"])
+	})
+}
+
+// TestDict tests one-off nested groups
+func TestDict(t *testing.T) {
+	t.Run("should encode fields as a nested object", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+
+		logger.Info("request served",
+			Dict("http",
+				String("method", "GET"),
+				Int("status", 200),
+				Dict("client", String("ip", "10.0.0.1")),
+			),
+			Dict("empty"),
+			String("route", "/orders"),
+		)
+
+		entries := entriesWithMessage(t, output(), "request served")
+		require.Len(t, entries, 1)
+		assert.Equal(t, map[string]interface{}{
+			"method": "GET",
+			"status": float64(200),
+			"client": map[string]interface{}{"ip": "10.0.0.1"},
+		}, entries[0]["http"])
+		assert.Equal(t, map[string]interface{}{}, entries[0]["empty"])
+		assert.Equal(t, "/orders", entries[0]["route"])
+	})
+}
+
+// TestMapFields tests maps encoded with sorted keys
+func TestMapFields(t *testing.T) {
+	t.Run("should encode maps in key order", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+
+		logger.Info("request received",
+			StringMap("headers", map[string]string{"x-b": "2", "accept": "json", "x-a": "1"}),
+			AnyMap("params", map[string]interface{}{"retries": 3, "queue": "mail", "delay": time.Second}),
+		)
+
+		assert.Contains(t, output(), `"headers":{"accept":"json","x-a":"1","x-b":"2"}`)
+		assert.Contains(t, output(), `"params":{"delay":"1s","queue":"mail","retries":3}`)
+	})
+
+	t.Run("should encode nil maps as empty objects", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+
+		logger.Info("empty maps", StringMap("headers", nil), AnyMap("params", nil))
+
+		assert.Contains(t, output(), `"headers":{},"params":{}`)
+	})
+}
+
+// TestRawJSON tests fields embedding encoded JSON
+func TestRawJSON(t *testing.T) {
+	t.Run("should embed valid JSON without escaping", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+
+		logger.Info("upstream response", RawJSON("body", []byte(`{"id":42,"tags":["a","b"]}`)))
+
+		assert.Contains(t, output(), `"body":{"id":42,"tags":["a","b"]}`)
+	})
+
+	t.Run("should log invalid JSON as a string", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+
+		logger.Info("upstream response", RawJSON("body", []byte(`{"id":`)))
+
+		assert.Contains(t, output(), `"body":"{\"id\":"`)
+	})
+}
+
+// TestBinaryFields tests base64 and hex fields
+func TestBinaryFields(t *testing.T) {
+	t.Run("should encode bytes as base64 and hex", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+
+		logger.Info("frame received", Binary("payload", []byte("hello")), Hex("checksum", []byte{0xde, 0xad, 0xbe, 0xef}))
+
+		assert.Contains(t, output(), `"payload":"aGVsbG8="`)
+		assert.Contains(t, output(), `"checksum":"deadbeef"`)
+	})
+
+	t.Run("should truncate values longer than DefaultBinaryLimit", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+
+		logger.Info("frame received", Binary("payload", make([]byte, DefaultBinaryLimit+1)))
+
+		assert.Contains(t, output(), `"payload":"`+strings.Repeat("A", 1366)+`==...(1025 bytes)"`)
+	})
+
+	t.Run("should truncate values longer than the field limit", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+
+		logger.Info("frame received", HexN("payload", []byte{0x01, 0x02, 0x03, 0x04}, 2), BinaryN("raw", []byte("hello"), 3))
+
+		assert.Contains(t, output(), `"payload":"0102...(4 bytes)"`)
+		assert.Contains(t, output(), `"raw":"aGVs...(5 bytes)"`)
+	})
+
+	t.Run("should not truncate without a limit", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+
+		logger.Info("frame received", HexN("payload", make([]byte, 2048), 0))
+
+		assert.Contains(t, output(), `"payload":"`+strings.Repeat("0", 4096)+`"`)
+	})
+}
+
+// countingStringer counts calls of String
+type countingStringer struct{ calls *int }
+
+func (s countingStringer) String() string {
+	*s.calls++
+	return "routes"
+}
+
+// TestLazy tests fields computed when encoded
+func TestLazy(t *testing.T) {
+	t.Run("should not compute values of disabled entries", func(t *testing.T) {
+		logger, _ := newFileLogger(t)
+		calls, stringerCalls := 0, 0
+
+		logger.Debug("cache state",
+			Lazy("entries", func() interface{} { calls++; return []int{1, 2} }),
+			Stringer("table", countingStringer{calls: &stringerCalls}),
+		)
+
+		assert.Zero(t, calls)
+		assert.Zero(t, stringerCalls)
+	})
+
+	t.Run("should compute values once across outputs", func(t *testing.T) {
+		dir := t.TempDir()
+		path, sinkPath := filepath.Join(dir, "app.log"), filepath.Join(dir, "sink.log")
+		logger, err := NewZapLogger(NewLoggerConfig(WithOutputPaths(path), WithSink(SinkConfig{Output: sinkPath})))
+		require.NoError(t, err)
+		calls := 0
+
+		logger.Info("cache state",
+			Lazy("entries", func() interface{} { calls++; return map[string]int{"hits": 3} }),
+			Stringer("table", countingStringer{calls: new(int)}),
+		)
+
+		assert.Equal(t, 1, calls)
+		for _, output := range []string{readFile(t, path), readFile(t, sinkPath)} {
+			entries := entriesWithMessage(t, output, "cache state")
+			require.Len(t, entries, 1)
+			assert.Equal(t, map[string]interface{}{"hits": float64(3)}, entries[0]["entries"])
+			assert.Equal(t, "routes", entries[0]["table"])
+		}
+	})
+
+	t.Run("should report panics as encoding errors", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+
+		assert.NotPanics(t, func() {
+			logger.Info("cache state", Lazy("entries", func() interface{} { panic("boom") }))
+		})
+		entries := entriesWithMessage(t, output(), "cache state")
+		require.Len(t, entries, 1)
+		assert.Contains(t, entries[0]["entriesError"], "lazy value panicked: boom")
 	})
 }
 
@@ -1,14 +1,18 @@
 package xlogger
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"go.uber.org/fx/fxevent"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+	gormlogger "gorm.io/gorm/logger"
 )
 
 const (
@@ -22,21 +26,80 @@ const (
 
 // ZapLogger implements Logger interface using zap as the underlying logger
 type ZapLogger struct {
-	logger           *zap.Logger
-	level            zapcore.Level
-	mu               sync.RWMutex
-	infraLogger      *ZapLogger
-	gormLogger       *GORMLogger
-	componentLoggers map[string]Logger
+	logger          *zap.Logger
+	level           zap.AtomicLevel // shared by all loggers derived from the same NewZapLogger
+	infraLogger     *ZapLogger
+	gormLogger      *GORMLogger
+	components      *componentCache // ForInfra loggers, shared by loggers with the same infraLogger
+	reload          *reloader       // outputs and cores swapped by Reconfigure, shared by derived loggers
+	contextTrace    bool            // trace fields come from WithContext instead of gls
+	tags            []string        // tags added to every entry by WithTags
+	componentLevels *componentLevels
+	component       string // infrastructure component whose level override applies
+	traceScope      *TraceScope
+	traceConflict   TraceConflict       // resolution of trace fields passed with another value
+	baggagePrefix   string              // prefix of baggage field keys
+	contextOnly     bool                // trace fields come only from WithContext (ContextBackend)
+	gormLevel       gormlogger.LogLevel // level of GORM loggers (0 to map it from the logger level)
+	idGenerator     IDGenerator         // generator of NewRequestID (nil for the process-wide one)
+	nameLevels      *nameLevels
+	name            string                         // dot-separated name set by Named
+	named           atomic.Pointer[componentCache] // Named loggers, by name
+}
+
+// componentCache holds the loggers returned by ForInfra, by component
+type componentCache struct {
+	mu      sync.RWMutex
+	loggers map[string]Logger
+}
+
+func newComponentCache() *componentCache {
+	return &componentCache{loggers: make(map[string]Logger)}
+}
+
+// get returns the cached logger of component, creating it with build once
+func (c *componentCache) get(component string, build func() Logger) Logger {
+	// Fast read-only check first
+	c.mu.RLock()
+	logger, exists := c.loggers[component]
+	c.mu.RUnlock()
+	if exists {
+		return logger
+	}
+
+	// Upgrade to write lock only when needed
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Double-check pattern after acquiring write lock
+	if logger, exists := c.loggers[component]; exists {
+		return logger
+	}
+	logger = build()
+	c.loggers[component] = logger
+	return logger
 }
 
 // determineEncoding extracts encoding determination logic
 func determineEncoding(format LogFormat) string {
-	normalized := format.Normalize()
-	if normalized == FormatText {
+	switch format.Normalize() {
+	case FormatText:
 		return "console"
+	case FormatProtobuf:
+		return protobufEncoding
+	case FormatMsgpack:
+		return msgpackEncoding
+	case FormatCBOR:
+		return cborEncoding
+	case FormatGCP:
+		return gcpEncoding
+	case FormatDatadog:
+		return datadogEncoding
+	case FormatPretty:
+		return prettyEncoding
+	default:
+		return "json"
 	}
-	return "json"
 }
 
 // createBaseEncoderConfig creates the base encoder configuration
@@ -57,10 +120,16 @@ func createBaseEncoderConfig() zapcore.EncoderConfig {
 	}
 }
 
-// adjustEncoderForConsole adjusts encoder config for console format
-func adjustEncoderForConsole(config *zap.Config) {
+// adjustEncoderForConsole adjusts encoder config for console format, writing
+// levels in style. The pretty encoding falls back to console outside
+// development mode, where multi-line entries would break line-based log
+// collectors.
+func adjustEncoderForConsole(config *zap.Config, style ConsoleStyle) {
+	if config.Encoding == prettyEncoding && !config.Development {
+		config.Encoding = "console"
+	}
 	if config.Encoding == "console" {
-		config.EncoderConfig.EncodeLevel = emojiLevelEncoder
+		config.EncoderConfig.EncodeLevel = style.levelEncoder()
 		config.EncoderConfig.EncodeTime = zapcore.TimeEncoderOfLayout(ConsoleTimeLayout)
 	}
 }
@@ -87,101 +156,347 @@ func emojiLevelEncoder(level zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
 	}
 }
 
-// NewZapLogger creates a ZapLogger with full configuration support
-func NewZapLogger(cfg *Config) (*ZapLogger, error) {
-	// Default configuration when no config provided
-	if cfg == nil {
-		cfg = DefaultLoggerConfig()
-	}
-
+// newBaseZapConfig builds the zap configuration of the base logger
+func newBaseZapConfig(cfg *Config) zap.Config {
 	// Determine encoding using helper function
 	encoding := determineEncoding(cfg.Format)
 	config := zap.Config{
-		Level:       zap.NewAtomicLevelAt(cfg.Level),
-		Development: cfg.Development,
-		Sampling: &zap.SamplingConfig{
-			Initial:    100,
-			Thereafter: 100,
-		},
+		Level:             zap.NewAtomicLevelAt(cfg.Level),
+		Development:       cfg.Development,
 		Encoding:          encoding,
 		EncoderConfig:     createBaseEncoderConfig(),
-		OutputPaths:       []string{"stdout"},
-		ErrorOutputPaths:  []string{"stderr"},
 		DisableCaller:     cfg.DisableCaller,
 		DisableStacktrace: cfg.DisableStacktrace,
 	}
-	adjustEncoderForConsole(&config)
+	adjustEncoderForConsole(&config, cfg.ConsoleStyle)
+	return config
+}
 
-	// Use CallerSkip from config for infrastructure logger
-	var zapOptions []zap.Option
-	if cfg.CallerSkip > 0 {
-		zapOptions = append(zapOptions, zap.AddCallerSkip(cfg.CallerSkip))
+// NewZapLogger creates a ZapLogger with full configuration support
+func NewZapLogger(cfg *Config) (*ZapLogger, error) {
+	// Default configuration when no config provided
+	if cfg == nil {
+		cfg = DefaultLoggerConfig()
+	}
+
+	// One atomic level drives the base, infrastructure and component loggers
+	level := zap.NewAtomicLevelAt(cfg.Level)
+
+	// Outputs are opened once and shared with the infrastructure logger
+	outputs, err := openOutputs(cfg)
+	if err != nil {
+		return nil, err
 	}
 
-	zapLogger, err := config.Build(zapOptions...)
+	names := newNameLevels()
+	zapLogger, err := buildBaseZapLogger(cfg, level, names, outputs)
 	if err != nil {
+		outputs.close()
 		return nil, err
 	}
 
+	reload := newReloader(outputs)
 	baseLogger := &ZapLogger{
-		logger:           zapLogger,
-		level:            cfg.Level,
-		componentLoggers: make(map[string]Logger),
+		logger:          reload.wrap(zapLogger, &reload.base),
+		level:           level,
+		components:      newComponentCache(),
+		reload:          reload,
+		componentLevels: newComponentLevels(),
+		traceScope:      cfg.TraceScope,
+		traceConflict:   cfg.TraceConflict,
+		baggagePrefix:   cfg.baggagePrefix(),
+		contextOnly:     cfg.TraceBackend == ContextBackend,
+		gormLevel:       cfg.GORMLevel,
+		idGenerator:     cfg.IDGenerator,
+		nameLevels:      names,
 	}
 
 	// Pre-create infrastructure loggers for performance
-	if err := baseLogger.initInfrastructureLoggers(cfg); err != nil {
+	if err := baseLogger.initInfrastructureLoggers(cfg, outputs); err != nil {
+		outputs.close()
 		return nil, fmt.Errorf("failed to initialize infrastructure loggers: %w", err)
 	}
 	return baseLogger, nil
 }
 
-// initInfrastructureLoggers pre-creates infrastructure and GORM loggers for performance
-func (l *ZapLogger) initInfrastructureLoggers(cfg *Config) error {
+// buildEncoder creates the encoder for a zap encoding name
+func buildEncoder(encoding string, encoderConfig zapcore.EncoderConfig) (zapcore.Encoder, error) {
+	switch encoding {
+	case "json":
+		return zapcore.NewJSONEncoder(encoderConfig), nil
+	case "console":
+		return zapcore.NewConsoleEncoder(encoderConfig), nil
+	case protobufEncoding:
+		return newProtobufEncoder(), nil
+	case msgpackEncoding:
+		return newMsgpackEncoder(), nil
+	case cborEncoding:
+		return newCBOREncoder(), nil
+	case gcpEncoding:
+		return newGCPEncoder(), nil
+	case datadogEncoding:
+		return newDatadogEncoder(), nil
+	case prettyEncoding:
+		return newPrettyEncoder(encoderConfig), nil
+	default:
+		return nil, fmt.Errorf("unknown encoding %q", encoding)
+	}
+}
+
+// isBinaryEncoding reports whether encoding writes binary entries
+func isBinaryEncoding(encoding string) bool {
+	switch encoding {
+	case protobufEncoding, msgpackEncoding, cborEncoding:
+		return true
+	}
+	return false
+}
+
+// buildZapLogger assembles a zap logger from config like zap.Config.Build,
+// but writes to already opened outputs instead of opening its own and
+// samples with sampling (nil to log every entry) instead of config.Sampling.
+// With names, entries are filtered by the level of their logger name before
+// reaching every output but the additional sinks.
+func buildZapLogger(config zap.Config, sampling *SamplingConfig, outputs *loggerOutputs, names *nameLevels, opts ...zap.Option) (*zap.Logger, error) {
+	encoder, err := buildEncoder(config.Encoding, config.EncoderConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	buildOptions := []zap.Option{zap.ErrorOutput(outputs.errSink)}
+	if config.Development {
+		buildOptions = append(buildOptions, zap.Development())
+	}
+	if !config.DisableCaller {
+		buildOptions = append(buildOptions, zap.AddCaller())
+	}
+	if !config.DisableStacktrace {
+		stackLevel := zapcore.ErrorLevel
+		if config.Development {
+			stackLevel = zapcore.WarnLevel
+		}
+		buildOptions = append(buildOptions, zap.AddStacktrace(stackLevel))
+	}
+	if sampling != nil {
+		buildOptions = append(buildOptions, zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+			return newSamplingCore(core, sampling)
+		}))
+	}
+	if explainer := outputs.explainer; explainer != nil {
+		buildOptions = append(buildOptions, zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+			return &explainCore{Core: core, explainer: explainer}
+		}))
+	}
+	// Outermost, so entries logged after Close are neither sampled nor
+	// explained
+	wrapClosed, err := closedCoreWrapper(config, outputs)
+	if err != nil {
+		return nil, err
+	}
+	buildOptions = append(buildOptions, zap.WrapCore(wrapClosed))
+
+	var level zapcore.LevelEnabler = config.Level
+	if names != nil {
+		level = names.enabler(config.Level)
+	}
+	var core zapcore.Core = zapcore.NewCore(encoder, outputs.sink, level)
+	if shadow := outputs.shadow; shadow != nil {
+		shadowConfig := config
+		shadowConfig.Encoding = shadow.encoding
+		shadowConfig.EncoderConfig = createBaseEncoderConfig()
+		adjustEncoderForConsole(&shadowConfig, outputs.style)
+
+		shadowEncoder, err := buildEncoder(shadowConfig.Encoding, shadowConfig.EncoderConfig)
+		if err != nil {
+			return nil, err
+		}
+		core = &shadowCore{
+			primary: core,
+			shadow:  zapcore.NewCore(shadowEncoder, shadow.sink, level),
+			outputs: shadow,
+		}
+	}
+	if outputs.partition != nil {
+		partitionEncoder, err := buildEncoder(config.Encoding, config.EncoderConfig)
+		if err != nil {
+			return nil, err
+		}
+		core = zapcore.NewTee(core, &partitionCore{
+			LevelEnabler: level,
+			enc:          partitionEncoder,
+			files:        outputs.partition,
+		})
+	}
+	if outputs.messages != nil {
+		core = zapcore.NewTee(core, &messageCore{LevelEnabler: level, out: outputs.messages})
+	}
+	if len(outputs.reporters) > 0 {
+		core = zapcore.NewTee(core, &reporterCore{LevelEnabler: level, reporters: outputs.reporters})
+	}
+	if len(outputs.hooks) > 0 {
+		core = zapcore.NewTee(core, &hookCore{LevelEnabler: level, hooks: outputs.hooks})
+	}
+	if outputs.producers != nil {
+		core = zapcore.NewTee(core, &producerCore{LevelEnabler: level, table: outputs.producers})
+	}
+	if names != nil {
+		core = &nameLevelCore{Core: core, global: config.Level, levels: names, explainer: outputs.explainer}
+	}
+	// Additional sinks keep their own minimum level
+	if len(outputs.teeSinks) > 0 {
+		sinkCores, err := newTeeSinkCores(config, outputs.teeSinks, outputs.style)
+		if err != nil {
+			return nil, err
+		}
+		core = zapcore.NewTee(append([]zapcore.Core{core}, sinkCores...)...)
+	}
+	if outputs.shapes != nil {
+		shapeEncoder, err := buildEncoder(config.Encoding, config.EncoderConfig)
+		if err != nil {
+			return nil, err
+		}
+		core = newShapeCore(core, shapeEncoder, outputs.shapes, outputs.errSink)
+	}
+	if len(outputs.retention) > 0 {
+		core = newRetentionCore(core, outputs.retention, outputs.errSink)
+	}
+	if outputs.masked != nil {
+		core = &secretsCore{Core: core, errSink: outputs.errSink, masked: outputs.masked}
+	}
+	if outputs.redactor != nil {
+		core = &redactCore{Core: core, errSink: outputs.errSink, redactor: outputs.redactor}
+	}
+	if outputs.process != nil {
+		core = &processorCore{Core: core, errSink: outputs.errSink, process: outputs.process}
+	}
+	// Outside processors and redaction, so the added fields are scrubbed
+	core = &errorFieldsCore{Core: core, errSink: outputs.errSink, chains: outputs.errChains}
+	if outputs.dedupe > 0 {
+		core = newDedupeCore(core, newDedupeState(outputs.dedupe, outputs.errSink))
+	}
+	return zap.New(core, append(buildOptions, opts...)...), nil
+}
+
+// buildBaseZapLogger builds the zap logger of the base logger of cfg,
+// filtered by name levels
+func buildBaseZapLogger(cfg *Config, level zap.AtomicLevel, names *nameLevels, outputs *loggerOutputs) (*zap.Logger, error) {
+	config := newBaseZapConfig(cfg)
+	config.Level = level
+	opts := loggerOptions(cfg)
+	if cfg.StacktraceLevel != nil && !cfg.DisableStacktrace {
+		// Replaces the default stack level of buildZapLogger
+		opts = append(opts, zap.AddStacktrace(*cfg.StacktraceLevel))
+	}
+	return buildZapLogger(config, cfg.samplingConfig(), outputs, names, opts...)
+}
+
+// buildInfraZapLogger builds the zap logger of the infrastructure loggers
+// of cfg, filtered by component levels
+func buildInfraZapLogger(cfg *Config, level zap.AtomicLevel, levels *componentLevels, outputs *loggerOutputs) (*zap.Logger, error) {
 	// Determine encoding using helper function
 	encoding := determineEncoding(cfg.Format)
 	infraConfig := zap.Config{
-		Level:       zap.NewAtomicLevelAt(cfg.Level),
-		Development: cfg.Development,
-		Sampling: &zap.SamplingConfig{
-			Initial:    100,
-			Thereafter: 100,
-		},
+		// Every level reaches the component level core, which applies the
+		// shared level or a per-component override
+		Level:             zap.NewAtomicLevelAt(zapcore.DebugLevel),
+		Development:       cfg.Development,
 		Encoding:          encoding,
 		EncoderConfig:     createBaseEncoderConfig(),
-		OutputPaths:       []string{"stdout"},
-		ErrorOutputPaths:  []string{"stderr"},
 		DisableCaller:     true,
 		DisableStacktrace: true,
 	}
-	adjustEncoderForConsole(&infraConfig)
+	adjustEncoderForConsole(&infraConfig, cfg.ConsoleStyle)
+
+	infraZapLogger, err := buildZapLogger(infraConfig, cfg.samplingConfig(), outputs, nil, loggerOptions(cfg)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create infrastructure logger: %w", err)
+	}
+
+	return infraZapLogger.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return &componentLevelCore{
+			Core:      core,
+			global:    level,
+			levels:    levels,
+			explainer: outputs.explainer,
+		}
+	})), nil
+}
 
-	// Use CallerSkip from config for infrastructure logger
-	var infraOptions []zap.Option
+// loggerOptions returns the zap options of cfg shared by the base and
+// infrastructure loggers
+func loggerOptions(cfg *Config) []zap.Option {
+	var options []zap.Option
 	if cfg.CallerSkip > 0 {
-		infraOptions = append(infraOptions, zap.AddCallerSkip(cfg.CallerSkip))
+		options = append(options, zap.AddCallerSkip(cfg.CallerSkip))
+	}
+	if fields := cfg.ServiceTags.fields(); len(fields) > 0 {
+		options = append(options, zap.Fields(fields...))
+	}
+	if cfg.HostFields {
+		options = append(options, zap.Fields(hostFields()...))
+	}
+	if len(cfg.StaticFields) > 0 {
+		options = append(options, zap.Fields(toZapFields(cfg.StaticFields)...))
 	}
+	return options
+}
 
-	infraZapLogger, err := infraConfig.Build(infraOptions...)
+// initInfrastructureLoggers pre-creates infrastructure and GORM loggers for performance
+func (l *ZapLogger) initInfrastructureLoggers(cfg *Config, outputs *loggerOutputs) error {
+	infraZapLogger, err := buildInfraZapLogger(cfg, l.level, l.componentLevels, outputs)
 	if err != nil {
-		return fmt.Errorf("failed to create infrastructure logger: %w", err)
+		return err
 	}
 
 	// Create simple infrastructure logger wrapper (no recursive initialization)
 	l.infraLogger = &ZapLogger{
-		logger: infraZapLogger,
-		level:  cfg.Level,
+		logger:          l.reload.wrap(infraZapLogger, &l.reload.infra),
+		level:           l.level,
+		components:      newComponentCache(),
+		reload:          l.reload,
+		componentLevels: l.componentLevels,
+		traceScope:      l.traceScope,
+		traceConflict:   l.traceConflict,
+		baggagePrefix:   l.baggagePrefix,
+		contextOnly:     l.contextOnly,
+		gormLevel:       l.gormLevel,
+		idGenerator:     l.idGenerator,
 	}
 
 	// Pre-create GORM logger using infrastructure logger for performance
-	l.gormLogger = NewGORMLogger(l.infraLogger)
+	l.gormLogger = l.newGORMLogger(l.infraLogger.forComponent("gorm"), "gorm")
 	return nil
 }
 
 // convertFieldsToZap converts our Field slice to zap.Field slice with performance optimizations
 func convertFieldsToZap(fields []Field) []zap.Field {
-	fields = withTraceFields(fields)
+	fields, _ = withTraceFields(defaultTraceScope, TraceConflictPreferCaller, fields)
+	return toZapFields(withBaggageFields(defaultTraceScope, defaultBaggagePrefix, fields))
+}
 
+// zapFields converts entry fields, merging the logger's tags
+func (l *ZapLogger) zapFields(fields []Field) []zap.Field {
+	// Frames above convertFields: zapFields and the logging method
+	return l.convertFields(mergeTags(l.tags, fields), 2)
+}
+
+// convertFields converts fields, adding gls trace fields unless the logger
+// already carries trace fields from a context or uses ContextBackend. skip is the number of frames
+// between the caller of the logger method and convertFields.
+func (l *ZapLogger) convertFields(fields []Field, skip int) []zap.Field {
+	if l.contextTrace || l.contextOnly {
+		return toZapFields(fields)
+	}
+	scope := l.scope()
+	fields, mismatches := withTraceFields(scope, l.traceConflict, fields)
+	if len(mismatches) > 0 && l.traceConflict == TraceConflictWarn {
+		l.warnTraceMismatches(mismatches, skip+1)
+	}
+	return toZapFields(withScopedFields(scope, withBaggageFields(scope, l.baggagePrefix, fields)))
+}
+
+// toZapFields converts fields without adding trace fields
+func toZapFields(fields []Field) []zap.Field {
 	fieldCount := len(fields)
 	if fieldCount == 0 {
 		return nil
@@ -189,175 +504,392 @@ func convertFieldsToZap(fields []Field) []zap.Field {
 
 	// Fast path for single field
 	if fieldCount == 1 {
-		field := fields[0]
-		key := field.Key()
-		// Direct type assertion without Type() method call
-		switch v := field.Value().(type) {
-		case string:
-			return []zap.Field{zap.String(key, v)}
-		case int:
-			return []zap.Field{zap.Int(key, v)}
-		case int64:
-			return []zap.Field{zap.Int64(key, v)}
-		case float64:
-			return []zap.Field{zap.Float64(key, v)}
-		case bool:
-			return []zap.Field{zap.Bool(key, v)}
-		case time.Time:
-			return []zap.Field{zap.Time(key, v)}
-		case time.Duration:
-			return []zap.Field{zap.Duration(key, v)}
-		case error:
-			return []zap.Field{zap.NamedError(key, v)}
-		default:
-			return []zap.Field{zap.Any(key, v)}
-		}
+		return []zap.Field{toZapField(fields[0])}
 	}
 
 	// Pre-allocate exact size for better memory efficiency
 	zapFields := make([]zap.Field, fieldCount)
-
-	// Optimized conversion loop
 	for i, field := range fields {
-		key := field.Key()
-		// Direct type assertion eliminates the overhead of Type() method call
-		switch v := field.Value().(type) {
-		case string:
-			zapFields[i] = zap.String(key, v)
-		case int:
-			zapFields[i] = zap.Int(key, v)
-		case int64:
-			zapFields[i] = zap.Int64(key, v)
-		case float64:
-			zapFields[i] = zap.Float64(key, v)
-		case bool:
-			zapFields[i] = zap.Bool(key, v)
-		case time.Time:
-			zapFields[i] = zap.Time(key, v)
-		case time.Duration:
-			zapFields[i] = zap.Duration(key, v)
-		case error:
-			zapFields[i] = zap.NamedError(key, v)
-		default:
-			// Fallback to Any type for unknown types
-			zapFields[i] = zap.Any(key, v)
-		}
+		zapFields[i] = toZapField(field)
 	}
 	return zapFields
 }
 
-// withTraceFields ensures request and correlation identifiers are appended
-// to each log entry when they are not already present.
-func withTraceFields(fields []Field) []Field {
-	requestID := TraceRequestID()
-	correlationID := TraceCorrelationID()
+// toZapField converts one field, picking the typed zap constructor of its
+// value so only unknown types go through reflection
+func toZapField(field Field) zap.Field {
+	switch field.Type() {
+	case ZapType:
+		return field.Value().(zap.Field)
+	case NamespaceType:
+		return zap.Namespace(field.Key())
+	}
+	key := field.Key()
+	// Direct type assertion eliminates the overhead of Type() method call
+	switch v := field.Value().(type) {
+	case string:
+		return zap.String(key, v)
+	case int:
+		return zap.Int(key, v)
+	case int64:
+		return zap.Int64(key, v)
+	case int32:
+		return zap.Int32(key, v)
+	case uint:
+		return zap.Uint(key, v)
+	case uint64:
+		return zap.Uint64(key, v)
+	case float64:
+		return zap.Float64(key, v)
+	case float32:
+		return zap.Float32(key, v)
+	case bool:
+		return zap.Bool(key, v)
+	case time.Time:
+		return zap.Time(key, v)
+	case time.Duration:
+		return zap.Duration(key, v)
+	case error:
+		return zap.NamedError(key, v)
+	case []string:
+		return zap.Strings(key, v)
+	case []int:
+		return zap.Ints(key, v)
+	case []time.Duration:
+		return zap.Durations(key, v)
+	case []byte:
+		return zap.ByteString(key, v)
+	case *string:
+		return zap.Stringp(key, v)
+	case *int:
+		return zap.Intp(key, v)
+	case FieldMarshaler:
+		return zap.Object(key, fieldObject{v})
+	default:
+		// Fallback to Any type for unknown types
+		return zap.Any(key, v)
+	}
+}
 
-	needRequestID := requestID != ""
-	needCorrelationID := correlationID != ""
+// scope returns the TraceScope the logger reads trace fields from
+func (l *ZapLogger) scope() *TraceScope {
+	if l.traceScope == nil {
+		return defaultTraceScope
+	}
+	return l.traceScope
+}
 
-	if !needRequestID && !needCorrelationID {
-		return fields
+// traceScopeOf returns the TraceScope of logger, or the default scope for
+// loggers without one
+func traceScopeOf(logger Logger) *TraceScope {
+	if scoped, ok := logger.(interface{ scope() *TraceScope }); ok {
+		return scoped.scope()
+	}
+	return defaultTraceScope
+}
+
+// withTraceFields ensures request, correlation and W3C trace identifiers and
+// the trace actor of scope are appended to each log entry when they are not
+// already present. Fields passed with a different value are resolved with conflict and
+// returned as mismatches.
+func withTraceFields(scope *TraceScope, conflict TraceConflict, fields []Field) ([]Field, []traceMismatch) {
+	requestID := scope.TraceRequestID()
+	correlationID := scope.TraceCorrelationID()
+	parent, hasParent := scope.CurrentTraceparent()
+	tenantID, userID := scope.actor().get()
+
+	if requestID == "" && correlationID == "" && !hasParent && tenantID == "" && userID == "" {
+		return fields, nil
 	}
 
-	hasRequestID := false
-	hasCorrelationID := false
+	traceFields := [...]struct {
+		key, value string
+	}{
+		{requestIDFieldKey, requestID},
+		{correlationIDFieldKey, correlationID},
+		{traceIDFieldKey, parent.TraceID},
+		{spanIDFieldKey, parent.SpanID},
+		{tenantIDFieldKey, tenantID},
+		{userIDFieldKey, userID},
+	}
+
+	var mismatches []traceMismatch
+	for _, traceField := range traceFields {
+		if traceField.value == "" {
+			continue
+		}
+		var mismatch *traceMismatch
+		fields, mismatch = resolveTraceField(conflict, fields, traceField.key, traceField.value)
+		if mismatch != nil {
+			mismatches = append(mismatches, *mismatch)
+		}
+	}
+
+	return fields, mismatches
+}
+
+// splitTags moves Tags fields out of fields and appends their values to tags
+func splitTags(tags []string, fields []Field) ([]string, []Field) {
+	hasTags := false
 	for _, field := range fields {
-		switch field.Key() {
-		case requestIDFieldKey:
-			hasRequestID = true
-		case correlationIDFieldKey:
-			hasCorrelationID = true
+		if field.Type() == TagsType {
+			hasTags = true
+			break
 		}
 	}
+	if !hasTags {
+		return tags, fields
+	}
 
-	if needRequestID && !hasRequestID {
-		fields = append(fields, String(requestIDFieldKey, requestID))
+	merged := append([]string(nil), tags...)
+	rest := make([]Field, 0, len(fields))
+	for _, field := range fields {
+		if field.Type() != TagsType {
+			rest = append(rest, field)
+			continue
+		}
+		for _, tag := range field.Value().([]string) {
+			if !containsString(merged, tag) {
+				merged = append(merged, tag)
+			}
+		}
 	}
-	if needCorrelationID && !hasCorrelationID {
-		fields = append(fields, String(correlationIDFieldKey, correlationID))
+	return merged, rest
+}
+
+// mergeTags replaces logger tags and Tags fields with a single tags field
+func mergeTags(tags []string, fields []Field) []Field {
+	tags, fields = splitTags(tags, fields)
+	if len(tags) == 0 {
+		return fields
+	}
+	return appendTopLevel(fields, Tags(tags...))
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
 	}
+	return false
+}
 
-	return fields
+// fieldIndex returns the index of the first field with key, or -1
+func fieldIndex(fields []Field, key string) int {
+	for i, field := range fields {
+		if field.Key() == key {
+			return i
+		}
+	}
+	return -1
 }
 
 // Debug logs a debug message with fields
 func (l *ZapLogger) Debug(msg string, fields ...Field) {
-	l.logger.Debug(msg, convertFieldsToZap(fields)...)
+	l.logger.Debug(msg, l.zapFields(fields)...)
 }
 
 // Info logs an info message with fields
 func (l *ZapLogger) Info(msg string, fields ...Field) {
-	l.logger.Info(msg, convertFieldsToZap(fields)...)
+	l.logger.Info(msg, l.zapFields(fields)...)
 }
 
 // Warn logs a warning message with fields
 func (l *ZapLogger) Warn(msg string, fields ...Field) {
-	l.logger.Warn(msg, convertFieldsToZap(fields)...)
+	l.logger.Warn(msg, l.zapFields(fields)...)
 }
 
 // Error logs an error message with fields
 func (l *ZapLogger) Error(msg string, fields ...Field) {
-	l.logger.Error(msg, convertFieldsToZap(fields)...)
+	l.logger.Error(msg, l.zapFields(fields)...)
+}
+
+// DebugFn logs a debug message, calling fieldsFn only if the entry is
+// enabled and not sampled out
+func (l *ZapLogger) DebugFn(msg string, fieldsFn func() []Field) {
+	if ce := l.logger.Check(zapcore.DebugLevel, msg); ce != nil {
+		ce.Write(l.zapFields(callFieldsFn(fieldsFn))...)
+	}
+}
+
+// InfoFn logs an info message, calling fieldsFn only if the entry is
+// enabled and not sampled out
+func (l *ZapLogger) InfoFn(msg string, fieldsFn func() []Field) {
+	if ce := l.logger.Check(zapcore.InfoLevel, msg); ce != nil {
+		ce.Write(l.zapFields(callFieldsFn(fieldsFn))...)
+	}
+}
+
+// WarnFn logs a warning message, calling fieldsFn only if the entry is
+// enabled and not sampled out
+func (l *ZapLogger) WarnFn(msg string, fieldsFn func() []Field) {
+	if ce := l.logger.Check(zapcore.WarnLevel, msg); ce != nil {
+		ce.Write(l.zapFields(callFieldsFn(fieldsFn))...)
+	}
+}
+
+// ErrorFn logs an error message, calling fieldsFn only if the entry is
+// enabled and not sampled out
+func (l *ZapLogger) ErrorFn(msg string, fieldsFn func() []Field) {
+	if ce := l.logger.Check(zapcore.ErrorLevel, msg); ce != nil {
+		ce.Write(l.zapFields(callFieldsFn(fieldsFn))...)
+	}
+}
+
+// callFieldsFn returns the fields of a lazy call site, tolerating nil
+func callFieldsFn(fieldsFn func() []Field) []Field {
+	if fieldsFn == nil {
+		return nil
+	}
+	return fieldsFn()
+}
+
+// DPanic logs an invariant violation with fields. In development mode it
+// then calls panic(), in production the entry is only logged, at a level
+// above Error, so broken assumptions surface in tests without crashing
+// production.
+func (l *ZapLogger) DPanic(msg string, fields ...Field) {
+	l.logger.DPanic(msg, l.zapFields(fields)...)
 }
 
 // Panic logs a panic message with fields then calls panic()
 func (l *ZapLogger) Panic(msg string, fields ...Field) {
-	l.logger.Panic(msg, convertFieldsToZap(fields)...)
+	l.logger.Panic(msg, l.zapFields(fields)...)
 }
 
 // Fatal logs a fatal message with fields then calls os.Exit(1)
 func (l *ZapLogger) Fatal(msg string, fields ...Field) {
-	l.logger.Fatal(msg, convertFieldsToZap(fields)...)
+	l.logger.Fatal(msg, l.zapFields(fields)...)
 }
 
 // With creates a new logger instance with additional fields pre-attached
 func (l *ZapLogger) With(fields ...Field) Logger {
-	newLogger := l.logger.With(convertFieldsToZap(fields)...)
-	return &ZapLogger{
-		logger:           newLogger,
-		level:            l.level,
-		mu:               sync.RWMutex{},
-		infraLogger:      l.infraLogger,
-		gormLogger:       l.gormLogger,
-		componentLoggers: make(map[string]Logger),
-	}
+	// Tags stay on the logger so they merge with entry tags into one array
+	tags, fields := splitTags(l.tags, fields)
+	child := l.derive(l.logger, l.contextTrace)
+	child.tags = tags
+	child.logger = l.logger.With(l.convertFields(fields, 1)...)
+	return child
 }
 
-// ForInfra returns a logger optimized for infrastructure components
-func (l *ZapLogger) ForInfra(component string) Logger {
-	// Normalize component name with early return for empty
-	if component == "" {
-		component = "unknown"
+// WithTags creates a new logger that adds tags to every entry
+func (l *ZapLogger) WithTags(tags ...string) Logger {
+	return l.With(Tags(tags...))
+}
+
+// WithContext returns a logger that adds the TraceContext stored by
+// ContextWithTrace or ContextWithTraceContext to every entry, instead of the
+// goroutine-local one, along with fields from extractors registered with
+// RegisterContextFields. Without any of them the logger is returned as is.
+// Loggers from ForInfra are shared and do not carry the context fields.
+func (l *ZapLogger) WithContext(ctx context.Context) Logger {
+	if ctx == nil {
+		return l
 	}
+	tc := TraceContextFromContext(ctx)
+	parent, _ := ParseTraceparent(tc.Traceparent)
 
-	// Fast read-only check first
-	l.mu.RLock()
-	if logger, exists := l.componentLoggers[component]; exists {
-		l.mu.RUnlock()
-		return logger
+	var fields []Field
+	for _, traceField := range [...]struct {
+		key, value string
+	}{
+		{requestIDFieldKey, tc.RequestID},
+		{correlationIDFieldKey, tc.CorrelationID},
+		{traceIDFieldKey, parent.TraceID},
+		{spanIDFieldKey, parent.SpanID},
+		{tenantIDFieldKey, tc.TenantID},
+		{userIDFieldKey, tc.UserID},
+	} {
+		if traceField.value != "" {
+			fields = append(fields, String(traceField.key, traceField.value))
+		}
 	}
-	l.mu.RUnlock()
+	for _, key := range sortedKeys(tc.Baggage) {
+		fields = append(fields, String(l.baggagePrefix+key, tc.Baggage[key]))
+	}
+	hasTrace := len(fields) > 0
 
-	// Upgrade to write lock only when needed
-	l.mu.Lock()
-	defer l.mu.Unlock()
+	fields = append(fields, contextFields(ctx)...)
+	if len(fields) == 0 {
+		return l
+	}
 
-	// Double-check pattern after acquiring write lock
-	if logger, exists := l.componentLoggers[component]; exists {
+	child := l.derive(l.logger, l.contextTrace || hasTrace)
+	child.logger = l.logger.With(toZapFields(fields)...)
+	return child
+}
+
+// derive wraps a child zap logger sharing the parent's caches and outputs
+func (l *ZapLogger) derive(newLogger *zap.Logger, contextTrace bool) *ZapLogger {
+	// ForInfra loggers come from the shared infrastructure logger and do not
+	// depend on this logger's fields, so every child can reuse the same cache.
+	// Without one they are built from this logger, so the child needs its own.
+	components := l.components
+	if l.infraLogger == nil || components == nil {
+		components = newComponentCache()
+	}
+	return &ZapLogger{
+		logger:          newLogger,
+		level:           l.level,
+		infraLogger:     l.infraLogger,
+		gormLogger:      l.gormLogger,
+		components:      components,
+		reload:          l.reload,
+		contextTrace:    contextTrace,
+		tags:            l.tags,
+		componentLevels: l.componentLevels,
+		component:       l.component,
+		traceScope:      l.traceScope,
+		traceConflict:   l.traceConflict,
+		baggagePrefix:   l.baggagePrefix,
+		contextOnly:     l.contextOnly,
+		gormLevel:       l.gormLevel,
+		idGenerator:     l.idGenerator,
+		name:            l.name,
+		nameLevels:      l.nameLevels,
+	}
+}
+
+// skipCaller skips skip more frames so entries logged through a wrapper
+// such as Group report the caller of the wrapper instead of its file
+func skipCaller(logger Logger, skip int) Logger {
+	zl, ok := logger.(*ZapLogger)
+	if !ok {
 		return logger
 	}
+	child := zl.derive(zl.logger.WithOptions(zap.AddCallerSkip(skip)), zl.contextTrace)
+	child.component = zl.component
+	return child
+}
 
-	// Fast path: use pre-cached infrastructure logger if available
-	if l.infraLogger != nil {
-		componentLogger := l.infraLogger.With(String("component", component))
-		l.componentLoggers[component] = componentLogger
-		return componentLogger
+// forComponent returns a logger filtered with the component's level override
+func (l *ZapLogger) forComponent(component string) *ZapLogger {
+	child := l.derive(l.logger.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return forComponent(core, component)
+	})), l.contextTrace)
+	child.component = component
+	return child
+}
+
+// ForInfra returns a logger optimized for infrastructure components.
+// Component loggers are created once and shared by the logger and all
+// loggers derived from it with With, WithTags and WithContext.
+func (l *ZapLogger) ForInfra(component string) Logger {
+	// Normalize component name with early return for empty
+	if component == "" {
+		component = "unknown"
 	}
 
-	// Fallback: create component logger from base logger
-	componentLogger := l.With(String("component", component))
-	l.componentLoggers[component] = componentLogger
-	return componentLogger
+	return l.components.get(component, func() Logger {
+		// Fast path: use pre-cached infrastructure logger if available
+		if l.infraLogger != nil {
+			return l.infraLogger.forComponent(component).With(String("component", component))
+		}
+
+		// Fallback: create component logger from base logger
+		return l.With(String("component", component))
+	})
 }
 
 // ForFxEvent returns a FX event logger that implements fxevent.Logger interface
@@ -371,7 +903,38 @@ func (l *ZapLogger) ForGORM() *GORMLogger {
 		return l.gormLogger
 	}
 	// Fallback: create GORM logger if not pre-cached
-	return NewGORMLogger(l)
+	return l.newGORMLogger(l, "gorm")
+}
+
+// ForGORMNamed returns a GORM logger whose entries carry name as their
+// component instead of "gorm", for services with several databases. Its
+// GORM log level is the one of WithGORMLevel, or else follows the component
+// level of name (see SetComponentLevel) when the logger is created. Its
+// thresholds are set independently of ForGORM and other names. An empty
+// name returns ForGORM.
+//
+// Example:
+//
+//	ordersDB, err := gorm.Open(postgres.Open(ordersDSN), &gorm.Config{
+//	    Logger: logger.ForGORMNamed("orders-db").SetSlowThreshold(200 * time.Millisecond),
+//	})
+func (l *ZapLogger) ForGORMNamed(name string) *GORMLogger {
+	if name == "" {
+		return l.ForGORM()
+	}
+	if l.infraLogger != nil {
+		return l.newGORMLogger(l.infraLogger.forComponent(name), name)
+	}
+	return l.newGORMLogger(l, name)
+}
+
+// newGORMLogger creates a GORM logger adapter of component writing to
+// logger, at the level of WithGORMLevel when set
+func (l *ZapLogger) newGORMLogger(logger Logger, component string) *GORMLogger {
+	if l.gormLevel != 0 {
+		return newGORMLoggerWithLevel(logger, component, l.gormLevel)
+	}
+	return newGORMLogger(logger, component)
 }
 
 // isIgnorableSyncError checks if a sync error can be safely ignored
@@ -390,6 +953,7 @@ func isIgnorableSyncError(err error) bool {
 		"sync /dev/stderr: invalid argument",
 		"sync stdout: inappropriate ioctl for device",
 		"sync stderr: inappropriate ioctl for device",
+		"sync /dev/null: invalid argument", // outputs discarded with os.DevNull
 	}
 
 	for _, ignorable := range ignorableErrors {
@@ -402,20 +966,150 @@ func isIgnorableSyncError(err error) bool {
 
 // Sync implements Logger interface
 func (l *ZapLogger) Sync() error {
-	err := l.logger.Sync()
-	if err != nil {
-		// Ignore sync errors for stdout/stderr when output is redirected or piped
-		// This commonly happens in containers, CI/CD, or when output is redirected
-		if isIgnorableSyncError(err) {
-			return nil
-		}
+	var errs []error
+	if l.infraLogger != nil {
+		// Infrastructure, component and GORM loggers share their own core,
+		// with pending reporter and dedupe state
+		errs = append(errs, syncZapLogger(l.infraLogger.logger))
 	}
-	return err
+	errs = append(errs, syncZapLogger(l.logger))
+	return errors.Join(errs...)
+}
+
+// Close syncs the base, infrastructure, component and GORM loggers, then
+// closes the outputs shared by every logger derived from the same
+// NewZapLogger, with those of previous configurations Reconfigure has not
+// closed yet. Entries logged afterwards are handled by the policy set
+// with WithAfterClosePolicy, dropped by default. When ctx is done first,
+// Close returns ctx.Err() and closing continues in the background. Later
+// calls return the result of the first.
+func (l *ZapLogger) Close(ctx context.Context) error {
+	if l.reload == nil {
+		return l.Sync()
+	}
+
+	var err error
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		// Closes the outputs current once Reconfigure is done
+		l.reload.mu.Lock()
+		defer l.reload.mu.Unlock()
+		outputs := l.outputs()
+		outputs.closeOnce.Do(func() {
+			outputs.closed.Store(true)
+			outputs.closeErr = l.Sync()
+			if outputs.close != nil {
+				outputs.close()
+			}
+			// Closes the outputs of previous configurations still draining
+			close(l.reload.stopCh)
+			l.reload.retiring.Wait()
+		})
+		err = outputs.closeErr
+	}()
+
+	select {
+	case <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// outputs returns the outputs of the current configuration, nil for loggers
+// not created by NewZapLogger
+func (l *ZapLogger) outputs() *loggerOutputs {
+	if l.reload == nil {
+		return nil
+	}
+	return l.reload.outputs.Load()
+}
+
+// syncZapLogger syncs logger, ignoring errors for stdout/stderr when output
+// is redirected or piped. This commonly happens in containers, CI/CD, or
+// when output is redirected.
+func syncZapLogger(logger *zap.Logger) error {
+	if err := logger.Sync(); err != nil && !isIgnorableSyncError(err) {
+		return err
+	}
+	return nil
 }
 
 // Level returns the current logging level
 func (l *ZapLogger) Level() zapcore.Level {
-	return l.level
+	if l.component != "" {
+		return l.ComponentLevel(l.component)
+	}
+	if l.name != "" {
+		return l.NamedLevel(l.name)
+	}
+	return l.level.Level()
+}
+
+// SetLevel changes the minimum level at runtime for this logger and every
+// logger sharing its level: the base logger, With and WithContext children,
+// and the infrastructure, component and GORM loggers. The GORM LogMode is
+// not changed.
+func (l *ZapLogger) SetLevel(level zapcore.Level) {
+	l.level.SetLevel(level)
+}
+
+// SetComponentLevel overrides the level of the infrastructure component
+// loggers returned by ForInfra(component), including "gorm" for ForGORM.
+// Overrides may be more or less verbose than the shared level.
+func (l *ZapLogger) SetComponentLevel(component string, level zapcore.Level) {
+	if l.componentLevels == nil {
+		return
+	}
+	l.componentLevels.update(func(levels map[string]zapcore.Level) {
+		levels[component] = level
+	})
+}
+
+// ResetComponentLevel removes the override of component so it follows the
+// shared level again.
+func (l *ZapLogger) ResetComponentLevel(component string) {
+	if l.componentLevels == nil {
+		return
+	}
+	l.componentLevels.update(func(levels map[string]zapcore.Level) {
+		delete(levels, component)
+	})
+}
+
+// ComponentLevel returns the effective level of an infrastructure component.
+func (l *ZapLogger) ComponentLevel(component string) zapcore.Level {
+	if l.componentLevels != nil {
+		if level, ok := l.componentLevels.get(component); ok {
+			return level
+		}
+	}
+	return l.level.Level()
+}
+
+// ComponentLevels returns the current per-component level overrides.
+func (l *ZapLogger) ComponentLevels() map[string]zapcore.Level {
+	if l.componentLevels == nil {
+		return map[string]zapcore.Level{}
+	}
+	return l.componentLevels.all()
+}
+
+// SinkStatus returns byte, entry and checksum accounting for each output path.
+// Loggers derived with With or ForInfra share the accounting of their parent.
+func (l *ZapLogger) SinkStatus() []SinkStatus {
+	return l.outputs().status()
+}
+
+// ShadowReport compares the current output with the shadow output configured
+// by WithShadow. It returns false when shadow logging is disabled.
+func (l *ZapLogger) ShadowReport() (ShadowReport, bool) {
+	outputs := l.outputs()
+	if outputs == nil || outputs.shadow == nil {
+		return ShadowReport{}, false
+	}
+	return outputs.shadow.report(), true
 }
 
 // NewNop creates a no-operation logger for testing purposes
@@ -423,9 +1117,8 @@ func (l *ZapLogger) Level() zapcore.Level {
 func NewNop() Logger {
 	nopLogger := zap.NewNop()
 	return &ZapLogger{
-		logger:           nopLogger,
-		level:            zapcore.InfoLevel,
-		mu:               sync.RWMutex{},
-		componentLoggers: make(map[string]Logger),
+		logger:     nopLogger,
+		level:      zap.NewAtomicLevelAt(zapcore.InfoLevel),
+		components: newComponentCache(),
 	}
 }
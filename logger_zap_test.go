@@ -3,13 +3,18 @@ package xlogger
 import (
 	"context"
 	"errors"
+	"os"
+	"path/filepath"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+	gormlogger "gorm.io/gorm/logger"
 )
 
 // TestNewZapLogger tests the NewZapLogger constructor
@@ -100,6 +105,37 @@ func TestNewZapLogger(t *testing.T) {
 		infraLogger := logger.ForInfra("test")
 		assert.NotNil(t, infraLogger)
 	})
+
+	t.Run("should write to configured output paths", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "app.log")
+		cfg := NewLoggerConfig(WithOutputPaths(path))
+
+		logger, err := NewZapLogger(cfg)
+		require.NoError(t, err)
+
+		logger.Info("to file")
+		logger.ForInfra("db").Info("infra to file")
+		_ = logger.Sync()
+
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), `"message":"to file"`)
+		assert.Contains(t, string(data), `"message":"infra to file"`)
+	})
+
+	t.Run("should fail with unknown output sink scheme", func(t *testing.T) {
+		cfg := NewLoggerConfig(WithOutputPaths("unknown-scheme://sink"))
+
+		_, err := NewZapLogger(cfg)
+		assert.Error(t, err)
+	})
+
+	t.Run("should fail with unknown error output sink scheme", func(t *testing.T) {
+		cfg := NewLoggerConfig(WithErrorOutputPaths("unknown-scheme://sink"))
+
+		_, err := NewZapLogger(cfg)
+		assert.Error(t, err)
+	})
 }
 
 // TestHelperFunctions tests the helper functions used in logger creation
@@ -109,6 +145,9 @@ func TestHelperFunctions(t *testing.T) {
 		assert.Equal(t, "console", determineEncoding(LogFormat("TEXT")))
 		assert.Equal(t, "json", determineEncoding(FormatJSON))
 		assert.Equal(t, "json", determineEncoding(LogFormat("JSON")))
+		assert.Equal(t, protobufEncoding, determineEncoding(FormatProtobuf))
+		assert.Equal(t, msgpackEncoding, determineEncoding(FormatMsgpack))
+		assert.Equal(t, cborEncoding, determineEncoding(FormatCBOR))
 		assert.Equal(t, "json", determineEncoding(LogFormat("invalid")))
 		assert.Equal(t, "json", determineEncoding(LogFormat("")))
 	})
@@ -129,7 +168,7 @@ func TestHelperFunctions(t *testing.T) {
 			EncoderConfig: createBaseEncoderConfig(),
 		}
 
-		adjustEncoderForConsole(config)
+		adjustEncoderForConsole(config, ConsoleStyleEmoji)
 
 		// Should have color level encoder for console
 		assert.NotNil(t, config.EncoderConfig.EncodeLevel)
@@ -145,7 +184,7 @@ func TestHelperFunctions(t *testing.T) {
 		// Store original encoding before adjustment
 		originalEncoding := config.Encoding
 
-		adjustEncoderForConsole(config)
+		adjustEncoderForConsole(config, ConsoleStyleEmoji)
 
 		// Should keep original encoding for JSON (not modified)
 		assert.Equal(t, originalEncoding, config.Encoding)
@@ -174,6 +213,37 @@ func TestZapLogger_BasicLogging(t *testing.T) {
 	})
 }
 
+// TestZapLogger_DPanic tests DPanic in production and development mode
+func TestZapLogger_DPanic(t *testing.T) {
+	newDPanicLogger := func(t *testing.T, dev bool) (*ZapLogger, func() string) {
+		path := filepath.Join(t.TempDir(), "app.log")
+		logger, err := NewZapLogger(NewLoggerConfig(WithOutputPaths(path), WithDevelopment(dev)))
+		require.NoError(t, err)
+		return logger, func() string { return readFile(t, path) }
+	}
+
+	t.Run("should only log in production", func(t *testing.T) {
+		logger, output := newDPanicLogger(t, false)
+
+		assert.NotPanics(t, func() {
+			logger.DPanic("negative total", Int("total", -1))
+		})
+		entries := entriesWithMessage(t, output(), "negative total")
+		require.Len(t, entries, 1)
+		assert.Equal(t, "dpanic", entries[0]["level"])
+		assert.Equal(t, float64(-1), entries[0]["total"])
+	})
+
+	t.Run("should log then panic in development", func(t *testing.T) {
+		logger, output := newDPanicLogger(t, true)
+
+		assert.PanicsWithValue(t, "negative total", func() {
+			logger.DPanic("negative total")
+		})
+		assert.Contains(t, output(), "negative total")
+	})
+}
+
 // TestZapLogger_WithFields tests the With method
 func TestZapLogger_WithFields(t *testing.T) {
 	logger := NewNop()
@@ -335,6 +405,51 @@ func TestZapLogger_ForInfra(t *testing.T) {
 			infraLogger.Debug("fallback debug message")
 		})
 	})
+
+	t.Run("should share component loggers with derived loggers", func(t *testing.T) {
+		zapLogger, err := NewZapLogger(NewLoggerConfig(WithOutputPaths(filepath.Join(t.TempDir(), "app.log"))))
+		require.NoError(t, err)
+
+		db := zapLogger.ForInfra("db")
+		assert.Same(t, db, zapLogger.With(String("request", "1")).ForInfra("db"))
+		assert.Same(t, db, zapLogger.WithTags("audit").ForInfra("db"))
+		assert.Same(t, db, zapLogger.WithContext(ContextWithTrace(context.Background(), "req-1", "corr-1")).ForInfra("db"))
+
+		// Components first requested from a child are visible to the parent
+		cache := zapLogger.With(String("request", "2")).ForInfra("cache")
+		assert.Same(t, cache, zapLogger.ForInfra("cache"))
+	})
+
+	t.Run("should create each component once under concurrent children", func(t *testing.T) {
+		zapLogger, err := NewZapLogger(NewLoggerConfig(WithOutputPaths(filepath.Join(t.TempDir(), "app.log"))))
+		require.NoError(t, err)
+
+		var wg sync.WaitGroup
+		results := make(chan Logger, 50)
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				child := zapLogger.With(Int("request", i))
+				results <- child.ForInfra("queue")
+			}(i)
+		}
+		wg.Wait()
+		close(results)
+
+		want := zapLogger.ForInfra("queue")
+		for got := range results {
+			assert.Same(t, want, got)
+		}
+	})
+
+	t.Run("should keep separate caches without an infrastructure logger", func(t *testing.T) {
+		zapLogger := NewNop().(*ZapLogger)
+		child := zapLogger.With(String("service", "billing")).(*ZapLogger)
+
+		assert.NotSame(t, zapLogger.components, child.components)
+		assert.NotSame(t, zapLogger.ForInfra("db"), child.ForInfra("db"))
+	})
 }
 
 // TestZapLogger_ForGORM tests the ForGORM method
@@ -404,6 +519,64 @@ func TestZapLogger_ForGORM(t *testing.T) {
 	})
 }
 
+// TestZapLogger_ForGORMNamed tests GORM loggers per database
+func TestZapLogger_ForGORMNamed(t *testing.T) {
+	t.Run("should tag entries with the database name", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+
+		logger.ForGORMNamed("orders-db").Error(context.Background(), "orders failed")
+		logger.ForGORMNamed("users-db").Error(context.Background(), "users failed")
+		logger.ForGORM().Error(context.Background(), "default failed")
+
+		log := output()
+		for msg, component := range map[string]string{
+			"orders failed":  "orders-db",
+			"users failed":   "users-db",
+			"default failed": "gorm",
+		} {
+			entries := entriesWithMessage(t, log, msg)
+			require.Len(t, entries, 1)
+			assert.Equal(t, component, entries[0]["component"])
+		}
+	})
+
+	t.Run("should map the component level of the name", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+		logger.SetComponentLevel("orders-db", zapcore.DebugLevel)
+
+		orders := logger.ForGORMNamed("orders-db")
+		assert.Equal(t, gormlogger.Info, orders.level)
+		assert.Equal(t, gormlogger.Warn, logger.ForGORMNamed("users-db").level)
+
+		orders.Trace(context.Background(), time.Now(), func() (string, int64) {
+			return "SELECT * FROM orders", 3
+		}, nil)
+		assert.Contains(t, output(), "SELECT * FROM orders")
+	})
+
+	t.Run("should keep thresholds independent", func(t *testing.T) {
+		logger, _ := newFileLogger(t)
+
+		orders := logger.ForGORMNamed("orders-db").SetSlowThreshold(50 * time.Millisecond)
+
+		assert.Equal(t, 50*time.Millisecond, orders.slowThreshold)
+		assert.Equal(t, 500*time.Millisecond, logger.ForGORMNamed("orders-db").slowThreshold)
+		assert.Equal(t, 500*time.Millisecond, logger.ForGORM().slowThreshold)
+	})
+
+	t.Run("should return ForGORM for an empty name", func(t *testing.T) {
+		logger, _ := newFileLogger(t)
+		assert.Same(t, logger.ForGORM(), logger.ForGORMNamed(""))
+	})
+
+	t.Run("should work without infrastructure loggers", func(t *testing.T) {
+		gormLogger := NewNop().ForGORMNamed("orders-db")
+		assert.NotPanics(t, func() {
+			gormLogger.Error(context.Background(), "nop")
+		})
+	})
+}
+
 // TestZapLogger_Sync tests the Sync method
 func TestZapLogger_Sync(t *testing.T) {
 	logger := NewNop()
@@ -412,6 +585,82 @@ func TestZapLogger_Sync(t *testing.T) {
 		err := logger.Sync()
 		assert.NoError(t, err)
 	})
+
+	t.Run("should flush infrastructure loggers", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "app.log.gz")
+		logger, err := NewZapLogger(NewLoggerConfig(
+			WithOutputPaths(path),
+			WithCompression(CompressionGzip, 0),
+			WithDedupe(time.Hour),
+		))
+		require.NoError(t, err)
+
+		logger.ForInfra("db").Warn("slow query")
+		logger.ForInfra("db").Warn("slow query")
+		require.NoError(t, logger.Sync())
+
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Contains(t, decompress(t, CompressionGzip, data), `"repeated":1`)
+	})
+}
+
+// TestZapLogger_Close tests flushing and closing the outputs
+func TestZapLogger_Close(t *testing.T) {
+	t.Run("should flush every logger and close once", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "app.log.gz")
+		reporter := &recordingReporter{}
+		logger, err := NewZapLogger(NewLoggerConfig(
+			WithOutputPaths(path),
+			WithCompression(CompressionGzip, 0),
+			WithErrorReporter(reporter),
+		))
+		require.NoError(t, err)
+
+		logger.Info("from base")
+		logger.ForInfra("cache").Info("from infra")
+		logger.ForGORM().Error(context.Background(), "from gorm")
+
+		child := logger.With(String("request", "1"))
+		require.NoError(t, child.Close(context.Background()))
+		require.NoError(t, logger.Close(context.Background()))
+
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		log := decompress(t, CompressionGzip, data)
+		assert.Contains(t, log, "from base")
+		assert.Contains(t, log, "from infra")
+		assert.Contains(t, log, "from gorm")
+		assert.GreaterOrEqual(t, reporter.syncs, 1)
+	})
+
+	t.Run("should return when the context is done", func(t *testing.T) {
+		block := make(chan struct{})
+		defer close(block)
+		logger, err := NewZapLogger(NewLoggerConfig(
+			WithOutputPaths(filepath.Join(t.TempDir(), "app.log")),
+			WithErrorReporter(blockingSyncReporter(block)),
+		))
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, logger.Close(ctx), context.DeadlineExceeded)
+	})
+
+	t.Run("should sync loggers without outputs", func(t *testing.T) {
+		assert.NoError(t, NewNop().Close(context.Background()))
+	})
+}
+
+// blockingSyncReporter is an ErrorReporter whose Sync waits for block
+type blockingSyncReporter chan struct{}
+
+func (r blockingSyncReporter) Report(ErrorReport) error { return nil }
+
+func (r blockingSyncReporter) Sync() error {
+	<-r
+	return nil
 }
 
 // TestIsIgnorableSyncError tests the isIgnorableSyncError function
@@ -456,6 +705,11 @@ func TestIsIgnorableSyncError(t *testing.T) {
 			err:      errors.New("sync stderr: inappropriate ioctl for device"),
 			expected: true,
 		},
+		{
+			name:     "should ignore discarded output invalid argument error",
+			err:      errors.New("sync /dev/null: invalid argument"),
+			expected: true,
+		},
 		{
 			name:     "should not ignore other sync errors",
 			err:      errors.New("sync /tmp/file: permission denied"),
@@ -507,6 +761,115 @@ func TestZapLogger_Level(t *testing.T) {
 	})
 }
 
+// TestZapLogger_SetLevel tests runtime level changes
+func TestZapLogger_SetLevel(t *testing.T) {
+	t.Run("should change level of base, derived and infrastructure loggers", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "app.log")
+		logger, err := NewZapLogger(NewLoggerConfig(WithOutputPaths(path), WithLevel(zapcore.InfoLevel)))
+		require.NoError(t, err)
+
+		child := logger.With(String("k", "v"))
+		component := logger.ForInfra("db")
+
+		logger.Debug("before base")
+		component.Debug("before component")
+
+		child.SetLevel(zapcore.DebugLevel)
+
+		assert.Equal(t, zapcore.DebugLevel, logger.Level())
+		assert.Equal(t, zapcore.DebugLevel, component.Level())
+		logger.Debug("after base")
+		child.Debug("after child")
+		component.Debug("after component")
+
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		output := string(data)
+		assert.NotContains(t, output, "before")
+		assert.Contains(t, output, "after base")
+		assert.Contains(t, output, "after child")
+		assert.Contains(t, output, "after component")
+	})
+
+	t.Run("should raise level at runtime", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "app.log")
+		logger, err := NewZapLogger(NewLoggerConfig(WithOutputPaths(path)))
+		require.NoError(t, err)
+
+		logger.SetLevel(zapcore.ErrorLevel)
+		logger.Warn("suppressed")
+		logger.ForInfra("cache").Warn("suppressed infra")
+		logger.Error("kept")
+
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.NotContains(t, string(data), "suppressed")
+		assert.Contains(t, string(data), "kept")
+	})
+
+	t.Run("should not share level between separate loggers", func(t *testing.T) {
+		first, err := NewZapLogger(nil)
+		require.NoError(t, err)
+		second, err := NewZapLogger(nil)
+		require.NoError(t, err)
+
+		first.SetLevel(zapcore.ErrorLevel)
+		assert.Equal(t, zapcore.InfoLevel, second.Level())
+	})
+}
+
+// TestZapLogger_LazyFields tests the DebugFn family of methods
+func TestZapLogger_LazyFields(t *testing.T) {
+	t.Run("should skip fieldsFn when level is disabled", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+
+		called := false
+		logger.DebugFn("expensive", func() []Field {
+			called = true
+			return nil
+		})
+
+		assert.False(t, called)
+		assert.NotContains(t, output(), "expensive")
+	})
+
+	t.Run("should write computed fields when enabled", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+		tagged := logger.WithTags("billing")
+
+		tagged.InfoFn("info entry", func() []Field { return []Field{Int("count", 3)} })
+		tagged.WarnFn("warn entry", func() []Field { return []Field{String("reason", "slow")} })
+		tagged.ErrorFn("error entry", nil)
+
+		out := output()
+		info := entriesWithMessage(t, out, "info entry")
+		require.Len(t, info, 1)
+		assert.EqualValues(t, 3, info[0]["count"])
+		assert.Equal(t, []interface{}{"billing"}, info[0]["tags"])
+		assert.Contains(t, info[0]["caller"], "logger_zap_test.go")
+
+		warn := entriesWithMessage(t, out, "warn entry")
+		require.Len(t, warn, 1)
+		assert.Equal(t, "slow", warn[0]["reason"])
+		assert.Len(t, entriesWithMessage(t, out, "error entry"), 1)
+	})
+
+	t.Run("should skip fieldsFn for sampled out entries", func(t *testing.T) {
+		logger, _ := newFileLogger(t)
+
+		calls := 0
+		for i := 0; i < 150; i++ {
+			logger.InfoFn("hot path", func() []Field {
+				calls++
+				return nil
+			})
+		}
+
+		// The sampler keeps the first 100 entries per second, then every 100th
+		assert.Equal(t, 100, calls)
+	})
+}
+
 // TestNewNop tests the NewNop function
 func TestNewNop(t *testing.T) {
 	t.Run("should create no-op logger", func(t *testing.T) {
@@ -625,6 +988,20 @@ func TestZapLogger_FieldTypes(t *testing.T) {
 		})
 	})
 
+	t.Run("should log zap fields next to xlogger fields", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+
+		logger.With(ZapField(zap.Strings("roles", []string{"admin"}))).
+			Info("zap fields", append(ZapFields(zap.Uint16("port", 8080)), String("host", "db-1"))...)
+		require.NoError(t, logger.Sync())
+
+		entries := entriesWithMessage(t, output(), "zap fields")
+		require.Len(t, entries, 1)
+		assert.Equal(t, []interface{}{"admin"}, entries[0]["roles"])
+		assert.Equal(t, float64(8080), entries[0]["port"])
+		assert.Equal(t, "db-1", entries[0]["host"])
+	})
+
 	t.Run("should handle zero values", func(t *testing.T) {
 		assert.NotPanics(t, func() {
 			logger.With(
@@ -655,6 +1032,52 @@ func TestZapLogger_ErrorHandling(t *testing.T) {
 	})
 }
 
+// TestZapLogger_StacktraceLevel tests the stack trace threshold
+func TestZapLogger_StacktraceLevel(t *testing.T) {
+	hasStack := func(t *testing.T, output, msg string) bool {
+		entries := entriesWithMessage(t, output, msg)
+		require.Len(t, entries, 1)
+		_, ok := entries[0]["stacktrace"]
+		return ok
+	}
+
+	t.Run("should attach stacks from the threshold independently of the level", func(t *testing.T) {
+		logger, output := newFileLogger(t, WithLevel(zapcore.InfoLevel), WithStacktraceLevel(zapcore.WarnLevel))
+
+		logger.Info("info entry")
+		logger.Warn("warn entry")
+		logger.Error("error entry")
+
+		assert.False(t, hasStack(t, output(), "info entry"))
+		assert.True(t, hasStack(t, output(), "warn entry"))
+		assert.True(t, hasStack(t, output(), "error entry"))
+	})
+
+	t.Run("should default to error level", func(t *testing.T) {
+		logger, output := newFileLogger(t, WithDisableStacktrace(false))
+
+		logger.Warn("warn entry")
+		logger.Error("error entry")
+
+		assert.False(t, hasStack(t, output(), "warn entry"))
+		assert.True(t, hasStack(t, output(), "error entry"))
+	})
+
+	t.Run("should keep stacks disabled when disabled afterwards", func(t *testing.T) {
+		logger, output := newFileLogger(t, WithStacktraceLevel(zapcore.WarnLevel), WithDisableStacktrace(true))
+
+		logger.Error("error entry")
+		assert.False(t, hasStack(t, output(), "error entry"))
+	})
+
+	t.Run("should not attach stacks to infrastructure entries", func(t *testing.T) {
+		logger, output := newFileLogger(t, WithStacktraceLevel(zapcore.DebugLevel))
+
+		logger.ForInfra("cache").Error("infra entry")
+		assert.False(t, hasStack(t, output(), "infra entry"))
+	})
+}
+
 // TestConvertFieldsToZap tests the convertFieldsToZap function for performance optimizations
 func TestConvertFieldsToZap(t *testing.T) {
 	t.Run("should handle empty fields", func(t *testing.T) {
@@ -668,6 +1091,7 @@ func TestConvertFieldsToZap(t *testing.T) {
 	})
 
 	t.Run("should append trace fields when present", func(t *testing.T) {
+		skipWithoutGLS(t)
 		err := RunWithTrace("req-trace-123", "corr-trace-456", func() error {
 			zapFields := convertFieldsToZap(nil)
 
@@ -683,6 +1107,7 @@ func TestConvertFieldsToZap(t *testing.T) {
 	})
 
 	t.Run("should not duplicate existing trace fields", func(t *testing.T) {
+		skipWithoutGLS(t)
 		err := RunWithTrace("req-trace-123", "corr-trace-456", func() error {
 			fields := []Field{
 				String(requestIDFieldKey, "existing-request"),
@@ -703,6 +1128,15 @@ func TestConvertFieldsToZap(t *testing.T) {
 		assert.NoError(t, err)
 	})
 
+	t.Run("should pass zap fields through unchanged", func(t *testing.T) {
+		stringer := zap.Stringer("addr", &strings.Builder{})
+		assert.Equal(t, []zap.Field{stringer}, convertFieldsToZap([]Field{ZapField(stringer)}))
+
+		zapFields := convertFieldsToZap([]Field{String("key", "value"), ZapField(zap.Uint8("retries", 3))})
+		require.Len(t, zapFields, 2)
+		assert.Equal(t, zap.Uint8("retries", 3), zapFields[1])
+	})
+
 	t.Run("fast path - single string field", func(t *testing.T) {
 		fields := []Field{String("key", "value")}
 		zapFields := convertFieldsToZap(fields)
@@ -952,3 +1386,121 @@ func TestConvertFieldsToZap(t *testing.T) {
 		})
 	})
 }
+
+// TestZapLogger_WithContext tests context-based trace propagation
+func TestZapLogger_WithContext(t *testing.T) {
+	t.Run("should add trace identifiers from context", func(t *testing.T) {
+		logger, readOutput := newFileLogger(t)
+		ctx := ContextWithTrace(context.Background(), "req-ctx", "corr-ctx")
+
+		logger.WithContext(ctx).With(String("k", "v")).Info("with context")
+
+		output := readOutput()
+		assert.Contains(t, output, `"request_id":"req-ctx"`)
+		assert.Contains(t, output, `"correlation_id":"corr-ctx"`)
+	})
+
+	t.Run("should prefer context over goroutine-local trace", func(t *testing.T) {
+		logger, readOutput := newFileLogger(t)
+		ctx := ContextWithTrace(context.Background(), "req-ctx", "")
+
+		RunWithTraceVoid("req-gls", "corr-gls", func() {
+			logger.WithContext(ctx).Info("context wins")
+		})
+
+		output := readOutput()
+		assert.Contains(t, output, `"request_id":"req-ctx"`)
+		assert.NotContains(t, output, "req-gls")
+		assert.NotContains(t, output, "correlation_id")
+	})
+
+	t.Run("should add fields from registered extractors", func(t *testing.T) {
+		skipWithoutGLS(t)
+		type tenantKey struct{}
+		RegisterContextFields(func(ctx context.Context) []Field {
+			if tenant, ok := ctx.Value(tenantKey{}).(string); ok {
+				return []Field{String("tenant", tenant)}
+			}
+			return nil
+		})
+
+		logger, readOutput := newFileLogger(t)
+		ctx := context.WithValue(context.Background(), tenantKey{}, "acme")
+
+		RunWithTraceVoid("req-gls", "", func() {
+			logger.WithContext(ctx).Info("extracted")
+		})
+
+		output := readOutput()
+		assert.Contains(t, output, `"tenant":"acme"`)
+		assert.Contains(t, output, `"request_id":"req-gls"`)
+	})
+
+	t.Run("should add fields from application extractors", func(t *testing.T) {
+		type userKey struct{}
+		RegisterContextFields(func(ctx context.Context) []Field {
+			if user, ok := ctx.Value(userKey{}).(string); ok {
+				return []Field{String("user_id", user), String("locale", "th-TH")}
+			}
+			return nil
+		})
+
+		logger, readOutput := newFileLogger(t)
+		logger.WithContext(context.WithValue(context.Background(), userKey{}, "u-42")).Info("extracted")
+
+		output := readOutput()
+		assert.Contains(t, output, `"user_id":"u-42"`)
+		assert.Contains(t, output, `"locale":"th-TH"`)
+	})
+
+	t.Run("should return same logger without trace in context", func(t *testing.T) {
+		logger, _ := newFileLogger(t)
+
+		assert.Same(t, logger, logger.WithContext(context.Background()))
+		//nolint:staticcheck // nil context is handled explicitly
+		assert.Same(t, logger, logger.WithContext(nil))
+	})
+}
+
+// TestZapLogger_Tags tests entry and logger tags
+func TestZapLogger_Tags(t *testing.T) {
+	t.Run("should emit entry tags as array", func(t *testing.T) {
+		logger, readOutput := newFileLogger(t)
+
+		logger.Info("tagged", Tags("retryable", "user-facing"), String("k", "v"))
+
+		output := readOutput()
+		assert.Contains(t, output, `"tags":["retryable","user-facing"]`)
+		assert.Contains(t, output, `"k":"v"`)
+	})
+
+	t.Run("should merge logger and entry tags into one array", func(t *testing.T) {
+		logger, readOutput := newFileLogger(t)
+
+		logger.WithTags("billing-impact").With(Tags("retryable")).Warn("merged", Tags("retryable", "user-facing"))
+
+		output := readOutput()
+		assert.Equal(t, 1, strings.Count(output, `"tags"`))
+		assert.Contains(t, output, `"tags":["billing-impact","retryable","user-facing"]`)
+	})
+
+	t.Run("should not emit tags without tags", func(t *testing.T) {
+		logger, readOutput := newFileLogger(t)
+
+		logger.With(String("k", "v")).Info("plain")
+
+		assert.NotContains(t, readOutput(), `"tags"`)
+	})
+
+	t.Run("should not share tags between siblings", func(t *testing.T) {
+		logger, readOutput := newFileLogger(t)
+		parent := logger.WithTags("parent")
+
+		parent.WithTags("first")
+		parent.Info("sibling")
+
+		output := readOutput()
+		assert.Contains(t, output, `"tags":["parent"]`)
+		assert.NotContains(t, output, "first")
+	})
+}
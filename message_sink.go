@@ -0,0 +1,45 @@
+package xlogger
+
+import (
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// messagePool provides the buffers of message lines
+var messagePool = buffer.NewPool()
+
+// messageCore writes only the message text of each entry, one per line,
+// without level, time or fields
+type messageCore struct {
+	zapcore.LevelEnabler
+	out zapcore.WriteSyncer
+}
+
+// With implements zapcore.Core
+func (c *messageCore) With([]zapcore.Field) zapcore.Core {
+	return c
+}
+
+// Check implements zapcore.Core
+func (c *messageCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write implements zapcore.Core
+func (c *messageCore) Write(ent zapcore.Entry, _ []zapcore.Field) error {
+	buf := messagePool.Get()
+	defer buf.Free()
+
+	buf.AppendString(ent.Message)
+	buf.AppendByte('\n')
+	_, err := c.out.Write(buf.Bytes())
+	return err
+}
+
+// Sync implements zapcore.Core
+func (c *messageCore) Sync() error {
+	return c.out.Sync()
+}
@@ -0,0 +1,38 @@
+package xlogger
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+// TestMessageOutput tests the verbatim message output
+func TestMessageOutput(t *testing.T) {
+	t.Run("should write only message text next to structured output", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "app.log")
+		messages := filepath.Join(dir, "messages.log")
+		logger, err := NewZapLogger(NewLoggerConfig(
+			WithOutputPaths(path),
+			WithMessageOutput(messages),
+			WithLevel(zapcore.InfoLevel),
+		))
+		require.NoError(t, err)
+
+		logger.With(String("service", "billing")).Info("Deploying v1.2.3", String("region", "eu"))
+		logger.Debug("filtered")
+		logger.ForInfra("db").Warn("Replica lagging")
+		require.NoError(t, logger.Sync())
+
+		assert.Equal(t, "Deploying v1.2.3\nReplica lagging\n", readFile(t, messages))
+		assert.Contains(t, readFile(t, path), `"region":"eu"`)
+	})
+
+	t.Run("should ignore empty paths", func(t *testing.T) {
+		cfg := NewLoggerConfig(WithMessageOutput())
+		assert.Nil(t, cfg.MessageOutputs)
+	})
+}
@@ -0,0 +1,190 @@
+package xlogger
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"time"
+)
+
+// Headers read and written by HTTPMiddleware
+const (
+	// RequestIDHeader carries the identifier of a single request.
+	RequestIDHeader = "X-Request-ID"
+	// CorrelationIDHeader carries the identifier shared by related requests.
+	CorrelationIDHeader = "X-Correlation-ID"
+
+	traceparentHeader = "traceparent"
+	tracestateHeader  = "tracestate"
+)
+
+// maxIncomingIDLength bounds the request and correlation IDs HTTPMiddleware
+// accepts from clients
+const maxIncomingIDLength = 128
+
+// httpConfig holds the settings shared by HTTPMiddleware and
+// NewRoundTripper
+type httpConfig struct {
+	headers TraceHeaderNames
+}
+
+// HTTPOption configures HTTPMiddleware and NewRoundTripper
+type HTTPOption func(*httpConfig)
+
+// WithTraceHeaderNames sets the headers carrying the request and correlation
+// IDs, traceparent and tracestate, so every service of a deployment agrees
+// on one propagation format. Empty names keep the defaults.
+//
+// Example:
+//
+//	names := xlogger.WithTraceHeaderNames(xlogger.TraceHeaderNames{
+//	    RequestID: "X-Amzn-Trace-Id",
+//	})
+//	handler := xlogger.HTTPMiddleware(logger, names)(mux)
+//	client := &http.Client{Transport: xlogger.NewRoundTripper(logger, nil, names)}
+func WithTraceHeaderNames(names TraceHeaderNames) HTTPOption {
+	return func(c *httpConfig) {
+		c.headers = names
+	}
+}
+
+func newHTTPConfig(opts []HTTPOption) *httpConfig {
+	cfg := &httpConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	cfg.headers = cfg.headers.withDefaults()
+	return cfg
+}
+
+// HTTPMiddleware returns net/http middleware that propagates request and
+// correlation IDs and writes one access log entry per request.
+//
+// The X-Request-ID and X-Correlation-ID headers, or those set with
+// WithTraceHeaderNames, are reused when present and valid: at most 128
+// letters, digits and "-_.:;=+/@" characters. Otherwise a request ID is
+// generated and the correlation ID defaults to it. Both are echoed on the
+// response, stored with the traceparent and tracestate headers in the
+// request context with ContextWithTraceContext, and the handler runs inside
+// RunWithTraceContext of the TraceScope of logger with them, so every entry
+// logged while serving the request carries them.
+//
+// The access log entry has method, path, status, latency and bytes fields
+// and is written at Error for 5xx responses and at Info otherwise.
+//
+// Example:
+//
+//	mux := http.NewServeMux()
+//	mux.HandleFunc("/orders", handleOrders)
+//	http.ListenAndServe(":8080", xlogger.HTTPMiddleware(logger)(mux))
+func HTTPMiddleware(logger Logger, opts ...HTTPOption) func(http.Handler) http.Handler {
+	cfg := newHTTPConfig(opts)
+	scope := traceScopeOf(logger)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			tc := cfg.headers.Extract(r.Header)
+			if !validIncomingID(tc.RequestID) {
+				tc.RequestID = ""
+			}
+			if !validIncomingID(tc.CorrelationID) {
+				tc.CorrelationID = ""
+			}
+			if tc.RequestID == "" {
+				tc.RequestID = newRequestID(logger)
+			}
+			if tc.CorrelationID == "" {
+				tc.CorrelationID = tc.RequestID
+			}
+			w.Header().Set(cfg.headers.RequestID, tc.RequestID)
+			w.Header().Set(cfg.headers.CorrelationID, tc.CorrelationID)
+
+			ctxTrace := TraceContextFromContext(r.Context())
+			ctxTrace.RequestID, ctxTrace.CorrelationID = tc.RequestID, tc.CorrelationID
+			ctxTrace.Traceparent, ctxTrace.Tracestate = tc.Traceparent, tc.Tracestate
+			r = r.WithContext(ContextWithTraceContext(r.Context(), ctxTrace))
+			rw := newResponseRecorder(w)
+
+			_ = scope.RunWithTraceContext(tc, func() error {
+				next.ServeHTTP(rw, r)
+
+				fields := []Field{
+					String("method", r.Method),
+					String("path", r.URL.Path),
+					Int("status", rw.status),
+					Duration("latency", time.Since(start)),
+					Int64("bytes", rw.bytes),
+				}
+				if rw.status >= http.StatusInternalServerError {
+					logger.Error("http request completed", fields...)
+				} else {
+					logger.Info("http request completed", fields...)
+				}
+				return nil
+			})
+		})
+	}
+}
+
+// validIncomingID reports whether a client supplied ID is safe to log and
+// echo back
+func validIncomingID(id string) bool {
+	if len(id) > maxIncomingIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		switch c := id[i]; {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':', c == ';', c == '=', c == '+', c == '/', c == '@':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// responseRecorder records the status and size of a response
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	bytes       int64
+	wroteHeader bool
+}
+
+func newResponseRecorder(w http.ResponseWriter) *responseRecorder {
+	return &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+}
+
+func (w *responseRecorder) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseRecorder) Write(p []byte) (int, error) {
+	w.wroteHeader = true
+	n, err := w.ResponseWriter.Write(p)
+	w.bytes += int64(n)
+	return n, err
+}
+
+// Flush implements http.Flusher for handlers streaming responses, doing
+// nothing when the underlying writer cannot flush
+func (w *responseRecorder) Flush() {
+	w.wroteHeader = true
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Hijack implements http.Hijacker for handlers upgrading connections, for
+// example to websockets
+func (w *responseRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return http.NewResponseController(w.ResponseWriter).Hijack()
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer
+func (w *responseRecorder) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,202 @@
+package xlogger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestHTTPMiddleware tests request ID propagation and access logging
+func TestHTTPMiddleware(t *testing.T) {
+	t.Run("should propagate incoming IDs", func(t *testing.T) {
+		skipWithoutGLS(t)
+		logger, output := newFileLogger(t)
+
+		var gotRequestID, gotCorrelationID, ctxRequestID string
+		handler := HTTPMiddleware(logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			gotRequestID, gotCorrelationID = TraceRequestID(), TraceCorrelationID()
+			ctxRequestID, _ = TraceFromContext(r.Context())
+			logger.Info("handling order")
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte("created"))
+		}))
+
+		req := httptest.NewRequest(http.MethodPost, "/orders?id=1", nil)
+		req.Header.Set(RequestIDHeader, "req-1")
+		req.Header.Set(CorrelationIDHeader, "corr-1")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, "req-1", gotRequestID)
+		assert.Equal(t, "corr-1", gotCorrelationID)
+		assert.Equal(t, "req-1", ctxRequestID)
+		assert.Equal(t, "req-1", rec.Header().Get(RequestIDHeader))
+		assert.Equal(t, "corr-1", rec.Header().Get(CorrelationIDHeader))
+
+		log := output()
+		handling := entriesWithMessage(t, log, "handling order")
+		require.Len(t, handling, 1)
+		assert.Equal(t, "req-1", handling[0]["request_id"])
+
+		access := entriesWithMessage(t, log, "http request completed")
+		require.Len(t, access, 1)
+		assert.Equal(t, "info", access[0]["level"])
+		assert.Equal(t, "POST", access[0]["method"])
+		assert.Equal(t, "/orders", access[0]["path"])
+		assert.EqualValues(t, http.StatusCreated, access[0]["status"])
+		assert.EqualValues(t, 7, access[0]["bytes"])
+		assert.NotEmpty(t, access[0]["latency"])
+		assert.Equal(t, "req-1", access[0]["request_id"])
+		assert.Equal(t, "corr-1", access[0]["correlation_id"])
+	})
+
+	t.Run("should generate missing IDs", func(t *testing.T) {
+		logger, _ := newFileLogger(t)
+
+		handler := HTTPMiddleware(logger)(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		requestID := rec.Header().Get(RequestIDHeader)
+		assert.Len(t, requestID, 32)
+		assert.Equal(t, requestID, rec.Header().Get(CorrelationIDHeader))
+	})
+
+	t.Run("should replace invalid incoming IDs", func(t *testing.T) {
+		logger, _ := newFileLogger(t)
+
+		handler := HTTPMiddleware(logger)(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+		for _, id := range []string{"req 1", "req\"1", "<script>", strings.Repeat("a", 129)} {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(RequestIDHeader, id)
+			req.Header.Set(CorrelationIDHeader, id)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			requestID := rec.Header().Get(RequestIDHeader)
+			assert.Len(t, requestID, 32, id)
+			assert.Equal(t, requestID, rec.Header().Get(CorrelationIDHeader), id)
+		}
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(RequestIDHeader, "Root=1-5759e988-bd862e3fe1be46a994272793;Sampled=1")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		assert.Equal(t, "Root=1-5759e988-bd862e3fe1be46a994272793;Sampled=1", rec.Header().Get(RequestIDHeader))
+	})
+
+	t.Run("should let handlers flush and hijack the response", func(t *testing.T) {
+		logger, _ := newFileLogger(t)
+
+		handler := HTTPMiddleware(logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/events" {
+				_, _ = w.Write([]byte("data: ready\n\n"))
+				w.(http.Flusher).Flush()
+				return
+			}
+			conn, buf, err := w.(http.Hijacker).Hijack()
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			defer func() { _ = conn.Close() }()
+			_, _ = buf.WriteString("HTTP/1.1 101 Switching Protocols\r\nConnection: Upgrade\r\nUpgrade: websocket\r\n\r\n")
+			_ = buf.Flush()
+		}))
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/events", nil))
+		assert.True(t, rec.Flushed)
+
+		server := httptest.NewServer(handler)
+		defer server.Close()
+		req, err := http.NewRequest(http.MethodGet, server.URL+"/ws", nil)
+		require.NoError(t, err)
+		req.Header.Set("Connection", "Upgrade")
+		req.Header.Set("Upgrade", "websocket")
+		resp, err := server.Client().Do(req)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		assert.Equal(t, http.StatusSwitchingProtocols, resp.StatusCode)
+	})
+
+	t.Run("should generate IDs with the generator of the logger", func(t *testing.T) {
+		logger, err := NewZapLogger(NewLoggerConfig(WithOutputPaths(t.TempDir()+"/app.log"), WithIDGenerator(NewULID)))
+		require.NoError(t, err)
+		defer func() { _ = logger.Close(t.Context()) }()
+
+		handler := HTTPMiddleware(logger.ForInfra("http"))(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		assert.Len(t, rec.Header().Get(RequestIDHeader), 26)
+		assert.Len(t, NewRequestID(), 32)
+	})
+
+	t.Run("should run inside the incoming traceparent", func(t *testing.T) {
+		skipWithoutGLS(t)
+		logger, _ := newFileLogger(t)
+		header := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+		var got string
+		handler := HTTPMiddleware(logger)(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+			got = TraceParent()
+		}))
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("traceparent", header)
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+
+		assert.Equal(t, header, got)
+	})
+
+	t.Run("should store the incoming traceparent in the request context", func(t *testing.T) {
+		logger, _ := newFileLogger(t)
+		header := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+		var got string
+		handler := HTTPMiddleware(logger)(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			got = TraceParentFromContext(r.Context())
+		}))
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("traceparent", header)
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+
+		assert.Equal(t, header, got)
+	})
+
+	t.Run("should run inside the trace scope of the logger", func(t *testing.T) {
+		skipWithoutGLS(t)
+		scope := NewTraceScope()
+		logger, err := NewZapLogger(NewLoggerConfig(WithOutputPaths(filepath.Join(t.TempDir(), "app.log")), WithTraceScope(scope)))
+		require.NoError(t, err)
+
+		var got string
+		handler := HTTPMiddleware(logger)(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+			got = scope.TraceRequestID()
+		}))
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(RequestIDHeader, "req-1")
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+
+		assert.Equal(t, "req-1", got)
+	})
+
+	t.Run("should log server errors at error level", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+
+		handler := HTTPMiddleware(logger)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			http.Error(w, "boom", http.StatusServiceUnavailable)
+		}))
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health", nil))
+
+		access := entriesWithMessage(t, output(), "http request completed")
+		require.Len(t, access, 1)
+		assert.Equal(t, "error", access[0]["level"])
+		assert.EqualValues(t, http.StatusServiceUnavailable, access[0]["status"])
+	})
+}
@@ -0,0 +1,177 @@
+package xlogger
+
+import (
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// nameSeparator joins the names of nested Named loggers
+const nameSeparator = "."
+
+// nameLevels holds level overrides of logger names. An override applies to
+// the name and every name below it, the most specific override winning.
+type nameLevels struct {
+	componentLevels
+}
+
+func newNameLevels() *nameLevels {
+	n := &nameLevels{}
+	n.overrides.Store(&map[string]zapcore.Level{})
+	return n
+}
+
+// lookup returns the override of name or of its closest parent
+func (n *nameLevels) lookup(name string) (zapcore.Level, bool) {
+	overrides := *n.overrides.Load()
+	if len(overrides) == 0 {
+		return 0, false
+	}
+	for name != "" {
+		if level, ok := overrides[name]; ok {
+			return level, true
+		}
+		i := strings.LastIndex(name, nameSeparator)
+		if i < 0 {
+			break
+		}
+		name = name[:i]
+	}
+	return 0, false
+}
+
+// levelOf returns the level of name, falling back to global
+func (n *nameLevels) levelOf(name string, global zap.AtomicLevel) zapcore.Level {
+	if level, ok := n.lookup(name); ok {
+		return level
+	}
+	return global.Level()
+}
+
+// enabler returns the level of the cores below a nameLevelCore, enabling
+// the levels of global and of every override
+func (n *nameLevels) enabler(global zap.AtomicLevel) zapcore.LevelEnabler {
+	return zap.LevelEnablerFunc(func(level zapcore.Level) bool {
+		if global.Enabled(level) {
+			return true
+		}
+		for _, override := range *n.overrides.Load() {
+			if override.Enabled(level) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// nameLevelCore filters entries with the override of their logger name,
+// falling back to the shared level. Its inner cores accept the levels of
+// every override so an override can be more verbose than the shared level.
+type nameLevelCore struct {
+	zapcore.Core
+	global    zap.AtomicLevel
+	levels    *nameLevels
+	explainer *dropExplainer
+}
+
+// Level reports the shared level to zap.Logger.Level
+func (c *nameLevelCore) Level() zapcore.Level {
+	return c.global.Level()
+}
+
+// With implements zapcore.Core
+func (c *nameLevelCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.Core = c.Core.With(fields)
+	return &clone
+}
+
+// Check implements zapcore.Core
+func (c *nameLevelCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if level := c.levels.levelOf(ent.LoggerName, c.global); !level.Enabled(ent.Level) {
+		if c.explainer != nil {
+			c.explainer.explain(ent, fmt.Sprintf(dropReasonLevel, ent.Level, level))
+		}
+		return ce
+	}
+	return c.Core.Check(ent, ce)
+}
+
+// Named returns a logger whose entries carry name in the "logger" key,
+// appended to the name of this logger with a dot, so Named("users") of a
+// logger named "api" logs as "api.users". Named loggers keep the fields of
+// this logger and are created once per name. Levels set with SetNamedLevel
+// apply to the name and every name below it. An empty name returns the
+// logger itself.
+//
+// Example:
+//
+//	repo := logger.Named("api").Named("users").Named("repo")
+//	repo.Debug("Query built") // "logger":"api.users.repo"
+//
+//	logger.SetNamedLevel("api.users", zapcore.DebugLevel)
+func (l *ZapLogger) Named(name string) Logger {
+	if name == "" {
+		return l
+	}
+	return l.namedCache().get(name, func() Logger {
+		child := l.derive(l.logger.Named(name), l.contextTrace)
+		child.name = name
+		if l.name != "" {
+			child.name = l.name + nameSeparator + name
+		}
+		return child
+	})
+}
+
+// namedCache returns the cache of Named loggers, created on first use so
+// With does not allocate one for every child
+func (l *ZapLogger) namedCache() *componentCache {
+	if cache := l.named.Load(); cache != nil {
+		return cache
+	}
+	l.named.CompareAndSwap(nil, newComponentCache())
+	return l.named.Load()
+}
+
+// SetNamedLevel overrides the level of loggers named name with Named and of
+// the names below it, unless they have an override of their own. Overrides
+// may be more or less verbose than the shared level. Infrastructure loggers
+// follow their component level instead.
+func (l *ZapLogger) SetNamedLevel(name string, level zapcore.Level) {
+	if l.nameLevels == nil {
+		return
+	}
+	l.nameLevels.update(func(levels map[string]zapcore.Level) {
+		levels[name] = level
+	})
+}
+
+// ResetNamedLevel removes the override of name so it follows its parent
+// name or the shared level again.
+func (l *ZapLogger) ResetNamedLevel(name string) {
+	if l.nameLevels == nil {
+		return
+	}
+	l.nameLevels.update(func(levels map[string]zapcore.Level) {
+		delete(levels, name)
+	})
+}
+
+// NamedLevel returns the effective level of loggers named name.
+func (l *ZapLogger) NamedLevel(name string) zapcore.Level {
+	if l.nameLevels == nil {
+		return l.level.Level()
+	}
+	return l.nameLevels.levelOf(name, l.level)
+}
+
+// NamedLevels returns the current per-name level overrides.
+func (l *ZapLogger) NamedLevels() map[string]zapcore.Level {
+	if l.nameLevels == nil {
+		return map[string]zapcore.Level{}
+	}
+	return l.nameLevels.all()
+}
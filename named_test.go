@@ -0,0 +1,81 @@
+package xlogger
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+// TestZapLogger_Named tests hierarchical logger names
+func TestZapLogger_Named(t *testing.T) {
+	t.Run("should join nested names with dots", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+
+		logger.Named("api").Named("users").Named("repo").Info("query built")
+		logger.Named("").Info("unnamed")
+
+		entries := entriesWithMessage(t, output(), "query built")
+		require.Len(t, entries, 1)
+		assert.Equal(t, "api.users.repo", entries[0]["logger"])
+		entries = entriesWithMessage(t, output(), "unnamed")
+		require.Len(t, entries, 1)
+		assert.NotContains(t, entries[0], "logger")
+	})
+
+	t.Run("should cache named loggers and keep fields", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+		child := logger.With(String("worker", "w1"))
+
+		assert.Same(t, child.Named("jobs"), child.Named("jobs"))
+		assert.NotSame(t, logger.Named("jobs"), child.Named("jobs"))
+
+		child.Named("jobs").Info("job done")
+		entries := entriesWithMessage(t, output(), "job done")
+		require.Len(t, entries, 1)
+		assert.Equal(t, "w1", entries[0]["worker"])
+		assert.Equal(t, "jobs", entries[0]["logger"])
+	})
+
+	t.Run("should apply the most specific name level", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+		logger.SetNamedLevel("api", zapcore.DebugLevel)
+		logger.SetNamedLevel("api.health", zapcore.ErrorLevel)
+
+		api := logger.Named("api")
+		api.Named("users").Debug("users debug")
+		api.Named("health").Warn("health warn")
+		logger.Debug("base debug")
+
+		assert.Equal(t, zapcore.DebugLevel, api.Named("users").Level())
+		assert.Equal(t, zapcore.ErrorLevel, api.Named("health").Level())
+		assert.Equal(t, zapcore.InfoLevel, logger.Level())
+		assert.Contains(t, output(), "users debug")
+		assert.NotContains(t, output(), "health warn")
+		assert.NotContains(t, output(), "base debug")
+
+		logger.ResetNamedLevel("api.health")
+		api.Named("health").Warn("health warn again")
+		assert.Contains(t, output(), "health warn again")
+		assert.Equal(t, map[string]zapcore.Level{"api": zapcore.DebugLevel}, logger.NamedLevels())
+	})
+
+	t.Run("should keep sink levels independent of name levels", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "errors.log")
+		logger, err := NewZapLogger(NewLoggerConfig(
+			WithOutputPaths(filepath.Join(t.TempDir(), "app.log")),
+			WithSink(SinkConfig{Output: path, MinLevel: zapcore.ErrorLevel}),
+		))
+		require.NoError(t, err)
+
+		logger.SetNamedLevel("noisy", zapcore.DebugLevel)
+		logger.Named("noisy").Debug("noisy debug")
+		logger.Named("noisy").Error("noisy error")
+
+		output := readFile(t, path)
+		assert.NotContains(t, output, "noisy debug")
+		assert.Contains(t, output, "noisy error")
+	})
+}
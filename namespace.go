@@ -0,0 +1,73 @@
+package xlogger
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Namespace creates a field nesting the fields after it in the same call
+// under key, like zap.Namespace, for schemas that group keys into objects.
+// Trace fields, tags and retention hints stay at the top level. Given to
+// With, it nests the fields of every later entry, trace fields included.
+//
+// Example:
+//
+//	logger.Info("request served",
+//	    xlogger.String("route", "/orders"),
+//	    xlogger.Namespace("http"),
+//	    xlogger.String("method", "GET"),
+//	    xlogger.Int("status", 200),
+//	)
+//	// {"message":"request served","route":"/orders","http":{"method":"GET","status":200}}
+func Namespace(key string) Field {
+	return Field{key: key, typ: NamespaceType}
+}
+
+// isNamespace reports whether field opens a namespace, including wrapped
+// zap.Namespace fields
+func isNamespace(field Field) bool {
+	switch field.Type() {
+	case NamespaceType:
+		return true
+	case ZapType:
+		return field.Value().(zap.Field).Type == zapcore.NamespaceType
+	}
+	return false
+}
+
+// topLevel returns the number of fields before the first namespace
+func topLevel(fields []Field) int {
+	for i, field := range fields {
+		if isNamespace(field) {
+			return i
+		}
+	}
+	return len(fields)
+}
+
+// appendTopLevel appends extra before the first namespace of fields, so it
+// is not nested, copying fields when extra is inserted
+func appendTopLevel(fields []Field, extra ...Field) []Field {
+	i := topLevel(fields)
+	if i == len(fields) {
+		return append(fields, extra...)
+	}
+	inserted := make([]Field, 0, len(fields)+len(extra))
+	inserted = append(append(inserted, fields[:i]...), extra...)
+	return append(inserted, fields[i:]...)
+}
+
+// appendTopLevelZap appends extra before the first namespace of fields,
+// always copying fields
+func appendTopLevelZap(fields []zapcore.Field, extra ...zapcore.Field) []zapcore.Field {
+	i := len(fields)
+	for j, field := range fields {
+		if field.Type == zapcore.NamespaceType {
+			i = j
+			break
+		}
+	}
+	inserted := make([]zapcore.Field, 0, len(fields)+len(extra))
+	inserted = append(append(inserted, fields[:i]...), extra...)
+	return append(inserted, fields[i:]...)
+}
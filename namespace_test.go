@@ -0,0 +1,62 @@
+package xlogger
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// TestNamespace tests grouping fields into nested objects
+func TestNamespace(t *testing.T) {
+	t.Run("should nest the fields after the namespace", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+
+		logger.Info("request served",
+			String("route", "/orders"),
+			Namespace("http"),
+			String("method", "GET"),
+			Int("status", 200),
+			ZapField(zap.Namespace("client")),
+			String("ip", "10.0.0.1"),
+		)
+
+		entries := entriesWithMessage(t, output(), "request served")
+		require.Len(t, entries, 1)
+		assert.Equal(t, "/orders", entries[0]["route"])
+		assert.Equal(t, map[string]interface{}{
+			"method": "GET",
+			"status": float64(200),
+			"client": map[string]interface{}{"ip": "10.0.0.1"},
+		}, entries[0]["http"])
+	})
+
+	t.Run("should keep trace fields, tags and retention at the top level", func(t *testing.T) {
+		skipWithoutGLS(t)
+		path := filepath.Join(t.TempDir(), "app.log")
+		logger, err := NewZapLogger(NewLoggerConfig(
+			WithOutputPaths(path),
+			WithRetentionHints(map[zapcore.Level]time.Duration{zapcore.InfoLevel: time.Hour}),
+		))
+		require.NoError(t, err)
+
+		fields := []Field{Namespace("http"), String("method", "GET"), Tags("slow")}
+		require.NoError(t, RunWithTrace("req-1", "corr-1", func() error {
+			logger.WithTags("api").Info("request served", fields...)
+			return nil
+		}))
+
+		entries := entriesWithMessage(t, readFile(t, path), "request served")
+		require.Len(t, entries, 1)
+		assert.Equal(t, "req-1", entries[0]["request_id"])
+		assert.Equal(t, "corr-1", entries[0]["correlation_id"])
+		assert.Equal(t, []interface{}{"api", "slow"}, entries[0]["tags"])
+		assert.Equal(t, "1h0m0s", entries[0]["retention"])
+		assert.Equal(t, map[string]interface{}{"method": "GET"}, entries[0]["http"])
+		assert.Equal(t, NamespaceType, fields[0].Type(), "caller fields are left untouched")
+	})
+}
@@ -0,0 +1,136 @@
+package xlogger
+
+import (
+	"strings"
+	"sync/atomic"
+)
+
+// natsComponent is the infrastructure component of NATS connection events
+const natsComponent = "nats"
+
+// natsSlowConsumer is part of the message of nats.ErrSlowConsumer
+const natsSlowConsumer = "slow consumer"
+
+// NATSConn is the part of *nats.Conn read by NATSLogger.
+type NATSConn interface {
+	ConnectedUrlRedacted() string
+	ConnectedServerId() string
+	LastError() error
+}
+
+// NATSSubscription is the part of *nats.Subscription read by NATSLogger.
+type NATSSubscription interface {
+	Pending() (int, int, error)
+	Dropped() (int, error)
+}
+
+// NATSLogger logs the connection events of a nats.go client under the
+// "nats" component. Its methods have the signatures of the nats.go
+// handlers, so they are passed as is to the handler options; the type
+// parameters are *nats.Conn and *nats.Subscription, which keeps xlogger
+// free of a nats.go dependency.
+type NATSLogger[C NATSConn, S NATSSubscription] struct {
+	logger     Logger
+	subject    func(S) string
+	reconnects *atomic.Int64 // Shared with the copies of WithSubject
+}
+
+// NewNATSLogger returns a NATS connection event logger.
+//
+// Example:
+//
+//	natsLogger := xlogger.NewNATSLogger[*nats.Conn, *nats.Subscription](logger)
+//	nc, err := nats.Connect(url,
+//	    nats.DisconnectErrHandler(natsLogger.Disconnected),
+//	    nats.ReconnectHandler(natsLogger.Reconnected),
+//	    nats.ClosedHandler(natsLogger.Closed),
+//	    nats.LameDuckModeHandler(natsLogger.LameDuckMode),
+//	    nats.ErrorHandler(natsLogger.AsyncError),
+//	)
+func NewNATSLogger[C NATSConn, S NATSSubscription](logger Logger) *NATSLogger[C, S] {
+	return &NATSLogger[C, S]{logger: logger.ForInfra(natsComponent), reconnects: new(atomic.Int64)}
+}
+
+// WithSubject returns a copy of the logger reading the subject of the
+// subscription of async errors, which nats.go only exposes as a field. The
+// copy shares the reconnection count of the logger.
+//
+// Example:
+//
+//	natsLogger = natsLogger.WithSubject(func(sub *nats.Subscription) string { return sub.Subject })
+func (n *NATSLogger[C, S]) WithSubject(subject func(S) string) *NATSLogger[C, S] {
+	clone := *n
+	clone.subject = subject
+	return &clone
+}
+
+// Disconnected logs a lost connection at Warn with its error, and a
+// disconnection without error, such as on Close, at Info. It is a
+// nats.ConnErrHandler.
+func (n *NATSLogger[C, S]) Disconnected(conn C, err error) {
+	if err == nil {
+		n.logger.Info("nats disconnected", natsConnFields(conn)...)
+		return
+	}
+	n.logger.Warn("nats disconnected", append(natsConnFields(conn), Error(err))...)
+}
+
+// Reconnected logs a reconnection at Info with the number of reconnections
+// logged so far. It is a nats.ConnHandler.
+func (n *NATSLogger[C, S]) Reconnected(conn C) {
+	n.logger.Info("nats reconnected", append(natsConnFields(conn), Int64("reconnects", n.reconnects.Add(1)))...)
+}
+
+// Closed logs a closed connection at Info, or at Warn with the last error
+// of the connection, such as when reconnecting gave up. It is a
+// nats.ConnHandler.
+func (n *NATSLogger[C, S]) Closed(conn C) {
+	if err := conn.LastError(); err != nil {
+		n.logger.Warn("nats connection closed", append(natsConnFields(conn), Error(err))...)
+		return
+	}
+	n.logger.Info("nats connection closed", natsConnFields(conn)...)
+}
+
+// LameDuckMode logs at Warn that the server of the connection is shutting
+// down. It is a nats.ConnHandler.
+func (n *NATSLogger[C, S]) LameDuckMode(conn C) {
+	n.logger.Warn("nats server entering lame duck mode", natsConnFields(conn)...)
+}
+
+// AsyncError logs an asynchronous error: slow consumers at Warn as "nats
+// slow consumer", other errors at Error. Entries of open subscriptions
+// carry their pending_msgs, pending_bytes and dropped counts, and the
+// subject read by WithSubject. It is a nats.ErrHandler.
+func (n *NATSLogger[C, S]) AsyncError(conn C, sub S, err error) {
+	fields := append(natsConnFields(conn), Error(err))
+	// Pending fails for nil and closed subscriptions
+	if msgs, bytes, pendingErr := sub.Pending(); pendingErr == nil {
+		fields = append(fields, Int("pending_msgs", msgs), Int("pending_bytes", bytes))
+		if dropped, droppedErr := sub.Dropped(); droppedErr == nil {
+			fields = append(fields, Int("dropped", dropped))
+		}
+		if n.subject != nil {
+			fields = append(fields, String("subject", n.subject(sub)))
+		}
+	}
+
+	if err != nil && strings.Contains(err.Error(), natsSlowConsumer) {
+		n.logger.Warn("nats slow consumer", fields...)
+		return
+	}
+	n.logger.Error("nats async error", fields...)
+}
+
+// natsConnFields returns the server URL and ID of conn, which are empty and
+// left out while it is not connected
+func natsConnFields(conn NATSConn) []Field {
+	var fields []Field
+	if url := conn.ConnectedUrlRedacted(); url != "" {
+		fields = append(fields, String("server_url", url))
+	}
+	if id := conn.ConnectedServerId(); id != "" {
+		fields = append(fields, String("server_id", id))
+	}
+	return fields
+}
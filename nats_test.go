@@ -0,0 +1,128 @@
+package xlogger
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeNATSConn stands for *nats.Conn
+type fakeNATSConn struct {
+	url     string
+	id      string
+	lastErr error
+}
+
+func (c *fakeNATSConn) ConnectedUrlRedacted() string { return c.url }
+func (c *fakeNATSConn) ConnectedServerId() string    { return c.id }
+func (c *fakeNATSConn) LastError() error             { return c.lastErr }
+
+// fakeNATSSubscription stands for *nats.Subscription, failing like it when nil
+type fakeNATSSubscription struct {
+	Subject string
+	pending int
+	dropped int
+}
+
+func (s *fakeNATSSubscription) Pending() (int, int, error) {
+	if s == nil {
+		return -1, -1, errors.New("nats: invalid subscription")
+	}
+	return s.pending, s.pending * 100, nil
+}
+
+func (s *fakeNATSSubscription) Dropped() (int, error) {
+	if s == nil {
+		return -1, errors.New("nats: invalid subscription")
+	}
+	return s.dropped, nil
+}
+
+// TestNATSLogger tests the NATS connection event handlers
+func TestNATSLogger(t *testing.T) {
+	conn := &fakeNATSConn{url: "nats://nats-1:4222", id: "NAXYZ"}
+
+	t.Run("should log connection events with the server", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+		natsLogger := NewNATSLogger[*fakeNATSConn, *fakeNATSSubscription](logger)
+
+		natsLogger.Disconnected(&fakeNATSConn{}, errors.New("connection reset by peer"))
+		natsLogger.Reconnected(conn)
+		natsLogger.Reconnected(conn)
+		natsLogger.LameDuckMode(conn)
+
+		log := output()
+		disconnected := entriesWithMessage(t, log, "nats disconnected")
+		require.Len(t, disconnected, 1)
+		assert.Equal(t, "warn", disconnected[0]["level"])
+		assert.Equal(t, "nats", disconnected[0]["component"])
+		assert.Equal(t, "connection reset by peer", disconnected[0]["error"])
+		assert.NotContains(t, disconnected[0], "server_url")
+
+		reconnected := entriesWithMessage(t, log, "nats reconnected")
+		require.Len(t, reconnected, 2)
+		assert.Equal(t, "nats://nats-1:4222", reconnected[0]["server_url"])
+		assert.Equal(t, "NAXYZ", reconnected[0]["server_id"])
+		assert.Equal(t, float64(2), reconnected[1]["reconnects"])
+		assert.Equal(t, "warn", entriesWithMessage(t, log, "nats server entering lame duck mode")[0]["level"])
+	})
+
+	t.Run("should return a copy from WithSubject sharing the reconnection count", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+		natsLogger := NewNATSLogger[*fakeNATSConn, *fakeNATSSubscription](logger)
+		withSubject := natsLogger.WithSubject(func(sub *fakeNATSSubscription) string { return sub.Subject })
+
+		sub := &fakeNATSSubscription{Subject: "orders.created"}
+		natsLogger.AsyncError(conn, sub, errors.New("nats: permissions violation"))
+		natsLogger.Reconnected(conn)
+		withSubject.Reconnected(conn)
+
+		log := output()
+		asyncErr := entriesWithMessage(t, log, "nats async error")
+		require.Len(t, asyncErr, 1)
+		assert.NotContains(t, asyncErr[0], "subject")
+		reconnected := entriesWithMessage(t, log, "nats reconnected")
+		require.Len(t, reconnected, 2)
+		assert.Equal(t, float64(2), reconnected[1]["reconnects"])
+	})
+
+	t.Run("should log closed connections at warn with their last error", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+		natsLogger := NewNATSLogger[*fakeNATSConn, *fakeNATSSubscription](logger)
+
+		natsLogger.Closed(&fakeNATSConn{})
+		natsLogger.Closed(&fakeNATSConn{lastErr: errors.New("nats: no servers available for connection")})
+
+		closed := entriesWithMessage(t, output(), "nats connection closed")
+		require.Len(t, closed, 2)
+		assert.Equal(t, "info", closed[0]["level"])
+		assert.Equal(t, "warn", closed[1]["level"])
+		assert.Equal(t, "nats: no servers available for connection", closed[1]["error"])
+	})
+
+	t.Run("should log slow consumers with the subscription", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+		natsLogger := NewNATSLogger[*fakeNATSConn, *fakeNATSSubscription](logger).
+			WithSubject(func(sub *fakeNATSSubscription) string { return sub.Subject })
+
+		sub := &fakeNATSSubscription{Subject: "orders.created", pending: 65536, dropped: 12}
+		natsLogger.AsyncError(conn, sub, errors.New("nats: slow consumer, messages dropped"))
+		natsLogger.AsyncError(conn, nil, errors.New("nats: permissions violation"))
+
+		log := output()
+		slow := entriesWithMessage(t, log, "nats slow consumer")
+		require.Len(t, slow, 1)
+		assert.Equal(t, "warn", slow[0]["level"])
+		assert.Equal(t, "orders.created", slow[0]["subject"])
+		assert.Equal(t, float64(65536), slow[0]["pending_msgs"])
+		assert.Equal(t, float64(12), slow[0]["dropped"])
+
+		asyncErr := entriesWithMessage(t, log, "nats async error")
+		require.Len(t, asyncErr, 1)
+		assert.Equal(t, "error", asyncErr[0]["level"])
+		assert.NotContains(t, asyncErr[0], "subject")
+		assert.NotContains(t, asyncErr[0], "pending_msgs")
+	})
+}
@@ -0,0 +1,290 @@
+package xlogger
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+)
+
+// netComponent is the infrastructure component used by dialer and resolver logging
+const netComponent = "net"
+
+// Default sampling of successful lookups and dials, per host and operation
+const (
+	defaultNetSampleTick       = time.Second
+	defaultNetSampleFirst      = 10
+	defaultNetSampleThereafter = 100
+)
+
+// netSampler decides which successful lookups and dials are logged: the
+// first entries per key and tick, then every Nth. Failures are never sampled.
+type netSampler struct {
+	tick       time.Duration
+	first      uint64
+	thereafter uint64
+
+	mu     sync.Mutex
+	counts map[string]*netSampleCount
+}
+
+type netSampleCount struct {
+	resetAt time.Time
+	n       uint64
+}
+
+func newNetSampler(tick time.Duration, first, thereafter int) *netSampler {
+	if tick <= 0 {
+		tick = defaultNetSampleTick
+	}
+	if first < 0 {
+		first = 0
+	}
+	if thereafter < 0 {
+		thereafter = 0
+	}
+	return &netSampler{
+		tick:       tick,
+		first:      uint64(first),
+		thereafter: uint64(thereafter),
+		counts:     make(map[string]*netSampleCount),
+	}
+}
+
+// sample reports whether the entry for key should be logged
+func (s *netSampler) sample(key string) bool {
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	count, ok := s.counts[key]
+	if !ok || now.After(count.resetAt) {
+		// Drop expired keys so the map tracks only recently active hosts
+		if !ok && len(s.counts) >= maxNetSampleKeys {
+			for k, c := range s.counts {
+				if now.After(c.resetAt) {
+					delete(s.counts, k)
+				}
+			}
+		}
+		count = &netSampleCount{resetAt: now.Add(s.tick)}
+		s.counts[key] = count
+	}
+
+	count.n++
+	if count.n <= s.first {
+		return true
+	}
+	return s.thereafter > 0 && (count.n-s.first)%s.thereafter == 0
+}
+
+// maxNetSampleKeys bounds the sampler before expired keys are pruned
+const maxNetSampleKeys = 1024
+
+// Resolver wraps a net.Resolver and logs DNS lookups under the "net"
+// component: successful lookups at Debug (sampled per host) and failures at
+// Warn, each with the lookup duration.
+type Resolver struct {
+	resolver *net.Resolver
+	logger   Logger
+	sampler  *netSampler
+}
+
+// NewResolver returns a logging resolver. A nil resolver uses net.DefaultResolver.
+//
+// Example:
+//
+//	resolver := xlogger.NewResolver(logger, nil)
+//	addrs, err := resolver.LookupHost(ctx, "db.internal")
+func NewResolver(logger Logger, resolver *net.Resolver) *Resolver {
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+	return &Resolver{
+		resolver: resolver,
+		logger:   logger.ForInfra(netComponent),
+		sampler:  newNetSampler(defaultNetSampleTick, defaultNetSampleFirst, defaultNetSampleThereafter),
+	}
+}
+
+// WithSampling logs the first successful lookups per host and tick, then
+// every thereafter-th. A thereafter of 0 drops the rest of the tick.
+func (r *Resolver) WithSampling(tick time.Duration, first, thereafter int) *Resolver {
+	r.sampler = newNetSampler(tick, first, thereafter)
+	return r
+}
+
+// LookupHost looks up host and returns its addresses.
+func (r *Resolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	start := time.Now()
+	addrs, err := r.resolver.LookupHost(ctx, host)
+	r.log(host, start, len(addrs), err)
+	return addrs, err
+}
+
+// LookupIPAddr looks up host and returns its IP addresses.
+func (r *Resolver) LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error) {
+	start := time.Now()
+	addrs, err := r.resolver.LookupIPAddr(ctx, host)
+	r.log(host, start, len(addrs), err)
+	return addrs, err
+}
+
+// LookupIP looks up host for the network "ip", "ip4" or "ip6".
+func (r *Resolver) LookupIP(ctx context.Context, network, host string) ([]net.IP, error) {
+	start := time.Now()
+	ips, err := r.resolver.LookupIP(ctx, network, host)
+	r.log(host, start, len(ips), err)
+	return ips, err
+}
+
+func (r *Resolver) log(host string, start time.Time, addresses int, err error) {
+	fields := []Field{
+		String("host", host),
+		Duration("duration", time.Since(start)),
+	}
+	if err != nil {
+		r.logger.Warn("dns lookup failed", append(fields, Error(err))...)
+		return
+	}
+	if r.sampler.sample("lookup " + host) {
+		r.logger.Debug("dns lookup completed", append(fields, Int("addresses", addresses))...)
+	}
+}
+
+// Dialer wraps a net.Dialer and logs DNS lookups and connection
+// establishment under the "net" component. Hostnames are resolved through
+// a logging Resolver and the addresses are dialed in order until one
+// connects. Failed attempts before the last are logged at Warn and a dial
+// that fails altogether at Error, each with its duration.
+type Dialer struct {
+	dialer   *net.Dialer
+	resolver *Resolver
+	logger   Logger
+	sampler  *netSampler
+}
+
+// NewDialer returns a logging dialer. A nil dialer uses a zero net.Dialer;
+// its Resolver, if set, is used for lookups.
+//
+// Example:
+//
+//	dialer := xlogger.NewDialer(logger, &net.Dialer{Timeout: 5 * time.Second})
+//	transport := &http.Transport{DialContext: dialer.DialContext}
+func NewDialer(logger Logger, dialer *net.Dialer) *Dialer {
+	if dialer == nil {
+		dialer = &net.Dialer{}
+	}
+	return &Dialer{
+		dialer:   dialer,
+		resolver: NewResolver(logger, dialer.Resolver),
+		logger:   logger.ForInfra(netComponent),
+		sampler:  newNetSampler(defaultNetSampleTick, defaultNetSampleFirst, defaultNetSampleThereafter),
+	}
+}
+
+// WithSampling sets the sampling of successful lookups and connections,
+// see Resolver.WithSampling.
+func (d *Dialer) WithSampling(tick time.Duration, first, thereafter int) *Dialer {
+	d.sampler = newNetSampler(tick, first, thereafter)
+	d.resolver.WithSampling(tick, first, thereafter)
+	return d
+}
+
+// Dial connects to address on the named network.
+func (d *Dialer) Dial(network, address string) (net.Conn, error) {
+	return d.DialContext(context.Background(), network, address)
+}
+
+// DialContext connects to address on the named network using ctx.
+func (d *Dialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	start := time.Now()
+
+	host, port, err := net.SplitHostPort(address)
+	if err != nil || !isResolvable(network) || net.ParseIP(host) != nil {
+		// Literal IPs, unix sockets and malformed addresses go straight to the dialer
+		return d.dialAttempts(ctx, network, address, []string{address}, start)
+	}
+
+	lookupNetwork := "ip"
+	switch network {
+	case "tcp4", "udp4":
+		lookupNetwork = "ip4"
+	case "tcp6", "udp6":
+		lookupNetwork = "ip6"
+	}
+	ips, err := d.resolver.LookupIP(ctx, lookupNetwork, host)
+	if err != nil {
+		return nil, err
+	}
+
+	targets := make([]string, len(ips))
+	for i, ip := range ips {
+		targets[i] = net.JoinHostPort(ip.String(), port)
+	}
+	return d.dialAttempts(ctx, network, address, targets, start)
+}
+
+// dialAttempts dials targets in order and logs the outcome
+func (d *Dialer) dialAttempts(ctx context.Context, network, address string, targets []string, start time.Time) (net.Conn, error) {
+	if d.dialer.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d.dialer.Timeout)
+		defer cancel()
+	}
+	dialer := *d.dialer
+	dialer.Timeout = 0
+
+	var lastErr error
+	attempts := 0
+	for _, target := range targets {
+		attempts++
+		attemptStart := time.Now()
+		conn, err := dialer.DialContext(ctx, network, target)
+		if err == nil {
+			if d.sampler.sample("dial " + address) {
+				d.logger.Debug("connection established",
+					String("network", network),
+					String("address", address),
+					String("remote_addr", conn.RemoteAddr().String()),
+					Int("attempts", attempts),
+					Duration("duration", time.Since(start)),
+				)
+			}
+			return conn, nil
+		}
+
+		lastErr = err
+		if ctx.Err() != nil || attempts == len(targets) {
+			break
+		}
+		d.logger.Warn("connection attempt failed",
+			String("network", network),
+			String("address", address),
+			String("target", target),
+			Int("attempt", attempts),
+			Duration("duration", time.Since(attemptStart)),
+			Error(err),
+		)
+	}
+
+	d.logger.Error("connection failed",
+		String("network", network),
+		String("address", address),
+		Int("attempts", attempts),
+		Duration("duration", time.Since(start)),
+		Error(lastErr),
+	)
+	return nil, lastErr
+}
+
+// isResolvable reports whether addresses on network may carry hostnames
+func isResolvable(network string) bool {
+	switch network {
+	case "tcp", "tcp4", "tcp6", "udp", "udp4", "udp6":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,150 @@
+package xlogger
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+// failingResolver returns a resolver whose DNS server cannot be reached
+func failingResolver() *net.Resolver {
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(context.Context, string, string) (net.Conn, error) {
+			return nil, errors.New("dns server unreachable")
+		},
+	}
+}
+
+// TestDialer tests the logging dialer
+func TestDialer(t *testing.T) {
+	t.Run("should log established connections", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+		logger.SetLevel(zapcore.DebugLevel)
+
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer listener.Close()
+
+		conn, err := NewDialer(logger, nil).Dial("tcp", listener.Addr().String())
+		require.NoError(t, err)
+		conn.Close()
+
+		entries := entriesWithMessage(t, output(), "connection established")
+		require.Len(t, entries, 1)
+		assert.Equal(t, "net", entries[0]["component"])
+		assert.Equal(t, listener.Addr().String(), entries[0]["address"])
+		assert.EqualValues(t, 1, entries[0]["attempts"])
+		assert.NotEmpty(t, entries[0]["duration"])
+	})
+
+	t.Run("should log failed connections", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		address := listener.Addr().String()
+		listener.Close()
+
+		_, err = NewDialer(logger, &net.Dialer{Timeout: time.Second}).DialContext(context.Background(), "tcp", address)
+		require.Error(t, err)
+
+		entries := entriesWithMessage(t, output(), "connection failed")
+		require.Len(t, entries, 1)
+		assert.Equal(t, "error", entries[0]["level"])
+		assert.Equal(t, address, entries[0]["address"])
+		assert.NotEmpty(t, entries[0]["error"])
+	})
+
+	t.Run("should log failed lookups", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+
+		_, err := NewDialer(logger, &net.Dialer{Resolver: failingResolver()}).Dial("tcp", "db.example.test:5432")
+		require.Error(t, err)
+
+		entries := entriesWithMessage(t, output(), "dns lookup failed")
+		require.Len(t, entries, 1)
+		assert.Equal(t, "warn", entries[0]["level"])
+		assert.Equal(t, "db.example.test", entries[0]["host"])
+	})
+
+	t.Run("should sample successful connections", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+		logger.SetLevel(zapcore.DebugLevel)
+
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer listener.Close()
+
+		dialer := NewDialer(logger, nil).WithSampling(time.Minute, 2, 0)
+		for i := 0; i < 5; i++ {
+			conn, err := dialer.Dial("tcp", listener.Addr().String())
+			require.NoError(t, err)
+			conn.Close()
+		}
+
+		assert.Equal(t, 2, strings.Count(output(), "connection established"))
+	})
+}
+
+// TestResolver tests the logging resolver
+func TestResolver(t *testing.T) {
+	t.Run("should log lookups", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+		logger.SetLevel(zapcore.DebugLevel)
+
+		addrs, err := NewResolver(logger, nil).LookupIPAddr(context.Background(), "127.0.0.1")
+		require.NoError(t, err)
+		require.Len(t, addrs, 1)
+
+		entries := entriesWithMessage(t, output(), "dns lookup completed")
+		require.Len(t, entries, 1)
+		assert.Equal(t, "127.0.0.1", entries[0]["host"])
+		assert.EqualValues(t, 1, entries[0]["addresses"])
+	})
+
+	t.Run("should never sample failures", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+
+		resolver := NewResolver(logger, failingResolver()).WithSampling(time.Minute, 1, 0)
+		for i := 0; i < 3; i++ {
+			_, err := resolver.LookupHost(context.Background(), "cache.example.test")
+			require.Error(t, err)
+		}
+
+		assert.Equal(t, 3, strings.Count(output(), "dns lookup failed"))
+	})
+}
+
+// TestNetSampler tests per-key sampling
+func TestNetSampler(t *testing.T) {
+	t.Run("should keep first entries then every Nth", func(t *testing.T) {
+		sampler := newNetSampler(time.Minute, 2, 3)
+
+		var kept []int
+		for i := 1; i <= 10; i++ {
+			if sampler.sample("a") {
+				kept = append(kept, i)
+			}
+		}
+
+		assert.Equal(t, []int{1, 2, 5, 8}, kept)
+		assert.True(t, sampler.sample("b"))
+	})
+
+	t.Run("should reset after tick", func(t *testing.T) {
+		sampler := newNetSampler(10*time.Millisecond, 1, 0)
+
+		assert.True(t, sampler.sample("a"))
+		assert.False(t, sampler.sample("a"))
+		time.Sleep(20 * time.Millisecond)
+		assert.True(t, sampler.sample("a"))
+	})
+}
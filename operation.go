@@ -0,0 +1,71 @@
+package xlogger
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Outcomes logged by Operation.End
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+)
+
+// Operation measures a named block of code, a lightweight alternative to
+// tracing spans: End logs its duration and outcome.
+type Operation struct {
+	logger Logger
+	start  time.Time
+	ended  atomic.Bool
+}
+
+// NewOperation starts an operation named name logging through logger. The
+// "operation started" entry is logged at Debug, so at Info level End logs a
+// single summary entry. fields are added to both entries, and trace fields
+// are added as to any entry.
+//
+// Example:
+//
+//	op := xlogger.NewOperation(logger, "charge_card", xlogger.String("order_id", id))
+//	err := gateway.Charge(ctx, order)
+//	op.End(err) // "operation completed" or "operation failed" with duration and outcome
+func NewOperation(logger Logger, name string, fields ...Field) *Operation {
+	return startOperation(logger, name, fields)
+}
+
+// StartOperation starts an operation logging through l.
+func (l *ZapLogger) StartOperation(name string, fields ...Field) *Operation {
+	return startOperation(l, name, fields)
+}
+
+// startOperation starts an operation for NewOperation and StartOperation,
+// whose caller the start entry reports
+func startOperation(logger Logger, name string, fields []Field) *Operation {
+	op := &Operation{
+		logger: skipCaller(logger.With(append([]Field{String("operation", name)}, fields...)...), 1),
+		start:  time.Now(),
+	}
+	skipCaller(op.logger, 1).Debug("operation started")
+	return op
+}
+
+// End logs the operation summary with its duration and outcome, at Info as
+// "operation completed" when err is nil and at Error as "operation failed"
+// otherwise. fields are added to the summary, for example result counts.
+// Only the first call logs.
+func (o *Operation) End(err error, fields ...Field) {
+	if o.ended.Swap(true) {
+		return
+	}
+	summary := append([]Field{Duration("duration", time.Since(o.start))}, fields...)
+	if err != nil {
+		o.logger.Error("operation failed", append(summary, String("outcome", OutcomeFailure), Error(err))...)
+		return
+	}
+	o.logger.Info("operation completed", append(summary, String("outcome", OutcomeSuccess))...)
+}
+
+// Elapsed returns the time since the operation started.
+func (o *Operation) Elapsed() time.Duration {
+	return time.Since(o.start)
+}
@@ -0,0 +1,60 @@
+package xlogger
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+// TestOperation tests timed operations
+func TestOperation(t *testing.T) {
+	t.Run("should log a single summary at info level", func(t *testing.T) {
+		skipWithoutGLS(t)
+		logger, output := newFileLogger(t)
+
+		RunWithTraceVoid("req-1", "", func() {
+			op := logger.StartOperation("charge_card", String("order_id", "o-1"))
+			op.End(nil, Int("attempts", 2))
+			op.End(errors.New("ignored"))
+		})
+
+		log := output()
+		assert.Empty(t, entriesWithMessage(t, log, "operation started"))
+		assert.Empty(t, entriesWithMessage(t, log, "operation failed"))
+		summary := entriesWithMessage(t, log, "operation completed")
+		require.Len(t, summary, 1)
+		assert.Equal(t, "info", summary[0]["level"])
+		assert.Equal(t, "charge_card", summary[0]["operation"])
+		assert.Equal(t, "o-1", summary[0]["order_id"])
+		assert.Equal(t, float64(2), summary[0]["attempts"])
+		assert.Equal(t, OutcomeSuccess, summary[0]["outcome"])
+		assert.Equal(t, "req-1", summary[0]["request_id"])
+		assert.Contains(t, summary[0], "duration")
+		assert.Contains(t, summary[0]["caller"], "operation_test.go")
+	})
+
+	t.Run("should log start and failure", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+		logger.SetLevel(zapcore.DebugLevel)
+
+		op := NewOperation(logger, "import")
+		op.End(errors.New("disk full"))
+		logger.StartOperation("export")
+
+		log := output()
+		started := entriesWithMessage(t, log, "operation started")
+		require.Len(t, started, 2)
+		assert.Equal(t, "import", started[0]["operation"])
+		for _, entry := range started {
+			assert.Contains(t, entry["caller"], "operation_test.go")
+		}
+		failed := entriesWithMessage(t, log, "operation failed")
+		require.Len(t, failed, 1)
+		assert.Equal(t, "error", failed[0]["level"])
+		assert.Equal(t, OutcomeFailure, failed[0]["outcome"])
+		assert.Equal(t, "disk full", failed[0]["error"])
+	})
+}
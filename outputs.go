@@ -0,0 +1,215 @@
+package xlogger
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// loggerOutputs holds the destinations shared by the base, infrastructure
+// and component loggers, so every output is opened and wrapped only once
+type loggerOutputs struct {
+	sink      zapcore.WriteSyncer
+	errSink   zapcore.WriteSyncer
+	sinks     []*countingSink
+	shadow    *shadowOutputs
+	explainer *dropExplainer
+	partition *partitionFiles
+	reporters []ErrorReporter
+	hooks     []Hook
+	producers *producerTable
+	dedupe    time.Duration
+	masked    *atomic.Uint64 // Secrets masked, nil unless secret detection is enabled
+	redactor  *redactor      // Keys and patterns masked, nil unless redaction is enabled
+	process   Processor      // Processors chained, nil without any
+	errChains bool           // Root cause kind and message added to error fields
+	messages  zapcore.WriteSyncer
+	teeSinks  []teeSink
+	audit     *auditOutput // Destinations of ForAudit, nil unless Config.Audit is set
+	retention RetentionHints
+	shapes    *entryShapes     // Entry shape metrics, nil unless enabled
+	postClose AfterClosePolicy // Handling of entries logged after Close
+	style     ConsoleStyle     // Levels of text shadow and sink outputs
+	close     func()
+
+	closeOnce sync.Once // Close of any logger sharing the outputs
+	closeErr  error
+	closed    atomic.Bool // Set by Close before syncing
+}
+
+// openOutputs opens the log and internal error outputs described by cfg.
+// Each log output path is compressed, unless it is stdout or stderr, and
+// accounted separately.
+func openOutputs(cfg *Config) (*loggerOutputs, error) {
+	var (
+		sinks   []*countingSink
+		writers []zapcore.WriteSyncer
+		closers []func()
+	)
+	closeAll := func() {
+		for _, c := range closers {
+			c()
+		}
+	}
+
+	for _, path := range pathsOrDefault(cfg.OutputPaths, "stdout") {
+		ws, closeSink, err := zap.Open(path)
+		if err != nil {
+			closeAll()
+			return nil, fmt.Errorf("failed to open outputs: %w", err)
+		}
+		closers = append(closers, closeSink)
+
+		// Terminals stay readable: only file and URL outputs are compressed
+		if !isTerminalPath(path) {
+			ws, err = compressSink(ws, cfg.Compression, cfg.CompressionLevel)
+			if err != nil {
+				closeAll()
+				return nil, err
+			}
+		}
+
+		counted := newCountingSink(path, ws)
+		sinks = append(sinks, counted)
+		writers = append(writers, counted)
+	}
+
+	errSink, closeErrSink, err := zap.Open(pathsOrDefault(cfg.ErrorOutputPaths, "stderr")...)
+	if err != nil {
+		closeAll()
+		return nil, fmt.Errorf("failed to open error outputs: %w", err)
+	}
+	closers = append(closers, closeErrSink)
+
+	sink := writers[0]
+	if len(writers) > 1 {
+		sink = zapcore.NewMultiWriteSyncer(writers...)
+	}
+
+	var shadow *shadowOutputs
+	if cfg.Shadow != nil {
+		var closeShadow func()
+		shadow, closeShadow, err = openShadowOutputs(cfg.Shadow, sink)
+		if err != nil {
+			closeAll()
+			return nil, err
+		}
+		closers = append(closers, closeShadow)
+		sink = shadow.primary
+	}
+
+	var partition *partitionFiles
+	if cfg.Partition != nil {
+		partition, err = openPartitionFiles(*cfg.Partition)
+		if err != nil {
+			closeAll()
+			return nil, err
+		}
+		closers = append(closers, partition.Close)
+	}
+
+	teeSinks, closeTeeSinks, err := openTeeSinks(cfg)
+	if err != nil {
+		closeAll()
+		return nil, err
+	}
+	closers = append(closers, closeTeeSinks)
+	for _, s := range teeSinks {
+		sinks = append(sinks, s.sink)
+	}
+
+	var audit *auditOutput
+	if cfg.Audit != nil {
+		var (
+			auditSinks []*countingSink
+			closeAudit func()
+		)
+		audit, auditSinks, closeAudit, err = openAuditOutput(cfg)
+		if err != nil {
+			closeAll()
+			return nil, err
+		}
+		closers = append(closers, closeAudit)
+		sinks = append(sinks, auditSinks...)
+	}
+
+	var messages zapcore.WriteSyncer
+	if len(cfg.MessageOutputs) > 0 {
+		var closeMessages func()
+		messages, closeMessages, err = zap.Open(cfg.MessageOutputs...)
+		if err != nil {
+			closeAll()
+			return nil, fmt.Errorf("failed to open message outputs: %w", err)
+		}
+		closers = append(closers, closeMessages)
+	}
+
+	var producers *producerTable
+	if cfg.ProducerTracking > 0 {
+		producers = newProducerTable(cfg.ProducerTracking)
+	}
+
+	var masked *atomic.Uint64
+	if cfg.DetectSecrets {
+		masked = new(atomic.Uint64)
+	}
+
+	var shapes *entryShapes
+	if cfg.EntryShape != nil {
+		shapes = newEntryShapes(*cfg.EntryShape)
+	}
+
+	var explainer *dropExplainer
+	if cfg.ExplainDrops {
+		explainer = newDropExplainer(errSink)
+	}
+
+	return &loggerOutputs{
+		sink:      sink,
+		errSink:   errSink,
+		sinks:     sinks,
+		shadow:    shadow,
+		explainer: explainer,
+		partition: partition,
+		reporters: cfg.ErrorReporters,
+		hooks:     cfg.Hooks,
+		producers: producers,
+		dedupe:    cfg.DedupeWindow,
+		masked:    masked,
+		redactor:  newRedactor(cfg.Redaction),
+		process:   chainProcessors(cfg.Processors),
+		errChains: cfg.ErrorChains,
+		messages:  messages,
+		teeSinks:  teeSinks,
+		audit:     audit,
+		retention: cfg.RetentionHints,
+		shapes:    shapes,
+		postClose: cfg.AfterClose.Normalize(),
+		style:     cfg.ConsoleStyle,
+		close:     closeAll,
+	}, nil
+}
+
+// status returns a snapshot of every log output
+func (o *loggerOutputs) status() []SinkStatus {
+	if o == nil {
+		return nil
+	}
+	statuses := make([]SinkStatus, len(o.sinks))
+	for i, s := range o.sinks {
+		statuses[i] = s.Status()
+	}
+	return statuses
+}
+
+// pathsOrDefault falls back to the standard stream when no paths are configured
+func pathsOrDefault(paths []string, fallback string) []string {
+	if len(paths) == 0 {
+		return []string{fallback}
+	}
+	return paths
+}
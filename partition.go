@@ -0,0 +1,293 @@
+package xlogger
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// Placeholders supported in PartitionConfig.PathTemplate
+const (
+	partitionDatePlaceholder      = "{date}"
+	partitionComponentPlaceholder = "{component}"
+)
+
+// partitionDateLayout formats the {date} placeholder
+const partitionDateLayout = "2006-01-02"
+
+// defaultPartitionComponent names the partition of entries without a component
+const defaultPartitionComponent = "app"
+
+// PartitionConfig describes file outputs partitioned by date and component.
+type PartitionConfig struct {
+	PathTemplate string        // Path with {date} and {component} placeholders, e.g. "logs/{date}/{component}.log"
+	MaxSize      int64         // Rotate a partition file once it reaches this many bytes (0 to disable)
+	MaxAge       time.Duration // Remove partition files not modified for this long (0 to keep forever)
+}
+
+// partitionFile is an open partition file and its current size
+type partitionFile struct {
+	file *os.File
+	size int64
+	date string
+}
+
+// partitionFiles owns the open files of a partitioned output
+type partitionFiles struct {
+	cfg PartitionConfig
+
+	mu    sync.Mutex
+	files map[string]*partitionFile
+	date  string
+}
+
+// openPartitionFiles prepares a partitioned output and applies retention
+// to files left by earlier runs
+func openPartitionFiles(cfg PartitionConfig) (*partitionFiles, error) {
+	if !strings.Contains(cfg.PathTemplate, partitionComponentPlaceholder) &&
+		!strings.Contains(cfg.PathTemplate, partitionDatePlaceholder) {
+		return nil, fmt.Errorf("partition path template %q has no {date} or {component} placeholder", cfg.PathTemplate)
+	}
+
+	p := &partitionFiles{cfg: cfg, files: make(map[string]*partitionFile)}
+	p.removeExpired(time.Now())
+	return p, nil
+}
+
+// path renders the template for an entry
+func (p *partitionFiles) path(date, component string) string {
+	if component == "" {
+		component = defaultPartitionComponent
+	}
+	return strings.NewReplacer(
+		partitionDatePlaceholder, date,
+		partitionComponentPlaceholder, sanitizePartitionName(component),
+	).Replace(p.cfg.PathTemplate)
+}
+
+// write appends an encoded entry to the partition of its date and component
+func (p *partitionFiles) write(t time.Time, component string, data []byte) error {
+	date := t.Format(partitionDateLayout)
+	path := p.path(date, component)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if date != p.date {
+		// Entries arrive in roughly increasing time order, so files of other
+		// days are done; close them and apply retention once per day change
+		p.date = date
+		for key, f := range p.files {
+			if f.date != date {
+				_ = f.file.Close()
+				delete(p.files, key)
+			}
+		}
+		p.removeExpired(time.Now())
+	}
+
+	f, err := p.open(path, date)
+	if err != nil {
+		return err
+	}
+	if p.cfg.MaxSize > 0 && f.size > 0 && f.size+int64(len(data)) > p.cfg.MaxSize {
+		if f, err = p.rotate(path, f); err != nil {
+			return err
+		}
+	}
+
+	n, err := f.file.Write(data)
+	f.size += int64(n)
+	return err
+}
+
+// open returns the open file for path, creating its directory when needed
+func (p *partitionFiles) open(path, date string) (*partitionFile, error) {
+	if f, ok := p.files[path]; ok {
+		return f, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("create partition directory: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("open partition file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("stat partition file: %w", err)
+	}
+
+	f := &partitionFile{file: file, size: info.Size(), date: date}
+	p.files[path] = f
+	return f, nil
+}
+
+// rotate renames a full partition file with a timestamp suffix and opens a new one
+func (p *partitionFiles) rotate(path string, f *partitionFile) (*partitionFile, error) {
+	if err := f.file.Close(); err != nil {
+		return nil, err
+	}
+	delete(p.files, path)
+
+	if err := os.Rename(path, rotatedPartitionPath(path, time.Now())); err != nil {
+		return nil, fmt.Errorf("rotate partition file: %w", err)
+	}
+	return p.open(path, f.date)
+}
+
+// rotatedPartitionPath inserts a timestamp before the extension:
+// "logs/2024-01-02/gorm.log" becomes "logs/2024-01-02/gorm-150405.000000.log".
+// A counter is appended when that name is already taken.
+func rotatedPartitionPath(path string, t time.Time) string {
+	ext := filepath.Ext(path)
+	base := strings.TrimSuffix(path, ext) + "-" + t.Format("150405.000000")
+	rotated := base + ext
+	for i := 1; ; i++ {
+		if _, err := os.Lstat(rotated); os.IsNotExist(err) {
+			return rotated
+		}
+		rotated = fmt.Sprintf("%s-%d%s", base, i, ext)
+	}
+}
+
+// removeExpired deletes partition files, current or rotated, that were not
+// modified within MaxAge, then any directories left empty
+func (p *partitionFiles) removeExpired(now time.Time) {
+	if p.cfg.MaxAge <= 0 {
+		return
+	}
+
+	ext := filepath.Ext(p.cfg.PathTemplate)
+	pattern := strings.NewReplacer(
+		partitionDatePlaceholder, "*",
+		partitionComponentPlaceholder, "*",
+	).Replace(strings.TrimSuffix(p.cfg.PathTemplate, ext)) + "*" + ext
+
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return
+	}
+
+	var dirs []string
+	for _, path := range matches {
+		if _, open := p.files[path]; open {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil || info.IsDir() || now.Sub(info.ModTime()) < p.cfg.MaxAge {
+			continue
+		}
+		if os.Remove(path) == nil {
+			dirs = append(dirs, filepath.Dir(path))
+		}
+	}
+
+	// Deepest first, so nested date directories are removed before their parents
+	sort.Sort(sort.Reverse(sort.StringSlice(dirs)))
+	for _, dir := range dirs {
+		_ = os.Remove(dir) // fails while the directory still has files
+	}
+}
+
+// Sync flushes every open partition file
+func (p *partitionFiles) Sync() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var errs []error
+	for _, f := range p.files {
+		errs = append(errs, f.file.Sync())
+	}
+	return errors.Join(errs...)
+}
+
+// Close closes every open partition file
+func (p *partitionFiles) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for path, f := range p.files {
+		_ = f.file.Close()
+		delete(p.files, path)
+	}
+}
+
+// sanitizePartitionName keeps component names from escaping the template directory
+func sanitizePartitionName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+}
+
+// partitionCore encodes entries and routes them to the partition of their
+// date and component. The component comes from a "component" field added
+// with With, as done by ForInfra and ForGORM, or on the entry itself.
+type partitionCore struct {
+	zapcore.LevelEnabler
+	enc       zapcore.Encoder
+	files     *partitionFiles
+	component string
+}
+
+// With implements zapcore.Core
+func (c *partitionCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := &partitionCore{
+		LevelEnabler: c.LevelEnabler,
+		enc:          c.enc.Clone(),
+		files:        c.files,
+		component:    partitionComponent(fields, c.component),
+	}
+	for i := range fields {
+		fields[i].AddTo(clone.enc)
+	}
+	return clone
+}
+
+// Check implements zapcore.Core
+func (c *partitionCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write implements zapcore.Core
+func (c *partitionCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	buf, err := c.enc.EncodeEntry(ent, fields)
+	if err != nil {
+		return err
+	}
+	defer buf.Free()
+
+	return c.files.write(ent.Time, partitionComponent(fields, c.component), buf.Bytes())
+}
+
+// Sync implements zapcore.Core
+func (c *partitionCore) Sync() error {
+	return c.files.Sync()
+}
+
+// partitionComponent returns the last string "component" field, or fallback
+func partitionComponent(fields []zapcore.Field, fallback string) string {
+	for i := len(fields) - 1; i >= 0; i-- {
+		if fields[i].Key == "component" && fields[i].Type == zapcore.StringType {
+			return fields[i].String
+		}
+	}
+	return fallback
+}
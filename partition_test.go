@@ -0,0 +1,109 @@
+package xlogger
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPartitionedOutput tests file outputs partitioned by date and component
+func TestPartitionedOutput(t *testing.T) {
+	newPartitionedLogger := func(t *testing.T, maxSize int64, maxAge time.Duration) (*ZapLogger, string) {
+		dir := t.TempDir()
+		logger, err := NewZapLogger(NewLoggerConfig(
+			WithOutputPaths(filepath.Join(dir, "all.log")),
+			WithPartitionedOutput(filepath.Join(dir, "logs", "{date}", "{component}.log"), maxSize, maxAge),
+		))
+		require.NoError(t, err)
+		return logger, dir
+	}
+
+	readPartition := func(t *testing.T, dir, component string) string {
+		data, err := os.ReadFile(filepath.Join(dir, "logs", time.Now().Format("2006-01-02"), component+".log"))
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	t.Run("should write each component to its own partition", func(t *testing.T) {
+		logger, dir := newPartitionedLogger(t, 0, 0)
+
+		logger.Info("app entry")
+		logger.ForInfra("db").Info("db entry")
+		logger.ForInfra("../escape").Info("escape entry")
+		logger.Info("field component", String("component", "jobs"))
+		require.NoError(t, logger.Sync())
+
+		app := readPartition(t, dir, "app")
+		assert.Contains(t, app, "app entry")
+		assert.NotContains(t, app, "db entry")
+		assert.Contains(t, readPartition(t, dir, "db"), "db entry")
+		assert.Contains(t, readPartition(t, dir, "___escape"), "escape entry")
+		assert.Contains(t, readPartition(t, dir, "jobs"), "field component")
+
+		all, err := os.ReadFile(filepath.Join(dir, "all.log"))
+		require.NoError(t, err)
+		assert.Contains(t, string(all), "db entry")
+	})
+
+	t.Run("should rotate full partitions", func(t *testing.T) {
+		logger, dir := newPartitionedLogger(t, 300, 0)
+
+		for i := 0; i < 10; i++ {
+			logger.Info("rotating entry", Int("i", i))
+		}
+
+		files, err := filepath.Glob(filepath.Join(dir, "logs", "*", "app*.log"))
+		require.NoError(t, err)
+		assert.Greater(t, len(files), 1)
+
+		total := 0
+		for _, file := range files {
+			info, err := os.Stat(file)
+			require.NoError(t, err)
+			assert.LessOrEqual(t, info.Size(), int64(300))
+
+			data, err := os.ReadFile(file)
+			require.NoError(t, err)
+			total += strings.Count(string(data), "rotating entry")
+		}
+		assert.Equal(t, 10, total)
+	})
+
+	t.Run("should remove expired partitions", func(t *testing.T) {
+		dir := t.TempDir()
+		old := filepath.Join(dir, "logs", "2020-01-01", "db.log")
+		oldRotated := filepath.Join(dir, "logs", "2020-01-01", "db-101010.000000.log")
+		unrelated := filepath.Join(dir, "logs", "notes.txt")
+		for _, path := range []string{old, oldRotated, unrelated} {
+			require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+			require.NoError(t, os.WriteFile(path, []byte("old\n"), 0o644))
+			require.NoError(t, os.Chtimes(path, time.Now().Add(-48*time.Hour), time.Now().Add(-48*time.Hour)))
+		}
+
+		logger, err := NewZapLogger(NewLoggerConfig(
+			WithOutputPaths(filepath.Join(dir, "all.log")),
+			WithPartitionedOutput(filepath.Join(dir, "logs", "{date}", "{component}.log"), 0, 24*time.Hour),
+		))
+		require.NoError(t, err)
+		logger.Info("fresh entry")
+
+		assert.NoFileExists(t, old)
+		assert.NoFileExists(t, oldRotated)
+		assert.NoDirExists(t, filepath.Dir(old))
+		assert.FileExists(t, unrelated)
+		assert.Contains(t, readPartition(t, dir, "app"), "fresh entry")
+	})
+
+	t.Run("should reject template without placeholders", func(t *testing.T) {
+		_, err := NewZapLogger(NewLoggerConfig(
+			WithOutputPaths(filepath.Join(t.TempDir(), "all.log")),
+			WithPartitionedOutput(filepath.Join(t.TempDir(), "app.log"), 0, 0),
+		))
+		assert.Error(t, err)
+	})
+}
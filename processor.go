@@ -0,0 +1,195 @@
+package xlogger
+
+import (
+	"net"
+	"regexp"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Processor rewrites an entry before it reaches the outputs, sinks, hooks
+// and error reporters, for example to scrub personal data. Changes to the
+// message and fields are logged; changes to the level, time and caller are
+// ignored. Processors run on the logging goroutine.
+type Processor func(entry *Entry)
+
+// Replacements written by the built-in scrubbers
+const (
+	scrubbedEmail      = "[EMAIL]"
+	scrubbedIP         = "[IP]"
+	scrubbedNationalID = "[NATIONAL_ID]"
+)
+
+var (
+	// ipCandidatePattern matches runs of characters that may form an IP
+	// address, checked with net.ParseIP
+	ipCandidatePattern = regexp.MustCompile(`[0-9A-Fa-f:.]{2,}`)
+
+	// NationalIDPattern matches US social security numbers, UK national
+	// insurance numbers and Thai citizen IDs
+	NationalIDPattern = regexp.MustCompile(`\b(?:\d{3}-\d{2}-\d{4}|[A-CEGHJ-PR-TW-Z]{2}\d{6}[A-D]|\d-\d{4}-\d{5}-\d{2}-\d)\b`)
+)
+
+// NewScrubber returns a Processor replacing every match of pattern with
+// replacement in the message and in string field values, including values
+// nested in objects and arrays.
+//
+// Example:
+//
+//	orderIDs := regexp.MustCompile(`ORD-\d{8}`)
+//	cfg := xlogger.NewLoggerConfig(
+//	    xlogger.WithProcessor(xlogger.NewScrubber(orderIDs, "[ORDER]")),
+//	)
+func NewScrubber(pattern *regexp.Regexp, replacement string) Processor {
+	return scrubWith(func(s string) string {
+		return pattern.ReplaceAllLiteralString(s, replacement)
+	})
+}
+
+// ScrubEmails returns a Processor replacing email addresses with "[EMAIL]".
+func ScrubEmails() Processor {
+	return NewScrubber(EmailPattern, scrubbedEmail)
+}
+
+// ScrubIPs returns a Processor replacing IPv4 and IPv6 addresses with
+// "[IP]". Candidates are checked with net.ParseIP, so versions and times
+// such as "1.2.3" or "12:30:45" are kept.
+func ScrubIPs() Processor {
+	return scrubWith(func(s string) string {
+		return ipCandidatePattern.ReplaceAllStringFunc(s, scrubIPCandidate)
+	})
+}
+
+// scrubIPCandidate replaces the address in candidate, which may carry
+// punctuation around it as in "addr:10.0.0.1" or "from 10.0.0.1."
+func scrubIPCandidate(candidate string) string {
+	for _, cut := range [][2]string{{"", ""}, {"", ".:"}, {".:", ".:"}} {
+		ip := strings.TrimRight(strings.TrimLeft(candidate, cut[0]), cut[1])
+		if ip != "" && net.ParseIP(ip) != nil {
+			return strings.Replace(candidate, ip, scrubbedIP, 1)
+		}
+	}
+	return candidate
+}
+
+// ScrubNationalIDs returns a Processor replacing national ID numbers
+// matched by NationalIDPattern with "[NATIONAL_ID]".
+func ScrubNationalIDs() Processor {
+	return NewScrubber(NationalIDPattern, scrubbedNationalID)
+}
+
+// scrubWith returns a Processor rewriting the message and string values
+// with scrub
+func scrubWith(scrub func(string) string) Processor {
+	return func(entry *Entry) {
+		entry.Message = scrub(entry.Message)
+		for key, value := range entry.Fields {
+			entry.Fields[key] = scrubValue(value, scrub)
+		}
+	}
+}
+
+// scrubValue rewrites the strings of value, descending into objects and
+// arrays
+func scrubValue(value interface{}, scrub func(string) string) interface{} {
+	switch v := value.(type) {
+	case string:
+		return scrub(v)
+	case map[string]interface{}:
+		for key, nested := range v {
+			v[key] = scrubValue(nested, scrub)
+		}
+	case []interface{}:
+		for i, nested := range v {
+			v[i] = scrubValue(nested, scrub)
+		}
+	}
+	return value
+}
+
+// chainProcessors returns a Processor running processors in order, nil
+// without any
+func chainProcessors(processors []Processor) Processor {
+	var chain []Processor
+	for _, p := range processors {
+		if p != nil {
+			chain = append(chain, p)
+		}
+	}
+	if len(chain) == 0 {
+		return nil
+	}
+	return func(entry *Entry) {
+		for _, p := range chain {
+			p(entry)
+		}
+	}
+}
+
+// processorCore hands every entry to the processors before the inner core.
+// Logger fields are kept here rather than added to the inner core, so the
+// processors can rewrite them too.
+type processorCore struct {
+	zapcore.Core
+	errSink zapcore.WriteSyncer
+	process Processor
+	fields  []zapcore.Field
+}
+
+// With implements zapcore.Core
+func (c *processorCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.fields = append(c.fields[:len(c.fields):len(c.fields)], fields...)
+	return &clone
+}
+
+// Check implements zapcore.Core
+func (c *processorCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Core.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write implements zapcore.Core. Processed fields are written in key order.
+func (c *processorCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	ent.Message, fields = processEntry(c.process, ent, c.fields, fields)
+	writeChecked(c.Core, ent, fields, c.errSink)
+	return nil
+}
+
+// processEntry hands the entry with the fields of every set to process,
+// returning the processed message and fields in key order
+func processEntry(process Processor, ent zapcore.Entry, fieldSets ...[]zapcore.Field) (string, []zapcore.Field) {
+	entry := Entry{
+		Level:   ent.Level,
+		Time:    ent.Time,
+		Message: ent.Message,
+	}
+	if ent.Caller.Defined {
+		entry.Caller = ent.Caller.TrimmedPath()
+	}
+
+	enc := zapcore.NewMapObjectEncoder()
+	for _, fields := range fieldSets {
+		for _, field := range fields {
+			field.AddTo(enc)
+		}
+	}
+	entry.Fields = enc.Fields
+	process(&entry)
+
+	keys := make([]string, 0, len(entry.Fields))
+	for key := range entry.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	processed := make([]zapcore.Field, len(keys))
+	for i, key := range keys {
+		processed[i] = zap.Any(key, entry.Fields[key])
+	}
+	return entry.Message, processed
+}
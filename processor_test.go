@@ -0,0 +1,91 @@
+package xlogger
+
+import (
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestScrubbers tests the built-in scrubbers on messages and field values
+func TestScrubbers(t *testing.T) {
+	tests := []struct {
+		name      string
+		processor Processor
+		input     string
+		want      string
+	}{
+		{"email", ScrubEmails(), "signup from jane.doe@example.com", "signup from [EMAIL]"},
+		{"ipv4", ScrubIPs(), "request from 10.0.0.1.", "request from [IP]."},
+		{"ipv4 after colon", ScrubIPs(), "addr:192.168.1.20", "addr:[IP]"},
+		{"ipv6", ScrubIPs(), "peer 2001:db8::1 and ::1", "peer [IP] and [IP]"},
+		{"time kept", ScrubIPs(), "started at 12:30:45", "started at 12:30:45"},
+		{"version kept", ScrubIPs(), "running v1.2.3", "running v1.2.3"},
+		{"hex word kept", ScrubIPs(), "cafe deadbeef", "cafe deadbeef"},
+		{"us ssn", ScrubNationalIDs(), "ssn 123-45-6789", "ssn [NATIONAL_ID]"},
+		{"uk nino", ScrubNationalIDs(), "nino AB123456C", "nino [NATIONAL_ID]"},
+		{"thai id", ScrubNationalIDs(), "id 1-1012-34567-89-0", "id [NATIONAL_ID]"},
+		{"custom", NewScrubber(regexp.MustCompile(`ORD-\d{8}`), "[ORDER]"), "order ORD-12345678", "order [ORDER]"},
+	}
+	for _, tt := range tests {
+		t.Run("should scrub "+tt.name, func(t *testing.T) {
+			entry := &Entry{Message: tt.input, Fields: map[string]interface{}{"note": tt.input}}
+			tt.processor(entry)
+			assert.Equal(t, tt.want, entry.Message)
+			assert.Equal(t, tt.want, entry.Fields["note"])
+		})
+	}
+
+	t.Run("should scrub nested values and keep other types", func(t *testing.T) {
+		entry := &Entry{Fields: map[string]interface{}{
+			"user":  map[string]interface{}{"email": "jane@example.com", "age": 42},
+			"peers": []interface{}{"10.0.0.1", "jane@example.com"},
+			"count": 3,
+		}}
+		chainProcessors([]Processor{ScrubEmails(), ScrubIPs()})(entry)
+		assert.Equal(t, map[string]interface{}{"email": "[EMAIL]", "age": 42}, entry.Fields["user"])
+		assert.Equal(t, []interface{}{"[IP]", "[EMAIL]"}, entry.Fields["peers"])
+		assert.Equal(t, 3, entry.Fields["count"])
+	})
+
+	t.Run("should return nil without processors", func(t *testing.T) {
+		assert.Nil(t, chainProcessors(nil))
+		assert.Nil(t, chainProcessors([]Processor{nil}))
+	})
+}
+
+// TestWithProcessor_Logger tests processors across outputs and sinks
+func TestWithProcessor_Logger(t *testing.T) {
+	t.Run("should rewrite messages, fields and With fields in every output", func(t *testing.T) {
+		dir := t.TempDir()
+		path, sinkPath := filepath.Join(dir, "app.log"), filepath.Join(dir, "sink.log")
+		logger, err := NewZapLogger(NewLoggerConfig(
+			WithOutputPaths(path),
+			WithSink(SinkConfig{Output: sinkPath}),
+			WithProcessor(ScrubEmails(), ScrubIPs()),
+			WithProcessor(func(entry *Entry) {
+				delete(entry.Fields, "debug_payload")
+				entry.Fields["processed"] = true
+			}),
+		))
+		require.NoError(t, err)
+
+		logger.With(String("client_ip", "203.0.113.7")).Info("signup from jane@example.com",
+			String("debug_payload", "raw"),
+			Int("attempt", 2),
+		)
+		require.NoError(t, logger.Sync())
+
+		for _, output := range []string{readFile(t, path), readFile(t, sinkPath)} {
+			entries := entriesWithMessage(t, output, "signup from [EMAIL]")
+			require.Len(t, entries, 1)
+			assert.Equal(t, "[IP]", entries[0]["client_ip"])
+			assert.Equal(t, float64(2), entries[0]["attempt"])
+			assert.Equal(t, true, entries[0]["processed"])
+			assert.NotContains(t, entries[0], "debug_payload")
+			assert.NotContains(t, output, "203.0.113.7")
+		}
+	})
+}
@@ -0,0 +1,203 @@
+package xlogger
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// defaultTopProducers is the number of call sites ProducersHandler returns
+// without an n query parameter
+const defaultTopProducers = 10
+
+// Producer is a log call site and the number of entries it emitted.
+type Producer struct {
+	Caller  string        `json:"caller"`  // file:line of the log call
+	Level   zapcore.Level `json:"level"`   // Level of the first entry counted
+	Message string        `json:"message"` // Message of the first entry counted
+	Count   uint64        `json:"count"`   // Entries emitted, an upper bound once the table has evicted call sites
+}
+
+// producerTable counts entries per call site in a bounded table. When full,
+// the least counted call site is replaced and the newcomer inherits its count
+// (the Space-Saving algorithm), so frequent call sites are never missed.
+type producerTable struct {
+	mu        sync.Mutex
+	size      int
+	producers map[string]*Producer
+}
+
+func newProducerTable(size int) *producerTable {
+	return &producerTable{size: size, producers: make(map[string]*Producer, size)}
+}
+
+// record counts one entry of the call site
+func (t *producerTable) record(caller string, level zapcore.Level, message string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if p, ok := t.producers[caller]; ok {
+		p.Count++
+		return
+	}
+
+	var count uint64
+	if len(t.producers) >= t.size {
+		var least *Producer
+		for _, p := range t.producers {
+			if least == nil || p.Count < least.Count {
+				least = p
+			}
+		}
+		delete(t.producers, least.Caller)
+		count = least.Count
+	}
+	t.producers[caller] = &Producer{Caller: caller, Level: level, Message: message, Count: count + 1}
+}
+
+// top returns the n most counted call sites, or all of them when n <= 0
+func (t *producerTable) top(n int) []Producer {
+	t.mu.Lock()
+	producers := make([]Producer, 0, len(t.producers))
+	for _, p := range t.producers {
+		producers = append(producers, *p)
+	}
+	t.mu.Unlock()
+
+	sort.Slice(producers, func(i, j int) bool {
+		if producers[i].Count != producers[j].Count {
+			return producers[i].Count > producers[j].Count
+		}
+		return producers[i].Caller < producers[j].Caller
+	})
+	if n > 0 && len(producers) > n {
+		producers = producers[:n]
+	}
+	return producers
+}
+
+// producerCore counts written entries per call site
+type producerCore struct {
+	zapcore.LevelEnabler
+	table *producerTable
+}
+
+// With implements zapcore.Core
+func (c *producerCore) With([]zapcore.Field) zapcore.Core {
+	return c
+}
+
+// Check implements zapcore.Core
+func (c *producerCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write implements zapcore.Core. The caller is only known here, as zap
+// resolves it after Check.
+func (c *producerCore) Write(ent zapcore.Entry, _ []zapcore.Field) error {
+	if ent.Caller.Defined {
+		c.table.record(ent.Caller.TrimmedPath(), ent.Level, ent.Message)
+	}
+	return nil
+}
+
+// Sync implements zapcore.Core
+func (c *producerCore) Sync() error {
+	return nil
+}
+
+// TopProducers returns the n call sites that emitted the most entries, or
+// all tracked call sites when n <= 0. It returns nil unless producer tracking
+// is enabled with WithProducerTracking.
+func (l *ZapLogger) TopProducers(n int) []Producer {
+	outputs := l.outputs()
+	if outputs == nil || outputs.producers == nil {
+		return nil
+	}
+	return outputs.producers.top(n)
+}
+
+// topProducer is implemented by loggers tracking call sites
+type topProducer interface {
+	TopProducers(n int) []Producer
+}
+
+// producersHandler serves the top log call sites of a logger
+type producersHandler struct {
+	logger Logger
+}
+
+// ProducersHandler returns an opt-in http.Handler listing the call sites that
+// emitted the most entries, to find the noisiest log statements. GET returns
+// a JSON array of Producer, limited by the n query parameter (default 10).
+// With format=prometheus the counts are written in the Prometheus text
+// format as xlogger_call_site_entries_total. Requires WithProducerTracking.
+//
+// Example:
+//
+//	mux.Handle("/debug/log-producers", xlogger.ProducersHandler(logger))
+//
+//	curl 'localhost:8080/debug/log-producers?n=5'
+func ProducersHandler(logger Logger) http.Handler {
+	return &producersHandler{logger: logger}
+}
+
+// ServeHTTP implements http.Handler
+func (h *producersHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", "GET")
+		writeHandlerError(w, http.StatusMethodNotAllowed, "only GET is supported")
+		return
+	}
+
+	query := r.URL.Query()
+	n := defaultTopProducers
+	if value := query.Get("n"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			writeHandlerError(w, http.StatusBadRequest, fmt.Sprintf("invalid n %q", value))
+			return
+		}
+		n = parsed
+	}
+
+	tracker, ok := h.logger.(topProducer)
+	if !ok {
+		writeHandlerError(w, http.StatusNotFound, "logger does not track producers")
+		return
+	}
+	producers := tracker.TopProducers(n)
+	if producers == nil {
+		writeHandlerError(w, http.StatusNotFound, "producer tracking is disabled")
+		return
+	}
+
+	if query.Get("format") == "prometheus" {
+		writePrometheusProducers(w, producers)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(producers)
+}
+
+// prometheusLabelEscaper escapes label values for the Prometheus text format
+var prometheusLabelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func writePrometheusProducers(w http.ResponseWriter, producers []Producer) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	fmt.Fprintln(w, "# HELP xlogger_call_site_entries_total Log entries emitted per call site.")
+	fmt.Fprintln(w, "# TYPE xlogger_call_site_entries_total counter")
+	for _, p := range producers {
+		fmt.Fprintf(w, "xlogger_call_site_entries_total{caller=\"%s\",level=\"%s\"} %d\n",
+			prometheusLabelEscaper.Replace(p.Caller), p.Level, p.Count)
+	}
+}
@@ -0,0 +1,121 @@
+package xlogger
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+// TestTopProducers tests per call site entry counting
+func TestTopProducers(t *testing.T) {
+	t.Run("should rank call sites by entries emitted", func(t *testing.T) {
+		logger, _ := newFileLogger(t, WithProducerTracking(10), WithSamplingDisabled())
+
+		for i := 0; i < 5; i++ {
+			logger.Info("noisy")
+		}
+		logger.Warn("quiet")
+		logger.Debug("filtered")
+
+		top := logger.TopProducers(0)
+		require.Len(t, top, 2)
+		assert.Equal(t, uint64(5), top[0].Count)
+		assert.Equal(t, "noisy", top[0].Message)
+		assert.Equal(t, zapcore.InfoLevel, top[0].Level)
+		assert.Contains(t, top[0].Caller, "producers_test.go:")
+		assert.Equal(t, "quiet", top[1].Message)
+
+		assert.Len(t, logger.TopProducers(1), 1)
+	})
+
+	t.Run("should keep frequent call sites when the table is full", func(t *testing.T) {
+		table := newProducerTable(2)
+		for i := 0; i < 10; i++ {
+			table.record("hot.go:1", zapcore.InfoLevel, "hot")
+		}
+		for i := 0; i < 5; i++ {
+			table.record(fmt.Sprintf("cold.go:%d", i), zapcore.InfoLevel, "cold")
+		}
+
+		top := table.top(0)
+		require.Len(t, top, 2)
+		assert.Equal(t, "hot.go:1", top[0].Caller)
+		assert.Equal(t, uint64(10), top[0].Count)
+		assert.Equal(t, "cold.go:4", top[1].Caller)
+	})
+
+	t.Run("should return nil when tracking is disabled", func(t *testing.T) {
+		logger, _ := newFileLogger(t)
+		logger.Info("untracked")
+
+		assert.Nil(t, logger.TopProducers(10))
+	})
+}
+
+// TestProducersHandler tests the top producers admin handler
+func TestProducersHandler(t *testing.T) {
+	serve := func(handler http.Handler, method, target string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(method, target, nil))
+		return rec
+	}
+
+	t.Run("should list top producers as JSON", func(t *testing.T) {
+		logger, _ := newFileLogger(t, WithProducerTracking(10), WithSamplingDisabled())
+		for i := 0; i < 3; i++ {
+			logger.Info("noisy")
+		}
+		logger.Info("quiet")
+
+		rec := serve(ProducersHandler(logger), http.MethodGet, "/?n=1")
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+		var producers []Producer
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &producers))
+		require.Len(t, producers, 1)
+		assert.Equal(t, "noisy", producers[0].Message)
+		assert.Equal(t, uint64(3), producers[0].Count)
+	})
+
+	t.Run("should write the Prometheus text format", func(t *testing.T) {
+		logger, _ := newFileLogger(t, WithProducerTracking(10), WithSamplingDisabled())
+		logger.Warn("slow")
+
+		rec := serve(ProducersHandler(logger), http.MethodGet, "/?format=prometheus")
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.True(t, strings.HasPrefix(rec.Header().Get("Content-Type"), "text/plain"))
+		body := rec.Body.String()
+		assert.Contains(t, body, "# TYPE xlogger_call_site_entries_total counter")
+		assert.Regexp(t, `xlogger_call_site_entries_total\{caller="[^"]*producers_test.go:\d+",level="warn"\} 1`, body)
+	})
+
+	t.Run("should reject invalid requests", func(t *testing.T) {
+		logger, _ := newFileLogger(t, WithProducerTracking(10), WithSamplingDisabled())
+
+		rec := serve(ProducersHandler(logger), http.MethodPost, "/")
+		assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+		assert.Equal(t, "GET", rec.Header().Get("Allow"))
+
+		rec = serve(ProducersHandler(logger), http.MethodGet, "/?n=many")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("should report disabled tracking", func(t *testing.T) {
+		logger, _ := newFileLogger(t)
+
+		rec := serve(ProducersHandler(logger), http.MethodGet, "/")
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Contains(t, rec.Body.String(), "producer tracking is disabled")
+
+		rec = serve(ProducersHandler(&MockLogger{}), http.MethodGet, "/")
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
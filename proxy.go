@@ -0,0 +1,199 @@
+package xlogger
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptrace"
+	"net/http/httputil"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// proxyComponent is the infrastructure component used by proxy logging
+const proxyComponent = "proxy"
+
+// proxyTraceKey stores the *proxyTrace of an inbound request in its context
+type proxyTraceKey struct{}
+
+// proxyTrace collects upstream details of one proxied request
+type proxyTrace struct {
+	mu              sync.Mutex
+	upstream        string
+	attempts        int
+	upstreamLatency time.Duration
+	upstreamStatus  int
+}
+
+func (t *proxyTrace) setUpstream(upstream string) {
+	t.mu.Lock()
+	t.upstream = upstream
+	t.mu.Unlock()
+}
+
+func (t *proxyTrace) addAttempt() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.attempts++
+	return t.attempts
+}
+
+func (t *proxyTrace) finishUpstream(latency time.Duration, status int) {
+	t.mu.Lock()
+	t.upstreamLatency = latency
+	t.upstreamStatus = status
+	t.mu.Unlock()
+}
+
+func (t *proxyTrace) fields() []Field {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	retries := t.attempts - 1
+	if retries < 0 {
+		retries = 0
+	}
+	return []Field{
+		String("upstream", t.upstream),
+		Int("upstream_status", t.upstreamStatus),
+		Duration("upstream_latency", t.upstreamLatency),
+		Int("retries", retries),
+	}
+}
+
+func proxyTraceFromContext(ctx context.Context) *proxyTrace {
+	trace, _ := ctx.Value(proxyTraceKey{}).(*proxyTrace)
+	return trace
+}
+
+// InstrumentReverseProxy installs logging on proxy and returns the handler to
+// serve instead of it. The proxy's ErrorLog, Transport, ModifyResponse and
+// ErrorHandler are wrapped, keeping any behavior already configured.
+//
+// Entries are written under the "proxy" component: upstream selection and
+// transport retries at Debug, upstream failures at Error, and one completion
+// entry per request with the client-facing latency next to the upstream
+// latency, so slow upstreams can be told apart from slow clients.
+//
+// Example:
+//
+//	proxy := httputil.NewSingleHostReverseProxy(target)
+//	http.Handle("/", xlogger.InstrumentReverseProxy(logger, proxy))
+func InstrumentReverseProxy(logger Logger, proxy *httputil.ReverseProxy) http.Handler {
+	logger = logger.ForInfra(proxyComponent)
+
+	if proxy.ErrorLog == nil {
+		proxy.ErrorLog = NewStdLog(logger, zapcore.ErrorLevel)
+	}
+
+	transport := proxy.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	proxy.Transport = &proxyTransport{next: transport, logger: logger}
+
+	modifyResponse := proxy.ModifyResponse
+	proxy.ModifyResponse = func(resp *http.Response) error {
+		if trace := proxyTraceFromContext(resp.Request.Context()); trace != nil {
+			logger.Debug("proxy upstream responded", trace.fields()...)
+		}
+		if modifyResponse != nil {
+			return modifyResponse(resp)
+		}
+		return nil
+	}
+
+	errorHandler := proxy.ErrorHandler
+	proxy.ErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
+		fields := []Field{
+			String("method", r.Method),
+			String("path", r.URL.Path),
+			Error(err),
+		}
+		if trace := proxyTraceFromContext(r.Context()); trace != nil {
+			fields = append(fields, trace.fields()...)
+		}
+		if errors.Is(err, context.Canceled) {
+			logger.Debug("proxy request canceled by client", fields...)
+		} else {
+			logger.Error("proxy upstream failed", fields...)
+		}
+
+		if errorHandler != nil {
+			errorHandler(w, r, err)
+			return
+		}
+		w.WriteHeader(http.StatusBadGateway)
+	}
+
+	return &proxyHandler{next: proxy, logger: logger}
+}
+
+// proxyHandler measures the client-facing side of proxied requests
+type proxyHandler struct {
+	next   http.Handler
+	logger Logger
+}
+
+// ServeHTTP implements http.Handler
+func (h *proxyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	trace := &proxyTrace{}
+	rw := newResponseRecorder(w)
+
+	h.next.ServeHTTP(rw, r.WithContext(context.WithValue(r.Context(), proxyTraceKey{}, trace)))
+
+	fields := append([]Field{
+		String("method", r.Method),
+		String("path", r.URL.Path),
+		Int("status", rw.status),
+		Int64("bytes", rw.bytes),
+		Duration("latency", time.Since(start)),
+	}, trace.fields()...)
+	h.logger.Info("proxy request completed", fields...)
+}
+
+// proxyTransport logs upstream selection and measures upstream latency.
+// Connection attempts are counted with httptrace, so retries performed by
+// http.Transport on reused connections show up as well.
+type proxyTransport struct {
+	next   http.RoundTripper
+	logger Logger
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *proxyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	trace := proxyTraceFromContext(req.Context())
+	if trace == nil {
+		trace = &proxyTrace{}
+	}
+	trace.setUpstream(req.URL.Host)
+	t.logger.Debug("proxy upstream selected",
+		String("upstream", req.URL.Host),
+		String("method", req.Method),
+		String("path", req.URL.Path),
+	)
+
+	clientTrace := &httptrace.ClientTrace{
+		GetConn: func(hostPort string) {
+			if attempt := trace.addAttempt(); attempt > 1 {
+				t.logger.Debug("proxy upstream retried",
+					String("upstream", hostPort),
+					Int("attempt", attempt),
+				)
+			}
+		},
+	}
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), clientTrace))
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	status := 0
+	if resp != nil {
+		status = resp.StatusCode
+	}
+	trace.finishUpstream(time.Since(start), status)
+	return resp, err
+}
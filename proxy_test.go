@@ -0,0 +1,144 @@
+package xlogger
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+// entriesWithMessage decodes the JSON entries with the given message
+func entriesWithMessage(t *testing.T, output, msg string) []map[string]interface{} {
+	t.Helper()
+
+	var entries []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		var entry map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		if entry["message"] == msg {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// TestInstrumentReverseProxy tests reverse proxy logging
+func TestInstrumentReverseProxy(t *testing.T) {
+	t.Run("should log upstream and client latency separately", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+		logger.SetLevel(zapcore.DebugLevel)
+
+		upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusCreated)
+			_, _ = io.WriteString(w, "hello")
+		}))
+		defer upstream.Close()
+		target, err := url.Parse(upstream.URL)
+		require.NoError(t, err)
+
+		handler := InstrumentReverseProxy(logger, httputil.NewSingleHostReverseProxy(target))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/greet", nil))
+
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Equal(t, "hello", rec.Body.String())
+
+		log := output()
+		require.Len(t, entriesWithMessage(t, log, "proxy upstream selected"), 1)
+		require.Len(t, entriesWithMessage(t, log, "proxy upstream responded"), 1)
+
+		completed := entriesWithMessage(t, log, "proxy request completed")
+		require.Len(t, completed, 1)
+		entry := completed[0]
+		assert.Equal(t, "proxy", entry["component"])
+		assert.Equal(t, "GET", entry["method"])
+		assert.Equal(t, "/greet", entry["path"])
+		assert.Equal(t, target.Host, entry["upstream"])
+		assert.EqualValues(t, http.StatusCreated, entry["status"])
+		assert.EqualValues(t, http.StatusCreated, entry["upstream_status"])
+		assert.EqualValues(t, 5, entry["bytes"])
+		assert.EqualValues(t, 0, entry["retries"])
+		assert.NotEmpty(t, entry["latency"])
+		assert.NotEmpty(t, entry["upstream_latency"])
+	})
+
+	t.Run("should log upstream failures and respond with bad gateway", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+
+		proxy := &httputil.ReverseProxy{
+			Director: func(r *http.Request) {
+				r.URL.Scheme = "http"
+				r.URL.Host = "upstream.invalid"
+			},
+			Transport: roundTripFunc(func(*http.Request) (*http.Response, error) {
+				return nil, errors.New("connection refused")
+			}),
+		}
+
+		rec := httptest.NewRecorder()
+		InstrumentReverseProxy(logger, proxy).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/orders", nil))
+
+		assert.Equal(t, http.StatusBadGateway, rec.Code)
+
+		log := output()
+		failed := entriesWithMessage(t, log, "proxy upstream failed")
+		require.Len(t, failed, 1)
+		assert.Equal(t, "error", failed[0]["level"])
+		assert.Equal(t, "upstream.invalid", failed[0]["upstream"])
+		assert.Contains(t, failed[0]["error"], "connection refused")
+
+		completed := entriesWithMessage(t, log, "proxy request completed")
+		require.Len(t, completed, 1)
+		assert.EqualValues(t, http.StatusBadGateway, completed[0]["status"])
+	})
+
+	t.Run("should keep configured hooks", func(t *testing.T) {
+		logger, _ := newFileLogger(t)
+
+		var modified, handled bool
+		proxy := &httputil.ReverseProxy{
+			Director: func(r *http.Request) {
+				r.URL.Scheme = "http"
+				r.URL.Host = "upstream.invalid"
+			},
+			Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Header:     http.Header{},
+					Body:       io.NopCloser(strings.NewReader("")),
+					Request:    r,
+				}, nil
+			}),
+			ModifyResponse: func(*http.Response) error {
+				modified = true
+				return errors.New("rejected")
+			},
+			ErrorHandler: func(w http.ResponseWriter, _ *http.Request, _ error) {
+				handled = true
+				w.WriteHeader(http.StatusTeapot)
+			},
+		}
+
+		rec := httptest.NewRecorder()
+		InstrumentReverseProxy(logger, proxy).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		assert.True(t, modified)
+		assert.True(t, handled)
+		assert.Equal(t, http.StatusTeapot, rec.Code)
+	})
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
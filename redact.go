@@ -0,0 +1,471 @@
+package xlogger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// redactedValue replaces redacted field values and pattern matches
+const redactedValue = "***"
+
+// DefaultRedactionKeys are field keys commonly holding credentials, for use
+// with WithRedaction
+var DefaultRedactionKeys = []string{
+	"password", "passwd", "secret", "token", "access_token", "refresh_token",
+	"api_key", "apikey", "authorization", "cookie", "set-cookie",
+}
+
+// Patterns of personal data commonly redacted, for use with WithRedaction
+var (
+	// CreditCardPattern matches card numbers of 13 to 19 digits, optionally
+	// grouped with spaces or dashes
+	CreditCardPattern = regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`)
+	// EmailPattern matches email addresses
+	EmailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+)
+
+// RedactionConfig lists the field keys whose values are replaced with "***"
+// and the patterns replaced with "***" in messages and string values.
+type RedactionConfig struct {
+	Keys     []string         // Field keys, matched case-insensitively
+	Patterns []*regexp.Regexp // Patterns masked in messages, strings, byte strings, stringers and errors
+}
+
+// redactor masks the keys and patterns of a RedactionConfig
+type redactor struct {
+	keys     map[string]struct{}
+	patterns []*regexp.Regexp
+}
+
+// newRedactor returns the redactor of cfg, nil when there is nothing to
+// redact
+func newRedactor(cfg *RedactionConfig) *redactor {
+	if cfg == nil || len(cfg.Keys) == 0 && len(cfg.Patterns) == 0 {
+		return nil
+	}
+	r := &redactor{keys: make(map[string]struct{}, len(cfg.Keys))}
+	for _, key := range cfg.Keys {
+		r.keys[strings.ToLower(key)] = struct{}{}
+	}
+	for _, pattern := range cfg.Patterns {
+		if pattern != nil {
+			r.patterns = append(r.patterns, pattern)
+		}
+	}
+	return r
+}
+
+// text returns s with every pattern match masked
+func (r *redactor) text(s string) (string, bool) {
+	redacted := false
+	for _, pattern := range r.patterns {
+		if pattern.MatchString(s) {
+			s = pattern.ReplaceAllString(s, redactedValue)
+			redacted = true
+		}
+	}
+	return s, redacted
+}
+
+// fields returns fields with redacted keys and pattern matches masked,
+// copying the slice only when something is masked
+func (r *redactor) fields(fields []zapcore.Field) []zapcore.Field {
+	redacted, copied := fields, false
+	for i, field := range fields {
+		masked, ok := r.field(field)
+		if !ok {
+			continue
+		}
+		if !copied {
+			redacted, copied = append([]zapcore.Field(nil), fields...), true
+		}
+		redacted[i] = masked
+	}
+	return redacted
+}
+
+// field returns the masked field, false when it is kept. Objects and arrays
+// are wrapped to mask their keys and values as they are encoded, and
+// reflected values are masked in their JSON form.
+func (r *redactor) field(field zapcore.Field) (zapcore.Field, bool) {
+	if r.masksKey(field.Key) {
+		return zapcore.Field{Key: field.Key, Type: zapcore.StringType, String: redactedValue}, true
+	}
+	switch field.Type {
+	case zapcore.ObjectMarshalerType:
+		if m, ok := field.Interface.(zapcore.ObjectMarshaler); ok {
+			field.Interface = redactedObject{marshaler: m, redactor: r}
+			return field, true
+		}
+	case zapcore.ArrayMarshalerType:
+		if m, ok := field.Interface.(zapcore.ArrayMarshaler); ok {
+			field.Interface = redactedArray{marshaler: m, redactor: r}
+			return field, true
+		}
+	case zapcore.ReflectType:
+		if value, ok := r.reflected(field.Interface); ok {
+			field.Interface = value
+			return field, true
+		}
+	}
+
+	var (
+		value string
+		ok    bool
+	)
+	switch field.Type {
+	case zapcore.StringType:
+		value, ok = r.text(field.String)
+	case zapcore.ByteStringType:
+		if b, isBytes := field.Interface.([]byte); isBytes {
+			value, ok = r.text(string(b))
+		}
+	case zapcore.ErrorType:
+		if err, isErr := field.Interface.(error); isErr && !isNilValue(err) {
+			value, ok = r.text(err.Error())
+		}
+	case zapcore.StringerType:
+		if s, isStringer := field.Interface.(fmt.Stringer); isStringer && !isNilValue(s) {
+			value, ok = r.text(s.String())
+		}
+	}
+	if !ok {
+		return field, false
+	}
+	return zapcore.Field{Key: field.Key, Type: zapcore.StringType, String: value}, true
+}
+
+// masksKey reports whether the values of key are redacted
+func (r *redactor) masksKey(key string) bool {
+	_, ok := r.keys[strings.ToLower(key)]
+	return ok
+}
+
+// reflected returns value with redacted keys and pattern matches masked in
+// its JSON form, false when nothing is masked or value has no JSON form
+func (r *redactor) reflected(value interface{}) (interface{}, bool) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return value, false
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var decoded interface{}
+	if err := decoder.Decode(&decoded); err != nil {
+		return value, false
+	}
+	return r.jsonValue(decoded)
+}
+
+// jsonValue masks the redacted keys and pattern matches of a decoded JSON
+// value in place, reporting whether anything was masked
+func (r *redactor) jsonValue(value interface{}) (interface{}, bool) {
+	masked := false
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if r.masksKey(key) {
+				v[key], masked = redactedValue, true
+			} else if item, ok := r.jsonValue(item); ok {
+				v[key], masked = item, true
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			if item, ok := r.jsonValue(item); ok {
+				v[i], masked = item, true
+			}
+		}
+	case string:
+		return r.text(v)
+	}
+	return value, masked
+}
+
+// redactedObject masks the keys and values of an object as it is encoded
+type redactedObject struct {
+	marshaler zapcore.ObjectMarshaler
+	redactor  *redactor
+}
+
+// MarshalLogObject implements zapcore.ObjectMarshaler
+func (o redactedObject) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	return o.marshaler.MarshalLogObject(&redactObjectEncoder{ObjectEncoder: enc, redactor: o.redactor})
+}
+
+// redactedArray masks the values of an array as it is encoded
+type redactedArray struct {
+	marshaler zapcore.ArrayMarshaler
+	redactor  *redactor
+}
+
+// MarshalLogArray implements zapcore.ArrayMarshaler
+func (a redactedArray) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	return a.marshaler.MarshalLogArray(&redactArrayEncoder{ArrayEncoder: enc, redactor: a.redactor})
+}
+
+// redactObjectEncoder writes "***" for the values of redacted keys, masks
+// pattern matches in strings and wraps nested objects and arrays
+type redactObjectEncoder struct {
+	zapcore.ObjectEncoder
+	redactor *redactor
+}
+
+// mask writes "***" for key when it is redacted
+func (e *redactObjectEncoder) mask(key string) bool {
+	if !e.redactor.masksKey(key) {
+		return false
+	}
+	e.ObjectEncoder.AddString(key, redactedValue)
+	return true
+}
+
+// AddArray implements zapcore.ObjectEncoder
+func (e *redactObjectEncoder) AddArray(key string, marshaler zapcore.ArrayMarshaler) error {
+	if e.mask(key) {
+		return nil
+	}
+	return e.ObjectEncoder.AddArray(key, redactedArray{marshaler: marshaler, redactor: e.redactor})
+}
+
+// AddObject implements zapcore.ObjectEncoder
+func (e *redactObjectEncoder) AddObject(key string, marshaler zapcore.ObjectMarshaler) error {
+	if e.mask(key) {
+		return nil
+	}
+	return e.ObjectEncoder.AddObject(key, redactedObject{marshaler: marshaler, redactor: e.redactor})
+}
+
+// AddReflected implements zapcore.ObjectEncoder
+func (e *redactObjectEncoder) AddReflected(key string, value interface{}) error {
+	if e.mask(key) {
+		return nil
+	}
+	value, _ = e.redactor.reflected(value)
+	return e.ObjectEncoder.AddReflected(key, value)
+}
+
+// AddString implements zapcore.ObjectEncoder
+func (e *redactObjectEncoder) AddString(key, value string) {
+	if !e.mask(key) {
+		value, _ = e.redactor.text(value)
+		e.ObjectEncoder.AddString(key, value)
+	}
+}
+
+// AddByteString implements zapcore.ObjectEncoder
+func (e *redactObjectEncoder) AddByteString(key string, value []byte) {
+	if e.mask(key) {
+		return
+	}
+	if masked, ok := e.redactor.text(string(value)); ok {
+		e.ObjectEncoder.AddString(key, masked)
+		return
+	}
+	e.ObjectEncoder.AddByteString(key, value)
+}
+
+// AddBinary implements zapcore.ObjectEncoder
+func (e *redactObjectEncoder) AddBinary(key string, value []byte) {
+	if !e.mask(key) {
+		e.ObjectEncoder.AddBinary(key, value)
+	}
+}
+
+// AddBool implements zapcore.ObjectEncoder
+func (e *redactObjectEncoder) AddBool(key string, value bool) {
+	if !e.mask(key) {
+		e.ObjectEncoder.AddBool(key, value)
+	}
+}
+
+// AddComplex128 implements zapcore.ObjectEncoder
+func (e *redactObjectEncoder) AddComplex128(key string, value complex128) {
+	if !e.mask(key) {
+		e.ObjectEncoder.AddComplex128(key, value)
+	}
+}
+
+// AddComplex64 implements zapcore.ObjectEncoder
+func (e *redactObjectEncoder) AddComplex64(key string, value complex64) {
+	if !e.mask(key) {
+		e.ObjectEncoder.AddComplex64(key, value)
+	}
+}
+
+// AddDuration implements zapcore.ObjectEncoder
+func (e *redactObjectEncoder) AddDuration(key string, value time.Duration) {
+	if !e.mask(key) {
+		e.ObjectEncoder.AddDuration(key, value)
+	}
+}
+
+// AddFloat64 implements zapcore.ObjectEncoder
+func (e *redactObjectEncoder) AddFloat64(key string, value float64) {
+	if !e.mask(key) {
+		e.ObjectEncoder.AddFloat64(key, value)
+	}
+}
+
+// AddFloat32 implements zapcore.ObjectEncoder
+func (e *redactObjectEncoder) AddFloat32(key string, value float32) {
+	if !e.mask(key) {
+		e.ObjectEncoder.AddFloat32(key, value)
+	}
+}
+
+// AddInt implements zapcore.ObjectEncoder
+func (e *redactObjectEncoder) AddInt(key string, value int) {
+	if !e.mask(key) {
+		e.ObjectEncoder.AddInt(key, value)
+	}
+}
+
+// AddInt64 implements zapcore.ObjectEncoder
+func (e *redactObjectEncoder) AddInt64(key string, value int64) {
+	if !e.mask(key) {
+		e.ObjectEncoder.AddInt64(key, value)
+	}
+}
+
+// AddInt32 implements zapcore.ObjectEncoder
+func (e *redactObjectEncoder) AddInt32(key string, value int32) {
+	if !e.mask(key) {
+		e.ObjectEncoder.AddInt32(key, value)
+	}
+}
+
+// AddInt16 implements zapcore.ObjectEncoder
+func (e *redactObjectEncoder) AddInt16(key string, value int16) {
+	if !e.mask(key) {
+		e.ObjectEncoder.AddInt16(key, value)
+	}
+}
+
+// AddInt8 implements zapcore.ObjectEncoder
+func (e *redactObjectEncoder) AddInt8(key string, value int8) {
+	if !e.mask(key) {
+		e.ObjectEncoder.AddInt8(key, value)
+	}
+}
+
+// AddTime implements zapcore.ObjectEncoder
+func (e *redactObjectEncoder) AddTime(key string, value time.Time) {
+	if !e.mask(key) {
+		e.ObjectEncoder.AddTime(key, value)
+	}
+}
+
+// AddUint implements zapcore.ObjectEncoder
+func (e *redactObjectEncoder) AddUint(key string, value uint) {
+	if !e.mask(key) {
+		e.ObjectEncoder.AddUint(key, value)
+	}
+}
+
+// AddUint64 implements zapcore.ObjectEncoder
+func (e *redactObjectEncoder) AddUint64(key string, value uint64) {
+	if !e.mask(key) {
+		e.ObjectEncoder.AddUint64(key, value)
+	}
+}
+
+// AddUint32 implements zapcore.ObjectEncoder
+func (e *redactObjectEncoder) AddUint32(key string, value uint32) {
+	if !e.mask(key) {
+		e.ObjectEncoder.AddUint32(key, value)
+	}
+}
+
+// AddUint16 implements zapcore.ObjectEncoder
+func (e *redactObjectEncoder) AddUint16(key string, value uint16) {
+	if !e.mask(key) {
+		e.ObjectEncoder.AddUint16(key, value)
+	}
+}
+
+// AddUint8 implements zapcore.ObjectEncoder
+func (e *redactObjectEncoder) AddUint8(key string, value uint8) {
+	if !e.mask(key) {
+		e.ObjectEncoder.AddUint8(key, value)
+	}
+}
+
+// AddUintptr implements zapcore.ObjectEncoder
+func (e *redactObjectEncoder) AddUintptr(key string, value uintptr) {
+	if !e.mask(key) {
+		e.ObjectEncoder.AddUintptr(key, value)
+	}
+}
+
+// redactArrayEncoder masks pattern matches in the strings of an array and
+// wraps its nested objects and arrays
+type redactArrayEncoder struct {
+	zapcore.ArrayEncoder
+	redactor *redactor
+}
+
+// AppendArray implements zapcore.ArrayEncoder
+func (e *redactArrayEncoder) AppendArray(marshaler zapcore.ArrayMarshaler) error {
+	return e.ArrayEncoder.AppendArray(redactedArray{marshaler: marshaler, redactor: e.redactor})
+}
+
+// AppendObject implements zapcore.ArrayEncoder
+func (e *redactArrayEncoder) AppendObject(marshaler zapcore.ObjectMarshaler) error {
+	return e.ArrayEncoder.AppendObject(redactedObject{marshaler: marshaler, redactor: e.redactor})
+}
+
+// AppendReflected implements zapcore.ArrayEncoder
+func (e *redactArrayEncoder) AppendReflected(value interface{}) error {
+	value, _ = e.redactor.reflected(value)
+	return e.ArrayEncoder.AppendReflected(value)
+}
+
+// AppendString implements zapcore.ArrayEncoder
+func (e *redactArrayEncoder) AppendString(value string) {
+	value, _ = e.redactor.text(value)
+	e.ArrayEncoder.AppendString(value)
+}
+
+// AppendByteString implements zapcore.ArrayEncoder
+func (e *redactArrayEncoder) AppendByteString(value []byte) {
+	if masked, ok := e.redactor.text(string(value)); ok {
+		e.ArrayEncoder.AppendString(masked)
+		return
+	}
+	e.ArrayEncoder.AppendByteString(value)
+}
+
+// redactCore masks redacted keys and patterns before entries reach the
+// outputs, sinks, hooks and reporters
+type redactCore struct {
+	zapcore.Core
+	errSink  zapcore.WriteSyncer
+	redactor *redactor
+}
+
+// With implements zapcore.Core
+func (c *redactCore) With(fields []zapcore.Field) zapcore.Core {
+	return &redactCore{Core: c.Core.With(c.redactor.fields(fields)), errSink: c.errSink, redactor: c.redactor}
+}
+
+// Check implements zapcore.Core
+func (c *redactCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Core.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write implements zapcore.Core
+func (c *redactCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	ent.Message, _ = c.redactor.text(ent.Message)
+	writeChecked(c.Core, ent, c.redactor.fields(fields), c.errSink)
+	return nil
+}
@@ -0,0 +1,185 @@
+package xlogger
+
+import (
+	"errors"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+// TestRedactor tests masking of redacted keys and patterns
+func TestRedactor(t *testing.T) {
+	r := newRedactor(&RedactionConfig{
+		Keys:     []string{"Password", "authorization"},
+		Patterns: []*regexp.Regexp{CreditCardPattern, EmailPattern},
+	})
+
+	t.Run("should mask values of redacted keys of any type", func(t *testing.T) {
+		for _, field := range []zapcore.Field{
+			{Key: "password", Type: zapcore.StringType, String: "hunter2"},
+			{Key: "PASSWORD", Type: zapcore.Int64Type, Integer: 1234},
+			{Key: "Authorization", Type: zapcore.StringType, String: "Bearer abc"},
+		} {
+			masked, ok := r.field(field)
+			assert.True(t, ok, field.Key)
+			assert.Equal(t, "***", masked.String)
+		}
+	})
+
+	t.Run("should mask pattern matches in strings, stringers and errors", func(t *testing.T) {
+		tests := []struct {
+			name  string
+			field zapcore.Field
+			want  string
+		}{
+			{"card", zapcore.Field{Key: "note", Type: zapcore.StringType, String: "paid with 4111 1111 1111 1111"}, "paid with ***"},
+			{"email", zapcore.Field{Key: "user", Type: zapcore.ByteStringType, Interface: []byte("jane@example.com")}, "***"},
+			{"error", zapcore.Field{Key: "error", Type: zapcore.ErrorType, Interface: errors.New("no account for jane@example.com")}, "no account for ***"},
+			{"stringer", Stringer("owner", stringerFunc(func() string { return "jane@example.com" })).Value().(zapcore.Field), "***"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				masked, ok := r.field(tt.field)
+				assert.True(t, ok)
+				assert.Equal(t, tt.want, masked.String)
+			})
+		}
+	})
+
+	t.Run("should keep other fields without copying", func(t *testing.T) {
+		fields := []zapcore.Field{{Key: "order_id", Type: zapcore.StringType, String: "ord-42"}}
+		redacted := r.fields(fields)
+		assert.Equal(t, &fields[0], &redacted[0])
+		assert.Nil(t, newRedactor(&RedactionConfig{}))
+	})
+
+	t.Run("should keep stringers and typed nil values without matches", func(t *testing.T) {
+		var typedNil *pointerError
+		fields := []zapcore.Field{
+			Binary("payload", []byte("ping")).Value().(zapcore.Field),
+			{Key: "error", Type: zapcore.ErrorType, Interface: typedNil},
+			{Key: "owner", Type: zapcore.StringerType, Interface: (*pointerStringer)(nil)},
+		}
+		var redacted []zapcore.Field
+		require.NotPanics(t, func() { redacted = r.fields(fields) })
+		assert.Equal(t, &fields[0], &redacted[0])
+	})
+}
+
+// stringerFunc implements fmt.Stringer with a function
+type stringerFunc func() string
+
+// String implements fmt.Stringer
+func (f stringerFunc) String() string {
+	return f()
+}
+
+// pointerStringer is a stringer whose String method panics on a nil receiver
+type pointerStringer struct {
+	s string
+}
+
+// String implements fmt.Stringer
+func (p *pointerStringer) String() string {
+	return p.s
+}
+
+// TestWithRedaction_Logger tests redaction across outputs and sinks
+func TestWithRedaction_Logger(t *testing.T) {
+	t.Run("should mask messages, fields and With fields in every output", func(t *testing.T) {
+		dir := t.TempDir()
+		path, sinkPath := filepath.Join(dir, "app.log"), filepath.Join(dir, "sink.log")
+		logger, err := NewZapLogger(NewLoggerConfig(
+			WithOutputPaths(path),
+			WithSink(SinkConfig{Output: sinkPath}),
+			WithRedaction(DefaultRedactionKeys, []*regexp.Regexp{EmailPattern}),
+		))
+		require.NoError(t, err)
+
+		logger.With(String("token", "abc123")).Info("signup from jane@example.com",
+			String("password", "hunter2"),
+			String("plan", "pro"),
+		)
+		require.NoError(t, logger.Sync())
+
+		for _, output := range []string{readFile(t, path), readFile(t, sinkPath)} {
+			entries := entriesWithMessage(t, output, "signup from ***")
+			require.Len(t, entries, 1)
+			assert.Equal(t, "***", entries[0]["token"])
+			assert.Equal(t, "***", entries[0]["password"])
+			assert.Equal(t, "pro", entries[0]["plan"])
+			assert.NotContains(t, output, "jane@example.com")
+		}
+	})
+
+	t.Run("should mask nested keys and values", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "app.log")
+		logger, err := NewZapLogger(NewLoggerConfig(
+			WithOutputPaths(path),
+			WithRedaction(DefaultRedactionKeys, []*regexp.Regexp{EmailPattern}),
+		))
+		require.NoError(t, err)
+
+		logger.Info("nested",
+			Dict("user", String("password", "hunter2"), String("name", "jane"), Dict("session", Int("token", 42))),
+			StringMap("headers", map[string]string{"authorization": "Bearer abc", "accept": "text/plain"}),
+			Any("cfg", map[string]interface{}{"token": "t0k3n", "retries": 3, "owner": map[string]string{"email": "jane@example.com"}}),
+			Object("login", redactTestLogin{Email: "jane@example.com", Secret: "s3cr3t"}),
+			Strings("contacts", []string{"jane@example.com", "ops"}),
+		)
+		require.NoError(t, logger.Sync())
+
+		log := readFile(t, path)
+		entries := entriesWithMessage(t, log, "nested")
+		require.Len(t, entries, 1)
+		entry := entries[0]
+		assert.Equal(t, map[string]interface{}{"password": "***", "name": "jane", "session": map[string]interface{}{"token": "***"}}, entry["user"])
+		assert.Equal(t, map[string]interface{}{"authorization": "***", "accept": "text/plain"}, entry["headers"])
+		assert.Equal(t, map[string]interface{}{"token": "***", "retries": float64(3), "owner": map[string]interface{}{"email": "***"}}, entry["cfg"])
+		assert.Equal(t, map[string]interface{}{"email": "***", "secret": "***"}, entry["login"])
+		assert.Equal(t, []interface{}{"***", "ops"}, entry["contacts"])
+		for _, leaked := range []string{"hunter2", "Bearer abc", "t0k3n", "s3cr3t", "jane@example.com"} {
+			assert.NotContains(t, log, leaked)
+		}
+	})
+
+	t.Run("should log typed nil errors and stringers", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "app.log")
+		logger, err := NewZapLogger(NewLoggerConfig(
+			WithOutputPaths(path),
+			WithRedaction(nil, []*regexp.Regexp{EmailPattern}),
+		))
+		require.NoError(t, err)
+
+		require.NotPanics(t, func() {
+			logger.Error("lookup failed",
+				Error((*pointerError)(nil)),
+				Stringer("owner", (*pointerStringer)(nil)),
+				Stringer("contact", &pointerStringer{s: "jane@example.com"}),
+			)
+		})
+
+		entries := entriesWithMessage(t, readFile(t, path), "lookup failed")
+		require.Len(t, entries, 1)
+		assert.Equal(t, "<nil>", entries[0]["error"])
+		assert.Equal(t, "<nil>", entries[0]["owner"])
+		assert.Equal(t, "***", entries[0]["contact"])
+	})
+}
+
+// redactTestLogin is a zapcore.ObjectMarshaler with a redacted key
+type redactTestLogin struct {
+	Email  string
+	Secret string
+}
+
+// MarshalLogObject implements zapcore.ObjectMarshaler
+func (l redactTestLogin) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("email", l.Email)
+	enc.AddByteString("secret", []byte(l.Secret))
+	return nil
+}
@@ -0,0 +1,326 @@
+package xlogger
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// ErrLoggerClosed is returned by Reconfigure after Close
+var ErrLoggerClosed = errors.New("xlogger: logger is closed")
+
+// reloadDrainTimeout bounds how long the outputs of a previous
+// configuration stay open for entries checked against it
+const reloadDrainTimeout = 10 * time.Second
+
+// reloader holds the outputs and cores Reconfigure swaps for every logger
+// derived from the same NewZapLogger
+type reloader struct {
+	mu       sync.Mutex // Serializes Reconfigure and Close
+	outputs  atomic.Pointer[loggerOutputs]
+	base     atomic.Pointer[reloadTarget]
+	infra    atomic.Pointer[reloadTarget]
+	stopCh   chan struct{}  // Closed by Close to stop waiting for drains
+	retiring sync.WaitGroup // Previous configurations not yet closed
+}
+
+// reloadTarget is the core of one configuration. Entries checked against
+// it hold a reference until they are written, so Reconfigure closes its
+// outputs only after they are drained, reloadDrainTimeout at the latest.
+type reloadTarget struct {
+	core    zapcore.Core
+	errSink zapcore.WriteSyncer
+	refs    atomic.Int64  // Entries checked against core and not yet written
+	retired atomic.Bool   // Set once Reconfigure swapped core out
+	drained chan struct{} // Closed once retired and refs drops to zero
+	once    sync.Once
+}
+
+func newReloadTarget(core zapcore.Core, errSink zapcore.WriteSyncer) *reloadTarget {
+	return &reloadTarget{core: core, errSink: errSink, drained: make(chan struct{})}
+}
+
+// acquireTarget returns the target stored in p, referenced until release
+func acquireTarget(p *atomic.Pointer[reloadTarget]) *reloadTarget {
+	for {
+		target := p.Load()
+		target.refs.Add(1)
+		if !target.retired.Load() {
+			return target
+		}
+		// Swapped out since the load; the new target is stored already
+		target.release()
+	}
+}
+
+// release drops a reference taken by acquireTarget
+func (t *reloadTarget) release() {
+	if t.refs.Add(-1) == 0 && t.retired.Load() {
+		t.once.Do(func() { close(t.drained) })
+	}
+}
+
+// retire waits for the entries still being written to the target, which
+// must no longer be stored where acquireTarget finds it, until timeout or
+// stop is closed
+func (t *reloadTarget) retire(timeout, stop <-chan struct{}) {
+	t.retired.Store(true)
+	// Closes drained when no entry is in flight
+	t.refs.Add(1)
+	t.release()
+	select {
+	case <-t.drained:
+	case <-timeout:
+	case <-stop:
+	}
+}
+
+func newReloader(outputs *loggerOutputs) *reloader {
+	r := &reloader{stopCh: make(chan struct{})}
+	r.outputs.Store(outputs)
+	return r
+}
+
+// wrap returns logger writing through the core stored in target, which
+// starts as the core of logger
+func (r *reloader) wrap(logger *zap.Logger, target *atomic.Pointer[reloadTarget]) *zap.Logger {
+	target.Store(newReloadTarget(logger.Core(), r.outputs.Load().errSink))
+	return logger.WithOptions(
+		zap.WrapCore(func(zapcore.Core) zapcore.Core {
+			return &reloadCore{target: target}
+		}),
+		zap.ErrorOutput(reloadErrorOutput{r}),
+	)
+}
+
+// reloadErrorOutput writes zap's internal errors to the error outputs of
+// the current configuration
+type reloadErrorOutput struct {
+	r *reloader
+}
+
+// Write implements zapcore.WriteSyncer
+func (w reloadErrorOutput) Write(p []byte) (int, error) {
+	return w.r.outputs.Load().errSink.Write(p)
+}
+
+// Sync implements zapcore.WriteSyncer
+func (w reloadErrorOutput) Sync() error {
+	return w.r.outputs.Load().errSink.Sync()
+}
+
+// reloadCore delegates to the core of the current configuration. Fields
+// added with With and the component set by forComponent are applied again
+// after Reconfigure swaps the core.
+type reloadCore struct {
+	target    *atomic.Pointer[reloadTarget]
+	fields    []zapcore.Field
+	component string
+	cache     atomic.Pointer[reloadCached] // current core with fields and component
+}
+
+// reloadCached is the core a reloadCore derived from target
+type reloadCached struct {
+	target *reloadTarget
+	core   zapcore.Core
+}
+
+// current returns the core of the current configuration with the fields
+// and component of c
+func (c *reloadCore) current() zapcore.Core {
+	return c.derive(c.target.Load())
+}
+
+// derive returns the core of target with the fields and component of c,
+// deriving it once per configuration
+func (c *reloadCore) derive(target *reloadTarget) zapcore.Core {
+	if cached := c.cache.Load(); cached != nil && cached.target == target {
+		return cached.core
+	}
+	core := target.core
+	if len(c.fields) > 0 {
+		core = core.With(c.fields)
+	}
+	if c.component != "" {
+		core = forComponent(core, c.component)
+	}
+	c.cache.Store(&reloadCached{target: target, core: core})
+	return core
+}
+
+// Enabled implements zapcore.LevelEnabler
+func (c *reloadCore) Enabled(level zapcore.Level) bool {
+	return c.current().Enabled(level)
+}
+
+// Level reports the current core's minimum level to zap.Logger.Level
+func (c *reloadCore) Level() zapcore.Level {
+	return zapcore.LevelOf(c.current())
+}
+
+// With implements zapcore.Core. The fields are encoded right away, like
+// zap does, and only encoded again after Reconfigure.
+func (c *reloadCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := &reloadCore{
+		target:    c.target,
+		fields:    append(c.fields[:len(c.fields):len(c.fields)], fields...),
+		component: c.component,
+	}
+	clone.current()
+	return clone
+}
+
+// Check implements zapcore.Core. The entry keeps the configuration it was
+// checked against until it is written.
+func (c *reloadCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	target := acquireTarget(c.target)
+	checked := c.derive(target).Check(ent, nil)
+	if checked == nil {
+		target.release()
+		return ce
+	}
+	checked.ErrorOutput = target.errSink
+	return ce.AddCore(ent, &reloadWrite{checked: checked, target: target})
+}
+
+// Write implements zapcore.Core
+func (c *reloadCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	target := acquireTarget(c.target)
+	defer target.release()
+	return c.derive(target).Write(ent, fields)
+}
+
+// Sync implements zapcore.Core
+func (c *reloadCore) Sync() error {
+	target := acquireTarget(c.target)
+	defer target.release()
+	return c.derive(target).Sync()
+}
+
+// reloadWrite writes an entry checked by reloadCore to the cores of its
+// configuration, then releases the configuration
+type reloadWrite struct {
+	checked *zapcore.CheckedEntry
+	target  *reloadTarget
+}
+
+// Enabled implements zapcore.LevelEnabler
+func (w *reloadWrite) Enabled(zapcore.Level) bool { return true }
+
+// With implements zapcore.Core
+func (w *reloadWrite) With([]zapcore.Field) zapcore.Core { return w }
+
+// Check implements zapcore.Core
+func (w *reloadWrite) Check(_ zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return ce
+}
+
+// Write implements zapcore.Core, reporting failures on the error outputs of
+// the configuration like zap does
+func (w *reloadWrite) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	defer w.target.release()
+	// ent carries the caller and stack zap added after Check
+	w.checked.Entry = ent
+	w.checked.Write(fields...)
+	return nil
+}
+
+// Sync implements zapcore.Core
+func (w *reloadWrite) Sync() error { return nil }
+
+// forComponent returns a copy of c filtered with the component's level
+func (c *reloadCore) forComponent(component string) zapcore.Core {
+	clone := &reloadCore{target: c.target, fields: c.fields, component: component}
+	clone.current()
+	return clone
+}
+
+// Reconfigure applies cfg to the running logger: the level, format, outputs
+// and entry pipeline (sinks, sampling, hooks, reporters and the other
+// options of Config) of the base, derived, infrastructure, component and
+// GORM loggers are swapped at once. Entries logged while swapping go to
+// either configuration, and entries checked before the swap, such as by
+// Logger.Check, are written to the previous one. The previous outputs are
+// synced and closed once those entries are written, after 10 seconds or
+// on Close at the latest, and the accounting of SinkStatus, TopProducers
+// and MaskedSecrets starts over.
+//
+// Caller, stack trace and development settings, TraceScope, TraceConflict
+// and component level overrides keep their values from NewZapLogger. When
+// cfg is invalid or an output cannot be opened, the logger keeps its
+// configuration and Reconfigure returns the error.
+//
+// Example:
+//
+//	cfg := xlogger.DefaultLoggerConfig()
+//	if err := yaml.Unmarshal(data, cfg); err != nil {
+//	    return err
+//	}
+//	if err := logger.Reconfigure(cfg); err != nil {
+//	    logger.Error("Config reload failed", xlogger.Error(err))
+//	}
+func (l *ZapLogger) Reconfigure(cfg *Config) error {
+	r := l.reload
+	if r == nil {
+		return errors.New("xlogger: logger was not created by NewZapLogger")
+	}
+	if cfg == nil {
+		cfg = DefaultLoggerConfig()
+	}
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	previous := r.outputs.Load()
+	if previous.closed.Load() {
+		return ErrLoggerClosed
+	}
+
+	outputs, err := openOutputs(cfg)
+	if err != nil {
+		return err
+	}
+	base, err := buildBaseZapLogger(cfg, l.level, l.nameLevels, outputs)
+	if err != nil {
+		outputs.close()
+		return err
+	}
+	infra, err := buildInfraZapLogger(cfg, l.level, l.componentLevels, outputs)
+	if err != nil {
+		outputs.close()
+		return err
+	}
+
+	previousBase, previousInfra := r.base.Load(), r.infra.Load()
+	r.outputs.Store(outputs)
+	r.base.Store(newReloadTarget(base.Core(), outputs.errSink))
+	r.infra.Store(newReloadTarget(infra.Core(), outputs.errSink))
+	l.level.SetLevel(cfg.Level)
+
+	// Entries checked against the previous configuration are written
+	// before its outputs are synced and closed, in the background so an
+	// entry checked and not yet written does not block Reconfigure. An
+	// entry never written keeps them open until the drain timeout or Close,
+	// which waits for them.
+	r.retiring.Add(1)
+	go func() {
+		defer r.retiring.Done()
+		timeout := make(chan struct{})
+		timer := time.AfterFunc(reloadDrainTimeout, func() { close(timeout) })
+		defer timer.Stop()
+		previousBase.retire(timeout, r.stopCh)
+		previousInfra.retire(timeout, r.stopCh)
+		_ = previousBase.core.Sync()
+		_ = previousInfra.core.Sync()
+		if previous.close != nil {
+			previous.close()
+		}
+	}()
+	return nil
+}
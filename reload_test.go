@@ -0,0 +1,226 @@
+package xlogger
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+// TestZapLogger_Reconfigure tests swapping the configuration of a live logger
+func TestZapLogger_Reconfigure(t *testing.T) {
+	t.Run("should swap outputs of base, derived and infrastructure loggers", func(t *testing.T) {
+		dir := t.TempDir()
+		first, second := filepath.Join(dir, "first.log"), filepath.Join(dir, "second.log")
+		logger, err := NewZapLogger(NewLoggerConfig(WithOutputPaths(first)))
+		require.NoError(t, err)
+		defer logger.Close(context.Background())
+
+		child := logger.With(String("worker", "w1"))
+		db := logger.ForInfra("db")
+		child.Info("before reload")
+
+		require.NoError(t, logger.Reconfigure(NewLoggerConfig(
+			WithOutputPaths(second),
+			WithLevel(zapcore.DebugLevel),
+		)))
+		child.Debug("child after reload")
+		db.Info("db after reload")
+		logger.Info("base after reload")
+
+		assert.Contains(t, readFile(t, first), "before reload")
+		assert.NotContains(t, readFile(t, first), "after reload")
+
+		output := readFile(t, second)
+		entries := entriesWithMessage(t, output, "child after reload")
+		require.Len(t, entries, 1)
+		assert.Equal(t, "w1", entries[0]["worker"])
+		assert.Len(t, entriesWithMessage(t, output, "db after reload"), 1)
+		assert.Len(t, entriesWithMessage(t, output, "base after reload"), 1)
+		assert.Equal(t, zapcore.DebugLevel, logger.Level())
+	})
+
+	t.Run("should swap the format and keep component levels", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "app.log")
+		logger, err := NewZapLogger(NewLoggerConfig(WithOutputPaths(path)))
+		require.NoError(t, err)
+		defer logger.Close(context.Background())
+
+		db := logger.ForInfra("db")
+		logger.SetComponentLevel("db", zapcore.ErrorLevel)
+		require.NoError(t, logger.Reconfigure(NewLoggerConfig(
+			WithOutputPaths(path),
+			WithFormat(FormatText),
+			WithConsoleStyle(ConsoleStylePlain),
+		)))
+		db.Warn("filtered by component")
+		logger.Warn("text entry")
+
+		output := readFile(t, path)
+		assert.NotContains(t, output, "filtered by component")
+		assert.Contains(t, output, "\tWARN\t")
+		assert.Contains(t, output, "text entry")
+	})
+
+	t.Run("should keep the configuration when cfg is invalid", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+		defer logger.Close(context.Background())
+
+		cfg := NewLoggerConfig(WithOutputPaths(filepath.Join(t.TempDir(), "app.log")))
+		cfg.Format = "xml"
+		err := logger.Reconfigure(cfg)
+		assert.ErrorContains(t, err, `format: unknown format "xml"`)
+
+		err = logger.Reconfigure(NewLoggerConfig(WithOutputPaths("unknown://app.log")))
+		assert.Error(t, err)
+
+		logger.Info("still logging")
+		assert.Contains(t, output(), "still logging")
+	})
+
+	t.Run("should write entries in flight to the previous outputs", func(t *testing.T) {
+		dir := t.TempDir()
+		first, second := filepath.Join(dir, "first.log"), filepath.Join(dir, "second.log")
+		logger, err := NewZapLogger(NewLoggerConfig(WithOutputPaths(first)))
+		require.NoError(t, err)
+		defer logger.Close(context.Background())
+
+		ce := logger.Check(zapcore.InfoLevel, "in flight")
+		require.NotNil(t, ce)
+		require.NoError(t, logger.Reconfigure(NewLoggerConfig(WithOutputPaths(second))))
+		ce.Write(String("key", "value"))
+		logger.Info("after reload")
+
+		entries := entriesWithMessage(t, readFile(t, first), "in flight")
+		require.Len(t, entries, 1)
+		assert.Equal(t, "value", entries[0]["key"])
+		assert.Contains(t, entries[0]["caller"], "reload_test.go")
+		assert.NotContains(t, readFile(t, first), "after reload")
+		assert.Contains(t, readFile(t, second), "after reload")
+	})
+
+	t.Run("should close the previous outputs on Close without waiting for checked entries", func(t *testing.T) {
+		dir := t.TempDir()
+		first := filepath.Join(dir, "first.log")
+		logger, err := NewZapLogger(NewLoggerConfig(WithOutputPaths(first), WithErrorOutputPaths(os.DevNull)))
+		require.NoError(t, err)
+
+		ce := logger.Check(zapcore.InfoLevel, "never written")
+		require.NotNil(t, ce)
+		require.NoError(t, logger.Reconfigure(NewLoggerConfig(WithOutputPaths(filepath.Join(dir, "second.log")))))
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		require.NoError(t, logger.Close(ctx))
+
+		ce.Write()
+		assert.NotContains(t, readFile(t, first), "never written")
+	})
+
+	t.Run("should write concurrent entries while reconfiguring", func(t *testing.T) {
+		dir := t.TempDir()
+		logger, err := NewZapLogger(NewLoggerConfig(WithOutputPaths(filepath.Join(dir, "0.log")), WithSamplingDisabled()))
+		require.NoError(t, err)
+		defer logger.Close(context.Background())
+
+		var wg sync.WaitGroup
+		const writers, entries = 4, 200
+		for w := 0; w < writers; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := 0; i < entries; i++ {
+					logger.Info("concurrent")
+				}
+			}()
+		}
+		paths := []string{filepath.Join(dir, "0.log")}
+		for i := 1; i <= 5; i++ {
+			paths = append(paths, filepath.Join(dir, fmt.Sprintf("%d.log", i)))
+			require.NoError(t, logger.Reconfigure(NewLoggerConfig(WithOutputPaths(paths[i]), WithSamplingDisabled())))
+		}
+		wg.Wait()
+
+		written := 0
+		for _, path := range paths {
+			written += strings.Count(readFile(t, path), `"message":"concurrent"`)
+		}
+		assert.Equal(t, writers*entries, written)
+	})
+
+	t.Run("should close the current outputs", func(t *testing.T) {
+		logger, _ := newFileLogger(t)
+		path := filepath.Join(t.TempDir(), "app.log")
+		require.NoError(t, logger.Reconfigure(NewLoggerConfig(WithOutputPaths(path))))
+		require.NoError(t, logger.Close(context.Background()))
+
+		logger.Info("after close")
+		assert.NotContains(t, readFile(t, path), "after close")
+		assert.ErrorIs(t, logger.Reconfigure(nil), ErrLoggerClosed)
+	})
+
+	t.Run("should reject loggers not created by NewZapLogger", func(t *testing.T) {
+		logger := NewNop().(*ZapLogger)
+		assert.Error(t, logger.Reconfigure(nil))
+	})
+}
+
+// TestReloadTarget_retire tests draining a configuration swapped out
+func TestReloadTarget_retire(t *testing.T) {
+	newTarget := func() *reloadTarget {
+		return newReloadTarget(zapcore.NewNopCore(), zapcore.AddSync(io.Discard))
+	}
+	closed := func() chan struct{} {
+		ch := make(chan struct{})
+		close(ch)
+		return ch
+	}
+
+	t.Run("should return once entries in flight are released", func(t *testing.T) {
+		target := newTarget()
+		var p atomic.Pointer[reloadTarget]
+		p.Store(target)
+		acquired := acquireTarget(&p)
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			target.retire(nil, nil)
+		}()
+		acquired.release()
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("retire did not return after release")
+		}
+	})
+
+	t.Run("should stop waiting on timeout or stop", func(t *testing.T) {
+		for _, tt := range []struct {
+			name          string
+			timeout, stop chan struct{}
+		}{
+			{name: "timeout", timeout: closed()},
+			{name: "stop", stop: closed()},
+		} {
+			target := newTarget()
+			var p atomic.Pointer[reloadTarget]
+			p.Store(target)
+			acquireTarget(&p)
+
+			target.retire(tt.timeout, tt.stop)
+			assert.Equal(t, int64(1), target.refs.Load(), tt.name)
+		}
+	})
+}
@@ -0,0 +1,178 @@
+package xlogger
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Replay reads entries recorded in a binary format (FormatProtobuf,
+// FormatMsgpack or FormatCBOR) and writes them through a logger built from
+// cfg, so encoder, sink or compression changes can be tested offline against
+// real traffic. Entries keep their recorded time, level, logger name, caller,
+// stacktrace and fields. Level filtering applies; sampling does not, so a
+// replay is deterministic. It returns the number of entries written.
+//
+// Entries can be recorded by logging in a binary format, or alongside the
+// current format with WithShadow(FormatProtobuf, "traffic.pb").
+//
+// Example:
+//
+//	in, _ := os.Open("traffic.pb")
+//	n, err := xlogger.Replay(xlogger.FormatProtobuf, in, xlogger.NewLoggerConfig(
+//	    xlogger.WithFormat(xlogger.FormatJSON),
+//	    xlogger.WithOutputPaths("replayed.log"),
+//	))
+func Replay(format LogFormat, r io.Reader, cfg *Config) (int, error) {
+	if cfg == nil {
+		cfg = DefaultLoggerConfig()
+	}
+
+	config := newBaseZapConfig(cfg)
+
+	outputs, err := openOutputs(cfg)
+	if err != nil {
+		return 0, err
+	}
+	defer outputs.close()
+
+	logger, err := buildZapLogger(config, nil, outputs, nil)
+	if err != nil {
+		return 0, err
+	}
+	core := logger.Core()
+
+	count := 0
+	err = readBinaryRecords(format, r, func(rec *entryRecord) error {
+		ent, err := replayEntry(rec)
+		if err != nil {
+			return err
+		}
+		if !cfg.Level.Enabled(ent.Level) {
+			return nil
+		}
+		if err := core.Write(ent, replayFields(rec.Fields)); err != nil {
+			return fmt.Errorf("replay entry %d: %w", count+1, err)
+		}
+		count++
+		return nil
+	})
+	if syncErr := core.Sync(); err == nil && syncErr != nil && !isIgnorableSyncError(syncErr) {
+		err = syncErr
+	}
+	return count, err
+}
+
+// replayEntry rebuilds the zap entry metadata of a recorded entry
+func replayEntry(rec *entryRecord) (zapcore.Entry, error) {
+	level, err := zapcore.ParseLevel(rec.Level)
+	if err != nil {
+		return zapcore.Entry{}, fmt.Errorf("replay entry: %w", err)
+	}
+
+	ent := zapcore.Entry{
+		Level:      level,
+		Time:       rec.Time,
+		LoggerName: rec.Logger,
+		Message:    rec.Message,
+		Stack:      rec.Stacktrace,
+	}
+	if i := strings.LastIndexByte(rec.Caller, ':'); i > 0 {
+		if line, err := strconv.Atoi(rec.Caller[i+1:]); err == nil {
+			ent.Caller = zapcore.NewEntryCaller(0, rec.Caller[:i], line, true)
+		}
+	}
+	return ent, nil
+}
+
+// replayFields converts recorded fields back into zap fields
+func replayFields(obj *objectValue) []zapcore.Field {
+	if obj == nil {
+		return nil
+	}
+	fields := make([]zapcore.Field, 0, len(obj.fields))
+	for _, kv := range obj.fields {
+		fields = append(fields, replayField(kv.key, kv.value))
+	}
+	return fields
+}
+
+func replayField(key string, value interface{}) zapcore.Field {
+	switch v := value.(type) {
+	case bool:
+		return zap.Bool(key, v)
+	case int64:
+		return zap.Int64(key, v)
+	case uint64:
+		return zap.Uint64(key, v)
+	case float64:
+		return zap.Float64(key, v)
+	case string:
+		return zap.String(key, v)
+	case []byte:
+		return zap.Binary(key, v)
+	case *objectValue:
+		return zap.Object(key, replayObject{v})
+	case []interface{}:
+		return zap.Array(key, replayArray(v))
+	default:
+		// nil, time.Time and time.Duration keep their type through Any
+		return zap.Any(key, v)
+	}
+}
+
+// replayObject re-emits a recorded nested object
+type replayObject struct {
+	obj *objectValue
+}
+
+// MarshalLogObject implements zapcore.ObjectMarshaler
+func (o replayObject) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, kv := range o.obj.fields {
+		replayField(kv.key, kv.value).AddTo(enc)
+	}
+	return nil
+}
+
+// replayArray re-emits a recorded array
+type replayArray []interface{}
+
+// MarshalLogArray implements zapcore.ArrayMarshaler
+func (a replayArray) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, item := range a {
+		var err error
+		switch v := item.(type) {
+		case bool:
+			enc.AppendBool(v)
+		case int64:
+			enc.AppendInt64(v)
+		case uint64:
+			enc.AppendUint64(v)
+		case float64:
+			enc.AppendFloat64(v)
+		case string:
+			enc.AppendString(v)
+		case []byte:
+			enc.AppendByteString(v)
+		case time.Time:
+			enc.AppendTime(v)
+		case time.Duration:
+			enc.AppendDuration(v)
+		case *objectValue:
+			err = enc.AppendObject(replayObject{v})
+		case []interface{}:
+			err = enc.AppendArray(replayArray(v))
+		default:
+			err = enc.AppendReflected(v)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
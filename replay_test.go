@@ -0,0 +1,117 @@
+package xlogger
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+// recordTraffic logs sample entries in a binary format and returns the recording
+func recordTraffic(t *testing.T, format LogFormat) []byte {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "traffic.bin")
+	logger, err := NewZapLogger(NewLoggerConfig(
+		WithFormat(format),
+		WithLevel(zapcore.DebugLevel),
+		WithOutputPaths(path),
+	))
+	require.NoError(t, err)
+
+	logger.Debug("cache miss", String("key", "user:1"))
+	logger.Info("request served",
+		Int("status", 200),
+		Duration("latency", 42*time.Millisecond),
+		Time("at", time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)),
+		Any("user", map[string]interface{}{"id": 7, "roles": []string{"admin", "ops"}}),
+		Any("max", uint64(18446744073709551615)),
+	)
+	logger.ForInfra("db").Warn("slow query", Float64("seconds", 1.5), Bool("retried", true))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return data
+}
+
+func convertRecording(t *testing.T, format LogFormat, data []byte) string {
+	t.Helper()
+
+	var out bytes.Buffer
+	require.NoError(t, ConvertToJSON(format, bytes.NewReader(data), &out))
+	return out.String()
+}
+
+// TestReplay tests replaying recorded entries through a new configuration
+func TestReplay(t *testing.T) {
+	t.Run("should replay through another binary format unchanged", func(t *testing.T) {
+		recording := recordTraffic(t, FormatProtobuf)
+		path := filepath.Join(t.TempDir(), "replayed.cbor")
+
+		n, err := Replay(FormatProtobuf, bytes.NewReader(recording), NewLoggerConfig(
+			WithFormat(FormatCBOR),
+			WithLevel(zapcore.DebugLevel),
+			WithOutputPaths(path),
+		))
+		require.NoError(t, err)
+		assert.Equal(t, 3, n)
+
+		replayed, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, convertRecording(t, FormatProtobuf, recording), convertRecording(t, FormatCBOR, replayed))
+	})
+
+	t.Run("should replay into JSON keeping recorded metadata", func(t *testing.T) {
+		recording := recordTraffic(t, FormatMsgpack)
+		path := filepath.Join(t.TempDir(), "replayed.log")
+
+		_, err := Replay(FormatMsgpack, bytes.NewReader(recording), NewLoggerConfig(
+			WithLevel(zapcore.DebugLevel),
+			WithOutputPaths(path),
+		))
+		require.NoError(t, err)
+
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		output := string(data)
+		assert.Contains(t, output, `"message":"request served"`)
+		assert.Contains(t, output, `"caller":"module/replay_test.go:`)
+		assert.Contains(t, output, `"user":{"id":7,"roles":["admin","ops"]}`)
+		assert.Contains(t, output, `"max":18446744073709551615`)
+		assert.Contains(t, output, `"component":"db"`)
+	})
+
+	t.Run("should apply level filtering", func(t *testing.T) {
+		recording := recordTraffic(t, FormatProtobuf)
+		path := filepath.Join(t.TempDir(), "replayed.log")
+
+		n, err := Replay(FormatProtobuf, bytes.NewReader(recording), NewLoggerConfig(
+			WithLevel(zapcore.WarnLevel),
+			WithOutputPaths(path),
+		))
+		require.NoError(t, err)
+		assert.Equal(t, 1, n)
+
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, 1, strings.Count(string(data), "\n"))
+	})
+
+	t.Run("should reject text formats", func(t *testing.T) {
+		_, err := Replay(FormatJSON, strings.NewReader("{}"), NewLoggerConfig(WithOutputPaths(os.DevNull)))
+		assert.Error(t, err)
+	})
+
+	t.Run("should report truncated recordings", func(t *testing.T) {
+		recording := recordTraffic(t, FormatProtobuf)
+
+		_, err := Replay(FormatProtobuf, bytes.NewReader(recording[:len(recording)-3]), NewLoggerConfig(WithOutputPaths(os.DevNull)))
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,170 @@
+package xlogger
+
+import (
+	"errors"
+	"runtime"
+	"strings"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// xloggerPackage prefixes the function names of this package's frames
+const xloggerPackage = "github.com/hotfixfirst/go-xlogger"
+
+// maxReportFrames bounds the stack captured for an ErrorReport
+const maxReportFrames = 64
+
+// ErrorReport is an Error, Panic or Fatal entry handed to an ErrorReporter.
+type ErrorReport struct {
+	Level         zapcore.Level
+	Time          time.Time
+	Message       string
+	Caller        string                 // file:line of the log call, when caller is enabled
+	Error         error                  // First error field of the entry, if any
+	Fields        map[string]interface{} // Logger and entry fields, without the trace IDs below
+	Stack         []runtime.Frame        // Stack of the log call or of ErrorWithStack, innermost frame first
+	RequestID     string
+	CorrelationID string
+	TraceID       string
+	SpanID        string
+}
+
+// ErrorReporter receives Error-and-above entries, for example to forward
+// them to an error tracker such as Sentry (see the xloggersentry package).
+// Report runs on the logging goroutine, so slow reporters should queue.
+// Reporters that buffer may also implement Sync() error, which is called by
+// Logger.Sync and before Panic and Fatal entries return.
+type ErrorReporter interface {
+	Report(report ErrorReport) error
+}
+
+// ErrorReporterFunc adapts a function to ErrorReporter.
+type ErrorReporterFunc func(report ErrorReport) error
+
+// Report implements ErrorReporter
+func (f ErrorReporterFunc) Report(report ErrorReport) error {
+	return f(report)
+}
+
+// reporterCore turns Error-and-above entries into ErrorReports
+type reporterCore struct {
+	zapcore.LevelEnabler
+	reporters []ErrorReporter
+	fields    []zapcore.Field
+}
+
+// Enabled implements zapcore.LevelEnabler
+func (c *reporterCore) Enabled(level zapcore.Level) bool {
+	return level >= zapcore.ErrorLevel && c.LevelEnabler.Enabled(level)
+}
+
+// With implements zapcore.Core
+func (c *reporterCore) With(fields []zapcore.Field) zapcore.Core {
+	return &reporterCore{
+		LevelEnabler: c.LevelEnabler,
+		reporters:    c.reporters,
+		fields:       append(c.fields[:len(c.fields):len(c.fields)], fields...),
+	}
+}
+
+// Check implements zapcore.Core
+func (c *reporterCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write implements zapcore.Core
+func (c *reporterCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	report := c.report(ent, fields)
+
+	var errs []error
+	for _, reporter := range c.reporters {
+		errs = append(errs, reporter.Report(report))
+	}
+	if ent.Level > zapcore.ErrorLevel {
+		// The process is about to panic or exit
+		errs = append(errs, c.Sync())
+	}
+	return errors.Join(errs...)
+}
+
+// Sync implements zapcore.Core
+func (c *reporterCore) Sync() error {
+	var errs []error
+	for _, reporter := range c.reporters {
+		if syncer, ok := reporter.(interface{ Sync() error }); ok {
+			errs = append(errs, syncer.Sync())
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// report builds the ErrorReport of an entry
+func (c *reporterCore) report(ent zapcore.Entry, fields []zapcore.Field) ErrorReport {
+	report := ErrorReport{
+		Level:   ent.Level,
+		Time:    ent.Time,
+		Message: ent.Message,
+		Stack:   callerStack(),
+	}
+	if ent.Caller.Defined {
+		report.Caller = ent.Caller.TrimmedPath()
+	}
+
+	enc := zapcore.NewMapObjectEncoder()
+	for _, list := range [][]zapcore.Field{c.fields, fields} {
+		for _, field := range list {
+			if err, ok := field.Interface.(error); ok && field.Type == zapcore.ErrorType && report.Error == nil {
+				report.Error = err
+			}
+			field.AddTo(enc)
+		}
+	}
+
+	if stack := errorStack(report.Error); stack != nil {
+		report.Stack = stack
+	}
+
+	for key, target := range map[string]*string{
+		requestIDFieldKey:     &report.RequestID,
+		correlationIDFieldKey: &report.CorrelationID,
+		traceIDFieldKey:       &report.TraceID,
+		spanIDFieldKey:        &report.SpanID,
+	} {
+		if value, ok := enc.Fields[key].(string); ok {
+			*target = value
+			delete(enc.Fields, key)
+		}
+	}
+	report.Fields = enc.Fields
+	return report
+}
+
+// callerStack returns the stack of the log call without the frames of zap
+// and of this package's logger methods
+func callerStack() []runtime.Frame {
+	pcs := make([]uintptr, maxReportFrames)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	var stack []runtime.Frame
+	for {
+		frame, more := frames.Next()
+		if len(stack) > 0 || !isLoggerFrame(frame.Function) {
+			stack = append(stack, frame)
+		}
+		if !more {
+			return stack
+		}
+	}
+}
+
+// isLoggerFrame reports whether fn belongs to zap or is a method of this
+// package or writeChecked, which passes entries between cores
+func isLoggerFrame(fn string) bool {
+	return strings.HasPrefix(fn, "go.uber.org/zap") || strings.HasPrefix(fn, xloggerPackage+".(") ||
+		fn == xloggerPackage+".writeChecked"
+}
@@ -0,0 +1,122 @@
+package xlogger
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+// recordingReporter collects reports and counts Sync calls
+type recordingReporter struct {
+	mu      sync.Mutex
+	reports []ErrorReport
+	syncs   int
+}
+
+func (r *recordingReporter) Report(report ErrorReport) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reports = append(r.reports, report)
+	return nil
+}
+
+func (r *recordingReporter) Sync() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.syncs++
+	return nil
+}
+
+// TestErrorReporter tests forwarding of Error-and-above entries
+func TestErrorReporter(t *testing.T) {
+	newReportingLogger := func(t *testing.T, opts ...Option) (*ZapLogger, *recordingReporter) {
+		reporter := &recordingReporter{}
+		opts = append([]Option{WithOutputPaths(t.TempDir() + "/app.log"), WithErrorReporter(reporter)}, opts...)
+		logger, err := NewZapLogger(NewLoggerConfig(opts...))
+		require.NoError(t, err)
+		return logger, reporter
+	}
+
+	t.Run("should report errors with fields, stack and trace IDs", func(t *testing.T) {
+		skipWithoutGLS(t)
+		logger, reporter := newReportingLogger(t)
+		cause := errors.New("card declined")
+
+		RunWithTraceVoid("req-1", "corr-1", func() {
+			logger.With(String("service", "billing")).Error("charge failed", Error(cause), Int("amount", 42))
+		})
+
+		require.Len(t, reporter.reports, 1)
+		report := reporter.reports[0]
+		assert.Equal(t, zapcore.ErrorLevel, report.Level)
+		assert.Equal(t, "charge failed", report.Message)
+		assert.Equal(t, cause, report.Error)
+		assert.Equal(t, "req-1", report.RequestID)
+		assert.Equal(t, "corr-1", report.CorrelationID)
+		assert.Equal(t, "billing", report.Fields["service"])
+		assert.EqualValues(t, 42, report.Fields["amount"])
+		assert.Equal(t, "card declined", report.Fields["error"])
+		assert.NotContains(t, report.Fields, "request_id")
+		assert.Contains(t, report.Caller, "reporter_test.go")
+		assert.False(t, report.Time.IsZero())
+
+		require.NotEmpty(t, report.Stack)
+		assert.True(t, strings.HasPrefix(report.Stack[0].Function, xloggerPackage+".TestErrorReporter"), report.Stack[0].Function)
+	})
+
+	t.Run("should ignore entries below error", func(t *testing.T) {
+		logger, reporter := newReportingLogger(t)
+
+		logger.Warn("slow")
+		logger.Info("ok")
+
+		assert.Empty(t, reporter.reports)
+	})
+
+	t.Run("should report infrastructure loggers", func(t *testing.T) {
+		logger, reporter := newReportingLogger(t)
+
+		logger.ForInfra("db").Error("connection lost")
+
+		require.Len(t, reporter.reports, 1)
+		assert.Equal(t, "db", reporter.reports[0].Fields["component"])
+		assert.Nil(t, reporter.reports[0].Error)
+	})
+
+	t.Run("should sync reporters before panic", func(t *testing.T) {
+		logger, reporter := newReportingLogger(t)
+
+		assert.Panics(t, func() { logger.Panic("invariant broken") })
+
+		require.Len(t, reporter.reports, 1)
+		assert.Equal(t, zapcore.PanicLevel, reporter.reports[0].Level)
+		assert.GreaterOrEqual(t, reporter.syncs, 1)
+
+		before := reporter.syncs
+		_ = logger.Sync()
+		assert.Greater(t, reporter.syncs, before)
+	})
+
+	t.Run("should accept functions and ignore nil", func(t *testing.T) {
+		var messages []string
+		cfg := NewLoggerConfig(
+			WithOutputPaths(t.TempDir()+"/app.log"),
+			WithErrorReporter(nil),
+			WithErrorReporter(ErrorReporterFunc(func(report ErrorReport) error {
+				messages = append(messages, report.Message)
+				return nil
+			})),
+		)
+		assert.Len(t, cfg.ErrorReporters, 1)
+
+		logger, err := NewZapLogger(cfg)
+		require.NoError(t, err)
+		logger.Error("reported")
+		assert.Equal(t, []string{"reported"}, messages)
+	})
+}
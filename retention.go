@@ -0,0 +1,52 @@
+package xlogger
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// retentionKey is the field carrying the retention hint of an entry
+const retentionKey = "retention"
+
+// RetentionHints maps levels to how long their entries should be kept.
+type RetentionHints map[zapcore.Level]time.Duration
+
+// retentionCore adds the retention hint of the entry level to each entry
+type retentionCore struct {
+	zapcore.Core
+	hints   map[zapcore.Level]zapcore.Field
+	errSink zapcore.WriteSyncer
+}
+
+func newRetentionCore(core zapcore.Core, hints RetentionHints, errSink zapcore.WriteSyncer) zapcore.Core {
+	fields := make(map[zapcore.Level]zapcore.Field, len(hints))
+	for level, retention := range hints {
+		fields[level] = zap.Duration(retentionKey, retention)
+	}
+	return &retentionCore{Core: core, hints: fields, errSink: errSink}
+}
+
+// With implements zapcore.Core
+func (c *retentionCore) With(fields []zapcore.Field) zapcore.Core {
+	return &retentionCore{Core: c.Core.With(fields), hints: c.hints, errSink: c.errSink}
+}
+
+// Check implements zapcore.Core
+func (c *retentionCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if _, ok := c.hints[ent.Level]; !ok {
+		return c.Core.Check(ent, ce)
+	}
+	if c.Core.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write implements zapcore.Core
+func (c *retentionCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	fields = appendTopLevelZap(fields, c.hints[ent.Level])
+	writeChecked(c.Core, ent, fields, c.errSink)
+	return nil
+}
@@ -0,0 +1,47 @@
+package xlogger
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+// TestRetentionHints tests per-level retention fields
+func TestRetentionHints(t *testing.T) {
+	t.Run("should stamp entries with the retention of their level", func(t *testing.T) {
+		path := t.TempDir() + "/app.log"
+		logger, err := NewZapLogger(NewLoggerConfig(
+			WithOutputPaths(path),
+			WithLevel(zapcore.DebugLevel),
+			WithRetentionHints(map[zapcore.Level]time.Duration{
+				zapcore.DebugLevel: 24 * time.Hour,
+				zapcore.ErrorLevel: 365 * 24 * time.Hour,
+			}),
+		))
+		require.NoError(t, err)
+
+		logger.Debug("cache miss")
+		logger.Info("request served")
+		logger.ForInfra("db").Error("connection lost")
+
+		log := readFile(t, path)
+		assert.Equal(t, "24h0m0s", entriesWithMessage(t, log, "cache miss")[0]["retention"])
+		assert.NotContains(t, entriesWithMessage(t, log, "request served")[0], "retention")
+		assert.Equal(t, "8760h0m0s", entriesWithMessage(t, log, "connection lost")[0]["retention"])
+	})
+
+	t.Run("should copy and replace hints", func(t *testing.T) {
+		hints := map[zapcore.Level]time.Duration{zapcore.InfoLevel: time.Hour}
+		cfg := NewLoggerConfig(
+			WithRetentionHints(map[zapcore.Level]time.Duration{zapcore.DebugLevel: time.Minute}),
+			WithRetentionHints(hints),
+		)
+		hints[zapcore.InfoLevel] = 0
+
+		assert.Equal(t, RetentionHints{zapcore.InfoLevel: time.Hour}, cfg.RetentionHints)
+		assert.Nil(t, NewLoggerConfig(WithRetentionHints(nil)).RetentionHints)
+	})
+}
@@ -0,0 +1,102 @@
+package xlogger
+
+import (
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// Default sampling: per message and level, the first 100 entries of each
+// second are logged, then every 100th
+const (
+	defaultSamplingInitial    = 100
+	defaultSamplingThereafter = 100
+)
+
+// samplingTick is the period over which sampling counts entries
+const samplingTick = time.Second
+
+// SamplingConfig limits repeated entries: per message and level, the first
+// Initial entries of each second are logged, then every Thereafter-th.
+type SamplingConfig struct {
+	Initial    int                             // Entries logged per message each second (0 to disable sampling)
+	Thereafter int                             // Then every Thereafter-th entry is logged (0 drops the rest)
+	Levels     map[zapcore.Level]LevelSampling // Per-level overrides of Initial and Thereafter
+}
+
+// LevelSampling overrides the sampling of one level. An Initial of 0 logs
+// every entry of the level.
+type LevelSampling struct {
+	Initial    int
+	Thereafter int
+}
+
+// samplingConfig returns the sampling applied by the logger, or nil when
+// sampling is disabled
+func (c *Config) samplingConfig() *SamplingConfig {
+	switch {
+	case c.DisableSampling:
+		return nil
+	case c.Sampling == nil:
+		return &SamplingConfig{Initial: defaultSamplingInitial, Thereafter: defaultSamplingThereafter}
+	default:
+		return c.Sampling
+	}
+}
+
+// withSamplingConfig returns c.Sampling, set to a copy of the default first
+// so options can adjust it
+func (c *Config) withSamplingConfig() *SamplingConfig {
+	if c.Sampling == nil {
+		c.Sampling = &SamplingConfig{Initial: defaultSamplingInitial, Thereafter: defaultSamplingThereafter}
+	}
+	return c.Sampling
+}
+
+// newSamplingCore wraps core with the samplers described by sampling
+func newSamplingCore(core zapcore.Core, sampling *SamplingConfig) zapcore.Core {
+	if sampling == nil {
+		return core
+	}
+	sampled := newSampler(core, sampling.Initial, sampling.Thereafter)
+	if len(sampling.Levels) == 0 {
+		return sampled
+	}
+
+	levels := make(map[zapcore.Level]zapcore.Core, len(sampling.Levels))
+	for level, override := range sampling.Levels {
+		levels[level] = newSampler(core, override.Initial, override.Thereafter)
+	}
+	return &levelSamplingCore{Core: sampled, levels: levels}
+}
+
+// newSampler samples core, or returns it as is when initial is not positive
+func newSampler(core zapcore.Core, initial, thereafter int) zapcore.Core {
+	if initial <= 0 {
+		return core
+	}
+	return zapcore.NewSamplerWithOptions(core, samplingTick, initial, thereafter)
+}
+
+// levelSamplingCore routes entries of overridden levels to their own sampler
+type levelSamplingCore struct {
+	zapcore.Core
+	levels map[zapcore.Level]zapcore.Core
+}
+
+// With implements zapcore.Core
+func (c *levelSamplingCore) With(fields []zapcore.Field) zapcore.Core {
+	levels := make(map[zapcore.Level]zapcore.Core, len(c.levels))
+	for level, core := range c.levels {
+		levels[level] = core.With(fields)
+	}
+	return &levelSamplingCore{Core: c.Core.With(fields), levels: levels}
+}
+
+// Check implements zapcore.Core
+func (c *levelSamplingCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if core, ok := c.levels[ent.Level]; ok {
+		return core.Check(ent, ce)
+	}
+	return c.Core.Check(ent, ce)
+}
@@ -0,0 +1,63 @@
+package xlogger
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+// TestSampling tests sampling of repeated entries
+func TestSampling(t *testing.T) {
+	logRepeated := func(t *testing.T, opts ...Option) string {
+		path := t.TempDir() + "/app.log"
+		opts = append([]Option{WithOutputPaths(path), WithLevel(zapcore.DebugLevel)}, opts...)
+		logger, err := NewZapLogger(NewLoggerConfig(opts...))
+		require.NoError(t, err)
+
+		for i := 0; i < 300; i++ {
+			logger.Debug("debug tick")
+			logger.Info("info tick")
+		}
+		return readFile(t, path)
+	}
+
+	t.Run("should sample 100 then every 100th by default", func(t *testing.T) {
+		log := logRepeated(t)
+		assert.Equal(t, 102, strings.Count(log, "debug tick"))
+		assert.Equal(t, 102, strings.Count(log, "info tick"))
+	})
+
+	t.Run("should use the configured rate", func(t *testing.T) {
+		log := logRepeated(t, WithSampling(10, 50))
+		assert.Equal(t, 15, strings.Count(log, "info tick"))
+	})
+
+	t.Run("should log every entry when disabled", func(t *testing.T) {
+		log := logRepeated(t, WithLevelSampling(zapcore.InfoLevel, 1, 0), WithSamplingDisabled())
+		assert.Equal(t, 300, strings.Count(log, "debug tick"))
+		assert.Equal(t, 300, strings.Count(log, "info tick"))
+	})
+
+	t.Run("should apply per-level overrides", func(t *testing.T) {
+		log := logRepeated(t,
+			WithLevelSampling(zapcore.DebugLevel, 0, 0),
+			WithLevelSampling(zapcore.InfoLevel, 5, 0),
+		)
+		assert.Equal(t, 300, strings.Count(log, "debug tick"))
+		assert.Equal(t, 5, strings.Count(log, "info tick"))
+	})
+
+	t.Run("should sample infrastructure loggers alike", func(t *testing.T) {
+		path := t.TempDir() + "/app.log"
+		logger, err := NewZapLogger(NewLoggerConfig(WithOutputPaths(path), WithSamplingDisabled()))
+		require.NoError(t, err)
+
+		for i := 0; i < 300; i++ {
+			logger.ForInfra("db").Info("query")
+		}
+		assert.Equal(t, 300, strings.Count(readFile(t, path), "query"))
+	})
+}
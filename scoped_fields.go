@@ -0,0 +1,118 @@
+package xlogger
+
+import "sync"
+
+// traceFieldsKey holds the scoped fields of a trace run
+const traceFieldsKey = "logger-trace-fields"
+
+// scopedFields are the fields of a trace run, added to as processing
+// discovers them
+type scopedFields struct {
+	mu     sync.Mutex
+	fields []Field
+}
+
+// get returns a copy of the fields, nil before any is added
+func (f *scopedFields) get() []Field {
+	if f == nil {
+		return nil
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Field(nil), f.fields...)
+}
+
+// add appends fields
+func (f *scopedFields) add(fields ...Field) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.fields = append(f.fields, fields...)
+}
+
+// inheritFields returns the scoped fields of a new run starting with those
+// of parent, which may be nil
+func inheritFields(parent *scopedFields) *scopedFields {
+	return &scopedFields{fields: parent.get()}
+}
+
+// RunWithFields executes fn within a trace run whose entries all carry
+// fields, keeping the trace IDs, actor and fields of an enclosing run. A
+// field added with the key of an enclosing field replaces it.
+//
+// Example:
+//
+//	err := xlogger.RunWithFields([]xlogger.Field{xlogger.String("job", "invoices")}, func() error {
+//	    return processBatch(ctx) // every entry includes job
+//	})
+func RunWithFields(fields []Field, fn func() error) error {
+	return defaultTraceScope.RunWithFields(fields, fn)
+}
+
+// AddScopedField adds field to every later entry of the current trace run,
+// for values discovered deep in a call stack such as the order being
+// processed. Like SetTraceActor it does not change enclosing runs, and it
+// reports false outside a run.
+//
+// Example:
+//
+//	order, err := parseOrder(msg)
+//	if err == nil {
+//	    xlogger.AddScopedField(xlogger.String("order_id", order.ID))
+//	}
+func AddScopedField(field Field) bool {
+	return defaultTraceScope.AddScopedField(field)
+}
+
+// RunWithFields is the scoped form of the package-level RunWithFields.
+func (s *TraceScope) RunWithFields(fields []Field, fn func() error) error {
+	if fn == nil {
+		return nil
+	}
+
+	values := s.runValues(traceValues{})
+	values[traceFieldsKey].(*scopedFields).add(fields...)
+
+	var result error
+	s.store.SetValues(values, func() {
+		result = fn()
+	})
+	return result
+}
+
+// AddScopedField is the scoped form of the package-level AddScopedField.
+func (s *TraceScope) AddScopedField(field Field) bool {
+	fields := s.scopedFields()
+	if fields == nil {
+		return false
+	}
+	fields.add(field)
+	return true
+}
+
+// scopedFields returns the scoped fields of the current run, nil outside a
+// run
+func (s *TraceScope) scopedFields() *scopedFields {
+	value, ok := s.store.GetValue(traceFieldsKey)
+	if !ok {
+		return nil
+	}
+	fields, _ := value.(*scopedFields)
+	return fields
+}
+
+// withScopedFields appends the scoped fields of scope as top-level fields,
+// the last field of each key winning, and leaving out keys the entry
+// already has
+func withScopedFields(scope *TraceScope, fields []Field) []Field {
+	scoped := scope.scopedFields().get()
+	if len(scoped) == 0 {
+		return fields
+	}
+	extra := make([]Field, 0, len(scoped))
+	for i, field := range scoped {
+		if fieldIndex(scoped[i+1:], field.key) < 0 && fieldIndex(fields, field.key) < 0 {
+			extra = append(extra, field)
+		}
+	}
+	return appendTopLevel(fields, extra...)
+}
@@ -0,0 +1,107 @@
+package xlogger
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestScopedFields tests fields attached to every entry of a trace run
+func TestScopedFields(t *testing.T) {
+	t.Run("should add run and discovered fields to later entries", func(t *testing.T) {
+		skipWithoutGLS(t)
+		logger, output := newFileLogger(t)
+
+		err := RunWithFields([]Field{String("job", "invoices")}, func() error {
+			logger.Info("batch started")
+			assert.True(t, AddScopedField(String("order_id", "o-1")))
+			logger.Info("order processed", String("status", "paid"))
+			return nil
+		})
+		require.NoError(t, err)
+
+		log := output()
+		started := entriesWithMessage(t, log, "batch started")
+		require.Len(t, started, 1)
+		assert.Equal(t, "invoices", started[0]["job"])
+		assert.NotContains(t, started[0], "order_id")
+		processed := entriesWithMessage(t, log, "order processed")
+		require.Len(t, processed, 1)
+		assert.Equal(t, "invoices", processed[0]["job"])
+		assert.Equal(t, "o-1", processed[0]["order_id"])
+		assert.Equal(t, "paid", processed[0]["status"])
+	})
+
+	t.Run("should keep fields passed with the entry and the last scoped value", func(t *testing.T) {
+		skipWithoutGLS(t)
+		logger, output := newFileLogger(t)
+
+		_ = RunWithFields([]Field{String("step", "parse")}, func() error {
+			AddScopedField(String("step", "charge"))
+			logger.Info("scoped")
+			logger.Info("explicit", String("step", "refund"))
+			return nil
+		})
+
+		log := output()
+		assert.Equal(t, "charge", entriesWithMessage(t, log, "scoped")[0]["step"])
+		assert.Equal(t, "refund", entriesWithMessage(t, log, "explicit")[0]["step"])
+		assert.NotContains(t, log, `"step":"parse"`)
+	})
+
+	t.Run("should inherit enclosing runs without changing them", func(t *testing.T) {
+		skipWithoutGLS(t)
+		logger, output := newFileLogger(t)
+
+		RunWithTraceVoid("req-1", "", func() {
+			AddScopedField(String("tenant", "acme"))
+			_ = RunWithFields([]Field{String("job", "export")}, func() error {
+				AddScopedField(String("file", "a.csv"))
+				logger.Info("inner")
+				return nil
+			})
+			logger.Info("outer")
+		})
+
+		log := output()
+		inner := entriesWithMessage(t, log, "inner")
+		require.Len(t, inner, 1)
+		assert.Equal(t, "req-1", inner[0]["request_id"])
+		assert.Equal(t, "acme", inner[0]["tenant"])
+		assert.Equal(t, "a.csv", inner[0]["file"])
+		outer := entriesWithMessage(t, log, "outer")
+		require.Len(t, outer, 1)
+		assert.Equal(t, "acme", outer[0]["tenant"])
+		assert.NotContains(t, outer[0], "job")
+		assert.NotContains(t, outer[0], "file")
+	})
+
+	t.Run("should carry fields to goroutines started with Go", func(t *testing.T) {
+		skipWithoutGLS(t)
+		logger, output := newFileLogger(t)
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+		_ = RunWithFields([]Field{String("job", "export")}, func() error {
+			Go(func() {
+				defer wg.Done()
+				logger.Info("worker")
+			})
+			return nil
+		})
+		wg.Wait()
+
+		assert.Equal(t, "export", entriesWithMessage(t, output(), "worker")[0]["job"])
+	})
+
+	t.Run("should be ignored outside a run", func(t *testing.T) {
+		errFailed := errors.New("failed")
+
+		assert.False(t, AddScopedField(String("order_id", "o-1")))
+		assert.ErrorIs(t, RunWithFields(nil, func() error { return errFailed }), errFailed)
+		assert.NoError(t, RunWithFields(nil, nil))
+	})
+}
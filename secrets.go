@@ -0,0 +1,230 @@
+package xlogger
+
+import (
+	"math"
+	"strings"
+	"sync/atomic"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// maskedSecret replaces suspected secrets in string fields
+const maskedSecret = "[MASKED]"
+
+// minTokenLength is the length of the shortest secret detected, an AWS
+// access key ID
+const minTokenLength = 20
+
+// Thresholds of the entropy heuristic for tokens without a known prefix
+const (
+	minSecretLength  = 24
+	minSecretEntropy = 4.0 // bits per character
+)
+
+// secretPrefixes are token prefixes of well-known credentials, with the
+// minimum length of a token carrying them
+var secretPrefixes = []struct {
+	prefix string
+	minLen int
+}{
+	{"AKIA", 20}, // AWS access key ID
+	{"ASIA", 20}, // AWS temporary access key ID
+	{"ghp_", 40}, // GitHub personal access token
+	{"gho_", 40}, // GitHub OAuth token
+	{"ghu_", 40}, // GitHub user-to-server token
+	{"ghs_", 40}, // GitHub server-to-server token
+	{"ghr_", 40}, // GitHub refresh token
+	{"github_pat_", 40},
+	{"glpat-", 26}, // GitLab personal access token
+	{"xoxb-", 20},  // Slack bot token
+	{"xoxp-", 20},  // Slack user token
+	{"xoxa-", 20},
+	{"xoxr-", 20},
+	{"xoxs-", 20},
+	{"sk_live_", 24}, // Stripe secret key
+	{"rk_live_", 24}, // Stripe restricted key
+	{"eyJ", 30},      // JWT, checked for three segments
+}
+
+// secretsCore masks suspected secrets in string fields before they reach
+// the outputs and counts them
+type secretsCore struct {
+	zapcore.Core
+	errSink zapcore.WriteSyncer
+	masked  *atomic.Uint64
+}
+
+// With implements zapcore.Core
+func (c *secretsCore) With(fields []zapcore.Field) zapcore.Core {
+	return &secretsCore{Core: c.Core.With(maskSecretFields(fields, c.masked)), errSink: c.errSink, masked: c.masked}
+}
+
+// Check implements zapcore.Core
+func (c *secretsCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Core.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write implements zapcore.Core
+func (c *secretsCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	writeChecked(c.Core, ent, maskSecretFields(fields, c.masked), c.errSink)
+	return nil
+}
+
+// maskSecretFields returns fields with suspected secrets masked, adding
+// them to counter and copying the slice only when something is masked
+func maskSecretFields(fields []zapcore.Field, counter *atomic.Uint64) []zapcore.Field {
+	masked, copied := fields, false
+	for i, field := range fields {
+		var (
+			value string
+			n     int
+		)
+		switch field.Type {
+		case zapcore.StringType:
+			value, n = maskSecrets(field.String)
+		case zapcore.ByteStringType:
+			if b, ok := field.Interface.([]byte); ok {
+				value, n = maskSecrets(string(b))
+			}
+		}
+		if n == 0 {
+			continue
+		}
+
+		if !copied {
+			masked, copied = append([]zapcore.Field(nil), fields...), true
+		}
+		masked[i] = zapcore.Field{Key: field.Key, Type: zapcore.StringType, String: value}
+		counter.Add(uint64(n))
+	}
+	return masked
+}
+
+// MaskedSecrets returns how many suspected secrets were masked since the
+// logger was created with WithSecretDetection, for example to export as a
+// metric.
+func (l *ZapLogger) MaskedSecrets() uint64 {
+	outputs := l.outputs()
+	if outputs == nil || outputs.masked == nil {
+		return 0
+	}
+	return outputs.masked.Load()
+}
+
+// maskSecrets replaces the tokens of s that look like secrets and returns
+// the number replaced
+func maskSecrets(s string) (string, int) {
+	if len(s) < minTokenLength {
+		return s, 0
+	}
+	if strings.Contains(s, "-----BEGIN") && strings.Contains(s, "PRIVATE KEY-----") {
+		return maskedSecret, 1
+	}
+
+	var (
+		b     strings.Builder
+		n     int
+		last  int
+		start = -1
+	)
+	flush := func(end int) {
+		if start < 0 {
+			return
+		}
+		if masked, ok := maskToken(s[start:end]); ok {
+			b.WriteString(s[last:start])
+			b.WriteString(masked)
+			last = end
+			n++
+		}
+		start = -1
+	}
+	for i := 0; i < len(s); i++ {
+		if isTokenChar(s[i]) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		flush(i)
+	}
+	flush(len(s))
+
+	if n == 0 {
+		return s, 0
+	}
+	b.WriteString(s[last:])
+	return b.String(), n
+}
+
+// maskToken returns the masked form of a token that looks like a secret.
+// Known prefixes are kept to help identify the credential.
+func maskToken(token string) (string, bool) {
+	for _, p := range secretPrefixes {
+		if !strings.HasPrefix(token, p.prefix) || len(token) < p.minLen {
+			continue
+		}
+		if p.prefix == "eyJ" && strings.Count(token, ".") != 2 {
+			continue
+		}
+		return p.prefix + maskedSecret, true
+	}
+
+	// Dots mark file names, hosts and versions, and hex tokens are usually
+	// IDs and hashes such as UUIDs and request IDs, rather than secrets
+	if len(token) >= minSecretLength && !strings.Contains(token, ".") && !isHexToken(token) &&
+		hasLetterAndDigit(token) && shannonEntropy(token) >= minSecretEntropy {
+		return maskedSecret, true
+	}
+	return "", false
+}
+
+// isTokenChar reports whether c can be part of a token or key
+func isTokenChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' ||
+		c == '_' || c == '-' || c == '.' || c == '+'
+}
+
+// isHexToken reports whether s only holds hex digits and dashes
+func isHexToken(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F' || c == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+func hasLetterAndDigit(s string) bool {
+	var letter, digit bool
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c >= '0' && c <= '9':
+			digit = true
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z':
+			letter = true
+		}
+	}
+	return letter && digit
+}
+
+// shannonEntropy returns the entropy of s in bits per character
+func shannonEntropy(s string) float64 {
+	var counts [256]int
+	for i := 0; i < len(s); i++ {
+		counts[s[i]]++
+	}
+
+	var entropy float64
+	for _, count := range counts {
+		if count > 0 {
+			p := float64(count) / float64(len(s))
+			entropy -= p * math.Log2(p)
+		}
+	}
+	return entropy
+}
@@ -0,0 +1,125 @@
+package xlogger
+
+import (
+	"fmt"
+	"io"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// ShadowConfig describes a candidate encoding that receives a copy of every
+// entry, so a format migration can be measured before switching to it.
+type ShadowConfig struct {
+	Format      LogFormat // Candidate format
+	OutputPaths []string  // Candidate destinations (empty to only measure)
+}
+
+// ShadowReport compares the current output with the shadow output.
+// Failures include encoding and write errors.
+type ShadowReport struct {
+	PrimaryEntries  uint64  // Entries written by the current encoder
+	ShadowEntries   uint64  // Entries written by the shadow encoder
+	PrimaryBytes    uint64  // Bytes written by the current encoder
+	ShadowBytes     uint64  // Bytes written by the shadow encoder
+	PrimaryFailures uint64  // Entries the current encoder failed to write
+	ShadowFailures  uint64  // Entries the shadow encoder failed to write
+	SizeDelta       int64   // ShadowBytes - PrimaryBytes
+	SizeRatio       float64 // ShadowBytes / PrimaryBytes (0 when nothing was written)
+}
+
+// shadowOutputs holds the shadow destination and the counters behind ShadowReport
+type shadowOutputs struct {
+	encoding        string
+	primary         *countingSink
+	sink            *countingSink
+	primaryFailures atomic.Uint64
+	shadowFailures  atomic.Uint64
+}
+
+// openShadowOutputs opens the shadow destinations and wraps the primary
+// sink so both sides are measured the same way
+func openShadowOutputs(shadow *ShadowConfig, primary zapcore.WriteSyncer) (*shadowOutputs, func(), error) {
+	ws := zapcore.AddSync(io.Discard)
+	closeSink := func() {}
+	if len(shadow.OutputPaths) > 0 {
+		var err error
+		ws, closeSink, err = zap.Open(shadow.OutputPaths...)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to open shadow outputs: %w", err)
+		}
+	}
+
+	return &shadowOutputs{
+		encoding: determineEncoding(shadow.Format),
+		primary:  newCountingSink("primary", primary),
+		sink:     newCountingSink("shadow", ws),
+	}, closeSink, nil
+}
+
+// report builds a ShadowReport from the current counters
+func (s *shadowOutputs) report() ShadowReport {
+	primary, shadow := s.primary.Status(), s.sink.Status()
+	report := ShadowReport{
+		PrimaryEntries:  primary.EntriesWritten,
+		ShadowEntries:   shadow.EntriesWritten,
+		PrimaryBytes:    primary.BytesWritten,
+		ShadowBytes:     shadow.BytesWritten,
+		PrimaryFailures: s.primaryFailures.Load(),
+		ShadowFailures:  s.shadowFailures.Load(),
+		SizeDelta:       int64(shadow.BytesWritten) - int64(primary.BytesWritten),
+	}
+	if primary.BytesWritten > 0 {
+		report.SizeRatio = float64(shadow.BytesWritten) / float64(primary.BytesWritten)
+	}
+	return report
+}
+
+// shadowCore writes every entry to the primary core and a copy to the shadow
+// core. Shadow failures are counted but never reported to the caller.
+type shadowCore struct {
+	primary zapcore.Core
+	shadow  zapcore.Core
+	outputs *shadowOutputs
+}
+
+// Enabled implements zapcore.LevelEnabler
+func (c *shadowCore) Enabled(level zapcore.Level) bool {
+	return c.primary.Enabled(level)
+}
+
+// With implements zapcore.Core
+func (c *shadowCore) With(fields []zapcore.Field) zapcore.Core {
+	return &shadowCore{
+		primary: c.primary.With(fields),
+		shadow:  c.shadow.With(fields),
+		outputs: c.outputs,
+	}
+}
+
+// Check implements zapcore.Core
+func (c *shadowCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write implements zapcore.Core
+func (c *shadowCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if err := c.shadow.Write(ent, fields); err != nil {
+		c.outputs.shadowFailures.Add(1)
+	}
+	if err := c.primary.Write(ent, fields); err != nil {
+		c.outputs.primaryFailures.Add(1)
+		return err
+	}
+	return nil
+}
+
+// Sync implements zapcore.Core
+func (c *shadowCore) Sync() error {
+	_ = c.shadow.Sync()
+	return c.primary.Sync()
+}
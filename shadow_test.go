@@ -0,0 +1,153 @@
+package xlogger
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// failingEncoder accepts fields but fails to encode every entry
+type failingEncoder struct {
+	zapcore.Encoder
+}
+
+func newFailingEncoder() failingEncoder {
+	return failingEncoder{zapcore.NewJSONEncoder(createBaseEncoderConfig())}
+}
+
+func (e failingEncoder) Clone() zapcore.Encoder {
+	return failingEncoder{e.Encoder.Clone()}
+}
+
+func (failingEncoder) EncodeEntry(zapcore.Entry, []zapcore.Field) (*buffer.Buffer, error) {
+	return nil, errors.New("cannot encode")
+}
+
+// TestZapLogger_ShadowReport tests shadow logging through the logger
+func TestZapLogger_ShadowReport(t *testing.T) {
+	t.Run("should write entries to both outputs", func(t *testing.T) {
+		dir := t.TempDir()
+		primaryPath := filepath.Join(dir, "app.log")
+		shadowPath := filepath.Join(dir, "app.msgpack")
+
+		logger, err := NewZapLogger(NewLoggerConfig(
+			WithOutputPaths(primaryPath),
+			WithShadow(FormatMsgpack, shadowPath),
+		))
+		require.NoError(t, err)
+
+		logger.Info("first", String("user", "alice"))
+		logger.ForInfra("db").Warn("second")
+		require.NoError(t, logger.Sync())
+
+		report, ok := logger.ShadowReport()
+		require.True(t, ok)
+		assert.Equal(t, uint64(2), report.PrimaryEntries)
+		assert.Equal(t, uint64(2), report.ShadowEntries)
+		assert.Zero(t, report.PrimaryFailures)
+		assert.Zero(t, report.ShadowFailures)
+
+		primary, err := os.ReadFile(primaryPath)
+		require.NoError(t, err)
+		shadow, err := os.ReadFile(shadowPath)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(len(primary)), report.PrimaryBytes)
+		assert.Equal(t, uint64(len(shadow)), report.ShadowBytes)
+		assert.Equal(t, int64(len(shadow))-int64(len(primary)), report.SizeDelta)
+		assert.InDelta(t, float64(len(shadow))/float64(len(primary)), report.SizeRatio, 1e-9)
+
+		var converted bytes.Buffer
+		require.NoError(t, ConvertToJSON(FormatMsgpack, bytes.NewReader(shadow), &converted))
+		assert.Contains(t, converted.String(), `"message":"first"`)
+		assert.Contains(t, converted.String(), `"user":"alice"`)
+	})
+
+	t.Run("should measure shadow without paths", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "app.log")
+		logger, err := NewZapLogger(NewLoggerConfig(
+			WithOutputPaths(path),
+			WithShadow(FormatText),
+		))
+		require.NoError(t, err)
+
+		logger.Info("measured")
+
+		report, ok := logger.ShadowReport()
+		require.True(t, ok)
+		assert.Equal(t, uint64(1), report.ShadowEntries)
+		assert.NotZero(t, report.ShadowBytes)
+
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		lines := 0
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		for scanner.Scan() {
+			lines++
+		}
+		assert.Equal(t, 1, lines)
+	})
+
+	t.Run("should report disabled shadow", func(t *testing.T) {
+		logger, err := NewZapLogger(NewLoggerConfig(WithOutputPaths(filepath.Join(t.TempDir(), "app.log"))))
+		require.NoError(t, err)
+
+		_, ok := logger.ShadowReport()
+		assert.False(t, ok)
+	})
+
+	t.Run("should fail with unknown shadow sink scheme", func(t *testing.T) {
+		_, err := NewZapLogger(NewLoggerConfig(WithShadow(FormatJSON, "unknown-scheme://sink")))
+		assert.Error(t, err)
+	})
+}
+
+// TestShadowCore tests failure isolation in the shadow core
+func TestShadowCore(t *testing.T) {
+	t.Run("should count shadow failures without failing primary", func(t *testing.T) {
+		var primaryBuf bytes.Buffer
+		outputs := &shadowOutputs{
+			primary: newCountingSink("primary", zapcore.AddSync(&primaryBuf)),
+			sink:    newCountingSink("shadow", zapcore.AddSync(&bytes.Buffer{})),
+		}
+		core := &shadowCore{
+			primary: zapcore.NewCore(zapcore.NewJSONEncoder(createBaseEncoderConfig()), outputs.primary, zapcore.InfoLevel),
+			shadow:  zapcore.NewCore(newFailingEncoder(), outputs.sink, zapcore.InfoLevel),
+			outputs: outputs,
+		}
+
+		err := core.With([]zapcore.Field{{Key: "k", Type: zapcore.StringType, String: "v"}}).
+			Write(zapcore.Entry{Level: zapcore.InfoLevel, Message: "kept"}, nil)
+		require.NoError(t, err)
+
+		report := outputs.report()
+		assert.Equal(t, uint64(1), report.PrimaryEntries)
+		assert.Equal(t, uint64(1), report.ShadowFailures)
+		assert.Zero(t, report.ShadowEntries)
+		assert.Contains(t, primaryBuf.String(), `"message":"kept"`)
+	})
+
+	t.Run("should follow primary level", func(t *testing.T) {
+		outputs := &shadowOutputs{
+			primary: newCountingSink("primary", zapcore.AddSync(&bytes.Buffer{})),
+			sink:    newCountingSink("shadow", zapcore.AddSync(&bytes.Buffer{})),
+		}
+		encoder := zapcore.NewJSONEncoder(createBaseEncoderConfig())
+		core := &shadowCore{
+			primary: zapcore.NewCore(encoder, outputs.primary, zapcore.WarnLevel),
+			shadow:  zapcore.NewCore(encoder.Clone(), outputs.sink, zapcore.DebugLevel),
+			outputs: outputs,
+		}
+
+		assert.False(t, core.Enabled(zapcore.InfoLevel))
+		assert.Nil(t, core.Check(zapcore.Entry{Level: zapcore.InfoLevel}, nil))
+		assert.NotNil(t, core.Check(zapcore.Entry{Level: zapcore.ErrorLevel}, nil))
+	})
+}
@@ -0,0 +1,109 @@
+package xlogger
+
+import (
+	"bytes"
+	"errors"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+// failingWriter always fails to write
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+// TestCountingSink tests per-sink byte, entry and checksum accounting
+func TestCountingSink(t *testing.T) {
+	t.Run("should count bytes entries and checksum", func(t *testing.T) {
+		var buf bytes.Buffer
+		sink := newCountingSink("memory", zapcore.AddSync(&buf))
+
+		_, err := sink.Write([]byte("first\n"))
+		require.NoError(t, err)
+		_, err = sink.Write([]byte("second\n"))
+		require.NoError(t, err)
+
+		status := sink.Status()
+		assert.Equal(t, "memory", status.Path)
+		assert.Equal(t, uint64(13), status.BytesWritten)
+		assert.Equal(t, uint64(2), status.EntriesWritten)
+		assert.Zero(t, status.WriteErrors)
+		assert.Equal(t, crc32.ChecksumIEEE(buf.Bytes()), status.Checksum)
+	})
+
+	t.Run("should count write errors", func(t *testing.T) {
+		sink := newCountingSink("broken", zapcore.AddSync(failingWriter{}))
+
+		_, err := sink.Write([]byte("lost\n"))
+		assert.Error(t, err)
+
+		status := sink.Status()
+		assert.Equal(t, uint64(1), status.WriteErrors)
+		assert.Zero(t, status.EntriesWritten)
+		assert.Zero(t, status.BytesWritten)
+	})
+}
+
+// TestZapLogger_SinkStatus tests sink accounting on the logger
+func TestZapLogger_SinkStatus(t *testing.T) {
+	t.Run("should report every output path", func(t *testing.T) {
+		dir := t.TempDir()
+		first := filepath.Join(dir, "first.log")
+		second := filepath.Join(dir, "second.log")
+
+		logger, err := NewZapLogger(NewLoggerConfig(WithOutputPaths(first, second)))
+		require.NoError(t, err)
+
+		logger.Info("one")
+		logger.With(String("k", "v")).Info("two")
+		logger.ForInfra("db").Info("three")
+		_ = logger.Sync()
+
+		statuses := logger.SinkStatus()
+		require.Len(t, statuses, 2)
+
+		for i, path := range []string{first, second} {
+			data, err := os.ReadFile(path)
+			require.NoError(t, err)
+
+			assert.Equal(t, path, statuses[i].Path)
+			assert.Equal(t, uint64(3), statuses[i].EntriesWritten)
+			assert.Equal(t, uint64(len(data)), statuses[i].BytesWritten)
+			assert.Equal(t, crc32.ChecksumIEEE(data), statuses[i].Checksum)
+		}
+	})
+
+	t.Run("should count uncompressed bytes", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "app.log.gz")
+		logger, err := NewZapLogger(NewLoggerConfig(
+			WithOutputPaths(path),
+			WithCompression(CompressionGzip, 0),
+		))
+		require.NoError(t, err)
+
+		logger.Info("compressed")
+		require.NoError(t, logger.Sync())
+
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		plain := decompress(t, CompressionGzip, data)
+
+		statuses := logger.SinkStatus()
+		require.Len(t, statuses, 1)
+		assert.Equal(t, uint64(len(plain)), statuses[0].BytesWritten)
+		assert.Equal(t, crc32.ChecksumIEEE([]byte(plain)), statuses[0].Checksum)
+	})
+
+	t.Run("should return nil for nop logger", func(t *testing.T) {
+		logger := NewNop().(*ZapLogger)
+		assert.Nil(t, logger.SinkStatus())
+	})
+}
@@ -0,0 +1,83 @@
+package xlogger
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// SinkConfig is an additional output with its own format and minimum level,
+// written next to OutputPaths.
+type SinkConfig struct {
+	Output           string        // "stdout", "stderr", a file path or a registered sink URL
+	Format           LogFormat     // Format of the output (empty for Config.Format)
+	MinLevel         zapcore.Level // Minimum level written to the output, independent of Config.Level
+	Compression      Compression   // Compression of the output, independent of Config.Compression (CompressionNone to write it plain)
+	CompressionLevel int           // Compression level (0 for the algorithm default)
+}
+
+// teeSink is an opened SinkConfig
+type teeSink struct {
+	sink     *countingSink
+	encoding string
+	level    zapcore.Level
+}
+
+// openTeeSinks opens the additional outputs of cfg and returns a function
+// closing them
+func openTeeSinks(cfg *Config) ([]teeSink, func(), error) {
+	var (
+		sinks   []teeSink
+		closers []func()
+	)
+	closeAll := func() {
+		for _, c := range closers {
+			c()
+		}
+	}
+
+	for _, sc := range cfg.Sinks {
+		ws, closeSink, err := zap.Open(sc.Output)
+		if err != nil {
+			closeAll()
+			return nil, nil, fmt.Errorf("failed to open sink %q: %w", sc.Output, err)
+		}
+		closers = append(closers, closeSink)
+		ws, err = compressSink(ws, sc.Compression, sc.CompressionLevel)
+		if err != nil {
+			closeAll()
+			return nil, nil, fmt.Errorf("failed to compress sink %q: %w", sc.Output, err)
+		}
+
+		format := sc.Format
+		if format == "" {
+			format = cfg.Format
+		}
+		sinks = append(sinks, teeSink{
+			sink:     newCountingSink(sc.Output, ws),
+			encoding: determineEncoding(format),
+			level:    sc.MinLevel,
+		})
+	}
+	return sinks, closeAll, nil
+}
+
+// newTeeSinkCores builds one core per additional output, encoding like
+// config but in the output's format, with text levels in style
+func newTeeSinkCores(config zap.Config, sinks []teeSink, style ConsoleStyle) ([]zapcore.Core, error) {
+	cores := make([]zapcore.Core, 0, len(sinks))
+	for _, s := range sinks {
+		sinkConfig := config
+		sinkConfig.Encoding = s.encoding
+		sinkConfig.EncoderConfig = createBaseEncoderConfig()
+		adjustEncoderForConsole(&sinkConfig, style)
+
+		encoder, err := buildEncoder(sinkConfig.Encoding, sinkConfig.EncoderConfig)
+		if err != nil {
+			return nil, err
+		}
+		cores = append(cores, zapcore.NewCore(encoder, s.sink, s.level))
+	}
+	return cores, nil
+}
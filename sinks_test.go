@@ -0,0 +1,84 @@
+package xlogger
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+// TestWithSink tests additional outputs with their own format and level
+func TestWithSink(t *testing.T) {
+	t.Run("should write each sink in its format from its level", func(t *testing.T) {
+		dir := t.TempDir()
+		console := filepath.Join(dir, "console.log")
+		debug := filepath.Join(dir, "debug.json")
+		alerts := filepath.Join(dir, "alerts.log")
+		logger, err := NewZapLogger(NewLoggerConfig(
+			WithFormat(FormatText),
+			WithOutputPaths(console),
+			WithSink(SinkConfig{Output: debug, Format: FormatJSON, MinLevel: zapcore.DebugLevel}),
+			WithSink(SinkConfig{Output: alerts, MinLevel: zapcore.ErrorLevel}),
+		))
+		require.NoError(t, err)
+
+		logger.Debug("cache miss", String("key", "user:1"))
+		logger.Info("request served")
+		logger.Error("payment failed")
+		require.NoError(t, logger.Sync())
+
+		consoleLog := readFile(t, console)
+		assert.NotContains(t, consoleLog, "cache miss")
+		assert.Contains(t, consoleLog, "request served")
+		assert.False(t, strings.HasPrefix(consoleLog, "{"))
+
+		debugEntries := readFile(t, debug)
+		require.Len(t, entriesWithMessage(t, debugEntries, "cache miss"), 1)
+		assert.Equal(t, "user:1", entriesWithMessage(t, debugEntries, "cache miss")[0]["key"])
+		assert.Len(t, entriesWithMessage(t, debugEntries, "payment failed"), 1)
+
+		alertLog := readFile(t, alerts)
+		assert.NotContains(t, alertLog, "request served")
+		assert.Contains(t, alertLog, "payment failed")
+		assert.False(t, strings.HasPrefix(alertLog, "{"), "empty format should follow Config.Format")
+	})
+
+	t.Run("should report sinks in SinkStatus", func(t *testing.T) {
+		dir := t.TempDir()
+		debug := filepath.Join(dir, "debug.json")
+		logger, err := NewZapLogger(NewLoggerConfig(
+			WithOutputPaths(filepath.Join(dir, "app.log")),
+			WithSink(SinkConfig{Output: debug, MinLevel: zapcore.DebugLevel}),
+		))
+		require.NoError(t, err)
+
+		logger.Debug("only in sink")
+
+		statuses := logger.SinkStatus()
+		require.Len(t, statuses, 2)
+		assert.Equal(t, debug, statuses[1].Path)
+		assert.Equal(t, uint64(1), statuses[1].EntriesWritten)
+		assert.Zero(t, statuses[0].EntriesWritten)
+	})
+
+	t.Run("should ignore sinks without output or with invalid format", func(t *testing.T) {
+		cfg := NewLoggerConfig(
+			WithSink(SinkConfig{Format: FormatJSON}),
+			WithSink(SinkConfig{Output: "stderr", Format: "xml"}),
+			WithSink(SinkConfig{Output: "stderr", Format: "JSON"}),
+		)
+		require.Len(t, cfg.Sinks, 1)
+		assert.Equal(t, FormatJSON, cfg.Sinks[0].Format)
+	})
+
+	t.Run("should fail on sinks that cannot be opened", func(t *testing.T) {
+		_, err := NewZapLogger(NewLoggerConfig(
+			WithOutputPaths(filepath.Join(t.TempDir(), "app.log")),
+			WithSink(SinkConfig{Output: "unknown://sink"}),
+		))
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,108 @@
+package xlogger
+
+import (
+	"io"
+	"log"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// sourceFieldKey marks entries captured by CaptureStdLog
+const sourceFieldKey = "source"
+
+// Frames between the caller of a *log.Logger method and stdLogWriter.Write:
+// the exported method and log.(*Logger).output
+const stdLogDepth = 2
+
+// NewStdLog returns a *log.Logger writing each line to logger at level,
+// for APIs such as httputil.ReverseProxy.ErrorLog and http.Server.ErrorLog.
+// Entries report the caller of the *log.Logger method.
+func NewStdLog(logger Logger, level zapcore.Level) *log.Logger {
+	return log.New(newStdLogWriter(skipCaller(logger, 1+stdLogDepth), level), "", 0)
+}
+
+// NewWriter returns an io.Writer logging each line written to it at level,
+// for database drivers and libraries writing their diagnostics to an
+// io.Writer. Blank lines are dropped and a write without trailing newline
+// is logged as a line. Panic and Fatal levels are logged at Error.
+//
+// Example:
+//
+//	cmd := exec.Command("pg_dump", dsn)
+//	cmd.Stderr = xlogger.NewWriter(logger.ForInfra("backup"), zapcore.WarnLevel)
+func NewWriter(logger Logger, level zapcore.Level) io.Writer {
+	return newStdLogWriter(skipCaller(logger, 1), level)
+}
+
+// StdLogger returns a *log.Logger writing each line to the logger at level.
+// See NewStdLog.
+//
+// Example:
+//
+//	server := &http.Server{
+//	    Addr:     ":8080",
+//	    ErrorLog: logger.StdLogger(zapcore.ErrorLevel),
+//	}
+func (l *ZapLogger) StdLogger(level zapcore.Level) *log.Logger {
+	return NewStdLog(l, level)
+}
+
+// Writer returns an io.Writer logging each line written to it at level.
+// See NewWriter.
+func (l *ZapLogger) Writer(level zapcore.Level) io.Writer {
+	return NewWriter(l, level)
+}
+
+// CaptureStdLog sends the output of the standard library's global logger
+// (log.Print, log.Printf and log.Println, also used by many libraries) to
+// logger at level, one entry per line with a "source" field of "stdlog".
+// The global flags are cleared since entries carry their own time and
+// caller; the prefix is kept. The returned restore function puts back the
+// previous output and flags.
+//
+// Example:
+//
+//	restore := xlogger.CaptureStdLog(logger, zapcore.InfoLevel)
+//	defer restore()
+//
+//	log.Printf("cache warmed in %s", elapsed) // {"level":"info","message":"cache warmed in 2s","source":"stdlog"}
+func CaptureStdLog(logger Logger, level zapcore.Level) (restore func()) {
+	output, flags := log.Writer(), log.Flags()
+	logger = logger.With(String(sourceFieldKey, "stdlog"))
+	log.SetOutput(newStdLogWriter(skipCaller(logger, 1+stdLogDepth), level))
+	log.SetFlags(0)
+
+	return func() {
+		log.SetOutput(output)
+		log.SetFlags(flags)
+	}
+}
+
+// stdLogWriter forwards standard library log output to a Logger
+type stdLogWriter struct {
+	log func(msg string, fields ...Field)
+}
+
+func newStdLogWriter(logger Logger, level zapcore.Level) *stdLogWriter {
+	switch level {
+	case zapcore.DebugLevel:
+		return &stdLogWriter{log: logger.Debug}
+	case zapcore.InfoLevel:
+		return &stdLogWriter{log: logger.Info}
+	case zapcore.WarnLevel:
+		return &stdLogWriter{log: logger.Warn}
+	default:
+		return &stdLogWriter{log: logger.Error}
+	}
+}
+
+// Write implements io.Writer
+func (w *stdLogWriter) Write(p []byte) (int, error) {
+	for _, line := range strings.Split(string(p), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			w.log(line)
+		}
+	}
+	return len(p), nil
+}
@@ -0,0 +1,93 @@
+package xlogger
+
+import (
+	"bytes"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+// TestNewStdLog tests the standard library log adapter
+func TestNewStdLog(t *testing.T) {
+	t.Run("should write lines at the given level", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+
+		NewStdLog(logger, zapcore.WarnLevel).Printf("http: proxy error: %s", "timeout")
+
+		entries := entriesWithMessage(t, output(), "http: proxy error: timeout")
+		require.Len(t, entries, 1)
+		assert.Equal(t, "warn", entries[0]["level"])
+		assert.Contains(t, entries[0]["caller"], "stdlog_test.go")
+	})
+
+	t.Run("should be available on the logger", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+
+		logger.StdLogger(zapcore.ErrorLevel).Println("http: TLS handshake error")
+
+		entries := entriesWithMessage(t, output(), "http: TLS handshake error")
+		require.Len(t, entries, 1)
+		assert.Equal(t, "error", entries[0]["level"])
+		assert.Contains(t, entries[0]["caller"], "stdlog_test.go")
+	})
+}
+
+// TestNewWriter tests the io.Writer adapter
+func TestNewWriter(t *testing.T) {
+	t.Run("should log each line of a write", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+
+		n, err := logger.Writer(zapcore.InfoLevel).Write([]byte("first line\r\n\nsecond line"))
+		require.NoError(t, err)
+		assert.Equal(t, 24, n)
+
+		for _, msg := range []string{"first line", "second line"} {
+			entries := entriesWithMessage(t, output(), msg)
+			require.Len(t, entries, 1)
+			assert.Equal(t, "info", entries[0]["level"])
+			assert.Contains(t, entries[0]["caller"], "stdlog_test.go")
+		}
+	})
+
+	t.Run("should log panic and fatal levels at error", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+
+		_, err := NewWriter(logger, zapcore.FatalLevel).Write([]byte("driver: connection reset\n"))
+		require.NoError(t, err)
+
+		entries := entriesWithMessage(t, output(), "driver: connection reset")
+		require.Len(t, entries, 1)
+		assert.Equal(t, "error", entries[0]["level"])
+	})
+}
+
+// TestCaptureStdLog tests redirecting the global standard library logger
+func TestCaptureStdLog(t *testing.T) {
+	t.Run("should log global log output until restored", func(t *testing.T) {
+		original := log.Writer()
+		defer log.SetOutput(original)
+
+		var previous bytes.Buffer
+		log.SetOutput(&previous)
+		flags := log.Flags()
+
+		logger, output := newFileLogger(t)
+		restore := CaptureStdLog(logger, zapcore.WarnLevel)
+		log.Printf("legacy warning %d", 1)
+		restore()
+		log.Print("after restore")
+
+		entries := entriesWithMessage(t, output(), "legacy warning 1")
+		require.Len(t, entries, 1)
+		assert.Equal(t, "warn", entries[0]["level"])
+		assert.Equal(t, "stdlog", entries[0]["source"])
+		assert.Contains(t, entries[0]["caller"], "stdlog_test.go")
+
+		assert.NotContains(t, output(), "after restore")
+		assert.Contains(t, previous.String(), "after restore")
+		assert.Equal(t, flags, log.Flags())
+	})
+}
@@ -0,0 +1,101 @@
+package xlogger
+
+import (
+	"fmt"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// SugaredLogger offers printf-style and loosely typed key/value logging on
+// top of a Logger, for code migrating from logrus or the standard library
+// log package. Messages are only formatted when the level is enabled. The
+// typed Field methods of Logger remain faster.
+type SugaredLogger struct {
+	logger Logger
+}
+
+// NewSugaredLogger returns a SugaredLogger writing to logger. Entries report
+// the caller of the SugaredLogger methods.
+//
+// Example:
+//
+//	sugar := xlogger.NewSugaredLogger(logger)
+//	sugar.Infof("Processed %d orders in %s", count, elapsed)
+//	sugar.Infow("Order shipped", "order_id", id, "carrier", "ups")
+func NewSugaredLogger(logger Logger) *SugaredLogger {
+	return &SugaredLogger{logger: skipCaller(logger, 1)}
+}
+
+// Desugar returns the Logger the SugaredLogger writes to.
+func (s *SugaredLogger) Desugar() Logger {
+	return s.logger
+}
+
+// With returns a SugaredLogger adding keysAndValues to every entry. Keys
+// that are not strings are formatted and a trailing value without key is
+// logged as extra_value.
+func (s *SugaredLogger) With(keysAndValues ...interface{}) *SugaredLogger {
+	return &SugaredLogger{logger: s.logger.With(convertKeysAndValues(keysAndValues)...)}
+}
+
+// Debugf formats the message with fmt.Sprintf and logs it at debug level.
+func (s *SugaredLogger) Debugf(template string, args ...interface{}) {
+	if s.enabled(zapcore.DebugLevel) {
+		s.logger.Debug(fmt.Sprintf(template, args...))
+	}
+}
+
+// Infof formats the message with fmt.Sprintf and logs it at info level.
+func (s *SugaredLogger) Infof(template string, args ...interface{}) {
+	if s.enabled(zapcore.InfoLevel) {
+		s.logger.Info(fmt.Sprintf(template, args...))
+	}
+}
+
+// Warnf formats the message with fmt.Sprintf and logs it at warn level.
+func (s *SugaredLogger) Warnf(template string, args ...interface{}) {
+	if s.enabled(zapcore.WarnLevel) {
+		s.logger.Warn(fmt.Sprintf(template, args...))
+	}
+}
+
+// Errorf formats the message with fmt.Sprintf and logs it at error level.
+func (s *SugaredLogger) Errorf(template string, args ...interface{}) {
+	if s.enabled(zapcore.ErrorLevel) {
+		s.logger.Error(fmt.Sprintf(template, args...))
+	}
+}
+
+// Debugw logs msg at debug level with keysAndValues as fields.
+func (s *SugaredLogger) Debugw(msg string, keysAndValues ...interface{}) {
+	if s.enabled(zapcore.DebugLevel) {
+		s.logger.Debug(msg, convertKeysAndValues(keysAndValues)...)
+	}
+}
+
+// Infow logs msg at info level with keysAndValues as fields.
+func (s *SugaredLogger) Infow(msg string, keysAndValues ...interface{}) {
+	if s.enabled(zapcore.InfoLevel) {
+		s.logger.Info(msg, convertKeysAndValues(keysAndValues)...)
+	}
+}
+
+// Warnw logs msg at warn level with keysAndValues as fields.
+func (s *SugaredLogger) Warnw(msg string, keysAndValues ...interface{}) {
+	if s.enabled(zapcore.WarnLevel) {
+		s.logger.Warn(msg, convertKeysAndValues(keysAndValues)...)
+	}
+}
+
+// Errorw logs msg at error level with keysAndValues as fields.
+func (s *SugaredLogger) Errorw(msg string, keysAndValues ...interface{}) {
+	if s.enabled(zapcore.ErrorLevel) {
+		s.logger.Error(msg, convertKeysAndValues(keysAndValues)...)
+	}
+}
+
+// enabled reports whether entries of level may be written, so disabled
+// calls skip formatting
+func (s *SugaredLogger) enabled(level zapcore.Level) bool {
+	return s.logger.Enabled(level)
+}
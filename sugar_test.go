@@ -0,0 +1,66 @@
+package xlogger
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+// TestSugaredLogger tests printf-style and key/value logging
+func TestSugaredLogger(t *testing.T) {
+	t.Run("should format messages", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+		sugar := NewSugaredLogger(logger)
+
+		sugar.Infof("processed %d orders", 3)
+		sugar.Warnf("queue at %d%%", 90)
+		sugar.Errorf("failed: %v", errors.New("timeout"))
+		sugar.Debugf("hidden %s", "debug")
+
+		assert.Len(t, entriesWithMessage(t, output(), "processed 3 orders"), 1)
+		assert.Len(t, entriesWithMessage(t, output(), "queue at 90%"), 1)
+		assert.Len(t, entriesWithMessage(t, output(), "failed: timeout"), 1)
+		assert.NotContains(t, output(), "hidden")
+	})
+
+	t.Run("should convert keys and values to fields", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+		logger.SetLevel(zapcore.DebugLevel)
+		sugar := NewSugaredLogger(logger).With("worker", "w1")
+
+		sugar.Infow("order shipped", "order_id", 42, "carrier", "ups")
+		sugar.Errorw("order failed", "error", errors.New("declined"), "dangling")
+		sugar.Debugw("debug entry", "step", 1)
+		sugar.Warnw("warn entry")
+
+		entries := entriesWithMessage(t, output(), "order shipped")
+		require.Len(t, entries, 1)
+		assert.Equal(t, "w1", entries[0]["worker"])
+		assert.Equal(t, float64(42), entries[0]["order_id"])
+		assert.Equal(t, "ups", entries[0]["carrier"])
+
+		entries = entriesWithMessage(t, output(), "order failed")
+		require.Len(t, entries, 1)
+		assert.Equal(t, "declined", entries[0]["error"])
+		assert.Equal(t, "dangling", entries[0]["extra_value"])
+		assert.Len(t, entriesWithMessage(t, output(), "debug entry"), 1)
+		assert.Len(t, entriesWithMessage(t, output(), "warn entry"), 1)
+	})
+
+	t.Run("should report the caller of the sugared method", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+		NewSugaredLogger(logger).Infof("from test")
+
+		entries := entriesWithMessage(t, output(), "from test")
+		require.Len(t, entries, 1)
+		assert.Contains(t, entries[0]["caller"], "sugar_test.go")
+	})
+
+	t.Run("should desugar to the logger", func(t *testing.T) {
+		logger := NewNop()
+		assert.NotNil(t, NewSugaredLogger(logger).Desugar())
+	})
+}
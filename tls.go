@@ -0,0 +1,157 @@
+package xlogger
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"sync"
+	"time"
+)
+
+// tlsComponent is the infrastructure component used by TLS logging
+const tlsComponent = "tls"
+
+// DefaultCertExpiryThreshold is how long before expiry certificates are reported
+const DefaultCertExpiryThreshold = 30 * 24 * time.Hour
+
+// certExpiryRewarn is how often the same certificate is reported again
+const certExpiryRewarn = 24 * time.Hour
+
+// tlsLogger logs handshakes and certificate expiry for an instrumented tls.Config
+type tlsLogger struct {
+	logger    Logger
+	threshold time.Duration
+
+	mu     sync.Mutex
+	warned map[[sha256.Size]byte]time.Time
+}
+
+// InstrumentTLSConfig returns a clone of cfg that logs under the "tls"
+// component: the negotiated version, cipher suite and ALPN protocol of every
+// handshake at Debug, and a Warn entry when the peer certificate chain or the
+// local certificate expires within threshold. Each certificate is reported at
+// most once a day. A non-positive threshold uses DefaultCertExpiryThreshold.
+//
+// Existing VerifyConnection, GetCertificate and GetClientCertificate
+// callbacks keep running; a VerifyConnection error still fails the handshake.
+//
+// Example:
+//
+//	server := &http.Server{
+//	    TLSConfig: xlogger.InstrumentTLSConfig(logger, tlsConfig, 14*24*time.Hour),
+//	}
+func InstrumentTLSConfig(logger Logger, cfg *tls.Config, threshold time.Duration) *tls.Config {
+	if cfg == nil {
+		cfg = &tls.Config{}
+	}
+	if threshold <= 0 {
+		threshold = DefaultCertExpiryThreshold
+	}
+
+	t := &tlsLogger{
+		logger:    logger.ForInfra(tlsComponent),
+		threshold: threshold,
+		warned:    make(map[[sha256.Size]byte]time.Time),
+	}
+
+	instrumented := cfg.Clone()
+
+	verifyConnection := cfg.VerifyConnection
+	instrumented.VerifyConnection = func(cs tls.ConnectionState) error {
+		t.logHandshake(cs)
+		for _, cert := range cs.PeerCertificates {
+			t.checkExpiry(cert, "peer", cs.ServerName)
+		}
+		if verifyConnection != nil {
+			return verifyConnection(cs)
+		}
+		return nil
+	}
+
+	if getCertificate := cfg.GetCertificate; getCertificate != nil {
+		instrumented.GetCertificate = func(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+			cert, err := getCertificate(hello)
+			if err == nil {
+				t.checkLocal(cert, hello.ServerName)
+			}
+			return cert, err
+		}
+	}
+	if getClientCertificate := cfg.GetClientCertificate; getClientCertificate != nil {
+		instrumented.GetClientCertificate = func(req *tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			cert, err := getClientCertificate(req)
+			if err == nil {
+				t.checkLocal(cert, "")
+			}
+			return cert, err
+		}
+	}
+
+	// Static certificates are checked once up front; callbacks cover the rest
+	for i := range cfg.Certificates {
+		t.checkLocal(&cfg.Certificates[i], cfg.ServerName)
+	}
+
+	return instrumented
+}
+
+func (t *tlsLogger) logHandshake(cs tls.ConnectionState) {
+	t.logger.Debug("tls handshake completed",
+		String("tls_version", tls.VersionName(cs.Version)),
+		String("cipher_suite", tls.CipherSuiteName(cs.CipherSuite)),
+		String("server_name", cs.ServerName),
+		String("negotiated_protocol", cs.NegotiatedProtocol),
+		Bool("resumed", cs.DidResume),
+	)
+}
+
+// checkLocal checks the leaf of a local certificate, parsing it if needed
+func (t *tlsLogger) checkLocal(cert *tls.Certificate, serverName string) {
+	if cert == nil {
+		return
+	}
+	leaf := cert.Leaf
+	if leaf == nil {
+		if len(cert.Certificate) == 0 {
+			return
+		}
+		parsed, err := x509.ParseCertificate(cert.Certificate[0])
+		if err != nil {
+			return
+		}
+		leaf = parsed
+	}
+	t.checkExpiry(leaf, "local", serverName)
+}
+
+// checkExpiry warns when cert expires within the threshold
+func (t *tlsLogger) checkExpiry(cert *x509.Certificate, role, serverName string) {
+	now := time.Now()
+	remaining := cert.NotAfter.Sub(now)
+	if remaining > t.threshold {
+		return
+	}
+
+	fingerprint := sha256.Sum256(cert.Raw)
+	t.mu.Lock()
+	if last, ok := t.warned[fingerprint]; ok && now.Sub(last) < certExpiryRewarn {
+		t.mu.Unlock()
+		return
+	}
+	t.warned[fingerprint] = now
+	t.mu.Unlock()
+
+	msg := "certificate expires soon"
+	if remaining <= 0 {
+		msg = "certificate expired"
+	}
+	t.logger.Warn(msg,
+		String("certificate", role),
+		String("subject", cert.Subject.String()),
+		String("issuer", cert.Issuer.String()),
+		String("serial", cert.SerialNumber.String()),
+		String("server_name", serverName),
+		Time("not_after", cert.NotAfter),
+		Duration("expires_in", remaining),
+	)
+}
@@ -0,0 +1,144 @@
+package xlogger
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+// newTestCertificate creates a self-signed certificate valid for validFor
+func newTestCertificate(t *testing.T, validFor time.Duration) tls.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(42),
+		Subject:      pkix.Name{CommonName: "service.test"},
+		DNSNames:     []string{"service.test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(validFor),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	leaf, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+}
+
+// handshake runs a TLS handshake between client and server configs
+func handshake(client, server *tls.Config) (clientErr, serverErr error) {
+	clientConn, serverConn := net.Pipe()
+	defer clientConn.Close()
+	defer serverConn.Close()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- tls.Server(serverConn, server).Handshake()
+	}()
+	clientErr = tls.Client(clientConn, client).Handshake()
+	if clientErr != nil {
+		clientConn.Close()
+	}
+	return clientErr, <-done
+}
+
+// TestInstrumentTLSConfig tests TLS handshake and certificate expiry logging
+func TestInstrumentTLSConfig(t *testing.T) {
+	t.Run("should log negotiated parameters at debug", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+		logger.SetLevel(zapcore.DebugLevel)
+		cert := newTestCertificate(t, 365*24*time.Hour)
+
+		server := InstrumentTLSConfig(logger, &tls.Config{Certificates: []tls.Certificate{cert}}, 0)
+		client := &tls.Config{InsecureSkipVerify: true, ServerName: "service.test", MaxVersion: tls.VersionTLS12}
+
+		clientErr, serverErr := handshake(client, server)
+		require.NoError(t, clientErr)
+		require.NoError(t, serverErr)
+
+		entries := entriesWithMessage(t, output(), "tls handshake completed")
+		require.Len(t, entries, 1)
+		assert.Equal(t, "tls", entries[0]["component"])
+		assert.Equal(t, "TLS 1.2", entries[0]["tls_version"])
+		assert.NotEmpty(t, entries[0]["cipher_suite"])
+		assert.Equal(t, "service.test", entries[0]["server_name"])
+		assert.NotContains(t, output(), "certificate expires soon")
+	})
+
+	t.Run("should warn about expiring local and peer certificates", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+		cert := newTestCertificate(t, 48*time.Hour)
+
+		server := InstrumentTLSConfig(logger, &tls.Config{Certificates: []tls.Certificate{cert}}, 7*24*time.Hour)
+		client := InstrumentTLSConfig(logger, &tls.Config{InsecureSkipVerify: true}, 7*24*time.Hour)
+
+		for i := 0; i < 3; i++ {
+			clientErr, serverErr := handshake(client, server)
+			require.NoError(t, clientErr)
+			require.NoError(t, serverErr)
+		}
+
+		entries := entriesWithMessage(t, output(), "certificate expires soon")
+		require.Len(t, entries, 2)
+		assert.Equal(t, "local", entries[0]["certificate"])
+		assert.Equal(t, "peer", entries[1]["certificate"])
+		for _, entry := range entries {
+			assert.Equal(t, "warn", entry["level"])
+			assert.Equal(t, "CN=service.test", entry["subject"])
+			assert.Equal(t, "42", entry["serial"])
+			assert.NotEmpty(t, entry["not_after"])
+		}
+	})
+
+	t.Run("should check certificates from callbacks", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+		cert := newTestCertificate(t, -time.Hour)
+		cert.Leaf = nil
+
+		server := InstrumentTLSConfig(logger, &tls.Config{
+			GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+				return &cert, nil
+			},
+		}, 0)
+
+		clientErr, serverErr := handshake(&tls.Config{InsecureSkipVerify: true, ServerName: "service.test"}, server)
+		require.NoError(t, clientErr)
+		require.NoError(t, serverErr)
+
+		entries := entriesWithMessage(t, output(), "certificate expired")
+		require.Len(t, entries, 1)
+		assert.Equal(t, "local", entries[0]["certificate"])
+		assert.Equal(t, "service.test", entries[0]["server_name"])
+	})
+
+	t.Run("should keep existing verification", func(t *testing.T) {
+		logger, _ := newFileLogger(t)
+		cert := newTestCertificate(t, 365*24*time.Hour)
+
+		client := InstrumentTLSConfig(logger, &tls.Config{
+			InsecureSkipVerify: true,
+			VerifyConnection: func(tls.ConnectionState) error {
+				return errors.New("pinned certificate mismatch")
+			},
+		}, 0)
+
+		clientErr, _ := handshake(client, &tls.Config{Certificates: []tls.Certificate{cert}})
+		require.Error(t, clientErr)
+		assert.Contains(t, clientErr.Error(), "pinned certificate mismatch")
+	})
+}
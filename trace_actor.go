@@ -0,0 +1,126 @@
+package xlogger
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// traceActorKey holds the actor set by SetTraceActor within a trace run
+const traceActorKey = "logger-trace-actor"
+
+const (
+	tenantIDFieldKey = "tenant_id"
+	userIDFieldKey   = "user_id"
+)
+
+// traceActor is the tenant and user of a trace run, set once the request is
+// authenticated rather than when the run starts
+type traceActor struct {
+	ids atomic.Pointer[[2]string] // tenant and user
+}
+
+// get returns the tenant and user, empty before SetTraceActor
+func (a *traceActor) get() (tenantID, userID string) {
+	if a == nil {
+		return "", ""
+	}
+	if ids := a.ids.Load(); ids != nil {
+		return ids[0], ids[1]
+	}
+	return "", ""
+}
+
+// SetTraceActor records the tenant and user of the current trace run, so
+// every later entry of the run carries tenant_id and user_id. Call it once
+// the request is authenticated. Nested runs start with the actor of the
+// enclosing run; setting it in a nested run does not change the enclosing
+// one. It reports false outside RunWithTrace and the other trace runs.
+//
+// Example:
+//
+//	xlogger.RunWithTraceVoid(requestID, "", func() {
+//	    claims := authenticate(r)
+//	    xlogger.SetTraceActor(claims.TenantID, claims.Subject)
+//	    logger.Info("Order placed") // includes tenant_id and user_id
+//	})
+func SetTraceActor(tenantID, userID string) bool {
+	return defaultTraceScope.SetTraceActor(tenantID, userID)
+}
+
+// TraceTenantID returns the tenant set by SetTraceActor in the current run.
+func TraceTenantID() string {
+	return defaultTraceScope.TraceTenantID()
+}
+
+// TraceUserID returns the user set by SetTraceActor in the current run.
+func TraceUserID() string {
+	return defaultTraceScope.TraceUserID()
+}
+
+// TraceTenantIDFromContext returns the tenant stored in ctx, the
+// context.Context counterpart of TraceTenantID.
+func TraceTenantIDFromContext(ctx context.Context) string {
+	return TraceContextFromContext(ctx).TenantID
+}
+
+// TraceUserIDFromContext returns the user stored in ctx, the context.Context
+// counterpart of TraceUserID.
+func TraceUserIDFromContext(ctx context.Context) string {
+	return TraceContextFromContext(ctx).UserID
+}
+
+// SetTraceActor is the scoped form of the package-level SetTraceActor.
+func (s *TraceScope) SetTraceActor(tenantID, userID string) bool {
+	actor := s.actor()
+	if actor == nil {
+		return false
+	}
+	actor.ids.Store(&[2]string{tenantID, userID})
+	return true
+}
+
+// TraceTenantID returns the tenant stored in this scope.
+func (s *TraceScope) TraceTenantID() string {
+	tenantID, _ := s.actor().get()
+	return tenantID
+}
+
+// TraceUserID returns the user stored in this scope.
+func (s *TraceScope) TraceUserID() string {
+	_, userID := s.actor().get()
+	return userID
+}
+
+// actor returns the actor of the current run, nil outside a run
+func (s *TraceScope) actor() *traceActor {
+	value, ok := s.store.GetValue(traceActorKey)
+	if !ok {
+		return nil
+	}
+	actor, _ := value.(*traceActor)
+	return actor
+}
+
+// runValues adds the actor and scoped fields of a new run to values,
+// starting with those of the enclosing run
+func (s *TraceScope) runValues(values traceValues) traceValues {
+	return inheritRunState(values, s.actor(), s.scopedFields())
+}
+
+// inheritRunState adds to values the actor and scoped fields of a new run
+// starting with actor and fields, which may be nil
+func inheritRunState(values traceValues, actor *traceActor, fields *scopedFields) traceValues {
+	values[traceActorKey] = inheritActor(actor)
+	values[traceFieldsKey] = inheritFields(fields)
+	return values
+}
+
+// inheritActor returns the actor of a new run starting with the tenant and
+// user of parent, which may be nil
+func inheritActor(parent *traceActor) *traceActor {
+	actor := &traceActor{}
+	if parent != nil {
+		actor.ids.Store(parent.ids.Load())
+	}
+	return actor
+}
@@ -0,0 +1,71 @@
+package xlogger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSetTraceActor tests tenant and user fields of a trace run
+func TestSetTraceActor(t *testing.T) {
+	t.Run("should add tenant and user after SetTraceActor", func(t *testing.T) {
+		skipWithoutGLS(t)
+		logger, output := newFileLogger(t)
+
+		RunWithTraceVoid("req-1", "", func() {
+			logger.Info("authenticating")
+			assert.True(t, SetTraceActor("acme", "u-42"))
+			assert.Equal(t, "acme", TraceTenantID())
+			assert.Equal(t, "u-42", TraceUserID())
+			logger.Info("order placed")
+		})
+		require.NoError(t, logger.Sync())
+
+		entries := decodeJSONLines(t, output())
+		require.Len(t, entries, 2)
+		assert.NotContains(t, entries[0], "tenant_id")
+		assert.Equal(t, "req-1", entries[1]["request_id"])
+		assert.Equal(t, "acme", entries[1]["tenant_id"])
+		assert.Equal(t, "u-42", entries[1]["user_id"])
+	})
+
+	t.Run("should scope the actor to the run", func(t *testing.T) {
+		skipWithoutGLS(t)
+		RunWithTraceVoid("req-1", "", func() {
+			SetTraceActor("acme", "u-42")
+
+			RunWithTraceVoid("req-2", "", func() {
+				assert.Equal(t, "acme", TraceTenantID())
+				SetTraceActor("globex", "u-7")
+				assert.Equal(t, "globex", TraceTenantID())
+			})
+
+			assert.Equal(t, "acme", TraceTenantID())
+			assert.Equal(t, "u-42", TraceUserID())
+		})
+
+		RunWithTraceVoid("req-3", "", func() {
+			assert.Empty(t, TraceTenantID())
+		})
+	})
+
+	t.Run("should be ignored outside a trace run", func(t *testing.T) {
+		assert.False(t, SetTraceActor("acme", "u-42"))
+		assert.Empty(t, TraceTenantID())
+		assert.Empty(t, TraceUserID())
+	})
+
+	t.Run("should apply to traceparent and baggage runs", func(t *testing.T) {
+		skipWithoutGLS(t)
+		_ = RunWithTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", func() error {
+			assert.True(t, SetTraceActor("acme", ""))
+			return nil
+		})
+		_ = RunWithTraceContext(TraceContext{RequestID: "req-1"}, func() error {
+			assert.True(t, SetTraceActor("", "u-42"))
+			assert.Empty(t, TraceTenantID())
+			return nil
+		})
+	})
+}
@@ -0,0 +1,103 @@
+package xlogger
+
+import (
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// TraceConflict decides what is logged when an entry carries a trace field
+// (request_id, correlation_id, trace_id or span_id) whose value differs from
+// the one in the trace scope. Such mismatches usually come from IDs copied
+// into fields and not updated when the request changed.
+type TraceConflict string
+
+const (
+	// TraceConflictPreferCaller keeps the value passed by the caller
+	TraceConflictPreferCaller TraceConflict = "prefer_caller"
+	// TraceConflictPreferContext replaces the caller's value with the value
+	// of the trace scope
+	TraceConflictPreferContext TraceConflict = "prefer_context"
+	// TraceConflictEmitBoth keeps the caller's value and adds the value of
+	// the trace scope under the key with the "_context" suffix
+	TraceConflictEmitBoth TraceConflict = "emit_both"
+	// TraceConflictWarn keeps the caller's value and logs a Warn naming the
+	// field and both values
+	TraceConflictWarn TraceConflict = "warn_on_mismatch"
+)
+
+// traceContextSuffix is appended to the keys of trace scope values emitted
+// next to the caller's with TraceConflictEmitBoth
+const traceContextSuffix = "_context"
+
+// Normalize returns the normalized lowercase strategy.
+func (c TraceConflict) Normalize() TraceConflict {
+	return TraceConflict(strings.ToLower(string(c)))
+}
+
+// isValid reports whether c is a known strategy
+func (c TraceConflict) isValid() bool {
+	switch c.Normalize() {
+	case TraceConflictPreferCaller, TraceConflictPreferContext, TraceConflictEmitBoth, TraceConflictWarn:
+		return true
+	}
+	return false
+}
+
+// traceMismatch is a trace field passed with a value differing from the
+// trace scope's
+type traceMismatch struct {
+	key, caller, context string
+}
+
+// resolveTraceField applies conflict to the trace field key whose value in
+// the trace scope is value, returning the fields and the mismatch found
+func resolveTraceField(conflict TraceConflict, fields []Field, key, value string) ([]Field, *traceMismatch) {
+	i := fieldIndex(fields[:topLevel(fields)], key)
+	if i < 0 {
+		return appendTopLevel(fields, String(key, value)), nil
+	}
+	caller := fieldText(fields[i])
+	if caller == value {
+		return fields, nil
+	}
+
+	switch conflict {
+	case TraceConflictPreferContext:
+		// Copy so the caller's slice is left untouched
+		fields = append([]Field(nil), fields...)
+		fields[i] = String(key, value)
+	case TraceConflictEmitBoth:
+		fields = appendTopLevel(fields, String(key+traceContextSuffix, value))
+	}
+	return fields, &traceMismatch{key: key, caller: caller, context: value}
+}
+
+// fieldText returns the value of field as text, to compare it with a trace ID
+func fieldText(field Field) string {
+	switch v := field.Value().(type) {
+	case string:
+		return v
+	case zapcore.Field:
+		if v.Type == zapcore.StringType {
+			return v.String
+		}
+		return fmt.Sprint(v.Interface)
+	}
+	return fmt.Sprint(field.Value())
+}
+
+// warnTraceMismatches logs a Warn for each mismatch. skip is the number of
+// frames between the caller of the logger method and this function.
+func (l *ZapLogger) warnTraceMismatches(mismatches []traceMismatch, skip int) {
+	logger := l.logger.WithOptions(zap.AddCallerSkip(skip))
+	for _, m := range mismatches {
+		logger.Warn("trace field mismatch",
+			zap.String("field", m.key),
+			zap.String("caller_value", m.caller),
+			zap.String("context_value", m.context),
+		)
+	}
+}
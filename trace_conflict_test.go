@@ -0,0 +1,108 @@
+package xlogger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// TestTraceConflict tests the strategies for trace fields passed with another value
+func TestTraceConflict(t *testing.T) {
+	t.Run("should keep the caller's value by default", func(t *testing.T) {
+		skipWithoutGLS(t)
+		logger, output := newFileLogger(t)
+
+		RunWithTraceVoid("req-context", "corr-context", func() {
+			logger.Info("order created", String(requestIDFieldKey, "req-caller"))
+		})
+
+		entries := entriesWithMessage(t, output(), "order created")
+		require.Len(t, entries, 1)
+		assert.Equal(t, "req-caller", entries[0][requestIDFieldKey])
+		assert.Equal(t, "corr-context", entries[0][correlationIDFieldKey])
+		assert.NotContains(t, entries[0], "request_id_context")
+		assert.Empty(t, entriesWithMessage(t, output(), "trace field mismatch"))
+	})
+
+	t.Run("should replace the caller's value with the context's", func(t *testing.T) {
+		skipWithoutGLS(t)
+		logger, output := newFileLogger(t, WithTraceConflict(TraceConflictPreferContext))
+		fields := []Field{String(requestIDFieldKey, "req-caller"), String("user", "alice")}
+
+		RunWithTraceVoid("req-context", "", func() {
+			logger.Info("order created", fields...)
+		})
+
+		entries := entriesWithMessage(t, output(), "order created")
+		require.Len(t, entries, 1)
+		assert.Equal(t, "req-context", entries[0][requestIDFieldKey])
+		assert.Equal(t, "alice", entries[0]["user"])
+		assert.Equal(t, "req-caller", fields[0].Value(), "caller's fields should be left untouched")
+	})
+
+	t.Run("should emit both values", func(t *testing.T) {
+		skipWithoutGLS(t)
+		logger, output := newFileLogger(t, WithTraceConflict(TraceConflictEmitBoth))
+
+		RunWithTraceVoid("req-context", "corr-context", func() {
+			logger.Info("order created",
+				String(requestIDFieldKey, "req-caller"),
+				String(correlationIDFieldKey, "corr-context"))
+		})
+
+		entries := entriesWithMessage(t, output(), "order created")
+		require.Len(t, entries, 1)
+		assert.Equal(t, "req-caller", entries[0][requestIDFieldKey])
+		assert.Equal(t, "req-context", entries[0]["request_id_context"])
+		assert.NotContains(t, entries[0], "correlation_id_context", "equal values are not duplicated")
+	})
+
+	t.Run("should warn about mismatches at the call site", func(t *testing.T) {
+		skipWithoutGLS(t)
+		logger, output := newFileLogger(t, WithTraceConflict(TraceConflictWarn))
+
+		RunWithTraceVoid("req-context", "corr-context", func() {
+			logger.Info("order created", ZapField(zap.String(requestIDFieldKey, "req-caller")))
+			logger.With(String(correlationIDFieldKey, "corr-caller")).Info("child")
+			logger.Info("matching", String(requestIDFieldKey, "req-context"))
+		})
+
+		entries := entriesWithMessage(t, output(), "order created")
+		require.Len(t, entries, 1)
+		assert.Equal(t, "req-caller", entries[0][requestIDFieldKey])
+
+		warnings := entriesWithMessage(t, output(), "trace field mismatch")
+		require.Len(t, warnings, 2)
+		assert.Equal(t, "warn", warnings[0]["level"])
+		assert.Equal(t, requestIDFieldKey, warnings[0]["field"])
+		assert.Equal(t, "req-caller", warnings[0]["caller_value"])
+		assert.Equal(t, "req-context", warnings[0]["context_value"])
+		assert.Equal(t, correlationIDFieldKey, warnings[1]["field"])
+		for _, warning := range warnings {
+			assert.Contains(t, warning["caller"], "trace_conflict_test.go")
+		}
+	})
+
+	t.Run("should compare non-string values as text", func(t *testing.T) {
+		logger, output := newFileLogger(t, WithTraceConflict(TraceConflictWarn))
+
+		RunWithTraceVoid("42", "", func() {
+			logger.Info("numeric", Int(requestIDFieldKey, 42))
+		})
+
+		assert.Empty(t, entriesWithMessage(t, output(), "trace field mismatch"))
+	})
+
+	t.Run("should ignore unknown strategies", func(t *testing.T) {
+		config := NewLoggerConfig(
+			WithTraceConflict(TraceConflictEmitBoth),
+			WithTraceConflict("newest"),
+		)
+		assert.Equal(t, TraceConflictEmitBoth, config.TraceConflict)
+
+		config = NewLoggerConfig(WithTraceConflict("PREFER_CONTEXT"))
+		assert.Equal(t, TraceConflictPreferContext, config.TraceConflict)
+	})
+}
@@ -0,0 +1,75 @@
+package xlogger
+
+// traceKeys are the goroutine-local values WrapFunc carries over, besides
+// the trace actor and scoped fields
+var traceKeys = [...]string{
+	traceRequestIDKey,
+	traceCorrelationIDKey,
+	traceParentKey,
+	traceStateKey,
+	traceBaggageKey,
+}
+
+// Go runs fn in a new goroutine with the trace state of the caller: the
+// request and correlation IDs, traceparent, baggage, trace actor and scoped
+// fields. Plain go statements start without any, so their entries lose the
+// trace fields.
+//
+// Example:
+//
+//	xlogger.RunWithTraceVoid(requestID, "", func() {
+//	    xlogger.Go(func() {
+//	        logger.Info("Sending receipt") // includes request_id
+//	    })
+//	})
+func Go(fn func()) {
+	defaultTraceScope.Go(fn)
+}
+
+// WrapFunc returns fn wrapped to run with the trace state of the caller of
+// WrapFunc, for functions handed to worker pools, timers or other APIs that
+// run them on their own goroutines. fn is returned as is outside a trace
+// run.
+//
+// Example:
+//
+//	pool.Submit(xlogger.WrapFunc(func() {
+//	    logger.Info("Resizing image") // includes request_id
+//	}))
+func WrapFunc(fn func()) func() {
+	return defaultTraceScope.WrapFunc(fn)
+}
+
+// Go is the scoped form of the package-level Go.
+func (s *TraceScope) Go(fn func()) {
+	if fn == nil {
+		return
+	}
+	go s.WrapFunc(fn)()
+}
+
+// WrapFunc is the scoped form of the package-level WrapFunc.
+func (s *TraceScope) WrapFunc(fn func()) func() {
+	if fn == nil {
+		return nil
+	}
+
+	captured := make(traceValues, len(traceKeys))
+	for _, key := range traceKeys {
+		if value, ok := s.store.GetValue(key); ok {
+			captured[key] = value
+		}
+	}
+	actor, fields := s.actor(), s.scopedFields()
+	if len(captured) == 0 && actor == nil && fields == nil {
+		return fn
+	}
+
+	return func() {
+		values := make(traceValues, len(captured)+2)
+		for key, value := range captured {
+			values[key] = value
+		}
+		s.store.SetValues(inheritRunState(values, actor, fields), fn)
+	}
+}
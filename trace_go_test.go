@@ -0,0 +1,86 @@
+package xlogger
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGo tests trace state carried over to new goroutines
+func TestGo(t *testing.T) {
+	t.Run("should carry trace state to the goroutine", func(t *testing.T) {
+		skipWithoutGLS(t)
+		path := filepath.Join(t.TempDir(), "app.log")
+		logger, err := NewZapLogger(NewLoggerConfig(WithOutputPaths(path)))
+		require.NoError(t, err)
+		defer func() { _ = logger.Close(t.Context()) }()
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+		_ = RunWithTraceContext(TraceContext{
+			RequestID:     "req-1",
+			CorrelationID: "corr-1",
+			Baggage:       map[string]string{"tenant": "acme"},
+		}, func() error {
+			SetTraceActor("acme", "u-42")
+			Go(func() {
+				defer wg.Done()
+				logger.Info("sending receipt")
+			})
+			return nil
+		})
+		wg.Wait()
+		require.NoError(t, logger.Sync())
+
+		entries := decodeJSONLines(t, readFile(t, path))
+		require.Len(t, entries, 1)
+		assert.Equal(t, "req-1", entries[0]["request_id"])
+		assert.Equal(t, "corr-1", entries[0]["correlation_id"])
+		assert.Equal(t, "acme", entries[0]["baggage.tenant"])
+		assert.Equal(t, "u-42", entries[0]["user_id"])
+	})
+
+	t.Run("should capture trace state when wrapping", func(t *testing.T) {
+		skipWithoutGLS(t)
+		var wrapped func()
+		RunWithTraceVoid("req-1", "corr-1", func() {
+			wrapped = WrapFunc(func() {
+				assert.Equal(t, "req-1", TraceRequestID())
+				assert.Equal(t, "corr-1", TraceCorrelationID())
+			})
+		})
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			assert.Empty(t, TraceRequestID())
+			wrapped()
+		}()
+		<-done
+	})
+
+	t.Run("should not share actor changes with the caller", func(t *testing.T) {
+		skipWithoutGLS(t)
+		RunWithTraceVoid("req-1", "", func() {
+			SetTraceActor("acme", "u-42")
+			WrapFunc(func() {
+				SetTraceActor("globex", "u-7")
+			})()
+			assert.Equal(t, "acme", TraceTenantID())
+		})
+	})
+
+	t.Run("should return fn outside a trace run", func(t *testing.T) {
+		calls := 0
+		fn := func() { calls++ }
+
+		WrapFunc(fn)()
+
+		assert.Equal(t, 1, calls)
+		assert.Nil(t, WrapFunc(nil))
+		Go(nil)
+	})
+}
@@ -0,0 +1,116 @@
+package xlogger
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// TraceGroup runs goroutines that keep the trace state of the goroutine
+// calling Go, for fan-out work whose entries must keep their request IDs.
+// It has the methods of golang.org/x/sync/errgroup.Group and the zero value
+// is ready to use.
+//
+// Example:
+//
+//	g, ctx := xlogger.TraceGroupWithContext(ctx)
+//	for _, id := range orderIDs {
+//	    g.Go(func() error {
+//	        logger.Info("Refunding order", xlogger.String("order_id", id)) // includes request_id
+//	        return refund(ctx, id)
+//	    })
+//	}
+//	if err := g.Wait(); err != nil {
+//	    return err
+//	}
+type TraceGroup struct {
+	cancel func(error)
+
+	wg  sync.WaitGroup
+	sem chan struct{}
+
+	errOnce sync.Once
+	err     error
+}
+
+// TraceGroupWithContext returns a TraceGroup and a context derived from ctx,
+// canceled when a function passed to Go first returns an error or when Wait
+// returns.
+func TraceGroupWithContext(ctx context.Context) (*TraceGroup, context.Context) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	return &TraceGroup{cancel: cancel}, ctx
+}
+
+// Go runs fn in a new goroutine with the trace state of the caller. It
+// blocks while the group runs its limit of goroutines. The first error
+// returned cancels the context of the group and is returned by Wait.
+func (g *TraceGroup) Go(fn func() error) {
+	if g.sem != nil {
+		g.sem <- struct{}{}
+	}
+	g.start(fn)
+}
+
+// TryGo runs fn like Go when the group runs fewer goroutines than its
+// limit and reports whether it did.
+func (g *TraceGroup) TryGo(fn func() error) bool {
+	if g.sem != nil {
+		select {
+		case g.sem <- struct{}{}:
+		default:
+			return false
+		}
+	}
+	g.start(fn)
+	return true
+}
+
+// SetLimit limits the number of goroutines running at once to n, negative
+// for no limit. It panics while goroutines are running.
+func (g *TraceGroup) SetLimit(n int) {
+	if n < 0 {
+		g.sem = nil
+		return
+	}
+	if len(g.sem) != 0 {
+		panic(fmt.Errorf("xlogger: modify limit while %v goroutines in the group are still active", len(g.sem)))
+	}
+	g.sem = make(chan struct{}, n)
+}
+
+// Wait blocks until every goroutine of the group returned and returns the
+// first error.
+func (g *TraceGroup) Wait() error {
+	g.wg.Wait()
+	if g.cancel != nil {
+		g.cancel(g.err)
+	}
+	return g.err
+}
+
+// start runs fn with the trace state of the caller
+func (g *TraceGroup) start(fn func() error) {
+	g.wg.Add(1)
+	run := WrapFunc(func() {
+		if err := fn(); err != nil {
+			g.errOnce.Do(func() {
+				g.err = err
+				if g.cancel != nil {
+					g.cancel(err)
+				}
+			})
+		}
+	})
+	go func() {
+		defer g.done()
+		run()
+	}()
+}
+
+// done releases the slot of a finished goroutine
+func (g *TraceGroup) done() {
+	if g.sem != nil {
+		<-g.sem
+	}
+	g.wg.Done()
+}
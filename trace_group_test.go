@@ -0,0 +1,85 @@
+package xlogger
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTraceGroup tests goroutines keeping the trace state of the caller
+func TestTraceGroup(t *testing.T) {
+	t.Run("should keep trace fields in every goroutine", func(t *testing.T) {
+		skipWithoutGLS(t)
+		path := filepath.Join(t.TempDir(), "app.log")
+		logger, err := NewZapLogger(NewLoggerConfig(WithOutputPaths(path)))
+		require.NoError(t, err)
+		defer func() { _ = logger.Close(t.Context()) }()
+
+		err = RunWithTrace("req-1", "corr-1", func() error {
+			var g TraceGroup
+			for range 3 {
+				g.Go(func() error {
+					logger.Info("worker")
+					return nil
+				})
+			}
+			return g.Wait()
+		})
+		require.NoError(t, err)
+		require.NoError(t, logger.Sync())
+
+		entries := decodeJSONLines(t, readFile(t, path))
+		require.Len(t, entries, 3)
+		for _, entry := range entries {
+			assert.Equal(t, "req-1", entry["request_id"])
+			assert.Equal(t, "corr-1", entry["correlation_id"])
+		}
+	})
+
+	t.Run("should return the first error and cancel the context", func(t *testing.T) {
+		errFailed := errors.New("failed")
+		g, ctx := TraceGroupWithContext(context.Background())
+
+		g.Go(func() error { return errFailed })
+		g.Go(func() error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+
+		assert.ErrorIs(t, g.Wait(), errFailed)
+		assert.ErrorIs(t, context.Cause(ctx), errFailed)
+	})
+
+	t.Run("should cancel the context when Wait returns", func(t *testing.T) {
+		g, ctx := TraceGroupWithContext(context.Background())
+		g.Go(func() error { return nil })
+
+		require.NoError(t, g.Wait())
+		assert.ErrorIs(t, ctx.Err(), context.Canceled)
+	})
+
+	t.Run("should limit running goroutines", func(t *testing.T) {
+		var g TraceGroup
+		g.SetLimit(1)
+		release := make(chan struct{})
+		var running atomic.Int32
+
+		g.Go(func() error {
+			running.Add(1)
+			<-release
+			return nil
+		})
+		assert.False(t, g.TryGo(func() error { return nil }))
+
+		close(release)
+		require.NoError(t, g.Wait())
+		assert.True(t, g.TryGo(func() error { return nil }))
+		require.NoError(t, g.Wait())
+		assert.Equal(t, int32(1), running.Load())
+	})
+}
@@ -0,0 +1,108 @@
+package xlogger
+
+import (
+	"cmp"
+	"context"
+	"net/http"
+)
+
+// TraceHeaderNames names the headers carrying trace state between services.
+// Empty names keep the defaults.
+type TraceHeaderNames struct {
+	RequestID     string // Request ID header (empty for "X-Request-ID")
+	CorrelationID string // Correlation ID header (empty for "X-Correlation-ID")
+	Traceparent   string // W3C traceparent header (empty for "traceparent")
+	Tracestate    string // W3C tracestate header (empty for "tracestate")
+}
+
+// withDefaults returns the header names with defaults filled in
+func (n TraceHeaderNames) withDefaults() TraceHeaderNames {
+	return TraceHeaderNames{
+		RequestID:     cmp.Or(n.RequestID, RequestIDHeader),
+		CorrelationID: cmp.Or(n.CorrelationID, CorrelationIDHeader),
+		Traceparent:   cmp.Or(n.Traceparent, traceparentHeader),
+		Tracestate:    cmp.Or(n.Tracestate, tracestateHeader),
+	}
+}
+
+// Extract returns the request and correlation IDs, traceparent and
+// tracestate of incoming request headers under these names.
+func (n TraceHeaderNames) Extract(header http.Header) TraceContext {
+	names := n.withDefaults()
+	return TraceContext{
+		RequestID:     header.Get(names.RequestID),
+		CorrelationID: header.Get(names.CorrelationID),
+		Traceparent:   header.Get(names.Traceparent),
+		Tracestate:    header.Get(names.Tracestate),
+	}
+}
+
+// Inject adds the request and correlation IDs, traceparent and tracestate
+// of tc to outgoing request headers under these names. Headers already set
+// are kept.
+//
+// Example:
+//
+//	names := xlogger.TraceHeaderNames{RequestID: "X-Amzn-Trace-Id"}
+//	names.Inject(msg.Headers, xlogger.TraceContextFromContext(ctx))
+func (n TraceHeaderNames) Inject(header http.Header, tc TraceContext) {
+	names := n.withDefaults()
+	setHeaderIfMissing(header, names.RequestID, tc.RequestID)
+	setHeaderIfMissing(header, names.CorrelationID, tc.CorrelationID)
+	setHeaderIfMissing(header, names.Traceparent, tc.Traceparent)
+	setHeaderIfMissing(header, names.Tracestate, tc.Tracestate)
+}
+
+// ExtractTraceHeaders returns the request and correlation IDs, traceparent
+// and tracestate of incoming request headers under the default names, for
+// RunWithTraceContext. Use TraceHeaderNames.Extract for other names.
+//
+// Example:
+//
+//	tc := xlogger.ExtractTraceHeaders(msg.Headers)
+//	if tc.RequestID == "" {
+//	    tc.RequestID = xlogger.NewRequestID()
+//	}
+//	_ = xlogger.RunWithTraceContext(tc, func() error { return handle(msg) })
+func ExtractTraceHeaders(header http.Header) TraceContext {
+	return TraceHeaderNames{}.Extract(header)
+}
+
+// InjectTraceHeaders adds the request and correlation IDs, traceparent and
+// tracestate of ctx (see ContextWithTraceContext) to outgoing request
+// headers under the default names, falling back to the goroutine-local
+// values for those ctx does not carry. Headers already set are kept.
+//
+// Example:
+//
+//	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+//	xlogger.InjectTraceHeaders(ctx, req.Header)
+func InjectTraceHeaders(ctx context.Context, header http.Header) {
+	defaultTraceScope.InjectTraceHeaders(ctx, header)
+}
+
+// InjectTraceHeaders is the scoped form of the package-level
+// InjectTraceHeaders.
+func (s *TraceScope) InjectTraceHeaders(ctx context.Context, header http.Header) {
+	TraceHeaderNames{}.Inject(header, s.outgoingTrace(ctx))
+}
+
+// outgoingTrace returns the trace state of ctx, taking the request and
+// correlation IDs and the W3C headers from this scope when ctx carries
+// neither of the pair
+func (s *TraceScope) outgoingTrace(ctx context.Context) TraceContext {
+	tc := TraceContextFromContext(ctx)
+	if tc.RequestID == "" && tc.CorrelationID == "" {
+		tc.RequestID, tc.CorrelationID = s.TraceRequestID(), s.TraceCorrelationID()
+	}
+	if tc.Traceparent == "" && tc.Tracestate == "" {
+		tc.Traceparent, tc.Tracestate = s.TraceParent(), s.TraceState()
+	}
+	return tc
+}
+
+func setHeaderIfMissing(header http.Header, key, value string) {
+	if value != "" && header.Get(key) == "" {
+		header.Set(key, value)
+	}
+}
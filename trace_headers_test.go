@@ -0,0 +1,124 @@
+package xlogger
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTraceHeaders tests extracting and injecting trace headers
+func TestTraceHeaders(t *testing.T) {
+	const parent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+	t.Run("should extract incoming headers", func(t *testing.T) {
+		header := http.Header{}
+		header.Set(RequestIDHeader, "req-1")
+		header.Set(CorrelationIDHeader, "corr-1")
+		header.Set("traceparent", parent)
+		header.Set("tracestate", "vendor=1")
+
+		assert.Equal(t, TraceContext{
+			RequestID:     "req-1",
+			CorrelationID: "corr-1",
+			Traceparent:   parent,
+			Tracestate:    "vendor=1",
+		}, ExtractTraceHeaders(header))
+	})
+
+	t.Run("should extract and inject under configured names", func(t *testing.T) {
+		names := TraceHeaderNames{RequestID: "X-Trace-Request"}
+		header := http.Header{}
+		names.Inject(header, TraceContext{RequestID: "req-1", CorrelationID: "corr-1", Traceparent: parent})
+
+		assert.Equal(t, "req-1", header.Get("X-Trace-Request"))
+		assert.Empty(t, header.Get(RequestIDHeader))
+		assert.Equal(t, "corr-1", header.Get(CorrelationIDHeader))
+		assert.Equal(t, TraceContext{RequestID: "req-1", CorrelationID: "corr-1", Traceparent: parent}, names.Extract(header))
+	})
+
+	t.Run("should inject the trace of the context", func(t *testing.T) {
+		header := http.Header{}
+		ctx := ContextWithTraceContext(context.Background(), TraceContext{
+			RequestID:   "req-1",
+			Traceparent: parent,
+			Tracestate:  "vendor=1",
+		})
+		InjectTraceHeaders(ctx, header)
+
+		assert.Equal(t, "req-1", header.Get(RequestIDHeader))
+		assert.Equal(t, parent, header.Get("traceparent"))
+		assert.Equal(t, "vendor=1", header.Get("tracestate"))
+	})
+
+	t.Run("should inject the trace of a custom scope", func(t *testing.T) {
+		skipWithoutGLS(t)
+		scope := NewTraceScope()
+		header := http.Header{}
+
+		_ = scope.RunWithTraceContext(TraceContext{RequestID: "req-1", Traceparent: parent}, func() error {
+			InjectTraceHeaders(context.Background(), header)
+			assert.Empty(t, header.Get(RequestIDHeader), "default scope must not see the custom scope")
+			scope.InjectTraceHeaders(context.Background(), header)
+			return nil
+		})
+
+		assert.Equal(t, "req-1", header.Get(RequestIDHeader))
+		assert.Equal(t, parent, header.Get("traceparent"))
+	})
+
+	t.Run("should inject the current trace and keep set headers", func(t *testing.T) {
+		skipWithoutGLS(t)
+		header := http.Header{}
+		header.Set(CorrelationIDHeader, "corr-caller")
+
+		_ = RunWithTraceContext(ExtractTraceHeaders(http.Header{
+			"X-Request-Id":     {"req-1"},
+			"X-Correlation-Id": {"corr-1"},
+			"Traceparent":      {parent},
+		}), func() error {
+			InjectTraceHeaders(context.Background(), header)
+			return nil
+		})
+
+		assert.Equal(t, "req-1", header.Get(RequestIDHeader))
+		assert.Equal(t, "corr-caller", header.Get(CorrelationIDHeader))
+		assert.Equal(t, parent, header.Get("traceparent"))
+		assert.Empty(t, header.Get("tracestate"))
+	})
+
+	t.Run("should use configured header names in the middleware and round tripper", func(t *testing.T) {
+		names := WithTraceHeaderNames(TraceHeaderNames{RequestID: "X-Trace-Request", CorrelationID: "X-Trace-Correlation"})
+
+		var forwarded http.Header
+		downstream := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			forwarded = r.Header.Clone()
+		}))
+		defer downstream.Close()
+
+		logger, _ := newFileLogger(t)
+		client := &http.Client{Transport: NewRoundTripper(logger, nil, names)}
+		handler := HTTPMiddleware(logger, names)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			req, err := http.NewRequestWithContext(r.Context(), http.MethodGet, downstream.URL, nil)
+			require.NoError(t, err)
+			resp, err := client.Do(req)
+			require.NoError(t, err)
+			_ = resp.Body.Close()
+		}))
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-Trace-Request", "req-1")
+		req.Header.Set(RequestIDHeader, "ignored")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, "req-1", rec.Header().Get("X-Trace-Request"))
+		assert.Equal(t, "req-1", rec.Header().Get("X-Trace-Correlation"))
+		assert.Equal(t, "req-1", forwarded.Get("X-Trace-Request"))
+		assert.Equal(t, "req-1", forwarded.Get("X-Trace-Correlation"))
+		assert.Empty(t, forwarded.Get(RequestIDHeader))
+	})
+}
@@ -1,7 +1,9 @@
 package xlogger
 
 import (
-	"github.com/jtolds/gls"
+	"context"
+	"maps"
+	"sync"
 )
 
 const (
@@ -9,50 +11,115 @@ const (
 	traceCorrelationIDKey = "logger-trace-correlation-id"
 )
 
-var traceContextManager = gls.NewContextManager()
+// defaultTraceScope backs the package-level trace functions
+var defaultTraceScope = NewTraceScope()
+
+// TraceScope holds goroutine-local trace state independently of other
+// scopes, so plugins and tests embedding xlogger can isolate their trace
+// identifiers. The package-level functions such as RunWithTrace and
+// TraceRequestID use a default scope; pass a scope to a logger with
+// WithTraceScope.
+type TraceScope struct {
+	store traceStore
+}
+
+// NewTraceScope creates a trace scope that shares no state with the
+// package-level functions or other scopes.
+func NewTraceScope() *TraceScope {
+	return &TraceScope{store: newTraceStore()}
+}
+
+// traceContextKey is the context key of the TraceContext stored by
+// ContextWithTrace and ContextWithTraceContext
+type traceContextKey struct{}
+
+// ContextFieldsFunc extracts log fields from a context for Logger.WithContext.
+type ContextFieldsFunc func(ctx context.Context) []Field
+
+var (
+	contextFieldsMu    sync.RWMutex
+	contextFieldsFuncs []ContextFieldsFunc
+)
 
 // RunWithTrace executes fn within a goroutine-local context that stores
 // request and correlation identifiers for later retrieval.
+//
+// Built with the xlogger_nogls tag, fn runs without them and entries logged
+// within fn carry no trace fields; GoroutineLocalTrace reports which build
+// is in use. Carry the identifiers in a context.Context with
+// ContextWithTrace and log with Logger.WithContext to trace in every build.
 func RunWithTrace(requestID, correlationID string, fn func() error) error {
+	return defaultTraceScope.RunWithTrace(requestID, correlationID, fn)
+}
+
+// RunWithTraceVoid executes fn within the trace context when no error
+// propagation is required.
+func RunWithTraceVoid(requestID, correlationID string, fn func()) {
+	defaultTraceScope.RunWithTraceVoid(requestID, correlationID, fn)
+}
+
+// TraceRequestID returns the goroutine-local request identifier.
+func TraceRequestID() string {
+	return defaultTraceScope.TraceRequestID()
+}
+
+// TraceCorrelationID returns the goroutine-local correlation identifier.
+func TraceCorrelationID() string {
+	return defaultTraceScope.TraceCorrelationID()
+}
+
+// TraceRequestIDFromContext returns the request ID stored in ctx, the
+// context.Context counterpart of TraceRequestID.
+func TraceRequestIDFromContext(ctx context.Context) string {
+	return TraceContextFromContext(ctx).RequestID
+}
+
+// TraceCorrelationIDFromContext returns the correlation ID stored in ctx,
+// the context.Context counterpart of TraceCorrelationID.
+func TraceCorrelationIDFromContext(ctx context.Context) string {
+	return TraceContextFromContext(ctx).CorrelationID
+}
+
+// RunWithTrace is the scoped form of the package-level RunWithTrace.
+func (s *TraceScope) RunWithTrace(requestID, correlationID string, fn func() error) error {
 	if fn == nil {
 		return nil
 	}
 
 	var result error
-	traceContextManager.SetValues(gls.Values{
+	s.store.SetValues(s.runValues(traceValues{
 		traceRequestIDKey:     requestID,
 		traceCorrelationIDKey: correlationID,
-	}, func() {
+	}), func() {
 		result = fn()
 	})
 	return result
 }
 
-// RunWithTraceVoid executes fn within the trace context when no error
-// propagation is required.
-func RunWithTraceVoid(requestID, correlationID string, fn func()) {
+// RunWithTraceVoid is the scoped form of the package-level RunWithTraceVoid.
+func (s *TraceScope) RunWithTraceVoid(requestID, correlationID string, fn func()) {
 	if fn == nil {
 		return
 	}
 
-	traceContextManager.SetValues(gls.Values{
+	s.store.SetValues(s.runValues(traceValues{
 		traceRequestIDKey:     requestID,
 		traceCorrelationIDKey: correlationID,
-	}, fn)
+	}), fn)
 }
 
-// TraceRequestID returns the goroutine-local request identifier.
-func TraceRequestID() string {
-	return getTraceValue(traceRequestIDKey)
+// TraceRequestID returns the request identifier stored in this scope.
+func (s *TraceScope) TraceRequestID() string {
+	return s.getTraceValue(traceRequestIDKey)
 }
 
-// TraceCorrelationID returns the goroutine-local correlation identifier.
-func TraceCorrelationID() string {
-	return getTraceValue(traceCorrelationIDKey)
+// TraceCorrelationID returns the correlation identifier stored in this scope.
+func (s *TraceScope) TraceCorrelationID() string {
+	return s.getTraceValue(traceCorrelationIDKey)
 }
 
-func getTraceValue(key string) string {
-	value, ok := traceContextManager.GetValue(key)
+func (s *TraceScope) getTraceValue(key string) string {
+	value, ok := s.store.GetValue(key)
 	if !ok || value == nil {
 		return ""
 	}
@@ -62,3 +129,79 @@ func getTraceValue(key string) string {
 	}
 	return ""
 }
+
+// ContextWithTrace returns a copy of ctx carrying request and correlation
+// identifiers, keeping the rest of its TraceContext. Unlike RunWithTrace it
+// survives worker pools and any API that passes context.Context; use
+// Logger.WithContext to log with it.
+func ContextWithTrace(ctx context.Context, requestID, correlationID string) context.Context {
+	tc := TraceContextFromContext(ctx)
+	tc.RequestID, tc.CorrelationID = requestID, correlationID
+	return context.WithValue(ctx, traceContextKey{}, tc)
+}
+
+// TraceFromContext returns the identifiers stored by ContextWithTrace, or
+// empty strings when ctx carries none.
+func TraceFromContext(ctx context.Context) (requestID, correlationID string) {
+	tc := TraceContextFromContext(ctx)
+	return tc.RequestID, tc.CorrelationID
+}
+
+// ContextWithTraceContext returns a copy of ctx carrying tc, the
+// context.Context counterpart of RunWithTraceContext. Logger.WithContext logs
+// its identifiers, W3C trace fields, trace actor and baggage.
+//
+// Example:
+//
+//	ctx = xlogger.ContextWithTraceContext(ctx, xlogger.CurrentTraceContext())
+//	jobs <- ctx // handed to another goroutine
+func ContextWithTraceContext(ctx context.Context, tc TraceContext) context.Context {
+	tc.Baggage = maps.Clone(tc.Baggage)
+	return context.WithValue(ctx, traceContextKey{}, tc)
+}
+
+// TraceContextFromContext returns the TraceContext stored in ctx, the
+// context.Context counterpart of the goroutine-local accessors such as
+// TraceRequestID and TraceBaggage. It is empty when ctx carries none.
+func TraceContextFromContext(ctx context.Context) TraceContext {
+	if ctx == nil {
+		return TraceContext{}
+	}
+	tc, _ := ctx.Value(traceContextKey{}).(TraceContext)
+	return tc
+}
+
+// RegisterContextFields adds an extractor whose fields Logger.WithContext
+// attaches to every entry, letting integrations such as xloggerotel and
+// application code read their own values, such as the tenant, user or
+// locale, from the context. Register extractors at startup.
+//
+// Example:
+//
+//	xlogger.RegisterContextFields(func(ctx context.Context) []xlogger.Field {
+//	    if tenant, ok := tenancy.FromContext(ctx); ok {
+//	        return []xlogger.Field{xlogger.String("tenant_id", tenant.ID)}
+//	    }
+//	    return nil
+//	})
+func RegisterContextFields(fn ContextFieldsFunc) {
+	if fn == nil {
+		return
+	}
+
+	contextFieldsMu.Lock()
+	defer contextFieldsMu.Unlock()
+	contextFieldsFuncs = append(contextFieldsFuncs, fn)
+}
+
+// contextFields returns the fields of every registered extractor
+func contextFields(ctx context.Context) []Field {
+	contextFieldsMu.RLock()
+	defer contextFieldsMu.RUnlock()
+
+	var fields []Field
+	for _, fn := range contextFieldsFuncs {
+		fields = append(fields, fn(ctx)...)
+	}
+	return fields
+}
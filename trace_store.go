@@ -0,0 +1,44 @@
+package xlogger
+
+import "strings"
+
+// traceValues are goroutine-local trace values by key
+type traceValues map[interface{}]interface{}
+
+// traceStore holds the goroutine-local values of a TraceScope. It is backed
+// by github.com/jtolds/gls unless built with the xlogger_nogls tag, which
+// leaves gls out of the binary and goroutine-local trace state empty, so
+// runs such as RunWithTrace only call their function (see
+// GoroutineLocalTrace).
+type traceStore interface {
+	// SetValues calls fn with values added to those of the caller
+	SetValues(values traceValues, fn func())
+	// GetValue returns the value of key set by an enclosing SetValues
+	GetValue(key interface{}) (interface{}, bool)
+}
+
+// TraceBackend selects where loggers read trace state from.
+type TraceBackend string
+
+const (
+	// GLSBackend reads the goroutine-local state of RunWithTrace and the
+	// other runs, unless a logger carries a context from WithContext
+	GLSBackend TraceBackend = "gls"
+	// ContextBackend reads trace state only from the contexts passed to
+	// Logger.WithContext, ignoring goroutine-local state
+	ContextBackend TraceBackend = "context"
+)
+
+// Normalize returns the normalized lowercase backend.
+func (b TraceBackend) Normalize() TraceBackend {
+	return TraceBackend(strings.ToLower(string(b)))
+}
+
+// isValid reports whether b is a known backend
+func (b TraceBackend) isValid() bool {
+	switch b.Normalize() {
+	case GLSBackend, ContextBackend:
+		return true
+	}
+	return false
+}
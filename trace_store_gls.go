@@ -0,0 +1,30 @@
+//go:build !xlogger_nogls
+
+package xlogger
+
+import "github.com/jtolds/gls"
+
+// GoroutineLocalTrace reports whether RunWithTrace and the other runs keep
+// goroutine-local trace state. It is false when built with the
+// xlogger_nogls tag, where only Logger.WithContext carries trace fields.
+const GoroutineLocalTrace = true
+
+// glsStore is the traceStore backed by gls
+type glsStore struct {
+	manager *gls.ContextManager
+}
+
+// newTraceStore returns the goroutine-local store of a new TraceScope
+func newTraceStore() traceStore {
+	return glsStore{manager: gls.NewContextManager()}
+}
+
+// SetValues implements traceStore
+func (s glsStore) SetValues(values traceValues, fn func()) {
+	s.manager.SetValues(gls.Values(values), fn)
+}
+
+// GetValue implements traceStore
+func (s glsStore) GetValue(key interface{}) (interface{}, bool) {
+	return s.manager.GetValue(key)
+}
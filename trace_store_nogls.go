@@ -0,0 +1,28 @@
+//go:build xlogger_nogls
+
+package xlogger
+
+// GoroutineLocalTrace reports whether RunWithTrace and the other runs keep
+// goroutine-local trace state. It is false when built with the
+// xlogger_nogls tag, where only Logger.WithContext carries trace fields.
+const GoroutineLocalTrace = false
+
+// noStore is the traceStore of builds without gls, which keeps no values:
+// RunWithTrace and the other runs call their function without trace state,
+// silently. Trace with ContextWithTrace and Logger.WithContext instead.
+type noStore struct{}
+
+// newTraceStore returns the goroutine-local store of a new TraceScope
+func newTraceStore() traceStore {
+	return noStore{}
+}
+
+// SetValues implements traceStore by calling fn
+func (noStore) SetValues(_ traceValues, fn func()) {
+	fn()
+}
+
+// GetValue implements traceStore, never finding a value
+func (noStore) GetValue(interface{}) (interface{}, bool) {
+	return nil, false
+}
@@ -0,0 +1,154 @@
+package xlogger
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// skipWithoutGLS skips tests of goroutine-local trace state in builds with
+// the xlogger_nogls tag, where it stays empty
+func skipWithoutGLS(t *testing.T) {
+	t.Helper()
+	if !GoroutineLocalTrace {
+		t.Skip("goroutine-local trace state is empty with the xlogger_nogls tag")
+	}
+}
+
+// TestGoroutineLocalTrace tests that the build flag matches the trace store
+func TestGoroutineLocalTrace(t *testing.T) {
+	t.Run("should report whether runs keep trace state", func(t *testing.T) {
+		var got string
+		RunWithTraceVoid("req-1", "", func() { got = TraceRequestID() })
+		assert.Equal(t, GoroutineLocalTrace, got == "req-1")
+	})
+}
+
+// TestWithTraceBackend tests reading trace fields only from contexts
+func TestWithTraceBackend(t *testing.T) {
+	t.Run("should ignore goroutine-local state with ContextBackend", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "app.log")
+		logger, err := NewZapLogger(NewLoggerConfig(WithOutputPaths(path), WithTraceBackend(ContextBackend)))
+		require.NoError(t, err)
+		defer func() { _ = logger.Close(t.Context()) }()
+		ctx := ContextWithTrace(context.Background(), "req-ctx", "")
+
+		RunWithTraceVoid("req-gls", "corr-gls", func() {
+			logger.Info("plain")
+			logger.WithContext(ctx).Info("contextual")
+		})
+
+		require.NoError(t, logger.Sync())
+		log := readFile(t, path)
+		plain := entriesWithMessage(t, log, "plain")
+		require.Len(t, plain, 1)
+		assert.NotContains(t, plain[0], "request_id")
+		contextual := entriesWithMessage(t, log, "contextual")
+		require.Len(t, contextual, 1)
+		assert.Equal(t, "req-ctx", contextual[0]["request_id"])
+		assert.NotContains(t, contextual[0], "correlation_id")
+	})
+
+	t.Run("should ignore unknown backends", func(t *testing.T) {
+		assert.Equal(t, ContextBackend, NewLoggerConfig(WithTraceBackend("Context")).TraceBackend)
+		assert.Empty(t, NewLoggerConfig(WithTraceBackend("tls")).TraceBackend)
+	})
+}
+
+// TestContextWithTraceContext tests the full trace state in a context
+func TestContextWithTraceContext(t *testing.T) {
+	const parent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+	t.Run("should log every part of the trace context", func(t *testing.T) {
+		logger, output := newFileLogger(t)
+		ctx := ContextWithTraceContext(context.Background(), TraceContext{
+			RequestID:   "req-1",
+			Traceparent: parent,
+			TenantID:    "acme",
+			Baggage:     map[string]string{"plan": "pro"},
+		})
+
+		logger.WithContext(ctx).Info("contextual")
+
+		entries := entriesWithMessage(t, output(), "contextual")
+		require.Len(t, entries, 1)
+		assert.Equal(t, "req-1", entries[0]["request_id"])
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", entries[0]["trace_id"])
+		assert.Equal(t, "00f067aa0ba902b7", entries[0]["span_id"])
+		assert.Equal(t, "acme", entries[0]["tenant_id"])
+		assert.Equal(t, "pro", entries[0]["baggage.plan"])
+	})
+
+	t.Run("should capture the goroutine-local trace context", func(t *testing.T) {
+		skipWithoutGLS(t)
+		var ctx context.Context
+		_ = RunWithTraceContext(TraceContext{
+			RequestID:   "req-1",
+			Traceparent: parent,
+			Tracestate:  "vendor=1",
+			UserID:      "u-42",
+			Baggage:     map[string]string{"plan": "pro"},
+		}, func() error {
+			assert.Equal(t, "u-42", TraceUserID())
+			ctx = ContextWithTraceContext(context.Background(), CurrentTraceContext())
+			return nil
+		})
+
+		assert.Equal(t, TraceContext{
+			RequestID:   "req-1",
+			Traceparent: parent,
+			Tracestate:  "vendor=1",
+			UserID:      "u-42",
+			Baggage:     map[string]string{"plan": "pro"},
+		}, TraceContextFromContext(ctx))
+	})
+
+	t.Run("should keep the trace context when setting IDs", func(t *testing.T) {
+		ctx := ContextWithTraceContext(context.Background(), TraceContext{TenantID: "acme"})
+		ctx = ContextWithTrace(ctx, "req-1", "corr-1")
+
+		assert.Equal(t, TraceContext{RequestID: "req-1", CorrelationID: "corr-1", TenantID: "acme"}, TraceContextFromContext(ctx))
+		//nolint:staticcheck // nil context is handled explicitly
+		assert.Equal(t, TraceContext{}, TraceContextFromContext(nil))
+	})
+}
+
+// TestTraceAccessorsFromContext tests the context.Context counterparts of
+// the goroutine-local accessors
+func TestTraceAccessorsFromContext(t *testing.T) {
+	const parent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+	t.Run("should read every part of the trace context", func(t *testing.T) {
+		ctx := ContextWithTraceContext(context.Background(), TraceContext{
+			RequestID:     "req-1",
+			CorrelationID: "corr-1",
+			Traceparent:   parent,
+			Tracestate:    "vendor=1",
+			TenantID:      "acme",
+			UserID:        "u-42",
+			Baggage:       map[string]string{"plan": "pro"},
+		})
+
+		assert.Equal(t, "req-1", TraceRequestIDFromContext(ctx))
+		assert.Equal(t, "corr-1", TraceCorrelationIDFromContext(ctx))
+		assert.Equal(t, parent, TraceParentFromContext(ctx))
+		assert.Equal(t, "vendor=1", TraceStateFromContext(ctx))
+		assert.Equal(t, "acme", TraceTenantIDFromContext(ctx))
+		assert.Equal(t, "u-42", TraceUserIDFromContext(ctx))
+		assert.Equal(t, "pro", TraceBaggageFromContext(ctx, "plan"))
+		assert.Empty(t, TraceBaggageFromContext(ctx, "region"))
+	})
+
+	t.Run("should return empty values without trace context", func(t *testing.T) {
+		ctx := context.Background()
+
+		assert.Empty(t, TraceRequestIDFromContext(ctx))
+		assert.Empty(t, TraceParentFromContext(ctx))
+		assert.Empty(t, TraceBaggageFromContext(ctx, "plan"))
+		//nolint:staticcheck // nil context is handled explicitly
+		assert.Empty(t, TraceUserIDFromContext(nil))
+	})
+}
@@ -0,0 +1,185 @@
+package xlogger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	traceParentKey = "logger-trace-parent"
+	traceStateKey  = "logger-trace-state"
+)
+
+const (
+	traceIDFieldKey = "trace_id"
+	spanIDFieldKey  = "span_id"
+)
+
+// traceparentLength is the length of a version 00 traceparent header
+const traceparentLength = 55
+
+// ErrInvalidTraceparent is returned for headers that are not valid W3C traceparent values.
+var ErrInvalidTraceparent = errors.New("invalid traceparent")
+
+// Traceparent is a parsed W3C Trace Context traceparent header.
+type Traceparent struct {
+	Version byte
+	TraceID string // 32 lowercase hex characters
+	SpanID  string // 16 lowercase hex characters (parent-id)
+	Flags   byte
+}
+
+// ParseTraceparent parses a W3C traceparent header such as
+// "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01".
+func ParseTraceparent(header string) (Traceparent, error) {
+	header = strings.TrimSpace(header)
+	if len(header) < traceparentLength {
+		return Traceparent{}, fmt.Errorf("%w: too short", ErrInvalidTraceparent)
+	}
+
+	version, ok := parseHexByte(header[0:2])
+	if !ok || version == 0xff {
+		return Traceparent{}, fmt.Errorf("%w: bad version", ErrInvalidTraceparent)
+	}
+	// Version 00 has a fixed length; later versions may append fields
+	if version == 0 && len(header) != traceparentLength ||
+		len(header) > traceparentLength && header[traceparentLength] != '-' {
+		return Traceparent{}, fmt.Errorf("%w: bad length", ErrInvalidTraceparent)
+	}
+	if header[2] != '-' || header[35] != '-' || header[52] != '-' {
+		return Traceparent{}, fmt.Errorf("%w: bad delimiters", ErrInvalidTraceparent)
+	}
+
+	traceID, spanID := header[3:35], header[36:52]
+	if !isTraceHex(traceID) {
+		return Traceparent{}, fmt.Errorf("%w: bad trace-id", ErrInvalidTraceparent)
+	}
+	if !isTraceHex(spanID) {
+		return Traceparent{}, fmt.Errorf("%w: bad parent-id", ErrInvalidTraceparent)
+	}
+	flags, ok := parseHexByte(header[53:55])
+	if !ok {
+		return Traceparent{}, fmt.Errorf("%w: bad trace-flags", ErrInvalidTraceparent)
+	}
+
+	return Traceparent{Version: version, TraceID: traceID, SpanID: spanID, Flags: flags}, nil
+}
+
+// String formats the traceparent as a version 00 header.
+func (p Traceparent) String() string {
+	return fmt.Sprintf("00-%s-%s-%02x", p.TraceID, p.SpanID, p.Flags)
+}
+
+// Sampled reports whether the sampled trace flag is set.
+func (p Traceparent) Sampled() bool {
+	return p.Flags&0x01 != 0
+}
+
+// parseHexByte parses two lowercase hex characters
+func parseHexByte(s string) (byte, bool) {
+	if strings.ToLower(s) != s {
+		return 0, false
+	}
+	v, err := strconv.ParseUint(s, 16, 8)
+	return byte(v), err == nil
+}
+
+// isTraceHex reports whether s is lowercase hex and not all zeros
+func isTraceHex(s string) bool {
+	nonZero := false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '0':
+		case c >= '1' && c <= '9', c >= 'a' && c <= 'f':
+			nonZero = true
+		default:
+			return false
+		}
+	}
+	return nonZero
+}
+
+// RunWithTraceparent executes fn within a goroutine-local context holding the
+// W3C traceparent header, adding trace_id and span_id to every log entry.
+// Invalid headers are ignored as required by the specification; fn still runs.
+func RunWithTraceparent(header string, fn func() error) error {
+	return defaultTraceScope.RunWithTraceparent(header, fn)
+}
+
+// CurrentTraceparent returns the goroutine-local traceparent.
+func CurrentTraceparent() (Traceparent, bool) {
+	return defaultTraceScope.CurrentTraceparent()
+}
+
+// TraceParent returns the goroutine-local traceparent header, or an empty
+// string outside RunWithTraceparent.
+func TraceParent() string {
+	return defaultTraceScope.TraceParent()
+}
+
+// TraceState returns the goroutine-local tracestate header.
+func TraceState() string {
+	return defaultTraceScope.TraceState()
+}
+
+// TraceParentFromContext returns the traceparent header stored in ctx, the
+// context.Context counterpart of TraceParent.
+func TraceParentFromContext(ctx context.Context) string {
+	return TraceContextFromContext(ctx).Traceparent
+}
+
+// TraceStateFromContext returns the tracestate header stored in ctx, the
+// context.Context counterpart of TraceState.
+func TraceStateFromContext(ctx context.Context) string {
+	return TraceContextFromContext(ctx).Tracestate
+}
+
+// RunWithTraceparent is the scoped form of the package-level
+// RunWithTraceparent.
+func (s *TraceScope) RunWithTraceparent(header string, fn func() error) error {
+	if fn == nil {
+		return nil
+	}
+
+	parent, err := ParseTraceparent(header)
+	if err != nil {
+		return fn()
+	}
+
+	var result error
+	s.store.SetValues(s.runValues(traceValues{
+		traceParentKey: parent,
+		traceStateKey:  "",
+	}), func() {
+		result = fn()
+	})
+	return result
+}
+
+// CurrentTraceparent returns the traceparent stored in this scope.
+func (s *TraceScope) CurrentTraceparent() (Traceparent, bool) {
+	value, ok := s.store.GetValue(traceParentKey)
+	if !ok {
+		return Traceparent{}, false
+	}
+	parent, ok := value.(Traceparent)
+	return parent, ok
+}
+
+// TraceParent returns the traceparent header stored in this scope.
+func (s *TraceScope) TraceParent() string {
+	parent, ok := s.CurrentTraceparent()
+	if !ok {
+		return ""
+	}
+	return parent.String()
+}
+
+// TraceState returns the tracestate header stored in this scope.
+func (s *TraceScope) TraceState() string {
+	return s.getTraceValue(traceStateKey)
+}
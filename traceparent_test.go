@@ -0,0 +1,112 @@
+package xlogger
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testTraceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func TestParseTraceparent(t *testing.T) {
+	t.Run("should parse valid header", func(t *testing.T) {
+		parent, err := ParseTraceparent(testTraceparent)
+
+		require.NoError(t, err)
+		assert.Equal(t, byte(0), parent.Version)
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", parent.TraceID)
+		assert.Equal(t, "00f067aa0ba902b7", parent.SpanID)
+		assert.True(t, parent.Sampled())
+		assert.Equal(t, testTraceparent, parent.String())
+	})
+
+	t.Run("should accept future versions with extra fields", func(t *testing.T) {
+		parent, err := ParseTraceparent("cc-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00-extra")
+
+		require.NoError(t, err)
+		assert.Equal(t, byte(0xcc), parent.Version)
+		assert.False(t, parent.Sampled())
+	})
+
+	invalid := map[string]string{
+		"empty":               "",
+		"version ff":          "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"version 00 too long": testTraceparent + "-extra",
+		"uppercase":           "00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+		"zero trace id":       "00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"zero span id":        "00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"bad delimiter":       "00_4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"bad flags":           "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-zz",
+	}
+	for name, header := range invalid {
+		t.Run("should reject "+name, func(t *testing.T) {
+			_, err := ParseTraceparent(header)
+			assert.ErrorIs(t, err, ErrInvalidTraceparent)
+		})
+	}
+}
+
+func TestRunWithTraceparent(t *testing.T) {
+	t.Run("should expose traceparent within fn", func(t *testing.T) {
+		skipWithoutGLS(t)
+		err := RunWithTraceContext(TraceContext{Traceparent: testTraceparent, Tracestate: "vendor=value"}, func() error {
+			assert.Equal(t, testTraceparent, TraceParent())
+			assert.Equal(t, "vendor=value", TraceState())
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Empty(t, TraceParent())
+		assert.Empty(t, TraceState())
+	})
+
+	t.Run("should run fn without trace for invalid header", func(t *testing.T) {
+		expectedErr := errors.New("fn error")
+		executed := false
+
+		err := RunWithTraceparent("invalid", func() error {
+			executed = true
+			assert.Empty(t, TraceParent())
+			return expectedErr
+		})
+
+		assert.True(t, executed)
+		assert.Equal(t, expectedErr, err)
+	})
+
+	t.Run("should combine with request trace", func(t *testing.T) {
+		skipWithoutGLS(t)
+		_ = RunWithTraceparent(testTraceparent, func() error {
+			return RunWithTrace("req-1", "corr-1", func() error {
+				assert.Equal(t, testTraceparent, TraceParent())
+				assert.Equal(t, "req-1", TraceRequestID())
+
+				fields, _ := withTraceFields(defaultTraceScope, TraceConflictPreferCaller, nil)
+				keys := make([]string, 0, len(fields))
+				for _, field := range fields {
+					keys = append(keys, field.Key())
+				}
+				assert.ElementsMatch(t, []string{"request_id", "correlation_id", "trace_id", "span_id"}, keys)
+				return nil
+			})
+		})
+	})
+
+	t.Run("should keep explicit trace fields", func(t *testing.T) {
+		skipWithoutGLS(t)
+		_ = RunWithTraceparent(testTraceparent, func() error {
+			fields, _ := withTraceFields(defaultTraceScope, TraceConflictPreferCaller, []Field{String("trace_id", "explicit")})
+
+			require.Len(t, fields, 2)
+			assert.Equal(t, "explicit", fields[0].Value())
+			assert.Equal(t, "span_id", fields[1].Key())
+			return nil
+		})
+	})
+
+	t.Run("should handle nil fn", func(t *testing.T) {
+		assert.NoError(t, RunWithTraceparent(testTraceparent, nil))
+	})
+}
@@ -0,0 +1,263 @@
+// Package xloggerfluent ships xlogger entries to a Fluentd or Fluent Bit
+// aggregator with the forward protocol, MessagePack over TCP.
+//
+// Importing the package registers the "fluent" sink scheme, so an output
+// such as "fluent://localhost:24224?tag=app.orders" can be passed to
+// xlogger.WithOutputPaths or xlogger.WithSink:
+//
+//	import _ "github.com/hotfixfirst/go-xlogger/xloggerfluent"
+//
+//	cfg := xlogger.NewLoggerConfig(
+//	    xlogger.WithSink(xlogger.SinkConfig{
+//	        Output: "fluent://fluentd:24224?tag=app.orders",
+//	        Format: xlogger.FormatMsgpack,
+//	    }),
+//	)
+//
+// Entries encoded with xlogger.FormatMsgpack are forwarded as records
+// holding time, level, message and the entry fields under "fields"; entries
+// in other formats are forwarded as a record holding the entry text in its
+// "log" key, like Fluent Bit's tail input.
+package xloggerfluent
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultPort is the forward protocol port used when an address has none
+	DefaultPort = "24224"
+	// DefaultTag is the tag of entries when none is configured
+	DefaultTag = "xlogger"
+
+	sinkScheme = "fluent"
+)
+
+// Defaults of the connection settings
+const (
+	defaultDialTimeout   = 5 * time.Second
+	defaultWriteTimeout  = 5 * time.Second
+	defaultReconnectWait = time.Second
+)
+
+// ErrSinkClosed is returned when writing to a closed Sink
+var ErrSinkClosed = errors.New("xloggerfluent: sink closed")
+
+// ErrUnavailable is returned instead of dialing again while the aggregator
+// is waited for after a failed connection
+var ErrUnavailable = errors.New("xloggerfluent: aggregator unavailable")
+
+func init() {
+	if err := zap.RegisterSink(sinkScheme, newURLSink); err != nil {
+		panic(err)
+	}
+}
+
+// newURLSink creates a Sink from a fluent://host[:port]?tag=... URL
+func newURLSink(u *url.URL) (zap.Sink, error) {
+	return NewSink(u.Host, u.Query().Get("tag"))
+}
+
+// Sink forwards every write as one forward protocol message. It implements
+// zap.Sink. The connection is established on the first write and
+// re-established after failures.
+type Sink struct {
+	address       string
+	tag           string
+	dialer        *net.Dialer
+	writeTimeout  time.Duration
+	reconnectWait time.Duration
+
+	mu          sync.Mutex
+	conn        net.Conn
+	lastFailure time.Time // Last failed dial, to wait reconnectWait before the next
+	closed      bool
+}
+
+// SinkOption configures a Sink
+type SinkOption func(*Sink)
+
+// WithDialer sets the dialer of the TCP connection, for example to set a
+// timeout or local address
+func WithDialer(dialer *net.Dialer) SinkOption {
+	return func(s *Sink) {
+		if dialer != nil {
+			s.dialer = dialer
+		}
+	}
+}
+
+// WithWriteTimeout bounds each write to the aggregator (0 for no limit)
+func WithWriteTimeout(timeout time.Duration) SinkOption {
+	return func(s *Sink) {
+		s.writeTimeout = timeout
+	}
+}
+
+// WithReconnectWait sets how long writes fail with ErrUnavailable after a
+// failed connection before dialing again, so an unreachable aggregator does
+// not cost a dial per entry
+func WithReconnectWait(wait time.Duration) SinkOption {
+	return func(s *Sink) {
+		s.reconnectWait = wait
+	}
+}
+
+// NewSink creates a sink forwarding entries tagged with tag to the
+// aggregator at address. An empty tag uses DefaultTag and an address
+// without port uses DefaultPort.
+//
+// Example:
+//
+//	sink, err := xloggerfluent.NewSink("fluentd:24224", "app.orders",
+//	    xloggerfluent.WithReconnectWait(5*time.Second),
+//	)
+//	_ = zap.RegisterSink("aggregator", func(*url.URL) (zap.Sink, error) { return sink, nil })
+func NewSink(address, tag string, opts ...SinkOption) (*Sink, error) {
+	if address == "" {
+		return nil, errors.New("xloggerfluent: empty address")
+	}
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		address = net.JoinHostPort(strings.Trim(address, "[]"), DefaultPort)
+	}
+	if tag == "" {
+		tag = DefaultTag
+	}
+
+	s := &Sink{
+		address:       address,
+		tag:           tag,
+		dialer:        &net.Dialer{Timeout: defaultDialTimeout},
+		writeTimeout:  defaultWriteTimeout,
+		reconnectWait: defaultReconnectWait,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s, nil
+}
+
+// Write sends p as one message, reconnecting once if the connection broke
+func (s *Sink) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return 0, ErrSinkClosed
+	}
+
+	msg := appendMessage(nil, s.tag, time.Now(), p)
+	reused := s.conn != nil
+	err := s.send(msg)
+	if err != nil && reused {
+		// The aggregator restarted or the network dropped the connection;
+		// retry once on a fresh one
+		err = s.send(msg)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// send writes msg, dialing when needed and discarding the connection on failure
+func (s *Sink) send(msg []byte) error {
+	if s.conn == nil {
+		if time.Since(s.lastFailure) < s.reconnectWait {
+			return ErrUnavailable
+		}
+		conn, err := s.dialer.Dial("tcp", s.address)
+		if err != nil {
+			s.lastFailure = time.Now()
+			return fmt.Errorf("xloggerfluent: dial %s: %w", s.address, err)
+		}
+		s.conn = conn
+	}
+
+	if s.writeTimeout > 0 {
+		_ = s.conn.SetWriteDeadline(time.Now().Add(s.writeTimeout))
+	}
+	if _, err := s.conn.Write(msg); err != nil {
+		_ = s.conn.Close()
+		s.conn = nil
+		return fmt.Errorf("xloggerfluent: write: %w", err)
+	}
+	return nil
+}
+
+// Sync implements zapcore.WriteSyncer; messages are sent on every Write
+func (s *Sink) Sync() error {
+	return nil
+}
+
+// Close closes the connection to the aggregator
+func (s *Sink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+// appendMessage appends the forward protocol message [tag, time, record].
+// Entries already encoded as a MessagePack map are the record; others are
+// wrapped in a map with the text under "log".
+func appendMessage(b []byte, tag string, t time.Time, entry []byte) []byte {
+	b = append(b, 0x93) // fixarray of 3
+	b = appendString(b, tag)
+
+	// EventTime: ext type 0 holding big-endian uint32 seconds and nanoseconds
+	b = append(b, 0xd7, 0x00)
+	b = binary.BigEndian.AppendUint32(b, uint32(t.Unix()))
+	b = binary.BigEndian.AppendUint32(b, uint32(t.Nanosecond()))
+
+	if isMsgpackMap(entry) {
+		return append(b, entry...)
+	}
+	b = append(b, 0x81) // fixmap of 1
+	b = appendString(b, "log")
+	return appendString(b, strings.TrimRight(string(entry), "\n"))
+}
+
+// isMsgpackMap reports whether entry starts with a MessagePack map header
+func isMsgpackMap(entry []byte) bool {
+	if len(entry) == 0 {
+		return false
+	}
+	c := entry[0]
+	return c >= 0x80 && c <= 0x8f || c == 0xde || c == 0xdf
+}
+
+func appendString(b []byte, s string) []byte {
+	switch n := len(s); {
+	case n < 32:
+		b = append(b, 0xa0|byte(n))
+	case n <= 0xff:
+		b = append(b, 0xd9, byte(n))
+	case n <= 0xffff:
+		b = append(b, 0xda)
+		b = binary.BigEndian.AppendUint16(b, uint16(n))
+	default:
+		b = append(b, 0xdb)
+		b = binary.BigEndian.AppendUint32(b, uint32(n))
+	}
+	return append(b, s...)
+}
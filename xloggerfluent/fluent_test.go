@@ -0,0 +1,333 @@
+package xloggerfluent
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hotfixfirst/go-xlogger"
+)
+
+// message is a decoded forward protocol message
+type message struct {
+	tag    string
+	time   time.Time
+	record map[string]interface{}
+}
+
+// aggregator is a forward protocol server collecting the messages it receives
+type aggregator struct {
+	listener net.Listener
+
+	mu       sync.Mutex
+	messages []message
+	conns    []net.Conn
+}
+
+func startAggregator(t *testing.T) *aggregator {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	a := &aggregator{listener: listener}
+	t.Cleanup(func() {
+		_ = listener.Close()
+		a.dropConnections()
+	})
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			a.mu.Lock()
+			a.conns = append(a.conns, conn)
+			a.mu.Unlock()
+			go a.serve(conn)
+		}
+	}()
+	return a
+}
+
+func (a *aggregator) serve(conn net.Conn) {
+	r := bufio.NewReader(conn)
+	for {
+		value, err := readValue(r)
+		if err != nil {
+			return
+		}
+		fields := value.([]interface{})
+		ext := fields[1].(extValue)
+		a.mu.Lock()
+		a.messages = append(a.messages, message{
+			tag: fields[0].(string),
+			time: time.Unix(int64(binary.BigEndian.Uint32(ext.data[:4])),
+				int64(binary.BigEndian.Uint32(ext.data[4:]))),
+			record: fields[2].(map[string]interface{}),
+		})
+		a.mu.Unlock()
+	}
+}
+
+// received waits for n messages and returns them
+func (a *aggregator) received(t *testing.T, n int) []message {
+	t.Helper()
+
+	var messages []message
+	require.Eventually(t, func() bool {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		messages = append([]message(nil), a.messages...)
+		return len(messages) >= n
+	}, 2*time.Second, 5*time.Millisecond)
+	return messages
+}
+
+// dropConnections closes the accepted connections, as an aggregator restart would
+func (a *aggregator) dropConnections() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, conn := range a.conns {
+		_ = conn.Close()
+	}
+	a.conns = nil
+}
+
+// TestSink tests forwarding entries to an aggregator
+func TestSink(t *testing.T) {
+	t.Run("should forward text entries under the log key", func(t *testing.T) {
+		agg := startAggregator(t)
+		sink, err := NewSink(agg.listener.Addr().String(), "app.orders")
+		require.NoError(t, err)
+		defer sink.Close()
+
+		before := time.Now().Add(-time.Second)
+		n, err := sink.Write([]byte("{\"message\":\"order created\"}\n"))
+		require.NoError(t, err)
+		assert.Equal(t, 28, n)
+
+		messages := agg.received(t, 1)
+		assert.Equal(t, "app.orders", messages[0].tag)
+		assert.True(t, messages[0].time.After(before))
+		assert.Equal(t, map[string]interface{}{"log": `{"message":"order created"}`}, messages[0].record)
+	})
+
+	t.Run("should reconnect after the connection broke", func(t *testing.T) {
+		agg := startAggregator(t)
+		sink, err := NewSink(agg.listener.Addr().String(), "")
+		require.NoError(t, err)
+		defer sink.Close()
+
+		_, err = sink.Write([]byte("first"))
+		require.NoError(t, err)
+		agg.received(t, 1)
+		agg.dropConnections()
+
+		// Writes to the dropped connection may be accepted by the local
+		// kernel before the reset is noticed
+		require.Eventually(t, func() bool {
+			_, _ = sink.Write([]byte("after restart"))
+			agg.mu.Lock()
+			defer agg.mu.Unlock()
+			return len(agg.messages) > 1
+		}, 2*time.Second, 10*time.Millisecond)
+
+		messages := agg.received(t, 2)
+		assert.Equal(t, DefaultTag, messages[1].tag)
+		assert.Equal(t, "after restart", messages[1].record["log"])
+	})
+
+	t.Run("should wait before dialing an unreachable aggregator again", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		address := listener.Addr().String()
+		require.NoError(t, listener.Close())
+
+		sink, err := NewSink(address, "", WithReconnectWait(time.Hour))
+		require.NoError(t, err)
+
+		_, err = sink.Write([]byte("lost"))
+		assert.ErrorContains(t, err, "dial")
+		_, err = sink.Write([]byte("lost"))
+		assert.ErrorIs(t, err, ErrUnavailable)
+	})
+
+	t.Run("should reject writes after Close", func(t *testing.T) {
+		sink, err := NewSink("127.0.0.1:1", "")
+		require.NoError(t, err)
+		require.NoError(t, sink.Close())
+
+		_, err = sink.Write([]byte("late"))
+		assert.ErrorIs(t, err, ErrSinkClosed)
+		assert.NoError(t, sink.Close())
+	})
+
+	t.Run("should apply the default port", func(t *testing.T) {
+		sink, err := NewSink("fluentd", "")
+		require.NoError(t, err)
+		assert.Equal(t, "fluentd:24224", sink.address)
+
+		_, err = NewSink("", "")
+		assert.Error(t, err)
+	})
+}
+
+// TestSinkURL tests the fluent sink scheme with xlogger
+func TestSinkURL(t *testing.T) {
+	t.Run("should forward msgpack entries as records", func(t *testing.T) {
+		agg := startAggregator(t)
+		logger, err := xlogger.NewZapLogger(xlogger.NewLoggerConfig(
+			xlogger.WithOutputPaths(filepath.Join(t.TempDir(), "app.log")),
+			xlogger.WithSink(xlogger.SinkConfig{
+				Output: fmt.Sprintf("fluent://%s?tag=app.orders", agg.listener.Addr()),
+				Format: xlogger.FormatMsgpack,
+			}),
+		))
+		require.NoError(t, err)
+
+		logger.Info("order created", xlogger.String("order_id", "o-1"))
+		require.NoError(t, logger.Sync())
+
+		messages := agg.received(t, 1)
+		assert.Equal(t, "app.orders", messages[0].tag)
+		assert.Equal(t, "order created", messages[0].record["message"])
+		assert.Equal(t, map[string]interface{}{"order_id": "o-1"}, messages[0].record["fields"])
+		assert.Equal(t, "info", messages[0].record["level"])
+	})
+}
+
+// extValue is an undecoded MessagePack extension
+type extValue struct {
+	typ  int8
+	data []byte
+}
+
+// readValue decodes the MessagePack types written by the sink and the
+// msgpack encoder
+func readValue(r *bufio.Reader) (interface{}, error) {
+	c, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case c <= 0x7f:
+		return int64(c), nil
+	case c >= 0x80 && c <= 0x8f:
+		return readMap(r, int(c&0x0f))
+	case c >= 0x90 && c <= 0x9f:
+		return readArray(r, int(c&0x0f))
+	case c >= 0xa0 && c <= 0xbf:
+		return readString(r, int(c&0x1f))
+	case c >= 0xe0:
+		return int64(int8(c)), nil
+	}
+
+	switch c {
+	case 0xc0:
+		return nil, nil
+	case 0xc2, 0xc3:
+		return c == 0xc3, nil
+	case 0xcb:
+		var v uint64
+		err := binary.Read(r, binary.BigEndian, &v)
+		return v, err
+	case 0xcc, 0xcd, 0xce, 0xcf, 0xd0, 0xd1, 0xd2, 0xd3:
+		size := 1 << ((c - 0xcc) % 4)
+		data, err := readN(r, size)
+		return data, err
+	case 0xd9, 0xda, 0xdb:
+		n, err := readSize(r, 1<<(c-0xd9))
+		if err != nil {
+			return nil, err
+		}
+		return readString(r, n)
+	case 0xde, 0xdf:
+		n, err := readSize(r, 2<<(c-0xde))
+		if err != nil {
+			return nil, err
+		}
+		return readMap(r, n)
+	case 0xd4, 0xd5, 0xd6, 0xd7, 0xd8:
+		return readExt(r, 1<<(c-0xd4))
+	case 0xc7:
+		n, err := readSize(r, 1)
+		if err != nil {
+			return nil, err
+		}
+		return readExt(r, n)
+	}
+	return nil, fmt.Errorf("unsupported msgpack type 0x%x", c)
+}
+
+func readSize(r *bufio.Reader, width int) (int, error) {
+	data, err := readN(r, width)
+	if err != nil {
+		return 0, err
+	}
+	var n int
+	for _, b := range data {
+		n = n<<8 | int(b)
+	}
+	return n, nil
+}
+
+func readN(r *bufio.Reader, n int) ([]byte, error) {
+	data := make([]byte, n)
+	_, err := io.ReadFull(r, data)
+	return data, err
+}
+
+func readString(r *bufio.Reader, n int) (interface{}, error) {
+	data, err := readN(r, n)
+	return string(data), err
+}
+
+func readExt(r *bufio.Reader, n int) (interface{}, error) {
+	typ, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	data, err := readN(r, n)
+	return extValue{typ: int8(typ), data: data}, err
+}
+
+func readArray(r *bufio.Reader, n int) (interface{}, error) {
+	values := make([]interface{}, n)
+	for i := range values {
+		value, err := readValue(r)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+func readMap(r *bufio.Reader, n int) (interface{}, error) {
+	values := make(map[string]interface{}, n)
+	for i := 0; i < n; i++ {
+		key, err := readValue(r)
+		if err != nil {
+			return nil, err
+		}
+		name, ok := key.(string)
+		if !ok {
+			return nil, errors.New("non-string map key")
+		}
+		if values[name], err = readValue(r); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
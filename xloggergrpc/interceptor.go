@@ -0,0 +1,288 @@
+package xloggergrpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/hotfixfirst/go-xlogger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// RequestIDMetadataKey carries the request ID, like X-Request-ID over HTTP.
+	RequestIDMetadataKey = "x-request-id"
+	// CorrelationIDMetadataKey carries the correlation ID, like X-Correlation-ID over HTTP.
+	CorrelationIDMetadataKey = "x-correlation-id"
+)
+
+// interceptorConfig holds the settings shared by all interceptors
+type interceptorConfig struct {
+	logPayloads bool
+}
+
+// InterceptorOption configures the logging interceptors
+type InterceptorOption func(*interceptorConfig)
+
+// WithPayloadLogging logs request and response messages at Debug. Messages
+// are only formatted when Debug is enabled, but they may contain sensitive
+// data and should not be enabled in production.
+func WithPayloadLogging(enabled bool) InterceptorOption {
+	return func(c *interceptorConfig) {
+		c.logPayloads = enabled
+	}
+}
+
+func newInterceptorConfig(opts []InterceptorOption) *interceptorConfig {
+	cfg := &interceptorConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// UnaryServerInterceptor returns a server interceptor that reads request and
+// correlation IDs from the incoming metadata (generating a request ID when
+// missing), runs the handler inside xlogger.RunWithTrace with the IDs also
+// stored in its context, and logs the method, status code and duration.
+//
+// Example:
+//
+//	server := grpc.NewServer(
+//	    grpc.ChainUnaryInterceptor(xloggergrpc.UnaryServerInterceptor(logger)),
+//	    grpc.ChainStreamInterceptor(xloggergrpc.StreamServerInterceptor(logger)),
+//	)
+func UnaryServerInterceptor(logger xlogger.Logger, opts ...InterceptorOption) grpc.UnaryServerInterceptor {
+	cfg := newInterceptorConfig(opts)
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		ctx, requestID, correlationID := incomingTrace(ctx, logger)
+
+		var resp interface{}
+		err := xlogger.RunWithTrace(requestID, correlationID, func() error {
+			cfg.logPayload(logger, "grpc request payload", info.FullMethod, req)
+
+			var err error
+			resp, err = handler(ctx, req)
+			if err == nil {
+				cfg.logPayload(logger, "grpc response payload", info.FullMethod, resp)
+			}
+			logCompletion(logger, "grpc request completed", info.FullMethod, start, err)
+			return err
+		})
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor is the streaming counterpart of UnaryServerInterceptor.
+// The stream context carries the trace IDs and, with payload logging, every
+// received and sent message is logged at Debug.
+func StreamServerInterceptor(logger xlogger.Logger, opts ...InterceptorOption) grpc.StreamServerInterceptor {
+	cfg := newInterceptorConfig(opts)
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		ctx, requestID, correlationID := incomingTrace(ss.Context(), logger)
+
+		return xlogger.RunWithTrace(requestID, correlationID, func() error {
+			err := handler(srv, &serverStream{ServerStream: ss, ctx: ctx, cfg: cfg, logger: logger, method: info.FullMethod})
+			logCompletion(logger, "grpc stream completed", info.FullMethod, start, err)
+			return err
+		})
+	}
+}
+
+// UnaryClientInterceptor returns a client interceptor that forwards the
+// request and correlation IDs of the context (or the goroutine-local trace)
+// as outgoing metadata and logs the method, status code and duration.
+//
+// Example:
+//
+//	conn, err := grpc.NewClient(target,
+//	    grpc.WithChainUnaryInterceptor(xloggergrpc.UnaryClientInterceptor(logger)),
+//	    grpc.WithChainStreamInterceptor(xloggergrpc.StreamClientInterceptor(logger)),
+//	)
+func UnaryClientInterceptor(logger xlogger.Logger, opts ...InterceptorOption) grpc.UnaryClientInterceptor {
+	cfg := newInterceptorConfig(opts)
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, callOpts ...grpc.CallOption) error {
+		start := time.Now()
+		cfg.logPayload(logger, "grpc request payload", method, req)
+
+		err := invoker(outgoingTrace(ctx), method, req, reply, cc, callOpts...)
+		if err == nil {
+			cfg.logPayload(logger, "grpc response payload", method, reply)
+		}
+		logCompletion(logger, "grpc call completed", method, start, err)
+		return err
+	}
+}
+
+// StreamClientInterceptor is the streaming counterpart of UnaryClientInterceptor.
+// The call is logged once the stream ends, when a receive returns io.EOF or an error.
+func StreamClientInterceptor(logger xlogger.Logger, opts ...InterceptorOption) grpc.StreamClientInterceptor {
+	cfg := newInterceptorConfig(opts)
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, callOpts ...grpc.CallOption) (grpc.ClientStream, error) {
+		start := time.Now()
+
+		cs, err := streamer(outgoingTrace(ctx), desc, cc, method, callOpts...)
+		if err != nil {
+			logCompletion(logger, "grpc stream completed", method, start, err)
+			return nil, err
+		}
+		return &clientStream{ClientStream: cs, cfg: cfg, logger: logger, method: method, start: start}, nil
+	}
+}
+
+// incomingTrace resolves the trace IDs of an incoming call, generating
+// missing ones with the generator of logger, stores them in the context and
+// echoes them in the response header
+func incomingTrace(ctx context.Context, logger xlogger.Logger) (context.Context, string, string) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	requestID := firstValue(md, RequestIDMetadataKey)
+	if requestID == "" {
+		requestID = newRequestID(logger)
+	}
+	correlationID := firstValue(md, CorrelationIDMetadataKey)
+	if correlationID == "" {
+		correlationID = requestID
+	}
+
+	_ = grpc.SetHeader(ctx, metadata.Pairs(
+		RequestIDMetadataKey, requestID,
+		CorrelationIDMetadataKey, correlationID,
+	))
+	return xlogger.ContextWithTrace(ctx, requestID, correlationID), requestID, correlationID
+}
+
+// newRequestID returns a new request ID of the generator of logger, set
+// with xlogger.WithIDGenerator
+func newRequestID(logger xlogger.Logger) string {
+	if gen, ok := logger.(interface{ NewRequestID() string }); ok {
+		return gen.NewRequestID()
+	}
+	return xlogger.NewRequestID()
+}
+
+// outgoingTrace adds the trace IDs of ctx, or of the goroutine, to the outgoing metadata
+func outgoingTrace(ctx context.Context) context.Context {
+	requestID, correlationID := xlogger.TraceFromContext(ctx)
+	if requestID == "" && correlationID == "" {
+		requestID, correlationID = xlogger.TraceRequestID(), xlogger.TraceCorrelationID()
+	}
+
+	md, _ := metadata.FromOutgoingContext(ctx)
+	var pairs []string
+	if requestID != "" && len(md.Get(RequestIDMetadataKey)) == 0 {
+		pairs = append(pairs, RequestIDMetadataKey, requestID)
+	}
+	if correlationID != "" && len(md.Get(CorrelationIDMetadataKey)) == 0 {
+		pairs = append(pairs, CorrelationIDMetadataKey, correlationID)
+	}
+	if len(pairs) == 0 {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, pairs...)
+}
+
+// logCompletion logs the outcome of a call: Info for OK, Error for codes
+// that indicate a server-side failure and Warn for the rest
+func logCompletion(logger xlogger.Logger, msg, method string, start time.Time, err error) {
+	code := status.Code(err)
+	fields := []xlogger.Field{
+		xlogger.String("grpc_method", method),
+		xlogger.String("grpc_code", code.String()),
+		xlogger.Duration("duration", time.Since(start)),
+	}
+	if err != nil {
+		fields = append(fields, xlogger.Error(err))
+	}
+
+	switch code {
+	case codes.OK:
+		logger.Info(msg, fields...)
+	case codes.Unknown, codes.DeadlineExceeded, codes.Unimplemented, codes.Internal, codes.Unavailable, codes.DataLoss:
+		logger.Error(msg, fields...)
+	default:
+		logger.Warn(msg, fields...)
+	}
+}
+
+// logPayload logs a message at Debug when payload logging is enabled
+func (c *interceptorConfig) logPayload(logger xlogger.Logger, msg, method string, payload interface{}) {
+	if !c.logPayloads {
+		return
+	}
+	logger.DebugFn(msg, func() []xlogger.Field {
+		return []xlogger.Field{
+			xlogger.String("grpc_method", method),
+			xlogger.Any("payload", payload),
+		}
+	})
+}
+
+// serverStream carries the trace context and logs stream payloads
+type serverStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	cfg    *interceptorConfig
+	logger xlogger.Logger
+	method string
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *serverStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		s.cfg.logPayload(s.logger, "grpc request payload", s.method, m)
+	}
+	return err
+}
+
+func (s *serverStream) SendMsg(m interface{}) error {
+	err := s.ServerStream.SendMsg(m)
+	if err == nil {
+		s.cfg.logPayload(s.logger, "grpc response payload", s.method, m)
+	}
+	return err
+}
+
+// clientStream logs stream payloads and the call once the stream ends
+type clientStream struct {
+	grpc.ClientStream
+	cfg    *interceptorConfig
+	logger xlogger.Logger
+	method string
+	start  time.Time
+	done   bool
+}
+
+func (s *clientStream) SendMsg(m interface{}) error {
+	err := s.ClientStream.SendMsg(m)
+	if err == nil {
+		s.cfg.logPayload(s.logger, "grpc request payload", s.method, m)
+	}
+	return err
+}
+
+func (s *clientStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err == nil {
+		s.cfg.logPayload(s.logger, "grpc response payload", s.method, m)
+		return nil
+	}
+	if !s.done {
+		s.done = true
+		callErr := err
+		if errors.Is(err, io.EOF) {
+			callErr = nil
+		}
+		logCompletion(s.logger, "grpc stream completed", s.method, s.start, callErr)
+	}
+	return err
+}
@@ -0,0 +1,324 @@
+package xloggergrpc
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hotfixfirst/go-xlogger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// newInterceptorLogger returns a logger writing JSON to a temp file and a
+// function returning the decoded entries with a message
+func newInterceptorLogger(t *testing.T) (*xlogger.ZapLogger, func(msg string) []map[string]interface{}) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	logger, err := xlogger.NewZapLogger(xlogger.NewLoggerConfig(xlogger.WithOutputPaths(path)))
+	require.NoError(t, err)
+
+	return logger, func(msg string) []map[string]interface{} {
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+
+		var entries []map[string]interface{}
+		for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+			if line == "" {
+				continue
+			}
+			var entry map[string]interface{}
+			require.NoError(t, json.Unmarshal([]byte(line), &entry))
+			if entry["message"] == msg {
+				entries = append(entries, entry)
+			}
+		}
+		return entries
+	}
+}
+
+// fakeServerStream is a ServerStream with a fixed context and queued messages
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx      context.Context
+	incoming []string
+	sent     []string
+}
+
+func (s *fakeServerStream) Context() context.Context { return s.ctx }
+
+func (s *fakeServerStream) SetHeader(metadata.MD) error { return nil }
+
+func (s *fakeServerStream) RecvMsg(m interface{}) error {
+	if len(s.incoming) == 0 {
+		return io.EOF
+	}
+	*m.(*string), s.incoming = s.incoming[0], s.incoming[1:]
+	return nil
+}
+
+func (s *fakeServerStream) SendMsg(m interface{}) error {
+	s.sent = append(s.sent, *m.(*string))
+	return nil
+}
+
+// skipWithoutGLS skips tests of goroutine-local trace state in builds with
+// the xlogger_nogls tag, where it stays empty
+func skipWithoutGLS(t *testing.T) {
+	t.Helper()
+	found := false
+	xlogger.RunWithTraceVoid("probe", "", func() { found = xlogger.TraceRequestID() != "" })
+	if !found {
+		t.Skip("goroutine-local trace state is empty with the xlogger_nogls tag")
+	}
+}
+
+// TestUnaryServerInterceptor tests trace extraction and call logging
+func TestUnaryServerInterceptor(t *testing.T) {
+	info := &grpc.UnaryServerInfo{FullMethod: "/orders.v1.Orders/Get"}
+
+	t.Run("should run handler inside incoming trace", func(t *testing.T) {
+		skipWithoutGLS(t)
+		logger, entries := newInterceptorLogger(t)
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+			RequestIDMetadataKey, "req-1",
+			CorrelationIDMetadataKey, "corr-1",
+		))
+
+		var glsRequestID, ctxCorrelationID string
+		resp, err := UnaryServerInterceptor(logger)(ctx, "request", info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			glsRequestID = xlogger.TraceRequestID()
+			_, ctxCorrelationID = xlogger.TraceFromContext(ctx)
+			return "response", nil
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "response", resp)
+		assert.Equal(t, "req-1", glsRequestID)
+		assert.Equal(t, "corr-1", ctxCorrelationID)
+
+		completed := entries("grpc request completed")
+		require.Len(t, completed, 1)
+		assert.Equal(t, "info", completed[0]["level"])
+		assert.Equal(t, "/orders.v1.Orders/Get", completed[0]["grpc_method"])
+		assert.Equal(t, "OK", completed[0]["grpc_code"])
+		assert.Equal(t, "req-1", completed[0]["request_id"])
+		assert.NotEmpty(t, completed[0]["duration"])
+	})
+
+	t.Run("should generate request ID and log failures", func(t *testing.T) {
+		logger, entries := newInterceptorLogger(t)
+
+		var requestID, correlationID string
+		_, err := UnaryServerInterceptor(logger)(context.Background(), "request", info, func(ctx context.Context, _ interface{}) (interface{}, error) {
+			requestID, correlationID = xlogger.TraceFromContext(ctx)
+			return nil, status.Error(codes.Unavailable, "database down")
+		})
+
+		require.Error(t, err)
+		assert.Len(t, requestID, 32)
+		assert.Equal(t, requestID, correlationID)
+
+		completed := entries("grpc request completed")
+		require.Len(t, completed, 1)
+		assert.Equal(t, "error", completed[0]["level"])
+		assert.Equal(t, "Unavailable", completed[0]["grpc_code"])
+		assert.Contains(t, completed[0]["error"], "database down")
+	})
+
+	t.Run("should log client errors at warn", func(t *testing.T) {
+		logger, entries := newInterceptorLogger(t)
+
+		_, _ = UnaryServerInterceptor(logger)(context.Background(), "request", info, func(context.Context, interface{}) (interface{}, error) {
+			return nil, status.Error(codes.NotFound, "no such order")
+		})
+
+		completed := entries("grpc request completed")
+		require.Len(t, completed, 1)
+		assert.Equal(t, "warn", completed[0]["level"])
+	})
+
+	t.Run("should log payloads only when enabled at debug", func(t *testing.T) {
+		logger, entries := newInterceptorLogger(t)
+		handler := func(context.Context, interface{}) (interface{}, error) { return "response", nil }
+
+		_, _ = UnaryServerInterceptor(logger, WithPayloadLogging(true))(context.Background(), "request", info, handler)
+		assert.Empty(t, entries("grpc request payload"))
+
+		logger.SetLevel(zapcore.DebugLevel)
+		_, _ = UnaryServerInterceptor(logger)(context.Background(), "request", info, handler)
+		assert.Empty(t, entries("grpc request payload"))
+
+		_, _ = UnaryServerInterceptor(logger, WithPayloadLogging(true))(context.Background(), "request", info, handler)
+		request := entries("grpc request payload")
+		require.Len(t, request, 1)
+		assert.Equal(t, "request", request[0]["payload"])
+		response := entries("grpc response payload")
+		require.Len(t, response, 1)
+		assert.Equal(t, "response", response[0]["payload"])
+	})
+}
+
+// TestStreamServerInterceptor tests streaming server logging
+func TestStreamServerInterceptor(t *testing.T) {
+	t.Run("should expose trace context and log stream", func(t *testing.T) {
+		skipWithoutGLS(t)
+		logger, entries := newInterceptorLogger(t)
+		logger.SetLevel(zapcore.DebugLevel)
+		stream := &fakeServerStream{
+			ctx:      metadata.NewIncomingContext(context.Background(), metadata.Pairs(RequestIDMetadataKey, "req-2")),
+			incoming: []string{"a", "b"},
+		}
+		info := &grpc.StreamServerInfo{FullMethod: "/orders.v1.Orders/Watch"}
+
+		var ctxRequestID string
+		err := StreamServerInterceptor(logger, WithPayloadLogging(true))(nil, stream, info, func(_ interface{}, ss grpc.ServerStream) error {
+			ctxRequestID, _ = xlogger.TraceFromContext(ss.Context())
+			for {
+				var msg string
+				if err := ss.RecvMsg(&msg); err != nil {
+					return nil
+				}
+				reply := strings.ToUpper(msg)
+				if err := ss.SendMsg(&reply); err != nil {
+					return err
+				}
+			}
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "req-2", ctxRequestID)
+		assert.Equal(t, []string{"A", "B"}, stream.sent)
+		assert.Len(t, entries("grpc request payload"), 2)
+		assert.Len(t, entries("grpc response payload"), 2)
+
+		completed := entries("grpc stream completed")
+		require.Len(t, completed, 1)
+		assert.Equal(t, "/orders.v1.Orders/Watch", completed[0]["grpc_method"])
+		assert.Equal(t, "req-2", completed[0]["request_id"])
+	})
+}
+
+// TestUnaryClientInterceptor tests trace propagation on outgoing calls
+func TestUnaryClientInterceptor(t *testing.T) {
+	t.Run("should forward context trace IDs", func(t *testing.T) {
+		logger, entries := newInterceptorLogger(t)
+		ctx := xlogger.ContextWithTrace(context.Background(), "req-3", "corr-3")
+
+		var md metadata.MD
+		err := UnaryClientInterceptor(logger)(ctx, "/orders.v1.Orders/Get", "request", nil, nil,
+			func(ctx context.Context, _ string, _, _ interface{}, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+				md, _ = metadata.FromOutgoingContext(ctx)
+				return nil
+			})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"req-3"}, md.Get(RequestIDMetadataKey))
+		assert.Equal(t, []string{"corr-3"}, md.Get(CorrelationIDMetadataKey))
+
+		completed := entries("grpc call completed")
+		require.Len(t, completed, 1)
+		assert.Equal(t, "OK", completed[0]["grpc_code"])
+	})
+
+	t.Run("should forward goroutine-local trace IDs", func(t *testing.T) {
+		skipWithoutGLS(t)
+		logger, _ := newInterceptorLogger(t)
+
+		var md metadata.MD
+		xlogger.RunWithTraceVoid("req-4", "corr-4", func() {
+			_ = UnaryClientInterceptor(logger)(context.Background(), "/orders.v1.Orders/Get", "request", nil, nil,
+				func(ctx context.Context, _ string, _, _ interface{}, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+					md, _ = metadata.FromOutgoingContext(ctx)
+					return nil
+				})
+		})
+
+		assert.Equal(t, []string{"req-4"}, md.Get(RequestIDMetadataKey))
+		assert.Equal(t, []string{"corr-4"}, md.Get(CorrelationIDMetadataKey))
+	})
+
+	t.Run("should keep explicit metadata", func(t *testing.T) {
+		logger, _ := newInterceptorLogger(t)
+		ctx := metadata.AppendToOutgoingContext(
+			xlogger.ContextWithTrace(context.Background(), "req-5", "corr-5"),
+			RequestIDMetadataKey, "explicit",
+		)
+
+		var md metadata.MD
+		_ = UnaryClientInterceptor(logger)(ctx, "/orders.v1.Orders/Get", "request", nil, nil,
+			func(ctx context.Context, _ string, _, _ interface{}, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+				md, _ = metadata.FromOutgoingContext(ctx)
+				return nil
+			})
+
+		assert.Equal(t, []string{"explicit"}, md.Get(RequestIDMetadataKey))
+		assert.Equal(t, []string{"corr-5"}, md.Get(CorrelationIDMetadataKey))
+	})
+}
+
+// fakeClientStream returns queued messages then an error from RecvMsg
+type fakeClientStream struct {
+	grpc.ClientStream
+	incoming []string
+	err      error
+}
+
+func (s *fakeClientStream) RecvMsg(m interface{}) error {
+	if len(s.incoming) == 0 {
+		return s.err
+	}
+	*m.(*string), s.incoming = s.incoming[0], s.incoming[1:]
+	return nil
+}
+
+// TestStreamClientInterceptor tests streaming client logging
+func TestStreamClientInterceptor(t *testing.T) {
+	t.Run("should log once when the stream ends", func(t *testing.T) {
+		logger, entries := newInterceptorLogger(t)
+		desc := &grpc.StreamDesc{ServerStreams: true}
+
+		cs, err := StreamClientInterceptor(logger)(context.Background(), desc, nil, "/orders.v1.Orders/Watch",
+			func(context.Context, *grpc.StreamDesc, *grpc.ClientConn, string, ...grpc.CallOption) (grpc.ClientStream, error) {
+				return &fakeClientStream{incoming: []string{"a"}, err: status.Error(codes.Internal, "broken")}, nil
+			})
+		require.NoError(t, err)
+
+		var msg string
+		require.NoError(t, cs.RecvMsg(&msg))
+		require.Error(t, cs.RecvMsg(&msg))
+		require.Error(t, cs.RecvMsg(&msg))
+
+		completed := entries("grpc stream completed")
+		require.Len(t, completed, 1)
+		assert.Equal(t, "error", completed[0]["level"])
+		assert.Equal(t, "Internal", completed[0]["grpc_code"])
+	})
+
+	t.Run("should treat EOF as success", func(t *testing.T) {
+		logger, entries := newInterceptorLogger(t)
+
+		cs, err := StreamClientInterceptor(logger)(context.Background(), &grpc.StreamDesc{}, nil, "/orders.v1.Orders/Watch",
+			func(context.Context, *grpc.StreamDesc, *grpc.ClientConn, string, ...grpc.CallOption) (grpc.ClientStream, error) {
+				return &fakeClientStream{err: io.EOF}, nil
+			})
+		require.NoError(t, err)
+
+		var msg string
+		assert.ErrorIs(t, cs.RecvMsg(&msg), io.EOF)
+
+		completed := entries("grpc stream completed")
+		require.Len(t, completed, 1)
+		assert.Equal(t, "OK", completed[0]["grpc_code"])
+	})
+}
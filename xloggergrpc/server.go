@@ -0,0 +1,120 @@
+package xloggergrpc
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"io"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Handler receives every entry pushed by an authenticated client.
+// Returning an error aborts the client stream with codes.Internal.
+type Handler func(ctx context.Context, service string, entry []byte) error
+
+// Server is the aggregator side of the log stream
+type Server struct {
+	handler Handler
+	apiKeys map[string]string
+}
+
+// ServerOption configures a Server
+type ServerOption func(*Server)
+
+// WithAPIKeys restricts the server to the given service name to API key pairs.
+// When no keys are configured every caller is accepted.
+func WithAPIKeys(keys map[string]string) ServerOption {
+	return func(s *Server) {
+		s.apiKeys = make(map[string]string, len(keys))
+		for service, key := range keys {
+			s.apiKeys[service] = key
+		}
+	}
+}
+
+// NewServer creates a log stream server that forwards entries to handler
+//
+// Example:
+//
+//	srv := xloggergrpc.NewServer(func(ctx context.Context, service string, entry []byte) error {
+//	    _, err := os.Stdout.Write(entry)
+//	    return err
+//	}, xloggergrpc.WithAPIKeys(map[string]string{"orders": "secret"}))
+//	grpcServer := grpc.NewServer(grpc.Creds(credentials.NewTLS(tlsConfig)))
+//	srv.Register(grpcServer)
+func NewServer(handler Handler, opts ...ServerOption) *Server {
+	s := &Server{handler: handler}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Register attaches the log stream service to a gRPC server
+func (s *Server) Register(registrar grpc.ServiceRegistrar) {
+	registrar.RegisterService(&grpc.ServiceDesc{
+		ServiceName: serviceName,
+		HandlerType: (*interface{})(nil),
+		Streams: []grpc.StreamDesc{
+			{
+				StreamName:    pushStreamName,
+				Handler:       s.push,
+				ClientStreams: true,
+			},
+		},
+		Metadata: "xlogger/v1/logstream",
+	}, s)
+}
+
+// push handles a single client stream until the client closes it
+func (s *Server) push(_ interface{}, stream grpc.ServerStream) error {
+	ctx := stream.Context()
+	service, err := s.authenticate(ctx)
+	if err != nil {
+		return err
+	}
+
+	for {
+		var f frame
+		if err := stream.RecvMsg(&f); err != nil {
+			if errors.Is(err, io.EOF) {
+				return stream.SendMsg(&frame{})
+			}
+			return err
+		}
+		if s.handler == nil {
+			continue
+		}
+		if err := s.handler(ctx, service, f.data); err != nil {
+			return status.Errorf(codes.Internal, "handle entry: %v", err)
+		}
+	}
+}
+
+// authenticate validates the caller's service name and API key
+func (s *Server) authenticate(ctx context.Context) (string, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	service := firstValue(md, ServiceMetadataKey)
+
+	if len(s.apiKeys) == 0 {
+		return service, nil
+	}
+
+	expected, ok := s.apiKeys[service]
+	provided := firstValue(md, APIKeyMetadataKey)
+	if !ok || subtle.ConstantTimeCompare([]byte(expected), []byte(provided)) != 1 {
+		return "", status.Error(codes.Unauthenticated, "invalid service or api key")
+	}
+	return service, nil
+}
+
+func firstValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
@@ -0,0 +1,244 @@
+package xloggergrpc
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// ErrSinkClosed is returned when writing to a closed Sink
+var ErrSinkClosed = errors.New("xloggergrpc: sink closed")
+
+// ErrStreamEnded is returned when the server closed the stream without an error status
+var ErrStreamEnded = errors.New("xloggergrpc: stream ended by server")
+
+// Sink streams encoded entries to a Server. It implements zap.Sink.
+type Sink struct {
+	conn    *grpc.ClientConn
+	service string
+	apiKey  string
+
+	mu     sync.Mutex
+	stream grpc.ClientStream
+	cancel context.CancelFunc
+	closed bool
+}
+
+// sinkOptions holds the dial settings collected from SinkOption values
+type sinkOptions struct {
+	service     string
+	apiKey      string
+	tlsConfig   *tls.Config
+	dialOptions []grpc.DialOption
+}
+
+// SinkOption configures a Sink
+type SinkOption func(*sinkOptions)
+
+// WithService identifies the client to the server with a service name and API key
+func WithService(service, apiKey string) SinkOption {
+	return func(o *sinkOptions) {
+		o.service = service
+		o.apiKey = apiKey
+	}
+}
+
+// WithTLS enables transport security; use NewMutualTLSConfig for mTLS
+func WithTLS(cfg *tls.Config) SinkOption {
+	return func(o *sinkOptions) {
+		o.tlsConfig = cfg
+	}
+}
+
+// WithDialOptions appends raw gRPC dial options
+func WithDialOptions(opts ...grpc.DialOption) SinkOption {
+	return func(o *sinkOptions) {
+		o.dialOptions = append(o.dialOptions, opts...)
+	}
+}
+
+// NewSink connects to a log stream server at target. The connection is
+// established lazily on the first write.
+//
+// Example:
+//
+//	sink, err := xloggergrpc.NewSink("aggregator:7070",
+//	    xloggergrpc.WithService("orders", "secret"),
+//	    xloggergrpc.WithTLS(tlsConfig),
+//	)
+//	_ = zap.RegisterSink("aggregator", func(*url.URL) (zap.Sink, error) { return sink, nil })
+func NewSink(target string, opts ...SinkOption) (*Sink, error) {
+	o := &sinkOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	creds := insecure.NewCredentials()
+	if o.tlsConfig != nil {
+		creds = credentials.NewTLS(o.tlsConfig)
+	}
+	dialOptions := append([]grpc.DialOption{grpc.WithTransportCredentials(creds)}, o.dialOptions...)
+
+	conn, err := grpc.NewClient(target, dialOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("xloggergrpc: dial %s: %w", target, err)
+	}
+
+	return &Sink{
+		conn:    conn,
+		service: o.service,
+		apiKey:  o.apiKey,
+	}, nil
+}
+
+// Write sends p as a single entry, reopening the stream once if it broke
+func (s *Sink) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return 0, ErrSinkClosed
+	}
+
+	err := s.send(p)
+	if err == nil {
+		return len(p), nil
+	}
+	if !isRetryable(err) {
+		return 0, err
+	}
+	// The stream was torn down by the network or a server restart; retry once on a fresh one
+	if err := s.send(p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// send writes one frame, opening the stream when needed and discarding it on failure
+func (s *Sink) send(p []byte) error {
+	if s.stream == nil {
+		if err := s.openStream(); err != nil {
+			return err
+		}
+	}
+
+	err := s.stream.SendMsg(&frame{data: p})
+	if errors.Is(err, io.EOF) {
+		// SendMsg reports io.EOF when the server ended the stream; the status is on RecvMsg
+		err = s.stream.RecvMsg(&frame{})
+		if err == nil || errors.Is(err, io.EOF) {
+			err = ErrStreamEnded
+		}
+	}
+	if err != nil {
+		s.resetStream()
+	}
+	return err
+}
+
+// isRetryable reports whether a send failure may succeed on a new stream.
+// Rejections such as Unauthenticated are final and returned to the caller.
+func isRetryable(err error) bool {
+	if errors.Is(err, ErrStreamEnded) {
+		return true
+	}
+	code := status.Code(err)
+	return code == codes.Unavailable || code == codes.Canceled
+}
+
+// Sync implements zapcore.WriteSyncer; entries are sent on every Write
+func (s *Sink) Sync() error {
+	return nil
+}
+
+// Close half-closes the stream, waits for the server acknowledgement and
+// releases the connection
+func (s *Sink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+
+	var streamErr error
+	if s.stream != nil {
+		if err := s.stream.CloseSend(); err == nil {
+			streamErr = s.stream.RecvMsg(&frame{})
+		} else {
+			streamErr = err
+		}
+		s.resetStream()
+	}
+
+	return errors.Join(streamErr, s.conn.Close())
+}
+
+// openStream starts a new client stream carrying the service credentials
+func (s *Sink) openStream() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	if s.service != "" || s.apiKey != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx,
+			ServiceMetadataKey, s.service,
+			APIKeyMetadataKey, s.apiKey)
+	}
+
+	desc := &grpc.StreamDesc{StreamName: pushStreamName, ClientStreams: true}
+	stream, err := s.conn.NewStream(ctx, desc, pushMethod, grpc.CallContentSubtype(codecName))
+	if err != nil {
+		cancel()
+		return fmt.Errorf("xloggergrpc: open stream: %w", err)
+	}
+
+	s.stream = stream
+	s.cancel = cancel
+	return nil
+}
+
+func (s *Sink) resetStream() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.stream = nil
+	s.cancel = nil
+}
+
+// NewMutualTLSConfig loads a certificate pair and CA bundle into a tls.Config
+// usable on both sides of the stream: the CA verifies servers for clients and
+// clients for servers, which are required to present a certificate.
+func NewMutualTLSConfig(certFile, keyFile, caFile string) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("xloggergrpc: load key pair: %w", err)
+	}
+
+	caPEM, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("xloggergrpc: read ca: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPEM) {
+		return nil, fmt.Errorf("xloggergrpc: no certificates found in %s", caFile)
+	}
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      pool,
+		ClientCAs:    pool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
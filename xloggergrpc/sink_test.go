@@ -0,0 +1,158 @@
+package xloggergrpc
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// received collects entries delivered to a test server
+type received struct {
+	mu       sync.Mutex
+	services []string
+	entries  []string
+}
+
+func (r *received) handle(_ context.Context, service string, entry []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.services = append(r.services, service)
+	r.entries = append(r.entries, string(entry))
+	return nil
+}
+
+func (r *received) snapshot() ([]string, []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.services...), append([]string(nil), r.entries...)
+}
+
+// startServer runs a Server on an in-memory listener and returns a dialer option for it
+func startServer(t *testing.T, srv *Server) grpc.DialOption {
+	t.Helper()
+
+	listener := bufconn.Listen(1 << 20)
+	grpcServer := grpc.NewServer()
+	srv.Register(grpcServer)
+	go func() { _ = grpcServer.Serve(listener) }()
+	t.Cleanup(grpcServer.Stop)
+
+	return grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+		return listener.DialContext(ctx)
+	})
+}
+
+func TestSink_StreamsEntries(t *testing.T) {
+	t.Run("should deliver each write as one entry", func(t *testing.T) {
+		rec := &received{}
+		dialer := startServer(t, NewServer(rec.handle))
+
+		sink, err := NewSink("passthrough:///bufnet", WithService("orders", ""), WithDialOptions(dialer))
+		require.NoError(t, err)
+
+		_, err = sink.Write([]byte("first\n"))
+		require.NoError(t, err)
+		_, err = sink.Write([]byte("second\n"))
+		require.NoError(t, err)
+		require.NoError(t, sink.Close())
+
+		services, entries := rec.snapshot()
+		assert.Equal(t, []string{"first\n", "second\n"}, entries)
+		assert.Equal(t, []string{"orders", "orders"}, services)
+	})
+
+	t.Run("should work as a zap core output", func(t *testing.T) {
+		rec := &received{}
+		dialer := startServer(t, NewServer(rec.handle))
+
+		sink, err := NewSink("passthrough:///bufnet", WithDialOptions(dialer))
+		require.NoError(t, err)
+
+		encoder := zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig())
+		logger := zap.New(zapcore.NewCore(encoder, sink, zapcore.InfoLevel))
+		logger.Info("streamed", zap.String("order_id", "o-1"))
+		require.NoError(t, sink.Close())
+
+		_, entries := rec.snapshot()
+		require.Len(t, entries, 1)
+		assert.Contains(t, entries[0], `"msg":"streamed"`)
+		assert.Contains(t, entries[0], `"order_id":"o-1"`)
+	})
+
+	t.Run("should reject writes after close", func(t *testing.T) {
+		dialer := startServer(t, NewServer(nil))
+
+		sink, err := NewSink("passthrough:///bufnet", WithDialOptions(dialer))
+		require.NoError(t, err)
+		require.NoError(t, sink.Close())
+
+		_, err = sink.Write([]byte("late"))
+		assert.ErrorIs(t, err, ErrSinkClosed)
+		assert.NoError(t, sink.Close())
+	})
+}
+
+func TestServer_APIKeys(t *testing.T) {
+	keys := map[string]string{"orders": "s3cret"}
+
+	t.Run("should accept a matching api key", func(t *testing.T) {
+		rec := &received{}
+		dialer := startServer(t, NewServer(rec.handle, WithAPIKeys(keys)))
+
+		sink, err := NewSink("passthrough:///bufnet", WithService("orders", "s3cret"), WithDialOptions(dialer))
+		require.NoError(t, err)
+
+		_, err = sink.Write([]byte("ok"))
+		require.NoError(t, err)
+		require.NoError(t, sink.Close())
+
+		_, entries := rec.snapshot()
+		assert.Equal(t, []string{"ok"}, entries)
+	})
+
+	t.Run("should reject an invalid api key", func(t *testing.T) {
+		rec := &received{}
+		dialer := startServer(t, NewServer(rec.handle, WithAPIKeys(keys)))
+
+		sink, err := NewSink("passthrough:///bufnet", WithService("orders", "wrong"), WithDialOptions(dialer))
+		require.NoError(t, err)
+		defer sink.Close()
+
+		// The rejection surfaces on the first write that observes the closed stream
+		deadline := time.Now().Add(2 * time.Second)
+		for err == nil && time.Now().Before(deadline) {
+			_, err = sink.Write([]byte("denied"))
+		}
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+		_, entries := rec.snapshot()
+		assert.Empty(t, entries)
+	})
+
+	t.Run("should reject an unknown service", func(t *testing.T) {
+		srv := NewServer(nil, WithAPIKeys(keys))
+		_, err := srv.authenticate(context.Background())
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+}
+
+func TestNewMutualTLSConfig(t *testing.T) {
+	t.Run("should fail on missing files", func(t *testing.T) {
+		cfg, err := NewMutualTLSConfig("missing.crt", "missing.key", "missing-ca.crt")
+		assert.Nil(t, cfg)
+		assert.Error(t, err)
+		assert.True(t, strings.HasPrefix(err.Error(), "xloggergrpc:"))
+	})
+}
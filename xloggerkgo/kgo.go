@@ -0,0 +1,47 @@
+// Package xloggerkgo routes the diagnostics of franz-go clients through
+// xlogger by implementing kgo.Logger.
+package xloggerkgo
+
+import (
+	"github.com/twmb/franz-go/pkg/kgo"
+
+	"github.com/hotfixfirst/go-xlogger"
+)
+
+// client is the kafka_client field of the entries of Logger
+const client = "franz-go"
+
+// Logger implements kgo.Logger with an xlogger.KafkaLogger, keeping the
+// levels and key/value pairs of franz-go.
+type Logger struct {
+	kafka *xlogger.KafkaLogger
+}
+
+var _ kgo.Logger = (*Logger)(nil)
+
+// New returns a kgo.Logger whose entries carry "franz-go" as kafka_client.
+// Pass logger.ForInfra("kafka") to control its level with
+// SetComponentLevel("kafka", level).
+//
+// Example:
+//
+//	client, err := kgo.NewClient(
+//	    kgo.SeedBrokers("localhost:9092"),
+//	    kgo.WithLogger(xloggerkgo.New(logger.ForInfra("kafka"))),
+//	)
+func New(logger xlogger.Logger) *Logger {
+	// Skips Log, so entries keep the caller in franz-go
+	return &Logger{kafka: xlogger.NewKafkaLogger(logger, client).AddCallerSkip(1)}
+}
+
+// Level implements kgo.Logger, returning the most verbose level the logger
+// writes so franz-go skips building the others.
+func (l *Logger) Level() kgo.LogLevel {
+	return kgo.LogLevel(l.kafka.Level())
+}
+
+// Log implements kgo.Logger. kgo.LogLevelNone and unknown levels are
+// dropped.
+func (l *Logger) Log(level kgo.LogLevel, msg string, keyvals ...any) {
+	l.kafka.Log(int8(level), msg, keyvals...)
+}
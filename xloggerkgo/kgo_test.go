@@ -0,0 +1,84 @@
+package xloggerkgo
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/hotfixfirst/go-xlogger"
+)
+
+func newFileLogger(t *testing.T) (*xlogger.ZapLogger, string) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	logger, err := xlogger.NewZapLogger(xlogger.NewLoggerConfig(xlogger.WithOutputPaths(path)))
+	require.NoError(t, err)
+	return logger, path
+}
+
+func readEntries(t *testing.T, path string) []map[string]interface{} {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var entries []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestLogger(t *testing.T) {
+	t.Run("should log franz-go levels and key/value pairs", func(t *testing.T) {
+		logger, path := newFileLogger(t)
+		var kgoLogger kgo.Logger = New(logger.ForInfra("kafka"))
+
+		kgoLogger.Log(kgo.LogLevelWarn, "unable to open connection to broker", "addr", "b1:9092", "err", errors.New("refused"))
+		kgoLogger.Log(kgo.LogLevelDebug, "wrote Produce v9")
+		kgoLogger.Log(kgo.LogLevelNone, "dropped")
+
+		entries := readEntries(t, path)
+		require.Len(t, entries, 1)
+		assert.Equal(t, "warn", entries[0]["level"])
+		assert.Equal(t, "unable to open connection to broker", entries[0]["message"])
+		assert.Equal(t, "franz-go", entries[0]["kafka_client"])
+		assert.Equal(t, "kafka", entries[0]["component"])
+		assert.Equal(t, "b1:9092", entries[0]["addr"])
+		assert.Equal(t, "refused", entries[0]["err"])
+	})
+
+	t.Run("should report the caller of Log", func(t *testing.T) {
+		logger, path := newFileLogger(t)
+
+		New(logger).Log(kgo.LogLevelInfo, "metadata refreshed")
+
+		entries := readEntries(t, path)
+		require.Len(t, entries, 1)
+		assert.Contains(t, entries[0]["caller"], "kgo_test.go")
+	})
+
+	t.Run("should report the most verbose enabled level", func(t *testing.T) {
+		logger, _ := newFileLogger(t)
+		kgoLogger := New(logger)
+
+		assert.Equal(t, kgo.LogLevelInfo, kgoLogger.Level())
+		logger.SetLevel(zapcore.DebugLevel)
+		assert.Equal(t, kgo.LogLevelDebug, kgoLogger.Level())
+		logger.SetLevel(zapcore.FatalLevel)
+		assert.Equal(t, kgo.LogLevelNone, kgoLogger.Level())
+	})
+}
@@ -0,0 +1,58 @@
+// Package xloggerotel attaches the active OpenTelemetry span context to
+// xlogger entries, so logs line up with traces without manual field plumbing.
+package xloggerotel
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/hotfixfirst/go-xlogger"
+)
+
+// Field keys match the W3C trace fields written by xlogger.RunWithTraceparent
+const (
+	TraceIDKey = "trace_id"
+	SpanIDKey  = "span_id"
+	SampledKey = "trace_sampled"
+)
+
+var registerOnce sync.Once
+
+// Register makes Logger.WithContext attach the span context of the context's
+// active span. It is safe to call more than once.
+//
+// Example:
+//
+//	xloggerotel.Register()
+//	logger.WithContext(ctx).Info("charging card") // includes trace_id, span_id, trace_sampled
+func Register() {
+	registerOnce.Do(func() {
+		xlogger.RegisterContextFields(Fields)
+	})
+}
+
+// Fields returns trace_id, span_id and trace_sampled for the active span in
+// ctx, or nil when ctx carries no valid span context.
+func Fields(ctx context.Context) []xlogger.Field {
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.IsValid() {
+		return nil
+	}
+	return []xlogger.Field{
+		xlogger.String(TraceIDKey, spanContext.TraceID().String()),
+		xlogger.String(SpanIDKey, spanContext.SpanID().String()),
+		xlogger.Bool(SampledKey, spanContext.IsSampled()),
+	}
+}
+
+// WithContext returns a logger carrying the span context of ctx without
+// requiring Register.
+func WithContext(logger xlogger.Logger, ctx context.Context) xlogger.Logger {
+	fields := Fields(ctx)
+	if len(fields) == 0 {
+		return logger
+	}
+	return logger.With(fields...)
+}
@@ -0,0 +1,109 @@
+package xloggerotel
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/hotfixfirst/go-xlogger"
+)
+
+func newSpanContext(t *testing.T, sampled bool) context.Context {
+	t.Helper()
+
+	traceID, err := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	require.NoError(t, err)
+	spanID, err := trace.SpanIDFromHex("00f067aa0ba902b7")
+	require.NoError(t, err)
+
+	var flags trace.TraceFlags
+	if sampled {
+		flags = trace.FlagsSampled
+	}
+	spanContext := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: flags,
+	})
+	return trace.ContextWithSpanContext(context.Background(), spanContext)
+}
+
+func newFileLogger(t *testing.T) (*xlogger.ZapLogger, string) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	logger, err := xlogger.NewZapLogger(xlogger.NewLoggerConfig(xlogger.WithOutputPaths(path)))
+	require.NoError(t, err)
+	return logger, path
+}
+
+func readLog(t *testing.T, path string) string {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestFields(t *testing.T) {
+	t.Run("should extract span context", func(t *testing.T) {
+		fields := Fields(newSpanContext(t, true))
+
+		require.Len(t, fields, 3)
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", fields[0].Value())
+		assert.Equal(t, "00f067aa0ba902b7", fields[1].Value())
+		assert.Equal(t, true, fields[2].Value())
+	})
+
+	t.Run("should report unsampled spans", func(t *testing.T) {
+		fields := Fields(newSpanContext(t, false))
+
+		require.Len(t, fields, 3)
+		assert.Equal(t, false, fields[2].Value())
+	})
+
+	t.Run("should return nil without span", func(t *testing.T) {
+		assert.Nil(t, Fields(context.Background()))
+	})
+}
+
+func TestWithContext(t *testing.T) {
+	t.Run("should add span fields to entries", func(t *testing.T) {
+		logger, path := newFileLogger(t)
+
+		WithContext(logger, newSpanContext(t, true)).Info("traced")
+
+		output := readLog(t, path)
+		assert.Contains(t, output, `"trace_id":"4bf92f3577b34da6a3ce929d0e0e4736"`)
+		assert.Contains(t, output, `"span_id":"00f067aa0ba902b7"`)
+		assert.Contains(t, output, `"trace_sampled":true`)
+	})
+
+	t.Run("should return logger without span", func(t *testing.T) {
+		logger, _ := newFileLogger(t)
+
+		assert.Same(t, logger, WithContext(logger, context.Background()))
+	})
+}
+
+func TestRegister(t *testing.T) {
+	t.Run("should make Logger.WithContext attach span fields", func(t *testing.T) {
+		Register()
+		Register()
+
+		logger, path := newFileLogger(t)
+		ctx := xlogger.ContextWithTrace(newSpanContext(t, true), "req-1", "")
+
+		logger.WithContext(ctx).Info("registered")
+
+		output := readLog(t, path)
+		assert.Contains(t, output, `"request_id":"req-1"`)
+		assert.Contains(t, output, `"trace_id":"4bf92f3577b34da6a3ce929d0e0e4736"`)
+		assert.Contains(t, output, `"trace_sampled":true`)
+	})
+}
@@ -0,0 +1,137 @@
+// Package xloggersentry forwards xlogger Error, Panic and Fatal entries to
+// Sentry as events carrying the entry fields, stack and trace IDs.
+package xloggersentry
+
+import (
+	"errors"
+	"reflect"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/hotfixfirst/go-xlogger"
+)
+
+// DefaultFlushTimeout bounds how long Sync waits for queued events
+const DefaultFlushTimeout = 2 * time.Second
+
+// ErrFlushTimeout is returned by Sync when events are still queued after the timeout
+var ErrFlushTimeout = errors.New("xloggersentry: flush timed out")
+
+// Reporter is an xlogger.ErrorReporter sending events through a Sentry hub.
+type Reporter struct {
+	hub          *sentry.Hub
+	flushTimeout time.Duration
+}
+
+// New returns a Reporter using hub, or sentry.CurrentHub() when hub is nil,
+// so it follows sentry.Init.
+//
+// Example:
+//
+//	_ = sentry.Init(sentry.ClientOptions{Dsn: dsn})
+//	cfg := xlogger.NewLoggerConfig(
+//	    xlogger.WithErrorReporter(xloggersentry.New(nil)),
+//	)
+func New(hub *sentry.Hub) *Reporter {
+	return &Reporter{hub: hub, flushTimeout: DefaultFlushTimeout}
+}
+
+// WithFlushTimeout sets how long Sync waits for queued events.
+func (r *Reporter) WithFlushTimeout(timeout time.Duration) *Reporter {
+	r.flushTimeout = timeout
+	return r
+}
+
+// Report implements xlogger.ErrorReporter
+func (r *Reporter) Report(report xlogger.ErrorReport) error {
+	r.currentHub().CaptureEvent(Event(report))
+	return nil
+}
+
+// Sync flushes queued events; xlogger calls it on Sync and before Panic and Fatal return
+func (r *Reporter) Sync() error {
+	if !r.currentHub().Flush(r.flushTimeout) {
+		return ErrFlushTimeout
+	}
+	return nil
+}
+
+func (r *Reporter) currentHub() *sentry.Hub {
+	if r.hub != nil {
+		return r.hub
+	}
+	return sentry.CurrentHub()
+}
+
+// Event converts an ErrorReport to a Sentry event. The stack is attached to
+// the exception built from the report's error, or to the current thread
+// when the entry has no error field.
+func Event(report xlogger.ErrorReport) *sentry.Event {
+	event := sentry.NewEvent()
+	event.Level = level(report.Level)
+	event.Message = report.Message
+	event.Timestamp = report.Time
+	event.Logger = "xlogger"
+	for key, value := range report.Fields {
+		event.Extra[key] = value
+	}
+	if report.Caller != "" {
+		event.Extra["caller"] = report.Caller
+	}
+	for key, value := range map[string]string{
+		"request_id":     report.RequestID,
+		"correlation_id": report.CorrelationID,
+	} {
+		if value != "" {
+			event.Tags[key] = value
+		}
+	}
+	if report.TraceID != "" {
+		event.Contexts["trace"] = sentry.Context{
+			"trace_id": report.TraceID,
+			"span_id":  report.SpanID,
+		}
+	}
+
+	stack := stacktrace(report)
+	if report.Error != nil {
+		event.Exception = []sentry.Exception{{
+			Type:       reflect.TypeOf(report.Error).String(),
+			Value:      report.Error.Error(),
+			Stacktrace: stack,
+		}}
+	} else if stack != nil {
+		event.Threads = []sentry.Thread{{Stacktrace: stack, Current: true}}
+	}
+	return event
+}
+
+// stacktrace converts the report stack; Sentry lists the outermost frame first
+func stacktrace(report xlogger.ErrorReport) *sentry.Stacktrace {
+	if len(report.Stack) == 0 {
+		return nil
+	}
+	frames := make([]sentry.Frame, len(report.Stack))
+	for i, frame := range report.Stack {
+		frames[len(frames)-1-i] = sentry.NewFrame(frame)
+	}
+	return &sentry.Stacktrace{Frames: frames}
+}
+
+// level maps a zap level to a Sentry level
+func level(l zapcore.Level) sentry.Level {
+	switch {
+	case l >= zapcore.FatalLevel:
+		return sentry.LevelFatal
+	case l >= zapcore.ErrorLevel:
+		return sentry.LevelError
+	case l >= zapcore.WarnLevel:
+		return sentry.LevelWarning
+	case l >= zapcore.InfoLevel:
+		return sentry.LevelInfo
+	default:
+		return sentry.LevelDebug
+	}
+}
@@ -0,0 +1,122 @@
+package xloggersentry
+
+import (
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/hotfixfirst/go-xlogger"
+)
+
+// newTestHub returns a hub whose client hands events to the returned function
+func newTestHub(t *testing.T) (*sentry.Hub, func() []*sentry.Event) {
+	t.Helper()
+
+	var (
+		mu     sync.Mutex
+		events []*sentry.Event
+	)
+	client, err := sentry.NewClient(sentry.ClientOptions{
+		BeforeSend: func(event *sentry.Event, _ *sentry.EventHint) *sentry.Event {
+			mu.Lock()
+			defer mu.Unlock()
+			events = append(events, event)
+			return nil
+		},
+	})
+	require.NoError(t, err)
+
+	return sentry.NewHub(client, sentry.NewScope()), func() []*sentry.Event {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]*sentry.Event(nil), events...)
+	}
+}
+
+// skipWithoutGLS skips tests of goroutine-local trace state in builds with
+// the xlogger_nogls tag, where it stays empty
+func skipWithoutGLS(t *testing.T) {
+	t.Helper()
+	found := false
+	xlogger.RunWithTraceVoid("probe", "", func() { found = xlogger.TraceRequestID() != "" })
+	if !found {
+		t.Skip("goroutine-local trace state is empty with the xlogger_nogls tag")
+	}
+}
+
+// TestReporter tests forwarding xlogger entries to Sentry
+func TestReporter(t *testing.T) {
+	newLogger := func(t *testing.T, reporter *Reporter) *xlogger.ZapLogger {
+		logger, err := xlogger.NewZapLogger(xlogger.NewLoggerConfig(
+			xlogger.WithOutputPaths(filepath.Join(t.TempDir(), "app.log")),
+			xlogger.WithErrorReporter(reporter),
+		))
+		require.NoError(t, err)
+		return logger
+	}
+
+	t.Run("should send errors as exception events", func(t *testing.T) {
+		skipWithoutGLS(t)
+		hub, events := newTestHub(t)
+		logger := newLogger(t, New(hub))
+
+		xlogger.RunWithTraceVoid("req-1", "corr-1", func() {
+			logger.Error("charge failed", xlogger.Error(errors.New("card declined")), xlogger.Int("amount", 42))
+		})
+
+		require.Len(t, events(), 1)
+		event := events()[0]
+		assert.Equal(t, sentry.LevelError, event.Level)
+		assert.Equal(t, "charge failed", event.Message)
+		assert.Equal(t, "req-1", event.Tags["request_id"])
+		assert.Equal(t, "corr-1", event.Tags["correlation_id"])
+		assert.EqualValues(t, 42, event.Extra["amount"])
+		assert.Contains(t, event.Extra["caller"], "sentry_test.go")
+
+		require.Len(t, event.Exception, 1)
+		assert.Equal(t, "*errors.errorString", event.Exception[0].Type)
+		assert.Equal(t, "card declined", event.Exception[0].Value)
+		require.NotNil(t, event.Exception[0].Stacktrace)
+		frames := event.Exception[0].Stacktrace.Frames
+		assert.Contains(t, frames[len(frames)-1].Function, "TestReporter")
+	})
+
+	t.Run("should attach stack to thread without error field", func(t *testing.T) {
+		hub, events := newTestHub(t)
+		logger := newLogger(t, New(hub))
+
+		logger.Error("queue stuck")
+
+		require.Len(t, events(), 1)
+		event := events()[0]
+		assert.Empty(t, event.Exception)
+		require.Len(t, event.Threads, 1)
+		assert.NotEmpty(t, event.Threads[0].Stacktrace.Frames)
+	})
+
+	t.Run("should add trace context", func(t *testing.T) {
+		event := Event(xlogger.ErrorReport{
+			Level:   zapcore.FatalLevel,
+			Message: "boom",
+			TraceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+			SpanID:  "00f067aa0ba902b7",
+		})
+
+		assert.Equal(t, sentry.LevelFatal, event.Level)
+		assert.Equal(t, sentry.Context{
+			"trace_id": "4bf92f3577b34da6a3ce929d0e0e4736",
+			"span_id":  "00f067aa0ba902b7",
+		}, event.Contexts["trace"])
+	})
+
+	t.Run("should flush on sync", func(t *testing.T) {
+		hub, _ := newTestHub(t)
+		assert.NoError(t, New(hub).Sync())
+	})
+}